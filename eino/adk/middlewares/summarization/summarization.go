@@ -0,0 +1,285 @@
+// Package summarization 提供 ChatModelAgent 的对话摘要中间件。
+//
+// 当对话历史超过 token 阈值时，中间件在 ChatModel 调用前用独立的模型把较早的消息压缩为摘要，
+// 只保留最近的消息原文。摘要以带标记的系统消息保存在智能体状态中，后续再次超过阈值时会与新的
+// 旧消息一起滚动压缩；同时写入会话变量，随检查点一起持久化。历史中没有摘要消息时，
+// 以会话变量中的摘要作为已有摘要，因此 Runner.Resume 或携带会话变量的新一轮运行不会丢失摘要。
+//
+// 注意：ChatModelAgent 只在配置了工具（ReAct 循环）时执行 BeforeChatModel 钩子。
+package summarization
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/favbox/eino/adk"
+	"github.com/favbox/eino/components/model"
+	"github.com/favbox/eino/schema"
+)
+
+const (
+	// ExtraKeySummary 摘要消息 Extra 中的标记键，用于在后续调用中识别已有摘要。
+	ExtraKeySummary = "_eino_summarization_summary"
+
+	// DefaultSessionKey 摘要在会话变量中的默认键名。
+	DefaultSessionKey = "_eino_summarization_summary"
+
+	defaultMaxTokensBeforeSummary = 32000
+	defaultMaxTokensForRecent     = 8000
+)
+
+// DefaultSystemPrompt 生成摘要时使用的默认系统提示词。
+const DefaultSystemPrompt = `You are summarizing an ongoing conversation between a user and an AI assistant so that the assistant can continue the task with limited context.
+
+Write a concise but complete summary that preserves:
+- The user's goals, requirements and constraints
+- Important facts, decisions and conclusions reached so far
+- Tool calls that were made and the key information in their results
+- Open questions and the work that still remains
+
+If a previous summary is provided, merge it with the new messages into a single updated summary. Output only the summary.`
+
+// summaryMessagePrefix 写入对话历史的摘要消息前缀。
+const summaryMessagePrefix = "Summary of the earlier conversation:\n"
+
+// Config 摘要中间件配置。
+type Config struct {
+	// Model 用于生成摘要的聊天模型，必填。
+	Model model.BaseChatModel
+
+	// Tokenizer 统计 token 的分词器，默认使用 schema.EstimateTokenizer。
+	Tokenizer schema.Tokenizer
+
+	// MaxTokensBeforeSummary 触发摘要的历史消息 token 阈值，默认 32000。
+	MaxTokensBeforeSummary int
+
+	// MaxTokensForRecent 摘要后保留原文的最近消息 token 预算，默认 8000。
+	// 工具调用与对应的工具结果不会被拆开。
+	MaxTokensForRecent int
+
+	// SystemPrompt 生成摘要时的系统提示词，默认使用 DefaultSystemPrompt。
+	SystemPrompt string
+
+	// SessionKey 摘要在会话变量中的键名，默认使用 DefaultSessionKey。
+	SessionKey string
+
+	// Callback 每次生成摘要后调用，可用于审计被压缩的消息，可选。
+	Callback func(ctx context.Context, info *SummaryInfo)
+}
+
+// SummaryInfo 一次摘要的详细信息。
+type SummaryInfo struct {
+	// PreviousSummary 本次合并前的摘要，首次摘要时为空。
+	PreviousSummary string
+	// Summarized 本次被压缩的消息。
+	Summarized []*schema.Message
+	// Summary 新生成的摘要。
+	Summary string
+	// TokensBefore 摘要前的历史消息 token 数。
+	TokensBefore int
+	// TokensAfter 摘要后的历史消息 token 数。
+	TokensAfter int
+}
+
+// New 创建摘要中间件，通过 adk.ChatModelAgentConfig.Middlewares 使用。
+func New(_ context.Context, config *Config) (adk.AgentMiddleware, error) {
+	if config == nil {
+		return adk.AgentMiddleware{}, errors.New("summarization config is required")
+	}
+	if config.Model == nil {
+		return adk.AgentMiddleware{}, errors.New("summarization 'Model' is required")
+	}
+
+	s := &summarizer{
+		model:                  config.Model,
+		tokenizer:              config.Tokenizer,
+		maxTokensBeforeSummary: config.MaxTokensBeforeSummary,
+		maxTokensForRecent:     config.MaxTokensForRecent,
+		systemPrompt:           config.SystemPrompt,
+		sessionKey:             config.SessionKey,
+		callback:               config.Callback,
+	}
+	if s.tokenizer == nil {
+		s.tokenizer = schema.NewEstimateTokenizer(nil)
+	}
+	if s.maxTokensBeforeSummary <= 0 {
+		s.maxTokensBeforeSummary = defaultMaxTokensBeforeSummary
+	}
+	if s.maxTokensForRecent <= 0 {
+		s.maxTokensForRecent = defaultMaxTokensForRecent
+	}
+	if s.maxTokensForRecent >= s.maxTokensBeforeSummary {
+		return adk.AgentMiddleware{}, fmt.Errorf("'MaxTokensForRecent'(%d) must be less than 'MaxTokensBeforeSummary'(%d)",
+			s.maxTokensForRecent, s.maxTokensBeforeSummary)
+	}
+	if s.systemPrompt == "" {
+		s.systemPrompt = DefaultSystemPrompt
+	}
+	if s.sessionKey == "" {
+		s.sessionKey = DefaultSessionKey
+	}
+
+	return adk.AgentMiddleware{BeforeChatModel: s.beforeChatModel}, nil
+}
+
+// IsSummaryMessage 判断消息是否为摘要中间件生成的摘要消息。
+func IsSummaryMessage(m *schema.Message) bool {
+	if m == nil || m.Extra == nil {
+		return false
+	}
+	marked, _ := m.Extra[ExtraKeySummary].(bool)
+	return marked
+}
+
+type summarizer struct {
+	model                  model.BaseChatModel
+	tokenizer              schema.Tokenizer
+	maxTokensBeforeSummary int
+	maxTokensForRecent     int
+	systemPrompt           string
+	sessionKey             string
+	callback               func(ctx context.Context, info *SummaryInfo)
+}
+
+func (s *summarizer) beforeChatModel(ctx context.Context, state *adk.ChatModelAgentState) error {
+	before, err := schema.CountMessagesTokens(ctx, s.tokenizer, state.Messages)
+	if err != nil {
+		return fmt.Errorf("[Summarization] failed to count tokens: %w", err)
+	}
+	if before <= s.maxTokensBeforeSummary {
+		return nil
+	}
+
+	// 拆分开头的系统消息、已有摘要与其余历史
+	var (
+		system   []*schema.Message
+		previous string
+		rest     = make([]*schema.Message, 0, len(state.Messages))
+	)
+	leading := true
+	for _, m := range state.Messages {
+		if m == nil {
+			continue
+		}
+		if leading && m.Role == schema.System {
+			if IsSummaryMessage(m) {
+				previous = strings.TrimPrefix(m.Content, summaryMessagePrefix)
+			} else {
+				system = append(system, m)
+			}
+			continue
+		}
+		leading = false
+		rest = append(rest, m)
+	}
+	if previous == "" {
+		// 历史中没有摘要消息时（如调用方重建了历史），回退到会话变量中持久化的摘要
+		if v, ok := adk.GetSessionValue(ctx, s.sessionKey); ok {
+			previous, _ = v.(string)
+		}
+	}
+
+	recent, err := schema.TrimMessages(ctx, rest, &schema.TrimConfig{
+		MaxTokens: s.maxTokensForRecent,
+		Tokenizer: s.tokenizer,
+	})
+	if err != nil {
+		return fmt.Errorf("[Summarization] failed to split messages: %w", err)
+	}
+	older := rest[:len(rest)-len(recent)]
+	if len(older) == 0 {
+		return nil
+	}
+
+	summary, err := s.summarize(ctx, previous, older)
+	if err != nil {
+		return err
+	}
+
+	summaryMsg := schema.SystemMessage(summaryMessagePrefix + summary)
+	summaryMsg.Extra = map[string]any{ExtraKeySummary: true}
+
+	messages := make([]*schema.Message, 0, len(system)+1+len(recent))
+	messages = append(messages, system...)
+	messages = append(messages, summaryMsg)
+	messages = append(messages, recent...)
+	state.Messages = messages
+
+	adk.AddSessionValue(ctx, s.sessionKey, summary)
+
+	if s.callback != nil {
+		after, err := schema.CountMessagesTokens(ctx, s.tokenizer, messages)
+		if err != nil {
+			return fmt.Errorf("[Summarization] failed to count tokens: %w", err)
+		}
+		s.callback(ctx, &SummaryInfo{
+			PreviousSummary: previous,
+			Summarized:      older,
+			Summary:         summary,
+			TokensBefore:    before,
+			TokensAfter:     after,
+		})
+	}
+
+	return nil
+}
+
+// summarize 调用模型将已有摘要与较早的消息合并为新摘要。
+func (s *summarizer) summarize(ctx context.Context, previous string, older []*schema.Message) (string, error) {
+	var sb strings.Builder
+	if previous != "" {
+		sb.WriteString("## Previous summary\n")
+		sb.WriteString(previous)
+		sb.WriteString("\n\n")
+	}
+	sb.WriteString("## New messages\n")
+	for _, m := range older {
+		writeTranscript(&sb, m)
+	}
+
+	out, err := s.model.Generate(ctx, []*schema.Message{
+		schema.SystemMessage(s.systemPrompt),
+		schema.UserMessage(sb.String()),
+	})
+	if err != nil {
+		return "", fmt.Errorf("[Summarization] failed to generate summary: %w", err)
+	}
+	if out == nil || strings.TrimSpace(out.Content) == "" {
+		return "", errors.New("[Summarization] model returned empty summary")
+	}
+
+	return strings.TrimSpace(out.Content), nil
+}
+
+// writeTranscript 将消息渲染为摘要模型可读的文本。
+func writeTranscript(sb *strings.Builder, m *schema.Message) {
+	switch m.Role {
+	case schema.Tool:
+		name := m.ToolName
+		if name == "" {
+			name = m.ToolCallID
+		}
+		fmt.Fprintf(sb, "[tool result: %s]: %s\n", name, m.Content)
+		return
+	case schema.Assistant:
+		if m.Content != "" {
+			fmt.Fprintf(sb, "[assistant]: %s\n", m.Content)
+		}
+		for _, tc := range m.ToolCalls {
+			fmt.Fprintf(sb, "[assistant tool call: %s]: %s\n", tc.Function.Name, tc.Function.Arguments)
+		}
+		return
+	}
+
+	content := m.Content
+	for _, part := range m.UserInputMultiContent {
+		if part.Type == schema.ChatMessagePartTypeText {
+			content += part.Text
+		} else {
+			content += fmt.Sprintf("<%s omitted>", part.Type)
+		}
+	}
+	fmt.Fprintf(sb, "[%s]: %s\n", m.Role, content)
+}
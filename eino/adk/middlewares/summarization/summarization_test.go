@@ -0,0 +1,265 @@
+package summarization
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/favbox/eino/adk"
+	"github.com/favbox/eino/components/model"
+	"github.com/favbox/eino/components/tool"
+	"github.com/favbox/eino/components/tool/utils"
+	"github.com/favbox/eino/compose"
+	mockModel "github.com/favbox/eino/internal/mock/components/model"
+	"github.com/favbox/eino/schema"
+)
+
+// oneTokenizer 每条消息固定计为 1 个 token
+type oneTokenizer struct{}
+
+func (oneTokenizer) CountTokens(_ context.Context, _ *schema.Message) (int, error) {
+	return 1, nil
+}
+
+func TestNew(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	_, err := New(ctx, nil)
+	assert.Error(t, err)
+	_, err = New(ctx, &Config{})
+	assert.Error(t, err)
+	_, err = New(ctx, &Config{Model: mockModel.NewMockBaseChatModel(ctrl), MaxTokensBeforeSummary: 10, MaxTokensForRecent: 10})
+	assert.Error(t, err)
+
+	mw, err := New(ctx, &Config{Model: mockModel.NewMockBaseChatModel(ctrl)})
+	assert.NoError(t, err)
+	assert.NotNil(t, mw.BeforeChatModel)
+}
+
+func TestBeforeChatModel(t *testing.T) {
+	ctx := context.Background()
+
+	sys := schema.SystemMessage("sys")
+	u1 := schema.UserMessage("u1")
+	call := schema.AssistantMessage("", []schema.ToolCall{{ID: "c1", Function: schema.FunctionCall{Name: "search", Arguments: `{"q":"x"}`}}})
+	r1 := schema.ToolMessage("r1", "c1", schema.WithToolName("search"))
+	a1 := schema.AssistantMessage("a1", nil)
+	u2 := schema.UserMessage("u2")
+
+	t.Run("未超过阈值时不摘要", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		cm := mockModel.NewMockBaseChatModel(ctrl)
+
+		mw, err := New(ctx, &Config{Model: cm, Tokenizer: oneTokenizer{}, MaxTokensBeforeSummary: 6, MaxTokensForRecent: 2})
+		assert.NoError(t, err)
+
+		state := &adk.ChatModelAgentState{Messages: []adk.Message{sys, u1, call, r1, a1, u2}}
+		assert.NoError(t, mw.BeforeChatModel(ctx, state))
+		assert.Len(t, state.Messages, 6)
+	})
+
+	t.Run("滚动摘要且不拆分工具调用", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		cm := mockModel.NewMockBaseChatModel(ctrl)
+
+		var inputs [][]*schema.Message
+		cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, in []*schema.Message, _ ...model.Option) (*schema.Message, error) {
+				inputs = append(inputs, in)
+				return schema.AssistantMessage("summary", nil), nil
+			}).Times(2)
+
+		var infos []*SummaryInfo
+		mw, err := New(ctx, &Config{
+			Model:                  cm,
+			Tokenizer:              oneTokenizer{},
+			MaxTokensBeforeSummary: 4,
+			MaxTokensForRecent:     3,
+			Callback: func(_ context.Context, info *SummaryInfo) {
+				infos = append(infos, info)
+			},
+		})
+		assert.NoError(t, err)
+
+		state := &adk.ChatModelAgentState{Messages: []adk.Message{sys, u1, call, r1, a1, u2}}
+		assert.NoError(t, mw.BeforeChatModel(ctx, state))
+
+		// 最近消息预算为 3，按轮次丢弃后只保留 u2，工具调用与结果整体被摘要
+		assert.Len(t, state.Messages, 3)
+		assert.Equal(t, sys, state.Messages[0])
+		assert.True(t, IsSummaryMessage(state.Messages[1]))
+		assert.Equal(t, []adk.Message{u2}, state.Messages[2:])
+
+		assert.Len(t, infos, 1)
+		assert.Equal(t, []*schema.Message{u1, call, r1, a1}, infos[0].Summarized)
+		assert.Equal(t, "", infos[0].PreviousSummary)
+		assert.Equal(t, 6, infos[0].TokensBefore)
+		assert.Equal(t, 3, infos[0].TokensAfter)
+		assert.Contains(t, inputs[0][1].Content, "[assistant tool call: search]: {\"q\":\"x\"}")
+		assert.Contains(t, inputs[0][1].Content, "[tool result: search]: r1")
+
+		// 再次超过阈值时合并已有摘要
+		a2 := schema.AssistantMessage("a2", nil)
+		u3 := schema.UserMessage("u3")
+		a3 := schema.AssistantMessage("a3", nil)
+		state.Messages = append(state.Messages, a2, u3, a3)
+		assert.NoError(t, mw.BeforeChatModel(ctx, state))
+
+		assert.Len(t, infos, 2)
+		assert.Equal(t, "summary", infos[1].PreviousSummary)
+		assert.Equal(t, []*schema.Message{u2, a2}, infos[1].Summarized)
+		assert.Contains(t, inputs[1][1].Content, "## Previous summary\nsummary")
+		assert.Len(t, state.Messages, 4)
+		assert.True(t, IsSummaryMessage(state.Messages[1]))
+		assert.Equal(t, []adk.Message{u3, a3}, state.Messages[2:])
+	})
+
+	t.Run("摘要模型出错", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		cm := mockModel.NewMockBaseChatModel(ctrl)
+		cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("boom")).Times(1)
+
+		mw, err := New(ctx, &Config{Model: cm, Tokenizer: oneTokenizer{}, MaxTokensBeforeSummary: 2, MaxTokensForRecent: 1})
+		assert.NoError(t, err)
+
+		err = mw.BeforeChatModel(ctx, &adk.ChatModelAgentState{Messages: []adk.Message{sys, u1, a1, u2}})
+		assert.ErrorContains(t, err, "boom")
+	})
+}
+
+func TestSummarizationWithAgent(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	summaryModel := mockModel.NewMockBaseChatModel(ctrl)
+	summaryModel.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(schema.AssistantMessage("earlier summary", nil), nil).Times(1)
+
+	var modelInput []*schema.Message
+	cm := mockModel.NewMockToolCallingChatModel(ctrl)
+	cm.EXPECT().WithTools(gomock.Any()).Return(cm, nil).AnyTimes()
+	cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, in []*schema.Message, _ ...model.Option) (*schema.Message, error) {
+			modelInput = in
+			return schema.AssistantMessage("done", nil), nil
+		}).Times(1)
+
+	mw, err := New(ctx, &Config{Model: summaryModel, Tokenizer: oneTokenizer{}, MaxTokensBeforeSummary: 3, MaxTokensForRecent: 1})
+	assert.NoError(t, err)
+
+	// 中间件只在 ReAct 循环中生效，需要至少配置一个工具
+	echo, err := utils.InferTool("echo", "echo input", func(_ context.Context, in string) (string, error) {
+		return in, nil
+	})
+	assert.NoError(t, err)
+
+	var sessionSummary any
+	agent, err := adk.NewChatModelAgent(ctx, &adk.ChatModelAgentConfig{
+		Name:        "assistant",
+		Description: "assistant",
+		Instruction: "你是一个有用的助手。",
+		Model:       cm,
+		ToolsConfig: adk.ToolsConfig{ToolsNodeConfig: compose.ToolsNodeConfig{Tools: []tool.BaseTool{echo}}},
+		Middlewares: []adk.AgentMiddleware{mw, {
+			BeforeChatModel: func(ctx context.Context, _ *adk.ChatModelAgentState) error {
+				sessionSummary, _ = adk.GetSessionValue(ctx, DefaultSessionKey)
+				return nil
+			},
+		}},
+	})
+	assert.NoError(t, err)
+
+	runner := adk.NewRunner(ctx, adk.RunnerConfig{Agent: agent})
+	iter := runner.Run(ctx, []adk.Message{
+		schema.UserMessage("u1"),
+		schema.AssistantMessage("a1", nil),
+		schema.UserMessage("u2"),
+	})
+	for {
+		event, ok := iter.Next()
+		if !ok {
+			break
+		}
+		assert.NoError(t, event.Err)
+	}
+
+	assert.Equal(t, "earlier summary", sessionSummary)
+	assert.Len(t, modelInput, 3)
+	assert.Contains(t, modelInput[0].Content, "你是一个有用的助手。")
+	assert.True(t, IsSummaryMessage(modelInput[1]))
+	assert.Equal(t, "u2", modelInput[2].Content)
+}
+
+func TestSummaryFromSessionValue(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	var summaryInput []*schema.Message
+	summaryModel := mockModel.NewMockBaseChatModel(ctrl)
+	summaryModel.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, in []*schema.Message, _ ...model.Option) (*schema.Message, error) {
+			summaryInput = in
+			return schema.AssistantMessage("merged summary", nil), nil
+		}).Times(1)
+
+	cm := mockModel.NewMockToolCallingChatModel(ctrl)
+	cm.EXPECT().WithTools(gomock.Any()).Return(cm, nil).AnyTimes()
+	cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(schema.AssistantMessage("done", nil), nil).Times(1)
+
+	var infos []*SummaryInfo
+	mw, err := New(ctx, &Config{
+		Model:                  summaryModel,
+		Tokenizer:              oneTokenizer{},
+		MaxTokensBeforeSummary: 3,
+		MaxTokensForRecent:     1,
+		Callback: func(_ context.Context, info *SummaryInfo) {
+			infos = append(infos, info)
+		},
+	})
+	assert.NoError(t, err)
+
+	echo, err := utils.InferTool("echo", "echo input", func(_ context.Context, in string) (string, error) {
+		return in, nil
+	})
+	assert.NoError(t, err)
+
+	var sessionSummary any
+	agent, err := adk.NewChatModelAgent(ctx, &adk.ChatModelAgentConfig{
+		Name:        "assistant",
+		Description: "assistant",
+		Model:       cm,
+		ToolsConfig: adk.ToolsConfig{ToolsNodeConfig: compose.ToolsNodeConfig{Tools: []tool.BaseTool{echo}}},
+		Middlewares: []adk.AgentMiddleware{mw, {
+			BeforeChatModel: func(ctx context.Context, _ *adk.ChatModelAgentState) error {
+				sessionSummary, _ = adk.GetSessionValue(ctx, DefaultSessionKey)
+				return nil
+			},
+		}},
+	})
+	assert.NoError(t, err)
+
+	// 恢复运行时历史中已没有摘要消息，只有上一轮持久化的会话变量
+	runner := adk.NewRunner(ctx, adk.RunnerConfig{Agent: agent})
+	iter := runner.Run(ctx, []adk.Message{
+		schema.UserMessage("u2"),
+		schema.AssistantMessage("a2", nil),
+		schema.UserMessage("u3"),
+	}, adk.WithSessionValues(map[string]any{DefaultSessionKey: "earlier summary"}))
+	for {
+		event, ok := iter.Next()
+		if !ok {
+			break
+		}
+		assert.NoError(t, event.Err)
+	}
+
+	assert.Len(t, infos, 1)
+	assert.Equal(t, "earlier summary", infos[0].PreviousSummary)
+	assert.Contains(t, summaryInput[1].Content, "## Previous summary\nearlier summary")
+	assert.Equal(t, "merged summary", sessionSummary)
+}
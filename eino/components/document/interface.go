@@ -44,7 +44,7 @@ type Loader interface {
 	//   - Markdown 文件
 	//   - 网页内容
 	//   - 数据库记录等
-	Load(ctx context.Context, src Source, opts ...LoaderOptions) ([]*schema.Document, error)
+	Load(ctx context.Context, src Source, opts ...LoaderOption) ([]*schema.Document, error)
 }
 
 // Transformer 接口定义了文档转换器的核心能力。
@@ -0,0 +1,39 @@
+package file
+
+import (
+	"context"
+	"sync"
+)
+
+// HashStore 保存文件内容哈希，用于增量加载。
+type HashStore interface {
+	// Get 返回文件上次成功加载时的内容哈希，ok 为 false 表示没有记录。
+	Get(ctx context.Context, path string) (hash string, ok bool, err error)
+	// Set 记录文件成功加载时的内容哈希。
+	Set(ctx context.Context, path, hash string) error
+}
+
+// MemoryHashStore 基于内存的 HashStore 实现，可并发使用。
+type MemoryHashStore struct {
+	mu     sync.RWMutex
+	hashes map[string]string
+}
+
+// NewMemoryHashStore 创建内存哈希存储。
+func NewMemoryHashStore() *MemoryHashStore {
+	return &MemoryHashStore{hashes: make(map[string]string)}
+}
+
+func (s *MemoryHashStore) Get(_ context.Context, path string) (string, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	hash, ok := s.hashes[path]
+	return hash, ok, nil
+}
+
+func (s *MemoryHashStore) Set(_ context.Context, path, hash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hashes[path] = hash
+	return nil
+}
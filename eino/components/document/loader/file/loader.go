@@ -0,0 +1,349 @@
+// Package file 提供从本地文件系统加载文档的 document.Loader。
+//
+// Source.URI 可以是单个文件、目录或 glob 模式（按完整路径匹配，"**" 匹配任意层目录），也可以带 "file://" 前缀：
+//
+//	loader, _ := file.NewLoader(ctx, &file.Config{
+//		Include: []string{"*.md", "docs/**/*.txt"},
+//		Exclude: []string{"**/drafts/**"},
+//	})
+//	docs, _ := loader.Load(ctx, document.Source{URI: "./knowledge"})
+//
+// 每个文件经 parser.Parser 解析（默认 parser.ExtParser），文档元数据中附带文件路径、大小与修改时间。
+// 单个文件失败不会中断整个加载，失败的文件记录在回调输出的 Extra 中，仅当全部文件失败时才返回错误。
+// 配置 HashStore 后可按内容哈希增量加载，跳过内容未变化的文件。
+package file
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/favbox/eino/callbacks"
+	"github.com/favbox/eino/components"
+	"github.com/favbox/eino/components/document"
+	"github.com/favbox/eino/components/document/parser"
+	"github.com/favbox/eino/schema"
+)
+
+const (
+	// MetaKeyFilePath 文档元数据中文件路径的键，值为 string。
+	MetaKeyFilePath = "_file_path"
+	// MetaKeyFileSize 文档元数据中文件大小（字节）的键，值为 int64。
+	MetaKeyFileSize = "_file_size"
+	// MetaKeyFileModTime 文档元数据中文件修改时间的键，值为 time.Time。
+	MetaKeyFileModTime = "_file_mod_time"
+	// MetaKeyContentHash 文档元数据中文件内容 SHA-256 的键，仅在配置 HashStore 时写入，值为十六进制 string。
+	MetaKeyContentHash = "_content_hash"
+
+	// ExtraKeyFailedFiles 回调输出 Extra 中加载失败的文件的键，值为 map[string]string（路径到错误信息）。
+	ExtraKeyFailedFiles = "file_loader_failed_files"
+	// ExtraKeySkippedFiles 回调输出 Extra 中因内容未变化而跳过的文件的键，值为 []string。
+	ExtraKeySkippedFiles = "file_loader_skipped_files"
+)
+
+// Config 文件加载器配置。
+type Config struct {
+	// Parser 文件解析器，默认使用无自定义配置的 parser.ExtParser。
+	// 解析时会传入 parser.WithURI(文件路径) 以及 document.WithParserOptions 指定的选项。
+	Parser parser.Parser
+	// Include 需要加载的文件模式，默认加载全部文件。
+	// 不含 "/" 的模式匹配文件名，否则匹配相对于加载目录的路径，"**" 匹配任意层目录。
+	Include []string
+	// Exclude 需要排除的文件或目录模式，规则同 Include，优先于 Include。
+	Exclude []string
+	// NonRecursive 为 true 时只加载目录的第一层文件。
+	NonRecursive bool
+	// HashStore 用于增量加载的内容哈希存储，内容哈希与上次成功加载时相同的文件会被跳过。
+	HashStore HashStore
+}
+
+// NewLoader 创建文件加载器。
+func NewLoader(ctx context.Context, config *Config) (document.Loader, error) {
+	if config == nil {
+		config = &Config{}
+	}
+
+	l := &fileLoader{
+		parser:       config.Parser,
+		nonRecursive: config.NonRecursive,
+		hashStore:    config.HashStore,
+	}
+	if l.parser == nil {
+		p, err := parser.NewExtParser(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		l.parser = p
+	}
+	for _, pattern := range config.Include {
+		m, err := compilePattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		l.include = append(l.include, m)
+	}
+	for _, pattern := range config.Exclude {
+		m, err := compilePattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		l.exclude = append(l.exclude, m)
+	}
+
+	return l, nil
+}
+
+type fileLoader struct {
+	parser       parser.Parser
+	include      []*pattern
+	exclude      []*pattern
+	nonRecursive bool
+	hashStore    HashStore
+}
+
+func (l *fileLoader) Load(ctx context.Context, src document.Source, opts ...document.LoaderOption) (docs []*schema.Document, err error) {
+	ctx = callbacks.EnsureRunInfo(ctx, l.GetType(), components.ComponentOfLoader)
+	ctx = callbacks.OnStart(ctx, &document.LoaderCallbackInput{Source: src})
+	defer func() {
+		if err != nil {
+			_ = callbacks.OnError(ctx, err)
+		}
+	}()
+
+	files, err := l.listFiles(src.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	parserOpts := document.GetLoaderCommonOptions(&document.LoaderOptions{}, opts...).ParserOptions
+	failed := make(map[string]string)
+	var (
+		skipped []string
+		errs    []error
+	)
+	for _, path := range files {
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		fileDocs, unchanged, loadErr := l.loadFile(ctx, path, parserOpts)
+		switch {
+		case loadErr != nil:
+			failed[path] = loadErr.Error()
+			errs = append(errs, fmt.Errorf("[%s] %w", path, loadErr))
+		case unchanged:
+			skipped = append(skipped, path)
+		default:
+			docs = append(docs, fileDocs...)
+		}
+	}
+	if len(errs) > 0 && len(errs) == len(files) {
+		return nil, fmt.Errorf("all files failed to load: %w", errors.Join(errs...))
+	}
+
+	output := &document.LoaderCallbackOutput{Source: src, Docs: docs}
+	if len(failed) > 0 || len(skipped) > 0 {
+		output.Extra = make(map[string]any, 2)
+		if len(failed) > 0 {
+			output.Extra[ExtraKeyFailedFiles] = failed
+		}
+		if len(skipped) > 0 {
+			output.Extra[ExtraKeySkippedFiles] = skipped
+		}
+	}
+	_ = callbacks.OnEnd(ctx, output)
+
+	return docs, nil
+}
+
+// loadFile 解析单个文件，unchanged 表示内容与上次加载时相同而被跳过。
+func (l *fileLoader) loadFile(ctx context.Context, path string, parserOpts []parser.Option) (docs []*schema.Document, unchanged bool, err error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var hash string
+	if l.hashStore != nil {
+		if hash, err = fileHash(path); err != nil {
+			return nil, false, err
+		}
+		prev, ok, err := l.hashStore.Get(ctx, path)
+		if err != nil {
+			return nil, false, fmt.Errorf("get content hash failed: %w", err)
+		}
+		if ok && prev == hash {
+			return nil, true, nil
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, false, err
+	}
+	defer f.Close()
+
+	popts := make([]parser.Option, 0, len(parserOpts)+1)
+	popts = append(popts, parser.WithURI(path))
+	popts = append(popts, parserOpts...)
+	docs, err = l.parser.Parse(ctx, f, popts...)
+	if err != nil {
+		return nil, false, fmt.Errorf("parse failed: %w", err)
+	}
+
+	for i, doc := range docs {
+		if doc == nil {
+			continue
+		}
+		if doc.ID == "" {
+			doc.ID = path
+			if len(docs) > 1 {
+				doc.ID = fmt.Sprintf("%s#%d", path, i)
+			}
+		}
+		if doc.MetaData == nil {
+			doc.MetaData = make(map[string]any, 4)
+		}
+		doc.MetaData[MetaKeyFilePath] = path
+		doc.MetaData[MetaKeyFileSize] = info.Size()
+		doc.MetaData[MetaKeyFileModTime] = info.ModTime()
+		if hash != "" {
+			doc.MetaData[MetaKeyContentHash] = hash
+		}
+	}
+
+	if l.hashStore != nil {
+		if err = l.hashStore.Set(ctx, path, hash); err != nil {
+			return nil, false, fmt.Errorf("set content hash failed: %w", err)
+		}
+	}
+
+	return docs, false, nil
+}
+
+// listFiles 根据 URI 列出待加载的文件，按路径排序。
+func (l *fileLoader) listFiles(uri string) ([]string, error) {
+	path := strings.TrimPrefix(uri, "file://")
+	if path == "" {
+		return nil, errors.New("source uri is required")
+	}
+
+	if hasMeta(path) {
+		root, glob := splitGlob(path)
+		m, err := compileGlob(glob)
+		if err != nil {
+			return nil, err
+		}
+		files, err := l.walk(root, m)
+		if err != nil {
+			return nil, err
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no files match %q", uri)
+		}
+		return files, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{filepath.Clean(path)}, nil
+	}
+
+	return l.walk(path, nil)
+}
+
+// walk 遍历目录，返回通过 glob（可为空）、Include 与 Exclude 过滤的文件。
+func (l *fileLoader) walk(root string, glob *pattern) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == "." {
+			return nil
+		}
+
+		if d.IsDir() {
+			if l.nonRecursive && glob == nil || l.excluded(rel, true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if glob != nil && !glob.match(rel) {
+			return nil
+		}
+		if l.excluded(rel, false) || !l.included(rel) {
+			return nil
+		}
+		files = append(files, path)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	return files, nil
+}
+
+func (l *fileLoader) included(rel string) bool {
+	if len(l.include) == 0 {
+		return true
+	}
+	for _, m := range l.include {
+		if m.match(rel) {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *fileLoader) excluded(rel string, isDir bool) bool {
+	for _, m := range l.exclude {
+		if m.match(rel) || isDir && m.matchDir(rel) {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *fileLoader) GetType() string {
+	return "FileLoader"
+}
+
+func (l *fileLoader) IsCallbacksEnabled() bool {
+	return true
+}
+
+func fileHash(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
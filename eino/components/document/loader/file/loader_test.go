@@ -0,0 +1,190 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/favbox/eino/callbacks"
+	"github.com/favbox/eino/components/document"
+	"github.com/favbox/eino/components/document/parser"
+	"github.com/favbox/eino/compose"
+	"github.com/favbox/eino/schema"
+)
+
+// writeFiles 在临时目录下创建文件，返回目录路径。
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	return dir
+}
+
+func relPaths(t *testing.T, dir string, docs []*schema.Document) []string {
+	t.Helper()
+	out := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		rel, err := filepath.Rel(dir, doc.MetaData[MetaKeyFilePath].(string))
+		assert.NoError(t, err)
+		out = append(out, filepath.ToSlash(rel))
+	}
+	sort.Strings(out)
+	return out
+}
+
+// failingParser 对指定文件名解析失败，其余按文本解析。
+type failingParser struct {
+	name string
+}
+
+func (p failingParser) Parse(ctx context.Context, reader io.Reader, opts ...parser.Option) ([]*schema.Document, error) {
+	if filepath.Base(parser.GetCommonOptions(&parser.Options{}, opts...).URI) == p.name {
+		return nil, errors.New("broken file")
+	}
+	return parser.TextParser{}.Parse(ctx, reader, opts...)
+}
+
+func TestLoader(t *testing.T) {
+	ctx := context.Background()
+	dir := writeFiles(t, map[string]string{
+		"a.md":              "# A",
+		"b.txt":             "B",
+		"docs/c.md":         "C",
+		"docs/drafts/d.md":  "D",
+		"docs/deep/e/f.txt": "F",
+		"node_modules/x.md": "X",
+	})
+
+	t.Run("加载单个文件", func(t *testing.T) {
+		l, err := NewLoader(ctx, nil)
+		assert.NoError(t, err)
+
+		path := filepath.Join(dir, "a.md")
+		docs, err := l.Load(ctx, document.Source{URI: "file://" + path}, document.WithParserOptions(parser.WithExtraMeta(map[string]any{"k": "v"})))
+		assert.NoError(t, err)
+		assert.Len(t, docs, 1)
+		assert.Equal(t, "# A", docs[0].Content)
+		assert.Equal(t, path, docs[0].ID)
+		assert.Equal(t, path, docs[0].MetaData[parser.MetaKeySource])
+		assert.Equal(t, int64(3), docs[0].MetaData[MetaKeyFileSize])
+		assert.NotZero(t, docs[0].MetaData[MetaKeyFileModTime])
+		assert.Equal(t, "v", docs[0].MetaData["k"])
+	})
+
+	t.Run("递归加载目录并过滤", func(t *testing.T) {
+		l, err := NewLoader(ctx, &Config{
+			Include: []string{"*.md", "docs/**/*.txt"},
+			Exclude: []string{"**/drafts/**", "node_modules"},
+		})
+		assert.NoError(t, err)
+
+		docs, err := l.Load(ctx, document.Source{URI: dir})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a.md", "docs/c.md", "docs/deep/e/f.txt"}, relPaths(t, dir, docs))
+	})
+
+	t.Run("只加载第一层", func(t *testing.T) {
+		l, err := NewLoader(ctx, &Config{NonRecursive: true})
+		assert.NoError(t, err)
+
+		docs, err := l.Load(ctx, document.Source{URI: dir})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a.md", "b.txt"}, relPaths(t, dir, docs))
+	})
+
+	t.Run("glob 模式", func(t *testing.T) {
+		l, err := NewLoader(ctx, nil)
+		assert.NoError(t, err)
+
+		docs, err := l.Load(ctx, document.Source{URI: filepath.Join(dir, "docs", "**", "*.md")})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"docs/c.md", "docs/drafts/d.md"}, relPaths(t, dir, docs))
+
+		// 不含 "**" 的 glob 只匹配对应层级的文件
+		docs, err = l.Load(ctx, document.Source{URI: filepath.Join(dir, "docs", "*.md")})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"docs/c.md"}, relPaths(t, dir, docs))
+
+		_, err = l.Load(ctx, document.Source{URI: filepath.Join(dir, "*.pdf")})
+		assert.ErrorContains(t, err, "no files match")
+	})
+
+	t.Run("单个文件失败不中断加载", func(t *testing.T) {
+		l, err := NewLoader(ctx, &Config{Parser: failingParser{name: "b.txt"}, NonRecursive: true})
+		assert.NoError(t, err)
+
+		var extra map[string]any
+		handler := callbacks.NewHandlerBuilder().OnEndFn(func(ctx context.Context, _ *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+			extra = document.ConvLoaderCallbackOutput(output).Extra
+			return ctx
+		}).Build()
+
+		docs, err := l.Load(callbacks.InitCallbacks(ctx, nil, handler), document.Source{URI: dir})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a.md"}, relPaths(t, dir, docs))
+		assert.Equal(t, map[string]string{filepath.Join(dir, "b.txt"): "parse failed: broken file"}, extra[ExtraKeyFailedFiles])
+
+		_, err = l.Load(ctx, document.Source{URI: filepath.Join(dir, "b.txt")})
+		assert.ErrorContains(t, err, "all files failed to load")
+	})
+
+	t.Run("按内容哈希增量加载", func(t *testing.T) {
+		dir := writeFiles(t, map[string]string{"a.txt": "A", "b.txt": "B"})
+		store := NewMemoryHashStore()
+		l, err := NewLoader(ctx, &Config{HashStore: store})
+		assert.NoError(t, err)
+
+		docs, err := l.Load(ctx, document.Source{URI: dir})
+		assert.NoError(t, err)
+		assert.Len(t, docs, 2)
+		assert.Len(t, docs[0].MetaData[MetaKeyContentHash], 64)
+
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "b.txt"), []byte("B2"), 0o644))
+		var skipped []string
+		handler := callbacks.NewHandlerBuilder().OnEndFn(func(ctx context.Context, _ *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+			skipped, _ = document.ConvLoaderCallbackOutput(output).Extra[ExtraKeySkippedFiles].([]string)
+			return ctx
+		}).Build()
+
+		docs, err = l.Load(callbacks.InitCallbacks(ctx, nil, handler), document.Source{URI: dir})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"b.txt"}, relPaths(t, dir, docs))
+		assert.Equal(t, "B2", docs[0].Content)
+		assert.Equal(t, []string{filepath.Join(dir, "a.txt")}, skipped)
+	})
+
+	t.Run("作为链中的加载器节点", func(t *testing.T) {
+		l, err := NewLoader(ctx, nil)
+		assert.NoError(t, err)
+
+		chain := compose.NewChain[document.Source, []*schema.Document]()
+		chain.AppendLoader(l)
+		r, err := chain.Compile(ctx)
+		assert.NoError(t, err)
+
+		docs, err := r.Invoke(ctx, document.Source{URI: filepath.Join(dir, "b.txt")},
+			compose.WithLoaderOption(document.WithParserOptions(parser.WithExtraMeta(map[string]any{"from": "chain"}))))
+		assert.NoError(t, err)
+		assert.Equal(t, "chain", docs[0].MetaData["from"])
+	})
+
+	t.Run("非法模式与路径", func(t *testing.T) {
+		_, err := NewLoader(ctx, &Config{Include: []string{"[a"}})
+		assert.Error(t, err)
+
+		l, err := NewLoader(ctx, nil)
+		assert.NoError(t, err)
+		_, err = l.Load(ctx, document.Source{URI: filepath.Join(dir, "missing")})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,123 @@
+package file
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// pattern 文件匹配模式，语法同 path.Match，另外 "**" 匹配任意层目录。
+type pattern struct {
+	// baseOnly 为 true 时只匹配文件名。
+	baseOnly bool
+	re       *regexp.Regexp
+}
+
+// compilePattern 编译 Include/Exclude 过滤模式，模式不含 "/" 时只匹配文件名。
+func compilePattern(p string) (*pattern, error) {
+	p = strings.TrimPrefix(filepath.ToSlash(p), "./")
+	re, err := compileRegexp(p)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pattern{baseOnly: !strings.Contains(p, "/"), re: re}, nil
+}
+
+// compileGlob 编译 URI 中的 glob，始终匹配相对于根目录的完整路径，如 "*.md" 不匹配子目录中的文件。
+func compileGlob(p string) (*pattern, error) {
+	re, err := compileRegexp(strings.TrimPrefix(filepath.ToSlash(p), "./"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &pattern{re: re}, nil
+}
+
+// compileRegexp 将以 "/" 分隔的模式转换为正则表达式。
+func compileRegexp(p string) (*regexp.Regexp, error) {
+	if _, err := path.Match(strings.ReplaceAll(p, "**", "*"), ""); err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", p, err)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(p); i++ {
+		switch c := p[i]; c {
+		case '*':
+			if i+1 < len(p) && p[i+1] == '*' {
+				i++
+				if i+1 < len(p) && p[i+1] == '/' {
+					i++
+					sb.WriteString("(?:.*/)?") // "**/" 匹配零或多层目录
+				} else {
+					sb.WriteString(".*")
+				}
+				continue
+			}
+			sb.WriteString("[^/]*")
+		case '?':
+			sb.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(p[i:], ']')
+			class := p[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + class + "]")
+			i += end
+		case '\\':
+			if i+1 < len(p) {
+				i++
+				sb.WriteString(regexp.QuoteMeta(string(p[i])))
+			}
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+
+	re, err := regexp.Compile(sb.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", p, err)
+	}
+
+	return re, nil
+}
+
+// match 判断以 "/" 分隔的相对路径是否匹配。
+func (p *pattern) match(rel string) bool {
+	if p.baseOnly {
+		return p.re.MatchString(path.Base(rel))
+	}
+	return p.re.MatchString(rel)
+}
+
+// matchDir 判断目录是否匹配，以 "/**" 结尾的模式同时匹配目录本身。
+func (p *pattern) matchDir(rel string) bool {
+	return p.match(rel + "/")
+}
+
+// hasMeta 判断路径是否包含 glob 元字符。
+func hasMeta(p string) bool {
+	return strings.ContainsAny(p, "*?[")
+}
+
+// splitGlob 将 glob 路径拆分为不含元字符的根目录与相对于根目录的模式。
+func splitGlob(p string) (root, glob string) {
+	parts := strings.Split(filepath.ToSlash(p), "/")
+	i := 0
+	for i < len(parts) && !hasMeta(parts[i]) {
+		i++
+	}
+	root = strings.Join(parts[:i], "/")
+	switch {
+	case root == "" && strings.HasPrefix(p, "/"):
+		root = "/"
+	case root == "":
+		root = "."
+	}
+	return filepath.FromSlash(root), strings.Join(parts[i:], "/")
+}
@@ -0,0 +1,307 @@
+package parser
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/favbox/eino/schema"
+)
+
+// MetaKeyTitle 是 HTML 文档标题的元数据键。
+const MetaKeyTitle = "_title"
+
+// HTMLParser 是 HTML 解析器。
+//
+// 参考 Readability 的思路提取网页正文：去除脚本、导航、页眉页脚等非正文元素，
+// 优先使用 <article>、<main> 元素，否则按段落文本量与链接密度为容器打分，选出得分最高的容器。
+// 正文按块级元素换行输出纯文本，<title> 写入元数据的 MetaKeyTitle。
+type HTMLParser struct {
+	// KeepFullText 为 true 时不做正文提取，输出 <body> 中的全部可见文本。
+	KeepFullText bool
+}
+
+var (
+	// htmlRawTextBlocks 内容不是 HTML 的元素，解析前整体移除，避免脚本中的 "<" 干扰解析。
+	htmlRawTextBlocks = []*regexp.Regexp{
+		regexp.MustCompile(`(?is)<script\b.*?</script\s*>`),
+		regexp.MustCompile(`(?is)<style\b.*?</style\s*>`),
+		regexp.MustCompile(`(?is)<noscript\b.*?</noscript\s*>`),
+		regexp.MustCompile(`(?is)<template\b.*?</template\s*>`),
+		regexp.MustCompile(`(?is)<svg\b.*?</svg\s*>`),
+		regexp.MustCompile(`(?s)<!--.*?-->`),
+	}
+
+	// htmlSkipTags 不属于正文的元素。
+	htmlSkipTags = map[string]bool{
+		"head": true, "nav": true, "header": true, "footer": true, "aside": true, "form": true,
+		"iframe": true, "button": true, "select": true, "object": true, "canvas": true,
+	}
+
+	// htmlBlockTags 输出文本时独占一行的元素。
+	htmlBlockTags = map[string]bool{
+		"p": true, "div": true, "section": true, "article": true, "main": true, "br": true, "hr": true,
+		"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "li": true, "ul": true, "ol": true,
+		"pre": true, "blockquote": true, "table": true, "tr": true, "dt": true, "dd": true, "figcaption": true,
+	}
+
+	htmlPositiveHint = regexp.MustCompile(`(?i)article|content|main|post|entry|text|body|story`)
+	htmlNegativeHint = regexp.MustCompile(`(?i)comment|footer|sidebar|nav|menu|share|social|related|advert|\bads?\b|banner|popup|breadcrumb`)
+	htmlWhitespace   = regexp.MustCompile(`[\s\x{00a0}]+`)
+)
+
+// htmlNode 简化的 DOM 节点，tag 为空时表示文本节点。
+type htmlNode struct {
+	tag      string
+	hint     string
+	text     string
+	parent   *htmlNode
+	children []*htmlNode
+}
+
+// Parse 解析 HTML 内容并返回正文文档。
+func (p HTMLParser) Parse(ctx context.Context, reader io.Reader, opts ...Option) ([]*schema.Document, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := parseHTMLTree(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	meta := newMeta(opts...)
+	if title := findHTMLNode(root, "title"); title != nil {
+		if t := strings.TrimSpace(htmlWhitespace.ReplaceAllString(title.textContent(), " ")); t != "" {
+			meta[MetaKeyTitle] = t
+		}
+	}
+
+	body := findHTMLNode(root, "body")
+	if body == nil {
+		body = root
+	}
+	content := body
+	if !p.KeepFullText {
+		content = mainContent(body)
+	}
+
+	return []*schema.Document{{Content: renderHTMLText(content, !p.KeepFullText), MetaData: meta}}, nil
+}
+
+// parseHTMLTree 以非严格模式的 XML 解码器构建 DOM，尽量容忍不规范的 HTML。
+func parseHTMLTree(src string) (*htmlNode, error) {
+	for _, re := range htmlRawTextBlocks {
+		src = re.ReplaceAllString(src, " ")
+	}
+
+	d := xml.NewDecoder(strings.NewReader(src))
+	d.Strict = false
+	d.AutoClose = xml.HTMLAutoClose
+	d.Entity = xml.HTMLEntity
+	d.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) { return input, nil }
+
+	root := &htmlNode{tag: "#document"}
+	cur := root
+	for {
+		tok, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			if len(root.children) == 0 {
+				return nil, err
+			}
+			break // 保留已解析的部分
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			n := &htmlNode{tag: strings.ToLower(t.Name.Local), parent: cur}
+			for _, attr := range t.Attr {
+				if name := strings.ToLower(attr.Name.Local); name == "class" || name == "id" {
+					n.hint += " " + attr.Value
+				}
+			}
+			cur.children = append(cur.children, n)
+			cur = n
+		case xml.EndElement:
+			tag := strings.ToLower(t.Name.Local)
+			for n := cur; n != root; n = n.parent {
+				if n.tag == tag {
+					cur = n.parent
+					break
+				}
+			}
+		case xml.CharData:
+			cur.children = append(cur.children, &htmlNode{text: string(t), parent: cur})
+		}
+	}
+
+	return root, nil
+}
+
+func findHTMLNode(n *htmlNode, tag string) *htmlNode {
+	if n.tag == tag {
+		return n
+	}
+	for _, c := range n.children {
+		if found := findHTMLNode(c, tag); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// textContent 返回节点内的全部文本，跳过非正文元素。
+func (n *htmlNode) textContent() string {
+	if n.tag == "" {
+		return n.text
+	}
+	var sb strings.Builder
+	for _, c := range n.children {
+		if c.tag != "" && htmlSkipTags[c.tag] {
+			continue
+		}
+		sb.WriteString(c.textContent())
+	}
+	return sb.String()
+}
+
+// linkDensity 返回链接文本占全部文本的比例。
+func (n *htmlNode) linkDensity() float64 {
+	total := utf8.RuneCountInString(strings.TrimSpace(n.textContent()))
+	if total == 0 {
+		return 0
+	}
+	var links int
+	var walk func(*htmlNode)
+	walk = func(x *htmlNode) {
+		for _, c := range x.children {
+			if c.tag == "a" {
+				links += utf8.RuneCountInString(strings.TrimSpace(c.textContent()))
+				continue
+			}
+			walk(c)
+		}
+	}
+	walk(n)
+	return float64(links) / float64(total)
+}
+
+// mainContent 选出正文容器：优先 <article>/<main>，否则按段落为祖先容器打分。
+func mainContent(body *htmlNode) *htmlNode {
+	var (
+		semantic *htmlNode
+		best     int
+		scores   = make(map[*htmlNode]float64)
+		order    []*htmlNode
+	)
+	addScore := func(n *htmlNode, s float64) {
+		if n == nil {
+			return
+		}
+		if _, ok := scores[n]; !ok {
+			order = append(order, n)
+		}
+		scores[n] += s
+	}
+
+	var walk func(*htmlNode)
+	walk = func(n *htmlNode) {
+		for _, c := range n.children {
+			if c.tag == "" || htmlSkipTags[c.tag] {
+				continue
+			}
+			switch c.tag {
+			case "article", "main":
+				if l := utf8.RuneCountInString(strings.TrimSpace(c.textContent())); l > best {
+					semantic, best = c, l
+				}
+			case "p", "pre", "blockquote", "td":
+				text := strings.TrimSpace(c.textContent())
+				if l := utf8.RuneCountInString(text); l >= 25 {
+					s := 1 + float64(strings.Count(text, ",")+strings.Count(text, "，")) + min(float64(l)/100, 3)
+					addScore(c.parent, s)
+					if c.parent != nil {
+						addScore(c.parent.parent, s/2)
+					}
+				}
+			}
+			walk(c)
+		}
+	}
+	walk(body)
+
+	if semantic != nil {
+		return semantic
+	}
+
+	var (
+		candidate *htmlNode
+		top       float64
+	)
+	for _, n := range order {
+		s := scores[n]
+		if htmlPositiveHint.MatchString(n.hint) {
+			s += 25
+		}
+		if htmlNegativeHint.MatchString(n.hint) {
+			s -= 25
+		}
+		s *= 1 - n.linkDensity()
+		if candidate == nil || s > top {
+			candidate, top = n, s
+		}
+	}
+	if candidate == nil || candidate.tag == "#document" {
+		return body
+	}
+
+	return candidate
+}
+
+// renderHTMLText 将节点渲染为纯文本，块级元素独占一行，行内空白折叠为单个空格。
+// pruneNoise 为 true 时跳过带有负面提示（如 comment、share）的子元素。
+func renderHTMLText(root *htmlNode, pruneNoise bool) string {
+	var (
+		lines []string
+		line  strings.Builder
+	)
+	flush := func() {
+		if s := strings.TrimSpace(htmlWhitespace.ReplaceAllString(line.String(), " ")); s != "" {
+			lines = append(lines, s)
+		}
+		line.Reset()
+	}
+
+	var walk func(*htmlNode)
+	walk = func(x *htmlNode) {
+		if x.tag == "" {
+			line.WriteString(x.text)
+			return
+		}
+		if htmlSkipTags[x.tag] || (pruneNoise && x != root && htmlNegativeHint.MatchString(x.hint) && !htmlPositiveHint.MatchString(x.hint)) {
+			return
+		}
+		block := htmlBlockTags[x.tag]
+		if block {
+			flush()
+		}
+		for _, c := range x.children {
+			walk(c)
+		}
+		if block {
+			flush()
+		}
+	}
+	walk(root)
+	flush()
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,97 @@
+package parser
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/favbox/eino/schema"
+)
+
+// MarkdownParser 是 Markdown 解析器。
+//
+// 将文档解析为单个 Document，文件开头以 "---" 包裹的 YAML front matter
+// 会从内容中移除，其顶层字段写入文档元数据（WithExtraMeta 指定的同名字段优先）。
+//
+// 示例：
+//
+//	---
+//	title: 安装指南
+//	tags: [install]
+//	---
+//	# 安装
+//
+// 解析后 MetaData["title"] 为 "安装指南"，Content 以 "# 安装" 开头。
+type MarkdownParser struct{}
+
+// Parse 解析 Markdown 内容并返回文档。
+func (p MarkdownParser) Parse(ctx context.Context, reader io.Reader, opts ...Option) ([]*schema.Document, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	frontMatter, body, err := splitFrontMatter(data)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := make(map[string]any, len(frontMatter)+1)
+	for k, v := range frontMatter {
+		meta[k] = v
+	}
+	for k, v := range newMeta(opts...) {
+		meta[k] = v
+	}
+
+	return []*schema.Document{{Content: string(body), MetaData: meta}}, nil
+}
+
+// splitFrontMatter 拆分 YAML front matter 与正文，没有 front matter 时原样返回内容。
+func splitFrontMatter(data []byte) (map[string]any, []byte, error) {
+	data = bytes.TrimPrefix(data, []byte("\uFEFF"))
+	firstLine, rest, ok := cutLine(data)
+	if !ok || string(bytes.TrimSpace(firstLine)) != "---" {
+		return nil, data, nil
+	}
+
+	for pos := 0; pos < len(rest); {
+		line, next, _ := cutLine(rest[pos:])
+		if trimmed := string(bytes.TrimSpace(line)); trimmed == "---" || trimmed == "..." {
+			frontMatter := make(map[string]any)
+			if err := yaml.Unmarshal(rest[:pos], &frontMatter); err != nil {
+				return nil, nil, fmt.Errorf("parse markdown front matter failed: %w", err)
+			}
+			return frontMatter, bytes.TrimLeft(next, "\r\n"), nil
+		}
+		pos = len(rest) - len(next)
+		if len(next) == 0 {
+			break
+		}
+	}
+
+	// 没有结束标记，不视为 front matter
+	return nil, data, nil
+}
+
+// cutLine 切出第一行（不含换行符），ok 表示是否存在换行符。
+func cutLine(data []byte) (line, rest []byte, ok bool) {
+	line, rest, ok = bytes.Cut(data, []byte("\n"))
+	return bytes.TrimSuffix(line, []byte("\r")), rest, ok
+}
+
+// newMeta 构建解析得到的文档的公共元数据：来源 URI 与额外元数据。
+func newMeta(opts ...Option) map[string]any {
+	opt := GetCommonOptions(&Options{}, opts...)
+
+	meta := make(map[string]any, len(opt.ExtraMeta)+1)
+	meta[MetaKeySource] = opt.URI
+	for k, v := range opt.ExtraMeta {
+		meta[k] = v
+	}
+
+	return meta
+}
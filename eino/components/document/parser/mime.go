@@ -0,0 +1,127 @@
+package parser
+
+import (
+	"archive/zip"
+	"bytes"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bytedance/sonic"
+)
+
+// 常用的 MIME 类型。
+const (
+	MIMEText     = "text/plain"
+	MIMEMarkdown = "text/markdown"
+	MIMEHTML     = "text/html"
+	MIMECSV      = "text/csv"
+	MIMEJSON     = "application/json"
+	MIMEJSONL    = "application/jsonl"
+	MIMEPDF      = "application/pdf"
+	MIMEZip      = "application/zip"
+	MIMEDOCX     = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	MIMEXLSX     = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	MIMEBinary   = "application/octet-stream"
+)
+
+// extMIMETypes 文本类格式没有魔数，按扩展名补充识别。
+var extMIMETypes = map[string]string{
+	".txt":      MIMEText,
+	".md":       MIMEMarkdown,
+	".markdown": MIMEMarkdown,
+	".htm":      MIMEHTML,
+	".html":     MIMEHTML,
+	".csv":      MIMECSV,
+	".json":     MIMEJSON,
+	".jsonl":    MIMEJSONL,
+	".ndjson":   MIMEJSONL,
+	".docx":     MIMEDOCX,
+	".xlsx":     MIMEXLSX,
+	".pdf":      MIMEPDF,
+}
+
+// DetectMIME 根据内容与 URI 推断文档的 MIME 类型，返回值不含参数（如 charset）。
+//
+// 判定顺序：
+//  1. 按魔数识别二进制格式（PDF、ZIP 等），ZIP 会进一步根据条目区分 DOCX 与 XLSX，
+//     因此扩展名错误的上传也能被正确识别
+//  2. 识别 HTML 等具有明显特征的文本格式
+//  3. 内容为通用文本时参考 URI 的扩展名（如 .md、.csv），否则尝试识别 JSON Lines
+func DetectMIME(data []byte, uri string) string {
+	detected := baseMIME(http.DetectContentType(data))
+
+	switch detected {
+	case MIMEZip:
+		return detectOOXML(data)
+	case MIMEText, MIMEBinary:
+	default:
+		return detected
+	}
+
+	if detected == MIMEText || len(data) == 0 || utf8.Valid(data) {
+		ext := strings.ToLower(filepath.Ext(uri))
+		if byExt, ok := extMIMETypes[ext]; ok && !isBinaryMIME(byExt) {
+			return byExt
+		}
+		if byExt := baseMIME(mime.TypeByExtension(ext)); strings.HasPrefix(byExt, "text/") {
+			return byExt
+		}
+		if isJSONLines(data) {
+			return MIMEJSONL
+		}
+		return MIMEText
+	}
+
+	return detected
+}
+
+// detectOOXML 根据 ZIP 中的条目识别 Office Open XML 文档。
+func detectOOXML(data []byte) string {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return MIMEZip
+	}
+	for _, f := range zr.File {
+		switch {
+		case f.Name == "word/document.xml":
+			return MIMEDOCX
+		case f.Name == "xl/workbook.xml":
+			return MIMEXLSX
+		}
+	}
+	return MIMEZip
+}
+
+// isJSONLines 判断内容是否为每行一个 JSON 对象，且至少有两行。
+func isJSONLines(data []byte) bool {
+	lines := 0
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		if line[0] != '{' || !sonic.ConfigStd.Valid(line) {
+			return false
+		}
+		lines++
+	}
+	return lines >= 2
+}
+
+func isBinaryMIME(m string) bool {
+	switch m {
+	case MIMEDOCX, MIMEXLSX, MIMEPDF:
+		return true
+	}
+	return false
+}
+
+func baseMIME(m string) string {
+	if idx := strings.IndexByte(m, ';'); idx >= 0 {
+		m = m[:idx]
+	}
+	return strings.TrimSpace(strings.ToLower(m))
+}
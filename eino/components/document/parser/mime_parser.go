@@ -0,0 +1,161 @@
+package parser
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/favbox/eino/schema"
+)
+
+// MetaKeyMIMEType 是文档 MIME 类型的元数据键。
+const MetaKeyMIMEType = "_mime_type"
+
+// MIMEParserConfig 定义了 MIME 解析器的配置。
+type MIMEParserConfig struct {
+	// Parsers 是 MIME 类型到解析器的映射，键不含参数，如 "text/markdown"。
+	//
+	// 会覆盖同名的内置解析器，内置解析器见 DefaultMIMEParsers。
+	Parsers map[string]Parser
+
+	// DisableBuiltinParsers 为 true 时不注册内置解析器。
+	DisableBuiltinParsers bool
+
+	// FallbackParser 是没有匹配的解析器时使用的解析器。
+	//
+	// 如果未设置，默认为 TextParser；为避免把二进制内容当作文本，
+	// 无法识别的二进制内容（application/octet-stream 等非文本类型）在没有匹配时会返回错误。
+	FallbackParser Parser
+}
+
+// DefaultMIMEParsers 返回内置的纯 Go 解析器，键为 MIME 类型。
+func DefaultMIMEParsers() map[string]Parser {
+	return map[string]Parser{
+		MIMEText:     TextParser{},
+		MIMEMarkdown: MarkdownParser{},
+		MIMEHTML:     HTMLParser{},
+		MIMECSV:      CSVParser{},
+		MIMEJSONL:    JSONLParser{},
+		MIMEDOCX:     DOCXParser{},
+		MIMEXLSX:     XLSXParser{},
+	}
+}
+
+// MIMEParser 是基于内容识别的解析器。
+//
+// 与 ExtParser 仅依据扩展名不同，MIMEParser 先通过 DetectMIME 从内容的魔数识别 MIME 类型，
+// 扩展名仅作为文本类格式的补充依据，因此可以处理无扩展名或扩展名错误的文件。
+// 识别出的类型记录在文档元数据的 MetaKeyMIMEType 中，也可通过 WithMIMEType 直接指定。
+//
+// 示例：
+//
+//	p, _ := parser.NewMIMEParser(ctx, nil)
+//	docs, err := p.Parse(ctx, upload, parser.WithURI("upload_1234"))
+type MIMEParser struct {
+	parsers        map[string]Parser
+	fallbackParser Parser
+}
+
+// mimeOptions 是 MIMEParser 的实现特定选项。
+type mimeOptions struct {
+	mimeType string
+}
+
+// WithMIMEType 跳过内容识别，直接使用指定的 MIME 类型选择解析器。
+func WithMIMEType(mimeType string) Option {
+	return WrapImplSpecificOptFn(func(o *mimeOptions) {
+		o.mimeType = mimeType
+	})
+}
+
+// NewMIMEParser 创建新的 MIME 解析器实例。
+func NewMIMEParser(ctx context.Context, conf *MIMEParserConfig) (*MIMEParser, error) {
+	if conf == nil {
+		conf = &MIMEParserConfig{}
+	}
+
+	p := &MIMEParser{
+		parsers:        make(map[string]Parser),
+		fallbackParser: conf.FallbackParser,
+	}
+	if !conf.DisableBuiltinParsers {
+		p.parsers = DefaultMIMEParsers()
+	}
+	for mimeType, parser := range conf.Parsers {
+		if parser == nil {
+			return nil, fmt.Errorf("parser for mime type %q is nil", mimeType)
+		}
+		p.parsers[baseMIME(mimeType)] = parser
+	}
+
+	if p.fallbackParser == nil {
+		p.fallbackParser = TextParser{}
+	}
+
+	return p, nil
+}
+
+// Parse 识别内容的 MIME 类型并交给对应的解析器解析。
+func (p *MIMEParser) Parse(ctx context.Context, reader io.Reader, opts ...Option) ([]*schema.Document, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	opt := GetCommonOptions(&Options{}, opts...)
+	mimeType := baseMIME(GetImplSpecificOptions(&mimeOptions{}, opts...).mimeType)
+	if mimeType == "" {
+		mimeType = DetectMIME(data, opt.URI)
+	}
+
+	parser, ok := p.parsers[mimeType]
+	if !ok {
+		if !isTextMIME(mimeType) {
+			return nil, errors.New("no parser found for mime type " + mimeType)
+		}
+		parser = p.fallbackParser
+	}
+
+	docs, err := parser.Parse(ctx, bytes.NewReader(data), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, doc := range docs {
+		if doc == nil {
+			continue
+		}
+
+		if doc.MetaData == nil {
+			doc.MetaData = make(map[string]any)
+		}
+
+		doc.MetaData[MetaKeyMIMEType] = mimeType
+		for k, v := range opt.ExtraMeta {
+			doc.MetaData[k] = v
+		}
+	}
+
+	return docs, nil
+}
+
+// GetParsers 返回已注册解析器的副本，键为 MIME 类型。
+func (p *MIMEParser) GetParsers() map[string]Parser {
+	res := make(map[string]Parser, len(p.parsers))
+	for k, v := range p.parsers {
+		res[k] = v
+	}
+
+	return res
+}
+
+func isTextMIME(m string) bool {
+	switch {
+	case strings.HasPrefix(m, "text/"), m == MIMEJSON, m == MIMEJSONL, strings.HasSuffix(m, "+xml"), m == "application/xml":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,240 @@
+package parser
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/favbox/eino/schema"
+)
+
+// buildZip 以给定的文件内容构建 ZIP 包，用于生成 DOCX/XLSX 测试数据。
+func buildZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		assert.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func docxFixture(t *testing.T) []byte {
+	return buildZip(t, map[string]string{
+		"[Content_Types].xml": `<Types/>`,
+		"word/document.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>
+<w:p><w:r><w:t>退货</w:t></w:r><w:r><w:t xml:space="preserve">政策</w:t></w:r></w:p>
+<w:p><w:r><w:t>七天</w:t><w:tab/><w:t>无理由</w:t></w:r></w:p>
+<w:tbl><w:tr><w:tc><w:p><w:r><w:t>A</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>B</w:t></w:r></w:p></w:tc></w:tr></w:tbl>
+</w:body></w:document>`,
+		"docProps/core.xml": `<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/"><dc:title>售后手册</dc:title></cp:coreProperties>`,
+	})
+}
+
+func xlsxFixture(t *testing.T) []byte {
+	return buildZip(t, map[string]string{
+		"[Content_Types].xml": `<Types/>`,
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="商品" sheetId="1" r:id="rId1"/><sheet name="空表" sheetId="2" r:id="rId2"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Target="worksheets/sheet1.xml"/><Relationship Id="rId2" Target="/xl/worksheets/sheet2.xml"/></Relationships>`,
+		"xl/sharedStrings.xml": `<sst><si><t>名称</t></si><si><t>价格</t></si><si><r><t>苹</t></r><r><t>果</t></r></si></sst>`,
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData>
+<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="inlineStr"><is><t>在售</t></is></c></row>
+<row r="2"><c r="A2" t="s"><v>2</v></c><c r="B2"><v>3.5</v></c><c r="C2" t="b"><v>1</v></c></row>
+<row r="3"/>
+<row r="4"><c r="A4" t="inlineStr"><is><t>梨</t></is></c><c r="C4" t="b"><v>0</v></c></row>
+</sheetData></worksheet>`,
+		"xl/worksheets/sheet2.xml": `<worksheet><sheetData/></worksheet>`,
+	})
+}
+
+func TestDetectMIME(t *testing.T) {
+	cases := []struct {
+		name string
+		data []byte
+		uri  string
+		want string
+	}{
+		{"PDF 魔数", []byte("%PDF-1.7\n..."), "report.txt", MIMEPDF},
+		{"扩展名错误的 DOCX", docxFixture(t), "upload.pdf", MIMEDOCX},
+		{"无扩展名的 XLSX", xlsxFixture(t), "blob", MIMEXLSX},
+		{"HTML", []byte("<!DOCTYPE html><html><body>hi</body></html>"), "", MIMEHTML},
+		{"按扩展名识别 Markdown", []byte("# 标题\n正文"), "README.md", MIMEMarkdown},
+		{"按扩展名识别 CSV", []byte("a,b\n1,2"), "data.csv", MIMECSV},
+		{"识别 JSON Lines", []byte("{\"a\":1}\n{\"a\":2}\n"), "", MIMEJSONL},
+		{"纯文本", []byte("你好"), "", MIMEText},
+		{"二进制", []byte{0x00, 0x01, 0x02, 0xff}, "", MIMEBinary},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.want, DetectMIME(c.data, c.uri))
+		})
+	}
+}
+
+func TestMIMEParser(t *testing.T) {
+	ctx := context.Background()
+	p, err := NewMIMEParser(ctx, nil)
+	assert.NoError(t, err)
+
+	t.Run("按内容选择解析器", func(t *testing.T) {
+		docs, err := p.Parse(ctx, bytes.NewReader(docxFixture(t)), WithURI("upload.bin"), WithExtraMeta(map[string]any{"tenant": "t1"}))
+		assert.NoError(t, err)
+		assert.Len(t, docs, 1)
+		assert.Equal(t, "退货政策\n七天\t无理由\nA\tB", docs[0].Content)
+		assert.Equal(t, MIMEDOCX, docs[0].MetaData[MetaKeyMIMEType])
+		assert.Equal(t, "售后手册", docs[0].MetaData[MetaKeyTitle])
+		assert.Equal(t, "t1", docs[0].MetaData["tenant"])
+		assert.Equal(t, "upload.bin", docs[0].MetaData[MetaKeySource])
+	})
+
+	t.Run("指定 MIME 类型", func(t *testing.T) {
+		docs, err := p.Parse(ctx, strings.NewReader("a,b\n1,2"), WithMIMEType("text/csv; charset=utf-8"))
+		assert.NoError(t, err)
+		assert.Len(t, docs, 1)
+		assert.Equal(t, MIMECSV, docs[0].MetaData[MetaKeyMIMEType])
+	})
+
+	t.Run("自定义解析器与无法识别的二进制", func(t *testing.T) {
+		custom := &ParserForTest{mock: func() ([]*schema.Document, error) {
+			return []*schema.Document{{Content: "pdf"}}, nil
+		}}
+		p, err := NewMIMEParser(ctx, &MIMEParserConfig{Parsers: map[string]Parser{MIMEPDF: custom}})
+		assert.NoError(t, err)
+		assert.Contains(t, p.GetParsers(), MIMEMarkdown)
+
+		docs, err := p.Parse(ctx, strings.NewReader("%PDF-1.4"))
+		assert.NoError(t, err)
+		assert.Equal(t, "pdf", docs[0].Content)
+
+		_, err = p.Parse(ctx, bytes.NewReader([]byte{0x00, 0x01, 0xff}))
+		assert.ErrorContains(t, err, "no parser found for mime type application/octet-stream")
+	})
+}
+
+func TestBuiltinParsers(t *testing.T) {
+	ctx := context.Background()
+	extra := WithExtraMeta(map[string]any{"tenant": "t1"})
+
+	t.Run("Markdown front matter", func(t *testing.T) {
+		src := "---\ntitle: 安装指南\ntags: [install, linux]\ntenant: ignored\n---\n\n# 安装\n正文\n"
+		docs, err := MarkdownParser{}.Parse(ctx, strings.NewReader(src), extra)
+		assert.NoError(t, err)
+		assert.Equal(t, "# 安装\n正文\n", docs[0].Content)
+		assert.Equal(t, "安装指南", docs[0].MetaData["title"])
+		assert.Equal(t, []any{"install", "linux"}, docs[0].MetaData["tags"])
+		assert.Equal(t, "t1", docs[0].MetaData["tenant"])
+
+		docs, err = MarkdownParser{}.Parse(ctx, strings.NewReader("---\n不是 front matter"))
+		assert.NoError(t, err)
+		assert.Equal(t, "---\n不是 front matter", docs[0].Content)
+
+		_, err = MarkdownParser{}.Parse(ctx, strings.NewReader("---\n: [\n---\n"))
+		assert.ErrorContains(t, err, "front matter")
+	})
+
+	t.Run("HTML 正文提取", func(t *testing.T) {
+		src := `<!DOCTYPE html><html><head><title> 退货说明 </title><style>p{color:red}</style></head>
+<body>
+<nav><a href="/">首页</a> <a href="/help">帮助</a></nav>
+<div class="sidebar"><p>热门推荐：这里是一些与正文无关的推荐内容，长度足够参与打分。</p></div>
+<div id="content">
+<h1>如何退货</h1>
+<p>自签收之日起七天内，商品保持完好即可申请无理由退货，运费由买家承担。</p>
+<p>登录后进入订单详情页，点击申请售后，按提示填写退货原因并提交即可。<br>审核通过后寄回商品。</p>
+<div class="share">分享到微博</div>
+<script>if (a < b) { alert(1) }</script>
+</div>
+<footer>版权所有</footer>
+</body></html>`
+		docs, err := HTMLParser{}.Parse(ctx, strings.NewReader(src), extra)
+		assert.NoError(t, err)
+		assert.Equal(t, "退货说明", docs[0].MetaData[MetaKeyTitle])
+		assert.Equal(t, "t1", docs[0].MetaData["tenant"])
+		assert.Equal(t, "如何退货\n自签收之日起七天内，商品保持完好即可申请无理由退货，运费由买家承担。\n"+
+			"登录后进入订单详情页，点击申请售后，按提示填写退货原因并提交即可。\n审核通过后寄回商品。", docs[0].Content)
+
+		docs, err = HTMLParser{KeepFullText: true}.Parse(ctx, strings.NewReader(src))
+		assert.NoError(t, err)
+		assert.Contains(t, docs[0].Content, "热门推荐")
+		assert.NotContains(t, docs[0].Content, "首页")
+		assert.NotContains(t, docs[0].Content, "alert")
+	})
+
+	t.Run("HTML 优先使用 article", func(t *testing.T) {
+		src := `<html><body><div><p>短</p></div><article><h2>标题</h2><p>正文 &amp; 内容</p></article></body></html>`
+		docs, err := HTMLParser{}.Parse(ctx, strings.NewReader(src))
+		assert.NoError(t, err)
+		assert.Equal(t, "标题\n正文 & 内容", docs[0].Content)
+	})
+
+	t.Run("CSV 每行一个文档", func(t *testing.T) {
+		docs, err := CSVParser{}.Parse(ctx, strings.NewReader("\uFEFF名称,价格,备注\n苹果,3.5,\n梨,2,甜\n"), extra)
+		assert.NoError(t, err)
+		assert.Len(t, docs, 2)
+		assert.Equal(t, "名称: 苹果\n价格: 3.5", docs[0].Content)
+		assert.Equal(t, map[string]string{"名称": "梨", "价格": "2", "备注": "甜"}, docs[1].MetaData[MetaKeyColumns])
+		assert.Equal(t, 1, docs[1].MetaData[MetaKeyRowIndex])
+		assert.Equal(t, "t1", docs[1].MetaData["tenant"])
+
+		docs, err = CSVParser{Comma: ';', NoHeader: true, ContentColumns: []string{"column_2"}}.Parse(ctx, strings.NewReader("a;b\nc;d"))
+		assert.NoError(t, err)
+		assert.Len(t, docs, 2)
+		assert.Equal(t, "column_2: b", docs[0].Content)
+	})
+
+	t.Run("JSONL 每行一个文档", func(t *testing.T) {
+		src := "{\"text\":\"第一条\",\"score\":1}\n\n{\"text\":{\"nested\":true}}\n"
+		docs, err := JSONLParser{ContentKey: "text"}.Parse(ctx, strings.NewReader(src), extra)
+		assert.NoError(t, err)
+		assert.Len(t, docs, 2)
+		assert.Equal(t, "第一条", docs[0].Content)
+		assert.Equal(t, float64(1), docs[0].MetaData[MetaKeyColumns].(map[string]any)["score"])
+		assert.Equal(t, `{"nested":true}`, docs[1].Content)
+		assert.Equal(t, 1, docs[1].MetaData[MetaKeyRowIndex])
+
+		_, err = JSONLParser{}.Parse(ctx, strings.NewReader("{}\nnot json"))
+		assert.ErrorContains(t, err, "line 2")
+	})
+
+	t.Run("XLSX 每行一个文档", func(t *testing.T) {
+		docs, err := XLSXParser{}.Parse(ctx, bytes.NewReader(xlsxFixture(t)), extra)
+		assert.NoError(t, err)
+		assert.Len(t, docs, 2)
+		assert.Equal(t, "名称: 苹果\n价格: 3.5\n在售: true", docs[0].Content)
+		assert.Equal(t, "商品", docs[0].MetaData[MetaKeySheet])
+		assert.Equal(t, map[string]string{"名称": "梨", "价格": "", "在售": "false"}, docs[1].MetaData[MetaKeyColumns])
+		assert.Equal(t, "t1", docs[1].MetaData["tenant"])
+
+		docs, err = XLSXParser{Sheets: []string{"空表"}}.Parse(ctx, bytes.NewReader(xlsxFixture(t)))
+		assert.NoError(t, err)
+		assert.Empty(t, docs)
+
+		// 小写引用按列字母解析，超出最大列数的引用按单元格位置对齐
+		fixture := buildZip(t, map[string]string{
+			"xl/workbook.xml":            `<workbook xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="s" r:id="rId1"/></sheets></workbook>`,
+			"xl/_rels/workbook.xml.rels": `<Relationships><Relationship Id="rId1" Target="worksheets/sheet1.xml"/></Relationships>`,
+			"xl/worksheets/sheet1.xml": `<worksheet><sheetData>
+<row r="1"><c r="a1" t="inlineStr"><is><t>名称</t></is></c><c r="b1" t="inlineStr"><is><t>价格</t></is></c></row>
+<row r="2"><c r="a2" t="inlineStr"><is><t>梨</t></is></c><c r="XFDZZZ2"><v>9</v></c></row>
+</sheetData></worksheet>`,
+		})
+		docs, err = XLSXParser{}.Parse(ctx, bytes.NewReader(fixture))
+		assert.NoError(t, err)
+		assert.Len(t, docs, 1)
+		assert.Equal(t, "名称: 梨\n价格: 9", docs[0].Content)
+
+		_, err = XLSXParser{}.Parse(ctx, strings.NewReader("not a zip"))
+		assert.ErrorContains(t, err, "open ooxml package failed")
+	})
+}
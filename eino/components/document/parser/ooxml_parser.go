@@ -0,0 +1,358 @@
+package parser
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/favbox/eino/schema"
+)
+
+// MetaKeySheet 是 XLSX 文档所属工作表名称的元数据键。
+const MetaKeySheet = "_sheet"
+
+// DOCXParser 是 Word（.docx）解析器。
+//
+// 提取 word/document.xml 中的段落文本，每个段落一行，表格单元格以制表符分隔；
+// docProps/core.xml 中的标题写入元数据的 MetaKeyTitle。
+type DOCXParser struct{}
+
+// Parse 解析 DOCX 内容并返回单个文档。
+func (p DOCXParser) Parse(ctx context.Context, reader io.Reader, opts ...Option) ([]*schema.Document, error) {
+	zr, err := openZip(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := readZipFile(zr, "word/document.xml")
+	if err != nil {
+		return nil, err
+	}
+	content, err := docxText(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse docx document failed: %w", err)
+	}
+
+	meta := newMeta(opts...)
+	if core, err := readZipFile(zr, "docProps/core.xml"); err == nil {
+		props := struct {
+			Title string `xml:"title"`
+		}{}
+		if xml.Unmarshal(core, &props) == nil && strings.TrimSpace(props.Title) != "" {
+			meta[MetaKeyTitle] = strings.TrimSpace(props.Title)
+		}
+	}
+
+	return []*schema.Document{{Content: content, MetaData: meta}}, nil
+}
+
+// docxText 按段落提取 document.xml 中的文本，表格每行一行、单元格以制表符分隔。
+func docxText(data []byte) (string, error) {
+	var (
+		lines   []string
+		line    strings.Builder
+		cells   []string
+		inT     bool
+		tcDepth int
+	)
+	flush := func() {
+		if s := strings.TrimRight(line.String(), "\t "); s != "" {
+			lines = append(lines, s)
+		}
+		line.Reset()
+	}
+
+	d := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inT = true
+			case "tab":
+				line.WriteByte('\t')
+			case "br", "cr":
+				line.WriteByte('\n')
+			case "tc":
+				if tcDepth == 0 {
+					flush()
+				}
+				tcDepth++
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inT = false
+			case "p":
+				if tcDepth > 0 {
+					line.WriteByte(' ') // 单元格内的段落以空格连接
+				} else {
+					flush()
+				}
+			case "tc":
+				if tcDepth--; tcDepth == 0 {
+					cells = append(cells, strings.TrimSpace(line.String()))
+					line.Reset()
+				}
+			case "tr":
+				if tcDepth == 0 {
+					line.WriteString(strings.Join(cells, "\t"))
+					cells = cells[:0]
+					flush()
+				}
+			}
+		case xml.CharData:
+			if inT {
+				line.Write(t)
+			}
+		}
+	}
+	flush()
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// XLSXParser 是 Excel（.xlsx）解析器。
+//
+// 与 CSVParser 一致，每个工作表的首行作为表头，其后每行解析为一个 Document，
+// 各列取值写入元数据的 MetaKeyColumns，工作表名称写入 MetaKeySheet，行号写入 MetaKeyRowIndex。
+// 公式单元格使用缓存的计算结果，日期等数字格式不做转换。
+type XLSXParser struct {
+	// Sheets 指定要解析的工作表名称，默认解析全部工作表。
+	Sheets []string
+	// NoHeader 为 true 时首行也视为数据，列名依次为 "column_1"、"column_2"……
+	NoHeader bool
+	// ContentColumns 指定组成文档内容的列，默认使用全部列。
+	ContentColumns []string
+}
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Items []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxRichText struct {
+	T string `xml:"t"`
+	R []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (r xlsxRichText) String() string {
+	if len(r.R) == 0 {
+		return r.T
+	}
+	var sb strings.Builder
+	for _, run := range r.R {
+		sb.WriteString(run.T)
+	}
+	return sb.String()
+}
+
+type xlsxSheet struct {
+	Rows []struct {
+		Cells []struct {
+			Ref    string       `xml:"r,attr"`
+			Type   string       `xml:"t,attr"`
+			Value  string       `xml:"v"`
+			Inline xlsxRichText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// Parse 解析 XLSX 内容，每个数据行返回一个文档。
+func (p XLSXParser) Parse(ctx context.Context, reader io.Reader, opts ...Option) ([]*schema.Document, error) {
+	zr, err := openZip(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	workbook := &xlsxWorkbook{}
+	if err = unmarshalZipFile(zr, "xl/workbook.xml", workbook); err != nil {
+		return nil, err
+	}
+	rels := &xlsxRelationships{}
+	if err = unmarshalZipFile(zr, "xl/_rels/workbook.xml.rels", rels); err != nil {
+		return nil, err
+	}
+	targets := make(map[string]string, len(rels.Items))
+	for _, rel := range rels.Items {
+		if strings.HasPrefix(rel.Target, "/") {
+			targets[rel.ID] = strings.TrimPrefix(rel.Target, "/")
+		} else {
+			targets[rel.ID] = path.Join("xl", rel.Target)
+		}
+	}
+
+	var sharedStrings []string
+	sst := &struct {
+		Items []xlsxRichText `xml:"si"`
+	}{}
+	if err = unmarshalZipFile(zr, "xl/sharedStrings.xml", sst); err == nil {
+		for _, item := range sst.Items {
+			sharedStrings = append(sharedStrings, item.String())
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	wanted := make(map[string]bool, len(p.Sheets))
+	for _, name := range p.Sheets {
+		wanted[name] = true
+	}
+
+	var docs []*schema.Document
+	for _, s := range workbook.Sheets {
+		if len(wanted) > 0 && !wanted[s.Name] {
+			continue
+		}
+		target, ok := targets[s.RID]
+		if !ok {
+			return nil, fmt.Errorf("xlsx sheet %q relationship %q not found", s.Name, s.RID)
+		}
+		sheet := &xlsxSheet{}
+		if err = unmarshalZipFile(zr, target, sheet); err != nil {
+			return nil, err
+		}
+
+		rows, err := xlsxRows(sheet, sharedStrings)
+		if err != nil {
+			return nil, fmt.Errorf("parse xlsx sheet %q failed: %w", s.Name, err)
+		}
+		var header []string
+		if !p.NoHeader && len(rows) > 0 {
+			header, rows = rows[0], rows[1:]
+		}
+		sheetDocs, err := rowsToDocs(header, rows, p.ContentColumns, opts, map[string]any{MetaKeySheet: s.Name})
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, sheetDocs...)
+	}
+
+	return docs, nil
+}
+
+// xlsxRows 将工作表转换为按列对齐的字符串行，跳过空行。
+func xlsxRows(sheet *xlsxSheet, sharedStrings []string) ([][]string, error) {
+	var rows [][]string
+	for _, r := range sheet.Rows {
+		var row []string
+		empty := true
+		for i, c := range r.Cells {
+			// 引用缺失或无法解析时按单元格的位置对齐
+			col := columnIndex(c.Ref)
+			if col < 0 {
+				col = i
+			}
+			var value string
+			switch c.Type {
+			case "s":
+				idx, err := strconv.Atoi(strings.TrimSpace(c.Value))
+				if err != nil || idx < 0 || idx >= len(sharedStrings) {
+					return nil, fmt.Errorf("invalid shared string index %q at %s", c.Value, c.Ref)
+				}
+				value = sharedStrings[idx]
+			case "inlineStr":
+				value = c.Inline.String()
+			case "b":
+				value = strconv.FormatBool(c.Value == "1")
+			default:
+				value = c.Value
+			}
+			for len(row) <= col {
+				row = append(row, "")
+			}
+			row[col] = value
+			if value != "" {
+				empty = false
+			}
+		}
+		if !empty {
+			rows = append(rows, row)
+		}
+	}
+
+	return rows, nil
+}
+
+// maxColumns 工作表的最大列数（列 XFD）。
+const maxColumns = 16384
+
+// columnIndex 将单元格引用（如 "AB12"，不区分大小写）的列字母转换为从 0 开始的列号，
+// 引用不以字母开头或超出最大列数时返回 -1。
+func columnIndex(ref string) int {
+	col := 0
+	for _, r := range ref {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			col = col*26 + int(r-'A') + 1
+		case r >= 'a' && r <= 'z':
+			col = col*26 + int(r-'a') + 1
+		default:
+			return col - 1
+		}
+		if col > maxColumns {
+			return -1
+		}
+	}
+	return col - 1
+}
+
+func openZip(reader io.Reader) (*zip.Reader, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("open ooxml package failed: %w", err)
+	}
+	return zr, nil
+}
+
+func readZipFile(zr *zip.Reader, name string) ([]byte, error) {
+	f, err := zr.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("open %s failed: %w", name, err)
+	}
+	defer f.Close()
+
+	return io.ReadAll(f)
+}
+
+func unmarshalZipFile(zr *zip.Reader, name string, v any) error {
+	data, err := readZipFile(zr, name)
+	if err != nil {
+		return err
+	}
+	if err = xml.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("parse %s failed: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package parser
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/favbox/eino/schema"
+)
+
+const (
+	// MetaKeyRowIndex 是表格类文档行号的元数据键，值为 int，从 0 开始（不含表头）。
+	MetaKeyRowIndex = "_row_index"
+	// MetaKeyColumns 是表格类文档各列取值的元数据键。
+	// CSV 与 XLSX 的值为 map[string]string，JSONL 的值为 map[string]any。
+	MetaKeyColumns = "_columns"
+)
+
+// CSVParser 是 CSV 解析器。
+//
+// 首行作为表头，其后每行解析为一个 Document，内容为 "列名: 值" 组成的多行文本，
+// 各列取值写入元数据的 MetaKeyColumns，行号写入 MetaKeyRowIndex。
+type CSVParser struct {
+	// Comma 是字段分隔符，默认 ','。
+	Comma rune
+	// NoHeader 为 true 时首行也视为数据，列名依次为 "column_1"、"column_2"……
+	NoHeader bool
+	// ContentColumns 指定组成文档内容的列，默认使用全部列。
+	ContentColumns []string
+}
+
+// Parse 解析 CSV 内容，每行返回一个文档。
+func (p CSVParser) Parse(ctx context.Context, reader io.Reader, opts ...Option) ([]*schema.Document, error) {
+	r := csv.NewReader(reader)
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	if p.Comma != 0 {
+		r.Comma = p.Comma
+	}
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("read csv failed: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	var header []string
+	if !p.NoHeader {
+		header, records = records[0], records[1:]
+		if len(header) > 0 {
+			header[0] = strings.TrimPrefix(header[0], "\uFEFF")
+		}
+	}
+
+	return rowsToDocs(header, records, p.ContentColumns, opts, nil)
+}
+
+// rowsToDocs 将表格行转换为文档，列数超出表头时以 "column_N" 命名。
+func rowsToDocs(header []string, rows [][]string, contentColumns []string, opts []Option, extra map[string]any) ([]*schema.Document, error) {
+	columnName := func(i int) string {
+		if i < len(header) && header[i] != "" {
+			return header[i]
+		}
+		return fmt.Sprintf("column_%d", i+1)
+	}
+
+	docs := make([]*schema.Document, 0, len(rows))
+	for idx, row := range rows {
+		columns := make(map[string]string, len(row))
+		names := make([]string, 0, len(row))
+		for i, v := range row {
+			name := columnName(i)
+			columns[name] = v
+			names = append(names, name)
+		}
+		if len(contentColumns) > 0 {
+			names = contentColumns
+		}
+
+		var sb strings.Builder
+		for _, name := range names {
+			v, ok := columns[name]
+			if !ok || v == "" {
+				continue
+			}
+			if sb.Len() > 0 {
+				sb.WriteByte('\n')
+			}
+			sb.WriteString(name)
+			sb.WriteString(": ")
+			sb.WriteString(v)
+		}
+
+		meta := newMeta(opts...)
+		for k, v := range extra {
+			meta[k] = v
+		}
+		meta[MetaKeyRowIndex] = idx
+		meta[MetaKeyColumns] = columns
+		docs = append(docs, &schema.Document{Content: sb.String(), MetaData: meta})
+	}
+
+	return docs, nil
+}
+
+// JSONLParser 是 JSON Lines 解析器。
+//
+// 每个非空行须为一个 JSON 对象，解析为一个 Document，
+// 对象的全部字段写入元数据的 MetaKeyColumns，行号写入 MetaKeyRowIndex。
+type JSONLParser struct {
+	// ContentKey 指定作为文档内容的字段，非字符串值会序列化为 JSON；默认使用整行内容。
+	ContentKey string
+}
+
+// Parse 解析 JSON Lines 内容，每行返回一个文档。
+func (p JSONLParser) Parse(ctx context.Context, reader io.Reader, opts ...Option) ([]*schema.Document, error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+
+	var docs []*schema.Document
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		record := make(map[string]any)
+		if err := sonic.UnmarshalString(text, &record); err != nil {
+			return nil, fmt.Errorf("parse jsonl line %d failed: %w", line, err)
+		}
+
+		content := text
+		if p.ContentKey != "" {
+			switch v := record[p.ContentKey].(type) {
+			case nil:
+				content = ""
+			case string:
+				content = v
+			default:
+				s, err := sonic.MarshalString(v)
+				if err != nil {
+					return nil, fmt.Errorf("marshal jsonl line %d content failed: %w", line, err)
+				}
+				content = s
+			}
+		}
+
+		meta := newMeta(opts...)
+		meta[MetaKeyRowIndex] = len(docs)
+		meta[MetaKeyColumns] = record
+		docs = append(docs, &schema.Document{Content: content, MetaData: meta})
+	}
+	if err := scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, fmt.Errorf("jsonl line too long: %w", err)
+		}
+		return nil, err
+	}
+
+	return docs, nil
+}
@@ -0,0 +1,32 @@
+// Package splitter 提供常用的文本切分器，均实现 document.Transformer。
+//
+//   - NewRecursiveSplitter：按分隔符层级递归切分，并在相邻分块间保留重叠内容
+//   - NewMarkdownHeaderSplitter：按 Markdown 标题切分，标题路径记录在元数据中
+//   - NewTokenSplitter：使用可插拔的 Tokenizer 按 token 数量切分
+//
+// 每个分块都是源文档内容的子串，继承源文档的元数据，并记录来源信息以便引用溯源：
+// MetaKeySourceID（源文档 ID）、MetaKeyChunkIndex（分块序号，从 0 开始）、
+// MetaKeyStartOffset 与 MetaKeyEndOffset（分块在源文档内容中的字节区间 [start, end)）。
+//
+// 示例：
+//
+//	s, _ := splitter.NewRecursiveSplitter(ctx, &splitter.RecursiveConfig{
+//		ChunkSize:   500,
+//		OverlapSize: 50,
+//	})
+//	chunks, _ := s.Transform(ctx, docs)
+//	start := chunks[0].MetaData[splitter.MetaKeyStartOffset].(int)
+package splitter
+
+const (
+	// MetaKeySourceID 分块元数据中源文档 ID 的键，值为 string。
+	MetaKeySourceID = "_source_id"
+	// MetaKeyChunkIndex 分块元数据中分块序号的键，值为 int，同一源文档内从 0 开始。
+	MetaKeyChunkIndex = "_chunk_index"
+	// MetaKeyStartOffset 分块元数据中起始字节偏移的键，值为 int。
+	MetaKeyStartOffset = "_start_offset"
+	// MetaKeyEndOffset 分块元数据中结束字节偏移（不含）的键，值为 int。
+	MetaKeyEndOffset = "_end_offset"
+	// MetaKeyHeaderPath 分块元数据中 Markdown 标题路径的键，值为 []string，由外到内排列。
+	MetaKeyHeaderPath = "_header_path"
+)
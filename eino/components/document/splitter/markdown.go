@@ -0,0 +1,135 @@
+package splitter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/favbox/eino/components/document"
+)
+
+// markdownHeader 匹配 ATX 风格的标题行，如 "## 安装"。
+var markdownHeader = regexp.MustCompile(`^ {0,3}(#{1,6})[ \t]+(.*?)(?:[ \t]+#+)?[ \t]*$`)
+
+// MarkdownHeaderConfig Markdown 标题切分器配置。
+type MarkdownHeaderConfig struct {
+	// Headers 参与切分的标题级别与其在元数据中的键，如 {"#": "h1", "##": "h2"}。
+	// 未列出的级别视为正文，为空时按全部 6 级标题切分且不写入单独的键。
+	// 无论是否配置，完整的标题路径都记录在 MetaKeyHeaderPath 中。
+	Headers map[string]string
+	// TrimHeaders 为 true 时分块内容不包含其标题行。
+	TrimHeaders bool
+	// IDGenerator 生成分块 ID，默认源文档有 ID 时为 "{源文档ID}_{序号}"。
+	IDGenerator IDGenerator
+}
+
+// NewMarkdownHeaderSplitter 创建 Markdown 标题切分器。
+// 每个标题开启一个新分块，代码块中的 # 行不视为标题，仅有标题而无正文的分块会被丢弃。
+func NewMarkdownHeaderSplitter(_ context.Context, config *MarkdownHeaderConfig) (document.Transformer, error) {
+	if config == nil {
+		return nil, errors.New("markdown header splitter config is required")
+	}
+
+	m := &markdownSplitter{
+		levels:      make(map[int]string),
+		trimHeaders: config.TrimHeaders,
+	}
+	for header, key := range config.Headers {
+		if len(header) == 0 || len(header) > 6 || strings.Trim(header, "#") != "" {
+			return nil, fmt.Errorf("invalid markdown header: %q", header)
+		}
+		m.levels[len(header)] = key
+	}
+	if len(m.levels) == 0 {
+		for level := 1; level <= 6; level++ {
+			m.levels[level] = ""
+		}
+	}
+
+	return newTransformer("MarkdownHeaderSplitter", m.split, config.IDGenerator), nil
+}
+
+type markdownSplitter struct {
+	levels      map[int]string
+	trimHeaders bool
+}
+
+// headerEntry 标题路径中的一级标题。
+type headerEntry struct {
+	level int
+	title string
+}
+
+func (m *markdownSplitter) split(_ context.Context, text string) ([]*chunk, error) {
+	var (
+		chunks    []*chunk
+		path      []headerEntry
+		fence     string
+		secStart  int
+		bodyStart int
+	)
+	closeSection := func(end int) {
+		if _, _, ok := trimSpan(text, bodyStart, end); !ok {
+			return
+		}
+		start := secStart
+		if m.trimHeaders {
+			start = bodyStart
+		}
+		start, end, _ = trimSpan(text, start, end)
+		chunks = append(chunks, &chunk{start: start, end: end, meta: m.headerMeta(path)})
+	}
+
+	for lineStart := 0; lineStart < len(text); {
+		lineEnd := len(text)
+		if idx := strings.IndexByte(text[lineStart:], '\n'); idx >= 0 {
+			lineEnd = lineStart + idx + 1
+		}
+		line := strings.TrimRight(text[lineStart:lineEnd], "\r\n")
+
+		switch trimmed := strings.TrimSpace(line); {
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+		case strings.HasPrefix(trimmed, "```"):
+			fence = "```"
+		case strings.HasPrefix(trimmed, "~~~"):
+			fence = "~~~"
+		default:
+			match := markdownHeader.FindStringSubmatch(line)
+			if match == nil {
+				break
+			}
+			level := len(match[1])
+			if _, ok := m.levels[level]; !ok {
+				break
+			}
+			closeSection(lineStart)
+			for len(path) > 0 && path[len(path)-1].level >= level {
+				path = path[:len(path)-1]
+			}
+			path = append(path, headerEntry{level: level, title: strings.TrimSpace(match[2])})
+			secStart, bodyStart = lineStart, lineEnd
+		}
+		lineStart = lineEnd
+	}
+	closeSection(len(text))
+
+	return chunks, nil
+}
+
+func (m *markdownSplitter) headerMeta(path []headerEntry) map[string]any {
+	titles := make([]string, 0, len(path))
+	meta := make(map[string]any, len(path)+1)
+	for _, h := range path {
+		titles = append(titles, h.title)
+		if key := m.levels[h.level]; key != "" {
+			meta[key] = h.title
+		}
+	}
+	meta[MetaKeyHeaderPath] = titles
+	return meta
+}
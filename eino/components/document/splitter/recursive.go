@@ -0,0 +1,148 @@
+package splitter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/favbox/eino/components/document"
+)
+
+// defaultSeparators 默认分隔符层级：段落、换行、中英文句末标点、空格，最后按字符切分。
+var defaultSeparators = []string{"\n\n", "\n", "。", "！", "？", ". ", "! ", "? ", " ", ""}
+
+// RecursiveConfig 递归字符切分器配置。
+type RecursiveConfig struct {
+	// ChunkSize 分块的最大长度，按 LenFunc 计算，必填。
+	ChunkSize int
+	// OverlapSize 相邻分块间的最大重叠长度，需小于 ChunkSize。
+	OverlapSize int
+	// Separators 由粗到细的分隔符层级，空字符串表示按字符切分，默认 defaultSeparators。
+	// 文本先按第一个分隔符切分，过长的片段再使用下一级分隔符，分隔符保留在前一个片段末尾。
+	Separators []string
+	// LenFunc 计算文本长度，默认按 Unicode 字符计数。
+	LenFunc func(string) int
+	// IDGenerator 生成分块 ID，默认源文档有 ID 时为 "{源文档ID}_{序号}"。
+	IDGenerator IDGenerator
+}
+
+// NewRecursiveSplitter 创建递归字符切分器。
+// 分块首尾的空白字符会被去除，全为空白的分块会被丢弃；无法再切分的片段可能超过 ChunkSize。
+func NewRecursiveSplitter(_ context.Context, config *RecursiveConfig) (document.Transformer, error) {
+	if config == nil {
+		return nil, errors.New("recursive splitter config is required")
+	}
+	if config.ChunkSize <= 0 {
+		return nil, fmt.Errorf("recursive splitter 'ChunkSize' must be positive, got %d", config.ChunkSize)
+	}
+	if config.OverlapSize < 0 || config.OverlapSize >= config.ChunkSize {
+		return nil, fmt.Errorf("recursive splitter 'OverlapSize' must be in [0, ChunkSize), got %d", config.OverlapSize)
+	}
+
+	r := &recursiveSplitter{
+		chunkSize:   config.ChunkSize,
+		overlapSize: config.OverlapSize,
+		separators:  config.Separators,
+		lenFunc:     config.LenFunc,
+	}
+	if len(r.separators) == 0 {
+		r.separators = defaultSeparators
+	}
+	if r.lenFunc == nil {
+		r.lenFunc = runeCount
+	}
+
+	return newTransformer("RecursiveSplitter", r.split, config.IDGenerator), nil
+}
+
+type recursiveSplitter struct {
+	chunkSize   int
+	overlapSize int
+	separators  []string
+	lenFunc     func(string) int
+}
+
+// piece 切分得到的连续片段。
+type piece struct {
+	start, end int
+	length     int
+}
+
+func (r *recursiveSplitter) split(_ context.Context, text string) ([]*chunk, error) {
+	pieces := r.splitPieces(text, 0, len(text), 0, nil)
+	return r.merge(text, pieces), nil
+}
+
+// splitPieces 将 text[start:end] 切分为不超过 chunkSize 的连续片段，片段首尾相接覆盖整个区间。
+func (r *recursiveSplitter) splitPieces(text string, start, end, level int, pieces []piece) []piece {
+	if length := r.lenFunc(text[start:end]); length <= r.chunkSize {
+		return append(pieces, piece{start: start, end: end, length: length})
+	}
+	if level >= len(r.separators) || r.separators[level] == "" {
+		return r.splitRunes(text, start, end, pieces)
+	}
+
+	sep := r.separators[level]
+	if !strings.Contains(text[start:end], sep) {
+		return r.splitPieces(text, start, end, level+1, pieces)
+	}
+	for pos := start; pos < end; {
+		next := end
+		if idx := strings.Index(text[pos:end], sep); idx >= 0 {
+			next = pos + idx + len(sep)
+		}
+		pieces = r.splitPieces(text, pos, next, level+1, pieces)
+		pos = next
+	}
+
+	return pieces
+}
+
+// splitRunes 按字符切分，每个片段为一个字符。
+func (r *recursiveSplitter) splitRunes(text string, start, end int, pieces []piece) []piece {
+	for pos := start; pos < end; {
+		_, size := utf8.DecodeRuneInString(text[pos:end])
+		pieces = append(pieces, piece{start: pos, end: pos + size, length: r.lenFunc(text[pos : pos+size])})
+		pos += size
+	}
+	return pieces
+}
+
+// merge 将相邻片段合并为不超过 chunkSize 的分块，新分块以上一分块末尾不超过 overlapSize 的片段开头。
+func (r *recursiveSplitter) merge(text string, pieces []piece) []*chunk {
+	var (
+		chunks []*chunk
+		window []piece
+		total  int
+	)
+	emit := func() {
+		if len(window) == 0 {
+			return
+		}
+		start, end, ok := trimSpan(text, window[0].start, window[len(window)-1].end)
+		if !ok {
+			return
+		}
+		if n := len(chunks); n > 0 && chunks[n-1].start <= start && end <= chunks[n-1].end {
+			return // 完全包含于上一个分块的重叠部分
+		}
+		chunks = append(chunks, &chunk{start: start, end: end})
+	}
+
+	for _, p := range pieces {
+		if total+p.length > r.chunkSize && len(window) > 0 {
+			emit()
+			for len(window) > 0 && (total > r.overlapSize || total+p.length > r.chunkSize) {
+				total -= window[0].length
+				window = window[1:]
+			}
+		}
+		window = append(window, p)
+		total += p.length
+	}
+	emit()
+
+	return chunks
+}
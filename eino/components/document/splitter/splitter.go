@@ -0,0 +1,119 @@
+package splitter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/favbox/eino/callbacks"
+	"github.com/favbox/eino/components"
+	"github.com/favbox/eino/components/document"
+	"github.com/favbox/eino/schema"
+)
+
+// IDGenerator 生成分块 ID，index 为分块在源文档内的序号。
+type IDGenerator func(ctx context.Context, src *schema.Document, index int) string
+
+// chunk 源文档内容中的一个分块。
+type chunk struct {
+	start, end int
+	// meta 分块特有的元数据，如标题路径。
+	meta map[string]any
+}
+
+// splitFunc 将文本切分为分块，分块区间按起始位置升序排列。
+type splitFunc func(ctx context.Context, text string) ([]*chunk, error)
+
+// transformer 切分器的公共实现，负责触发回调与构建分块文档。
+type transformer struct {
+	typ         string
+	split       splitFunc
+	idGenerator IDGenerator
+}
+
+func (t *transformer) Transform(ctx context.Context, src []*schema.Document, _ ...document.TransformerOption) (docs []*schema.Document, err error) {
+	ctx = callbacks.EnsureRunInfo(ctx, t.GetType(), components.ComponentOfTransformer)
+	ctx = callbacks.OnStart(ctx, &document.TransformerCallbackInput{Input: src})
+	defer func() {
+		if err != nil {
+			_ = callbacks.OnError(ctx, err)
+		}
+	}()
+
+	for _, doc := range src {
+		if doc == nil {
+			continue
+		}
+		chunks, err := t.split(ctx, doc.Content)
+		if err != nil {
+			return nil, fmt.Errorf("split document[%s] failed: %w", doc.ID, err)
+		}
+		for i, c := range chunks {
+			docs = append(docs, t.buildDoc(ctx, doc, i, c))
+		}
+	}
+
+	_ = callbacks.OnEnd(ctx, &document.TransformerCallbackOutput{Output: docs})
+
+	return docs, nil
+}
+
+func (t *transformer) buildDoc(ctx context.Context, src *schema.Document, index int, c *chunk) *schema.Document {
+	meta := make(map[string]any, len(src.MetaData)+len(c.meta)+4)
+	for k, v := range src.MetaData {
+		meta[k] = v
+	}
+	for k, v := range c.meta {
+		meta[k] = v
+	}
+	meta[MetaKeySourceID] = src.ID
+	meta[MetaKeyChunkIndex] = index
+	meta[MetaKeyStartOffset] = c.start
+	meta[MetaKeyEndOffset] = c.end
+
+	return &schema.Document{
+		ID:       t.idGenerator(ctx, src, index),
+		Content:  src.Content[c.start:c.end],
+		MetaData: meta,
+	}
+}
+
+func (t *transformer) GetType() string {
+	return t.typ
+}
+
+func (t *transformer) IsCallbacksEnabled() bool {
+	return true
+}
+
+// defaultIDGenerator 源文档有 ID 时生成 "{源文档ID}_{序号}"，否则为空。
+func defaultIDGenerator(_ context.Context, src *schema.Document, index int) string {
+	if src.ID == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s_%d", src.ID, index)
+}
+
+func newTransformer(typ string, split splitFunc, idGenerator IDGenerator) *transformer {
+	if idGenerator == nil {
+		idGenerator = defaultIDGenerator
+	}
+	return &transformer{typ: typ, split: split, idGenerator: idGenerator}
+}
+
+// trimSpan 去除区间首尾的空白字符，区间全为空白时返回 false。
+func trimSpan(text string, start, end int) (int, int, bool) {
+	s := text[start:end]
+	trimmed := strings.TrimLeftFunc(s, unicode.IsSpace)
+	start += len(s) - len(trimmed)
+	trimmed = strings.TrimRightFunc(trimmed, unicode.IsSpace)
+	end = start + len(trimmed)
+	return start, end, start < end
+}
+
+// runeCount 默认的长度函数，按 Unicode 字符计数。
+func runeCount(s string) int {
+	return utf8.RuneCountInString(s)
+}
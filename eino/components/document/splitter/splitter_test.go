@@ -0,0 +1,208 @@
+package splitter
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/favbox/eino/callbacks"
+	"github.com/favbox/eino/components"
+	"github.com/favbox/eino/components/document"
+	"github.com/favbox/eino/schema"
+)
+
+// assertOffsets 校验每个分块的内容与其记录的源文档字节区间一致。
+func assertOffsets(t *testing.T, src *schema.Document, chunks []*schema.Document) {
+	t.Helper()
+	for i, c := range chunks {
+		start, end := c.MetaData[MetaKeyStartOffset].(int), c.MetaData[MetaKeyEndOffset].(int)
+		assert.Equal(t, src.Content[start:end], c.Content)
+		assert.Equal(t, i, c.MetaData[MetaKeyChunkIndex])
+		assert.Equal(t, src.ID, c.MetaData[MetaKeySourceID])
+	}
+}
+
+func contents(docs []*schema.Document) []string {
+	out := make([]string, 0, len(docs))
+	for _, d := range docs {
+		out = append(out, d.Content)
+	}
+	return out
+}
+
+func TestRecursiveSplitter(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("按分隔符层级切分", func(t *testing.T) {
+		s, err := NewRecursiveSplitter(ctx, &RecursiveConfig{ChunkSize: 12})
+		assert.NoError(t, err)
+
+		src := &schema.Document{ID: "doc", Content: "第一段第一句。第一段第二句。\n\n第二段。", MetaData: map[string]any{"lang": "zh"}}
+		chunks, err := s.Transform(ctx, []*schema.Document{src})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"第一段第一句。", "第一段第二句。", "第二段。"}, contents(chunks))
+		assertOffsets(t, src, chunks)
+		assert.Equal(t, "doc_1", chunks[1].ID)
+		assert.Equal(t, "zh", chunks[1].MetaData["lang"])
+	})
+
+	t.Run("相邻分块重叠", func(t *testing.T) {
+		s, err := NewRecursiveSplitter(ctx, &RecursiveConfig{ChunkSize: 11, OverlapSize: 5, Separators: []string{" "}})
+		assert.NoError(t, err)
+
+		src := &schema.Document{Content: "aa bb cc dd ee ff gg"}
+		chunks, err := s.Transform(ctx, []*schema.Document{src})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"aa bb cc", "cc dd ee", "ee ff gg"}, contents(chunks))
+		assertOffsets(t, src, chunks)
+		assert.Empty(t, chunks[0].ID)
+	})
+
+	t.Run("无法按分隔符切分时按字符切分", func(t *testing.T) {
+		s, err := NewRecursiveSplitter(ctx, &RecursiveConfig{ChunkSize: 4, LenFunc: func(s string) int { return len(s) }})
+		assert.NoError(t, err)
+
+		src := &schema.Document{Content: "abcdefghij"}
+		chunks, err := s.Transform(ctx, []*schema.Document{src})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"abcd", "efgh", "ij"}, contents(chunks))
+	})
+
+	t.Run("配置校验", func(t *testing.T) {
+		_, err := NewRecursiveSplitter(ctx, &RecursiveConfig{})
+		assert.ErrorContains(t, err, "ChunkSize")
+		_, err = NewRecursiveSplitter(ctx, &RecursiveConfig{ChunkSize: 5, OverlapSize: 5})
+		assert.ErrorContains(t, err, "OverlapSize")
+	})
+}
+
+func TestMarkdownHeaderSplitter(t *testing.T) {
+	ctx := context.Background()
+	src := &schema.Document{ID: "readme", Content: `简介内容
+
+# 安装
+## Linux
+apt install foo
+` + "```sh\n# 这不是标题\n```" + `
+## macOS
+brew install foo
+# 使用
+### 参数
+-v 输出详细日志
+`}
+
+	t.Run("按全部标题切分", func(t *testing.T) {
+		s, err := NewMarkdownHeaderSplitter(ctx, &MarkdownHeaderConfig{})
+		assert.NoError(t, err)
+
+		chunks, err := s.Transform(ctx, []*schema.Document{src})
+		assert.NoError(t, err)
+		assert.Len(t, chunks, 4)
+		assertOffsets(t, src, chunks)
+		assert.Equal(t, "简介内容", chunks[0].Content)
+		assert.Equal(t, []string{}, chunks[0].MetaData[MetaKeyHeaderPath])
+		assert.Equal(t, "## Linux\napt install foo\n```sh\n# 这不是标题\n```", chunks[1].Content)
+		assert.Equal(t, []string{"安装", "Linux"}, chunks[1].MetaData[MetaKeyHeaderPath])
+		assert.Equal(t, []string{"安装", "macOS"}, chunks[2].MetaData[MetaKeyHeaderPath])
+		assert.Equal(t, []string{"使用", "参数"}, chunks[3].MetaData[MetaKeyHeaderPath])
+	})
+
+	t.Run("指定级别并去除标题行", func(t *testing.T) {
+		s, err := NewMarkdownHeaderSplitter(ctx, &MarkdownHeaderConfig{
+			Headers:     map[string]string{"#": "h1"},
+			TrimHeaders: true,
+		})
+		assert.NoError(t, err)
+
+		chunks, err := s.Transform(ctx, []*schema.Document{src})
+		assert.NoError(t, err)
+		assert.Len(t, chunks, 3)
+		assertOffsets(t, src, chunks)
+		assert.True(t, strings.HasPrefix(chunks[1].Content, "## Linux"))
+		assert.Equal(t, "安装", chunks[1].MetaData["h1"])
+		assert.Equal(t, "### 参数\n-v 输出详细日志", chunks[2].Content)
+		assert.Equal(t, "使用", chunks[2].MetaData["h1"])
+	})
+
+	t.Run("非法标题配置", func(t *testing.T) {
+		_, err := NewMarkdownHeaderSplitter(ctx, &MarkdownHeaderConfig{Headers: map[string]string{"h1": "h1"}})
+		assert.Error(t, err)
+	})
+}
+
+func TestTokenSplitter(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("默认分词器", func(t *testing.T) {
+		s, err := NewTokenSplitter(ctx, &TokenConfig{ChunkSize: 4, OverlapSize: 1})
+		assert.NoError(t, err)
+
+		src := &schema.Document{ID: "doc", Content: "hello world, 你好世界 eino"}
+		chunks, err := s.Transform(ctx, []*schema.Document{src})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"hello world, 你", "你好世界", "界 eino"}, contents(chunks))
+		assertOffsets(t, src, chunks)
+	})
+
+	t.Run("自定义分词器", func(t *testing.T) {
+		byteTokenizer := TokenizerFunc(func(_ context.Context, text string) ([]Token, error) {
+			tokens := make([]Token, 0, len(text))
+			for i := range len(text) {
+				tokens = append(tokens, Token{Start: i, End: i + 1})
+			}
+			return tokens, nil
+		})
+		s, err := NewTokenSplitter(ctx, &TokenConfig{Tokenizer: byteTokenizer, ChunkSize: 3})
+		assert.NoError(t, err)
+
+		chunks, err := s.Transform(ctx, []*schema.Document{{Content: "abcdefg"}})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"abc", "def", "g"}, contents(chunks))
+	})
+
+	t.Run("分词失败与非法区间", func(t *testing.T) {
+		s, err := NewTokenSplitter(ctx, &TokenConfig{ChunkSize: 3, Tokenizer: TokenizerFunc(func(context.Context, string) ([]Token, error) {
+			return nil, errors.New("boom")
+		})})
+		assert.NoError(t, err)
+		_, err = s.Transform(ctx, []*schema.Document{{ID: "x", Content: "abc"}})
+		assert.ErrorContains(t, err, "split document[x] failed: tokenize failed: boom")
+
+		s, err = NewTokenSplitter(ctx, &TokenConfig{ChunkSize: 3, Tokenizer: TokenizerFunc(func(context.Context, string) ([]Token, error) {
+			return []Token{{Start: 0, End: 10}}, nil
+		})})
+		assert.NoError(t, err)
+		_, err = s.Transform(ctx, []*schema.Document{{Content: "abc"}})
+		assert.ErrorContains(t, err, "invalid token[0]")
+	})
+}
+
+func TestSplitterCallbacks(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewRecursiveSplitter(ctx, &RecursiveConfig{ChunkSize: 100})
+	assert.NoError(t, err)
+
+	var (
+		info   *callbacks.RunInfo
+		output *document.TransformerCallbackOutput
+	)
+	handler := callbacks.NewHandlerBuilder().
+		OnStartFn(func(ctx context.Context, ri *callbacks.RunInfo, _ callbacks.CallbackInput) context.Context {
+			info = ri
+			return ctx
+		}).
+		OnEndFn(func(ctx context.Context, _ *callbacks.RunInfo, out callbacks.CallbackOutput) context.Context {
+			output = document.ConvTransformerCallbackOutput(out)
+			return ctx
+		}).Build()
+
+	ctx = callbacks.InitCallbacks(ctx, nil, handler)
+	chunks, err := s.Transform(ctx, []*schema.Document{{Content: "hello"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "RecursiveSplitter", info.Type)
+	assert.Equal(t, components.ComponentOfTransformer, info.Component)
+	assert.Equal(t, chunks, output.Output)
+}
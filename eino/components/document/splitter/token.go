@@ -0,0 +1,123 @@
+package splitter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/favbox/eino/components/document"
+)
+
+// Token 文本中的一个 token，Start 与 End 为其字节区间 [Start, End)。
+type Token struct {
+	Start int
+	End   int
+}
+
+// Tokenizer 分词器，返回按位置升序排列且互不重叠的 token。
+type Tokenizer interface {
+	Tokenize(ctx context.Context, text string) ([]Token, error)
+}
+
+// TokenizerFunc 将函数适配为 Tokenizer。
+type TokenizerFunc func(ctx context.Context, text string) ([]Token, error)
+
+func (f TokenizerFunc) Tokenize(ctx context.Context, text string) ([]Token, error) {
+	return f(ctx, text)
+}
+
+// SimpleTokenizer 近似的通用分词器：连续的字母数字为一个 token，其余每个非空白字符为一个 token。
+// 适合在没有模型专用分词器时粗略估计 token 数量。
+var SimpleTokenizer Tokenizer = TokenizerFunc(simpleTokenize)
+
+func simpleTokenize(_ context.Context, text string) ([]Token, error) {
+	var tokens []Token
+	wordStart := -1
+	for pos, r := range text {
+		isWord := r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_')
+		if isWord {
+			if wordStart < 0 {
+				wordStart = pos
+			}
+			continue
+		}
+		if wordStart >= 0 {
+			tokens = append(tokens, Token{Start: wordStart, End: pos})
+			wordStart = -1
+		}
+		if !unicode.IsSpace(r) {
+			tokens = append(tokens, Token{Start: pos, End: pos + utf8.RuneLen(r)})
+		}
+	}
+	if wordStart >= 0 {
+		tokens = append(tokens, Token{Start: wordStart, End: len(text)})
+	}
+	return tokens, nil
+}
+
+// TokenConfig token 切分器配置。
+type TokenConfig struct {
+	// Tokenizer 分词器，默认 SimpleTokenizer。
+	Tokenizer Tokenizer
+	// ChunkSize 每个分块的最大 token 数，必填。
+	ChunkSize int
+	// OverlapSize 相邻分块间重叠的 token 数，需小于 ChunkSize。
+	OverlapSize int
+	// IDGenerator 生成分块 ID，默认源文档有 ID 时为 "{源文档ID}_{序号}"。
+	IDGenerator IDGenerator
+}
+
+// NewTokenSplitter 创建 token 切分器，分块内容为其首个 token 起点到末个 token 终点之间的原文。
+func NewTokenSplitter(_ context.Context, config *TokenConfig) (document.Transformer, error) {
+	if config == nil {
+		return nil, errors.New("token splitter config is required")
+	}
+	if config.ChunkSize <= 0 {
+		return nil, fmt.Errorf("token splitter 'ChunkSize' must be positive, got %d", config.ChunkSize)
+	}
+	if config.OverlapSize < 0 || config.OverlapSize >= config.ChunkSize {
+		return nil, fmt.Errorf("token splitter 'OverlapSize' must be in [0, ChunkSize), got %d", config.OverlapSize)
+	}
+
+	t := &tokenSplitter{
+		tokenizer:   config.Tokenizer,
+		chunkSize:   config.ChunkSize,
+		overlapSize: config.OverlapSize,
+	}
+	if t.tokenizer == nil {
+		t.tokenizer = SimpleTokenizer
+	}
+
+	return newTransformer("TokenSplitter", t.split, config.IDGenerator), nil
+}
+
+type tokenSplitter struct {
+	tokenizer   Tokenizer
+	chunkSize   int
+	overlapSize int
+}
+
+func (t *tokenSplitter) split(ctx context.Context, text string) ([]*chunk, error) {
+	tokens, err := t.tokenizer.Tokenize(ctx, text)
+	if err != nil {
+		return nil, fmt.Errorf("tokenize failed: %w", err)
+	}
+	for i, tok := range tokens {
+		if tok.Start < 0 || tok.End > len(text) || tok.Start > tok.End || (i > 0 && tok.Start < tokens[i-1].End) {
+			return nil, fmt.Errorf("invalid token[%d] span [%d, %d)", i, tok.Start, tok.End)
+		}
+	}
+
+	var chunks []*chunk
+	for i := 0; i < len(tokens); i += t.chunkSize - t.overlapSize {
+		j := min(i+t.chunkSize, len(tokens))
+		chunks = append(chunks, &chunk{start: tokens[i].Start, end: tokens[j-1].End})
+		if j == len(tokens) {
+			break
+		}
+	}
+
+	return chunks, nil
+}
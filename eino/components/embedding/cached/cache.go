@@ -0,0 +1,153 @@
+package cached
+
+import (
+	"container/list"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sync"
+
+	"github.com/favbox/eino/internal/fsutil"
+)
+
+// Cache 向量缓存，键为十六进制的 SHA-256。
+type Cache interface {
+	// MGet 批量读取向量，结果与 keys 一一对应，未命中的为 nil。
+	MGet(ctx context.Context, keys []string) ([][]float64, error)
+	// MSet 批量写入向量。
+	MSet(ctx context.Context, keys []string, vectors [][]float64) error
+}
+
+// LRUCache 容量有限的内存缓存，超出容量时淘汰最久未使用的向量，可并发使用。
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[string]*list.Element
+}
+
+type lruEntry struct {
+	key    string
+	vector []float64
+}
+
+// NewLRUCache 创建容量为 capacity 个向量的 LRU 缓存。
+func NewLRUCache(capacity int) (*LRUCache, error) {
+	if capacity <= 0 {
+		return nil, fmt.Errorf("lru cache capacity must be positive, got %d", capacity)
+	}
+	return &LRUCache{capacity: capacity, ll: list.New(), items: make(map[string]*list.Element)}, nil
+}
+
+func (c *LRUCache) MGet(_ context.Context, keys []string) ([][]float64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	vectors := make([][]float64, len(keys))
+	for i, key := range keys {
+		if el, ok := c.items[key]; ok {
+			c.ll.MoveToFront(el)
+			vectors[i] = append([]float64(nil), el.Value.(*lruEntry).vector...)
+		}
+	}
+	return vectors, nil
+}
+
+func (c *LRUCache) MSet(_ context.Context, keys []string, vectors [][]float64) error {
+	if len(keys) != len(vectors) {
+		return fmt.Errorf("got %d vectors for %d keys", len(vectors), len(keys))
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, key := range keys {
+		vector := append([]float64(nil), vectors[i]...)
+		if el, ok := c.items[key]; ok {
+			el.Value.(*lruEntry).vector = vector
+			c.ll.MoveToFront(el)
+			continue
+		}
+		c.items[key] = c.ll.PushFront(&lruEntry{key: key, vector: vector})
+		if c.ll.Len() > c.capacity {
+			oldest := c.ll.Back()
+			c.ll.Remove(oldest)
+			delete(c.items, oldest.Value.(*lruEntry).key)
+		}
+	}
+	return nil
+}
+
+// Len 返回缓存中的向量数量。
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+// DiskCache 磁盘缓存，每个向量以小端 float64 序列保存为 {Dir}/{键前两位}/{键} 文件。
+// 写入时先写临时文件再重命名，可被多个进程共享。
+type DiskCache struct {
+	dir string
+}
+
+// NewDiskCache 创建磁盘缓存，目录不存在时自动创建。
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if dir == "" {
+		return nil, errors.New("disk cache dir is required")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create disk cache dir failed: %w", err)
+	}
+	return &DiskCache{dir: dir}, nil
+}
+
+func (c *DiskCache) MGet(_ context.Context, keys []string) ([][]float64, error) {
+	vectors := make([][]float64, len(keys))
+	for i, key := range keys {
+		path, err := fsutil.ShardedPath(c.dir, key, "")
+		if err != nil {
+			return nil, err
+		}
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read cache file failed: %w", err)
+		}
+		if len(data)%8 != 0 {
+			return nil, fmt.Errorf("corrupted cache file: %s", path)
+		}
+		vector := make([]float64, len(data)/8)
+		for j := range vector {
+			vector[j] = math.Float64frombits(binary.LittleEndian.Uint64(data[j*8:]))
+		}
+		vectors[i] = vector
+	}
+	return vectors, nil
+}
+
+func (c *DiskCache) MSet(_ context.Context, keys []string, vectors [][]float64) error {
+	if len(keys) != len(vectors) {
+		return fmt.Errorf("got %d vectors for %d keys", len(vectors), len(keys))
+	}
+
+	for i, key := range keys {
+		path, err := fsutil.ShardedPath(c.dir, key, "")
+		if err != nil {
+			return err
+		}
+		data := make([]byte, len(vectors[i])*8)
+		for j, v := range vectors[i] {
+			binary.LittleEndian.PutUint64(data[j*8:], math.Float64bits(v))
+		}
+		if err = fsutil.WriteFileAtomic(path, data); err != nil {
+			return fmt.Errorf("write cache file failed: %w", err)
+		}
+	}
+	return nil
+}
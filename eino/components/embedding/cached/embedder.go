@@ -0,0 +1,306 @@
+// Package cached 提供带缓存与分批能力的 embedding.Embedder 包装器。
+//
+// 输入文本先按 (模型名, 文本) 的哈希查询缓存，未命中的文本去重后按 BatchSize 分批，
+// 以不超过 MaxConcurrency 的并发调用被包装的 Embedder，每批失败时按 MaxRetries 重试，
+// 结果写回缓存。缓存命中与未命中的数量通过 embedding.CallbackOutput 的 Extra 上报。
+//
+// 示例：
+//
+//	cache, _ := cached.NewDiskCache("./.embedding_cache")
+//	emb, _ := cached.NewEmbedder(ctx, &cached.Config{
+//		Embedder:       openaiEmbedder,
+//		Model:          "text-embedding-3-small",
+//		Cache:          cache,
+//		BatchSize:      64,
+//		MaxConcurrency: 4,
+//		MaxRetries:     2,
+//	})
+package cached
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/favbox/eino/callbacks"
+	"github.com/favbox/eino/components"
+	"github.com/favbox/eino/components/embedding"
+	"github.com/favbox/eino/internal/delegate"
+	"github.com/favbox/eino/internal/safe"
+)
+
+const (
+	// ExtraKeyCacheHits 回调输出 Extra 中缓存命中数的键，值为 int。
+	ExtraKeyCacheHits = "embedding_cache_hits"
+	// ExtraKeyCacheMisses 回调输出 Extra 中缓存未命中数的键，值为 int。
+	ExtraKeyCacheMisses = "embedding_cache_misses"
+	// ExtraKeyCacheError 回调输出 Extra 中缓存读写错误的键，值为 string。
+	ExtraKeyCacheError = "embedding_cache_error"
+
+	defaultBatchSize = 32
+)
+
+// Config 包装器配置。
+type Config struct {
+	// Embedder 被包装的嵌入模型，必填。调用选项会原样传给它。
+	Embedder embedding.Embedder
+	// Model 未通过 embedding.WithModel 指定模型时，缓存键使用的模型名。
+	// 应与 Embedder 的默认模型一致，避免不同模型的向量相互污染。
+	Model string
+	// Cache 向量缓存，为空时不使用缓存。缓存读写失败不会中断向量化，错误记录在回调输出中。
+	Cache Cache
+	// BatchSize 每批的最大文本数，默认 32。
+	BatchSize int
+	// MaxConcurrency 同时进行的最大批次数，默认 1。
+	MaxConcurrency int
+	// MaxRetries 每批失败后的最大重试次数，默认不重试。
+	MaxRetries int
+	// RetryBackoff 返回第 attempt 次重试（从 1 开始）前的等待时间，默认 100ms * 2^(attempt-1)。
+	RetryBackoff func(attempt int) time.Duration
+}
+
+// NewEmbedder 创建带缓存与分批能力的 Embedder。
+func NewEmbedder(_ context.Context, config *Config) (embedding.Embedder, error) {
+	if config == nil {
+		return nil, errors.New("cached embedder config is required")
+	}
+	if config.Embedder == nil {
+		return nil, errors.New("cached embedder 'Embedder' is required")
+	}
+	if config.BatchSize < 0 || config.MaxConcurrency < 0 || config.MaxRetries < 0 {
+		return nil, errors.New("cached embedder 'BatchSize', 'MaxConcurrency' and 'MaxRetries' must not be negative")
+	}
+
+	e := &cachedEmbedder{
+		embedder:       config.Embedder,
+		model:          config.Model,
+		cache:          config.Cache,
+		batchSize:      config.BatchSize,
+		maxConcurrency: config.MaxConcurrency,
+		maxRetries:     config.MaxRetries,
+		retryBackoff:   config.RetryBackoff,
+	}
+	if e.batchSize == 0 {
+		e.batchSize = defaultBatchSize
+	}
+	if e.maxConcurrency == 0 {
+		e.maxConcurrency = 1
+	}
+	if e.retryBackoff == nil {
+		e.retryBackoff = defaultRetryBackoff
+	}
+
+	return e, nil
+}
+
+type cachedEmbedder struct {
+	embedder       embedding.Embedder
+	model          string
+	cache          Cache
+	batchSize      int
+	maxConcurrency int
+	maxRetries     int
+	retryBackoff   func(attempt int) time.Duration
+}
+
+func (e *cachedEmbedder) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) (embeddings [][]float64, err error) {
+	model := e.model
+	if m := embedding.GetCommonOptions(&embedding.Options{}, opts...).Model; m != nil {
+		model = *m
+	}
+	conf := &embedding.Config{Model: model}
+
+	ctx = callbacks.EnsureRunInfo(ctx, e.GetType(), components.ComponentOfEmbedding)
+	ctx = callbacks.OnStart(ctx, &embedding.CallbackInput{Texts: texts, Config: conf})
+	defer func() {
+		if err != nil {
+			_ = callbacks.OnError(ctx, err)
+		}
+	}()
+
+	extra := make(map[string]any, 3)
+	embeddings = make([][]float64, len(texts))
+	keys := make([]string, len(texts))
+	for i, text := range texts {
+		keys[i] = cacheKey(model, text)
+	}
+
+	if e.cache != nil {
+		cachedVectors, cacheErr := e.cache.MGet(ctx, keys)
+		switch {
+		case cacheErr != nil:
+			extra[ExtraKeyCacheError] = cacheErr.Error()
+		case len(cachedVectors) != len(keys):
+			extra[ExtraKeyCacheError] = fmt.Sprintf("cache returned %d vectors for %d keys", len(cachedVectors), len(keys))
+		default:
+			copy(embeddings, cachedVectors)
+		}
+	}
+
+	// 未命中的文本去重后再向量化
+	var (
+		missTexts []string
+		missKeys  []string
+		positions = make(map[string][]int)
+		hits      int
+	)
+	for i, key := range keys {
+		if embeddings[i] != nil {
+			hits++
+			continue
+		}
+		if _, ok := positions[key]; !ok {
+			missTexts = append(missTexts, texts[i])
+			missKeys = append(missKeys, key)
+		}
+		positions[key] = append(positions[key], i)
+	}
+	extra[ExtraKeyCacheHits] = hits
+	extra[ExtraKeyCacheMisses] = len(texts) - hits
+
+	if len(missTexts) > 0 {
+		vectors, err := e.embedBatches(ctx, missTexts, opts)
+		if err != nil {
+			return nil, err
+		}
+		for i, key := range missKeys {
+			for _, pos := range positions[key] {
+				embeddings[pos] = vectors[i]
+			}
+		}
+		if e.cache != nil {
+			if cacheErr := e.cache.MSet(ctx, missKeys, vectors); cacheErr != nil {
+				extra[ExtraKeyCacheError] = cacheErr.Error()
+			}
+		}
+	}
+
+	_ = callbacks.OnEnd(ctx, &embedding.CallbackOutput{Embeddings: embeddings, Config: conf, Extra: extra})
+
+	return embeddings, nil
+}
+
+// embedBatches 分批并发向量化，任一批次在重试后仍失败即取消其余批次并返回错误。
+func (e *cachedEmbedder) embedBatches(ctx context.Context, texts []string, opts []embedding.Option) ([][]float64, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	vectors := make([][]float64, len(texts))
+	sem := make(chan struct{}, e.maxConcurrency)
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	for start := 0; start < len(texts); start += e.batchSize {
+		end := min(start+e.batchSize, len(texts))
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			var err error
+			defer func() {
+				if panicErr := recover(); panicErr != nil {
+					err = safe.NewPanicErr(panicErr, debug.Stack())
+				}
+				if err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = fmt.Errorf("embed batch [%d, %d) failed: %w", start, end, err)
+					}
+					mu.Unlock()
+					cancel()
+				}
+			}()
+
+			var batch [][]float64
+			batch, err = e.embedWithRetry(ctx, texts[start:end], opts)
+			if err == nil {
+				copy(vectors[start:end], batch)
+			}
+		}(start, end)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return vectors, nil
+}
+
+// embedWithRetry 向量化单个批次，失败时按退避时间重试。
+func (e *cachedEmbedder) embedWithRetry(ctx context.Context, texts []string, opts []embedding.Option) ([][]float64, error) {
+	for attempt := 0; ; attempt++ {
+		vectors, err := e.embed(ctx, texts, opts)
+		if err == nil && len(vectors) != len(texts) {
+			err = fmt.Errorf("embedder returned %d vectors for %d texts", len(vectors), len(texts))
+		}
+		if err == nil {
+			return vectors, nil
+		}
+		if attempt >= e.maxRetries || ctx.Err() != nil {
+			return nil, err
+		}
+
+		timer := time.NewTimer(e.retryBackoff(attempt + 1))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+	}
+}
+
+// embed 调用被包装的 Embedder。
+func (e *cachedEmbedder) embed(ctx context.Context, texts []string, opts []embedding.Option) ([][]float64, error) {
+	return delegate.Invoke(ctx, e.embedder, &callbacks.RunInfo{Component: components.ComponentOfEmbedding},
+		&embedding.CallbackInput{Texts: texts},
+		func(ctx context.Context) ([][]float64, error) {
+			return e.embedder.EmbedStrings(ctx, texts, opts...)
+		},
+		func(vectors [][]float64) callbacks.CallbackOutput {
+			return &embedding.CallbackOutput{Embeddings: vectors}
+		})
+}
+
+// GetType 返回组件类型名称，用于回调的 RunInfo。
+func (e *cachedEmbedder) GetType() string {
+	return "Cached"
+}
+
+// IsCallbacksEnabled 带缓存的 Embedder 自行触发回调，编排框架无需再包装。
+func (e *cachedEmbedder) IsCallbacksEnabled() bool {
+	return true
+}
+
+// cacheKey 以模型名与文本的 SHA-256 作为缓存键。
+func cacheKey(model, text string) string {
+	h := sha256.New()
+	h.Write([]byte(model))
+	h.Write([]byte{0})
+	h.Write([]byte(text))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func defaultRetryBackoff(attempt int) time.Duration {
+	return 100 * time.Millisecond << (attempt - 1)
+}
@@ -0,0 +1,172 @@
+package cached
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/favbox/eino/callbacks"
+	"github.com/favbox/eino/components/embedding"
+)
+
+// countingEmbedder 以文本长度与模型名长度生成向量，并记录每次调用的批次。
+type countingEmbedder struct {
+	mu       sync.Mutex
+	batches  [][]string
+	failures int32 // 前 failures 次调用返回错误
+	calls    int32
+	inflight int32
+	peak     int32
+}
+
+func (c *countingEmbedder) EmbedStrings(_ context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	n := atomic.AddInt32(&c.inflight, 1)
+	defer atomic.AddInt32(&c.inflight, -1)
+	for {
+		peak := atomic.LoadInt32(&c.peak)
+		if n <= peak || atomic.CompareAndSwapInt32(&c.peak, peak, n) {
+			break
+		}
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	if atomic.AddInt32(&c.calls, 1) <= atomic.LoadInt32(&c.failures) {
+		return nil, errors.New("rate limited")
+	}
+
+	model := ""
+	if m := embedding.GetCommonOptions(&embedding.Options{}, opts...).Model; m != nil {
+		model = *m
+	}
+	c.mu.Lock()
+	c.batches = append(c.batches, texts)
+	c.mu.Unlock()
+
+	vectors := make([][]float64, len(texts))
+	for i, text := range texts {
+		vectors[i] = []float64{float64(len(text)), float64(len(model))}
+	}
+	return vectors, nil
+}
+
+func TestCachedEmbedder(t *testing.T) {
+	ctx := context.Background()
+	noBackoff := func(int) time.Duration { return 0 }
+
+	t.Run("分批并发", func(t *testing.T) {
+		inner := &countingEmbedder{}
+		e, err := NewEmbedder(ctx, &Config{Embedder: inner, BatchSize: 2, MaxConcurrency: 2})
+		assert.NoError(t, err)
+
+		vectors, err := e.EmbedStrings(ctx, []string{"a", "bb", "ccc", "dddd", "eeeee"})
+		assert.NoError(t, err)
+		assert.Equal(t, [][]float64{{1, 0}, {2, 0}, {3, 0}, {4, 0}, {5, 0}}, vectors)
+		assert.Len(t, inner.batches, 3)
+		assert.LessOrEqual(t, inner.peak, int32(2))
+	})
+
+	t.Run("缓存命中与回调统计", func(t *testing.T) {
+		inner := &countingEmbedder{}
+		cache, err := NewLRUCache(10)
+		assert.NoError(t, err)
+		e, err := NewEmbedder(ctx, &Config{Embedder: inner, Cache: cache, Model: "m1"})
+		assert.NoError(t, err)
+
+		var output *embedding.CallbackOutput
+		handler := callbacks.NewHandlerBuilder().OnEndFn(func(ctx context.Context, info *callbacks.RunInfo, out callbacks.CallbackOutput) context.Context {
+			if info.Type == "Cached" {
+				output = embedding.ConvCallbackOutput(out)
+			}
+			return ctx
+		}).Build()
+		ctx := callbacks.InitCallbacks(ctx, nil, handler)
+
+		_, err = e.EmbedStrings(ctx, []string{"a", "b", "a"})
+		assert.NoError(t, err)
+		assert.Equal(t, [][]string{{"a", "b"}}, inner.batches, "重复文本只向量化一次")
+		assert.Equal(t, 0, output.Extra[ExtraKeyCacheHits])
+		assert.Equal(t, 3, output.Extra[ExtraKeyCacheMisses])
+		assert.Equal(t, "m1", output.Config.Model)
+
+		vectors, err := e.EmbedStrings(ctx, []string{"b", "cc"})
+		assert.NoError(t, err)
+		assert.Equal(t, [][]float64{{1, 0}, {2, 0}}, vectors)
+		assert.Equal(t, []string{"cc"}, inner.batches[1])
+		assert.Equal(t, 1, output.Extra[ExtraKeyCacheHits])
+		assert.Equal(t, 1, output.Extra[ExtraKeyCacheMisses])
+
+		// 不同模型的缓存互不影响
+		vectors, err = e.EmbedStrings(ctx, []string{"b"}, embedding.WithModel("m22"))
+		assert.NoError(t, err)
+		assert.Equal(t, [][]float64{{1, 3}}, vectors)
+		assert.Equal(t, 0, output.Extra[ExtraKeyCacheHits])
+	})
+
+	t.Run("失败重试", func(t *testing.T) {
+		inner := &countingEmbedder{failures: 2}
+		e, err := NewEmbedder(ctx, &Config{Embedder: inner, MaxRetries: 2, RetryBackoff: noBackoff})
+		assert.NoError(t, err)
+
+		vectors, err := e.EmbedStrings(ctx, []string{"a"})
+		assert.NoError(t, err)
+		assert.Equal(t, [][]float64{{1, 0}}, vectors)
+		assert.Equal(t, int32(3), inner.calls)
+
+		inner = &countingEmbedder{failures: 5}
+		e, err = NewEmbedder(ctx, &Config{Embedder: inner, MaxRetries: 1, RetryBackoff: noBackoff})
+		assert.NoError(t, err)
+		_, err = e.EmbedStrings(ctx, []string{"a"})
+		assert.ErrorContains(t, err, "embed batch [0, 1) failed: rate limited")
+		assert.Equal(t, int32(2), inner.calls)
+	})
+
+	t.Run("配置校验", func(t *testing.T) {
+		_, err := NewEmbedder(ctx, &Config{})
+		assert.ErrorContains(t, err, "Embedder")
+		_, err = NewEmbedder(ctx, &Config{Embedder: &countingEmbedder{}, BatchSize: -1})
+		assert.Error(t, err)
+	})
+}
+
+func TestCaches(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("LRU 淘汰", func(t *testing.T) {
+		c, err := NewLRUCache(2)
+		assert.NoError(t, err)
+		assert.NoError(t, c.MSet(ctx, []string{"k1", "k2"}, [][]float64{{1}, {2}}))
+		_, _ = c.MGet(ctx, []string{"k1"})
+		assert.NoError(t, c.MSet(ctx, []string{"k3"}, [][]float64{{3}}))
+
+		vectors, err := c.MGet(ctx, []string{"k1", "k2", "k3"})
+		assert.NoError(t, err)
+		assert.Equal(t, [][]float64{{1}, nil, {3}}, vectors)
+		assert.Equal(t, 2, c.Len())
+
+		_, err = NewLRUCache(0)
+		assert.Error(t, err)
+	})
+
+	t.Run("磁盘缓存", func(t *testing.T) {
+		dir := t.TempDir()
+		c, err := NewDiskCache(dir)
+		assert.NoError(t, err)
+
+		key := cacheKey("m", "text")
+		assert.NoError(t, c.MSet(ctx, []string{key}, [][]float64{{0.5, -1.25}}))
+
+		reopened, err := NewDiskCache(dir)
+		assert.NoError(t, err)
+		vectors, err := reopened.MGet(ctx, []string{key, cacheKey("m", "other")})
+		assert.NoError(t, err)
+		assert.Equal(t, [][]float64{{0.5, -1.25}, nil}, vectors)
+
+		_, err = c.MGet(ctx, []string{"../x"})
+		assert.ErrorContains(t, err, "invalid cache key")
+	})
+}
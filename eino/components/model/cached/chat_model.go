@@ -0,0 +1,322 @@
+// Package cached 提供带响应缓存的 model.BaseChatModel 包装器。
+//
+// 缓存键由命名空间、规范化后的输入消息与影响输出的调用选项（模型名、Temperature、TopP、MaxTokens、
+// Stop、工具与 ToolChoice）共同计算。Stream 命中缓存时，将缓存的完整消息重新拆分为数据块返回；
+// 未命中时透传被包装模型的流，完整读取后再写入缓存。
+//
+// 是否命中通过 model.CallbackOutput 的 Extra 上报，参见 ExtraKeyCacheHit。
+//
+// 示例：
+//
+//	store, _ := cached.NewFileStore("./.llm_cache")
+//	cm, _ := cached.NewChatModel(ctx, &cached.Config{
+//		Model:     openaiModel,
+//		Store:     store,
+//		Namespace: "eval-v2",
+//		TTL:       24 * time.Hour,
+//	})
+//	msg, _ := cm.Generate(ctx, msgs, cached.WithBypass()) // 跳过读取，强制刷新缓存
+package cached
+
+import (
+	"context"
+	"errors"
+	"io"
+	"runtime/debug"
+	"time"
+
+	"github.com/favbox/eino/callbacks"
+	"github.com/favbox/eino/components"
+	"github.com/favbox/eino/components/model"
+	"github.com/favbox/eino/internal/delegate"
+	"github.com/favbox/eino/internal/modelmsg"
+	"github.com/favbox/eino/internal/safe"
+	"github.com/favbox/eino/schema"
+)
+
+const (
+	// ExtraKeyCacheHit 回调输出 Extra 中是否命中缓存的键，值为 bool。
+	ExtraKeyCacheHit = "chat_model_cache_hit"
+	// ExtraKeyCacheKey 回调输出 Extra 中缓存键的键，值为 string。
+	ExtraKeyCacheKey = "chat_model_cache_key"
+	// ExtraKeyCacheError 回调输出 Extra 中缓存读写错误的键，值为 string。
+	ExtraKeyCacheError = "chat_model_cache_error"
+)
+
+// Config 包装器配置。
+type Config struct {
+	// Model 被包装的聊天模型，必填。
+	// 若其同时实现 model.ToolCallingChatModel，返回的包装器也实现该接口。
+	Model model.BaseChatModel
+	// Store 缓存存储，默认使用 NewMemoryStore。缓存读写失败不会中断调用，错误记录在回调输出中。
+	Store Store
+	// Namespace 缓存命名空间，用于隔离不同的模型、提示词版本或实验。
+	Namespace string
+	// TTL 缓存有效期，默认永不过期。
+	TTL time.Duration
+	// ChunkSize Stream 命中缓存时每个数据块的最大字符数，小于等于 0 时整条消息作为单个数据块。
+	ChunkSize int
+}
+
+// cacheOptions 是缓存包装器的实现特定选项。
+type cacheOptions struct {
+	bypass bool
+}
+
+// WithBypass 跳过本次调用的缓存读取，被包装模型的响应仍会写入缓存。
+func WithBypass() model.Option {
+	return model.WrapImplSpecificOptFn(func(o *cacheOptions) {
+		o.bypass = true
+	})
+}
+
+// NewChatModel 创建带响应缓存的聊天模型。
+func NewChatModel(_ context.Context, config *Config) (model.BaseChatModel, error) {
+	if config == nil {
+		return nil, errors.New("cached chat model config is required")
+	}
+	if config.Model == nil {
+		return nil, errors.New("cached chat model 'Model' is required")
+	}
+	if config.TTL < 0 {
+		return nil, errors.New("cached chat model 'TTL' must not be negative")
+	}
+
+	cm := &chatModel{
+		model:     config.Model,
+		store:     config.Store,
+		namespace: config.Namespace,
+		ttl:       config.TTL,
+		chunkSize: config.ChunkSize,
+	}
+	if cm.store == nil {
+		cm.store = NewMemoryStore()
+	}
+	if _, ok := config.Model.(model.ToolCallingChatModel); ok {
+		return &toolCallingChatModel{chatModel: cm}, nil
+	}
+
+	return cm, nil
+}
+
+type chatModel struct {
+	model     model.BaseChatModel
+	tools     []*schema.ToolInfo
+	store     Store
+	namespace string
+	ttl       time.Duration
+	chunkSize int
+}
+
+func (c *chatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (outMsg *schema.Message, err error) {
+	options := model.GetCommonOptions(&model.Options{Tools: c.tools}, opts...)
+	cbInput := modelmsg.CallbackInput(input, options)
+	ctx = callbacks.EnsureRunInfo(ctx, c.GetType(), components.ComponentOfChatModel)
+	ctx = callbacks.OnStart(ctx, cbInput)
+	defer func() {
+		if err != nil {
+			_ = callbacks.OnError(ctx, err)
+		}
+	}()
+
+	key, err := c.cacheKey(input, options)
+	if err != nil {
+		return nil, err
+	}
+	extra := map[string]any{ExtraKeyCacheKey: key, ExtraKeyCacheHit: false}
+
+	if cachedMsg := c.lookup(ctx, key, opts, extra); cachedMsg != nil {
+		extra[ExtraKeyCacheHit] = true
+		_ = callbacks.OnEnd(ctx, &model.CallbackOutput{Message: cachedMsg, Config: cbInput.Config, Extra: extra})
+		return cachedMsg, nil
+	}
+
+	outMsg, err = c.generate(ctx, input, opts, cbInput)
+	if err != nil {
+		return nil, err
+	}
+	if setErr := c.store.Set(ctx, key, outMsg, c.ttl); setErr != nil {
+		extra[ExtraKeyCacheError] = setErr.Error()
+	}
+
+	_ = callbacks.OnEnd(ctx, &model.CallbackOutput{Message: outMsg, Config: cbInput.Config, TokenUsage: tokenUsage(outMsg), Extra: extra})
+
+	return outMsg, nil
+}
+
+func (c *chatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (outStream *schema.StreamReader[*schema.Message], err error) {
+	options := model.GetCommonOptions(&model.Options{Tools: c.tools}, opts...)
+	cbInput := modelmsg.CallbackInput(input, options)
+	ctx = callbacks.EnsureRunInfo(ctx, c.GetType(), components.ComponentOfChatModel)
+	ctx = callbacks.OnStart(ctx, cbInput)
+	defer func() {
+		if err != nil {
+			_ = callbacks.OnError(ctx, err)
+		}
+	}()
+
+	key, err := c.cacheKey(input, options)
+	if err != nil {
+		return nil, err
+	}
+	extra := map[string]any{ExtraKeyCacheKey: key, ExtraKeyCacheHit: false}
+
+	if cachedMsg := c.lookup(ctx, key, opts, extra); cachedMsg != nil {
+		extra[ExtraKeyCacheHit] = true
+		outStream = schema.StreamReaderFromArray(modelmsg.Chunk(cachedMsg, c.chunkSize))
+	} else {
+		sr, err := c.stream(ctx, input, opts, cbInput)
+		if err != nil {
+			return nil, err
+		}
+		outStream = c.storeOnDone(ctx, key, sr)
+	}
+
+	hit := extra[ExtraKeyCacheHit].(bool)
+	srs := outStream.Copy(2)
+	cbStream := schema.StreamReaderWithConvert(srs[0], func(msg *schema.Message) (callbacks.CallbackOutput, error) {
+		out := &model.CallbackOutput{Message: msg, Config: cbInput.Config, Extra: extra}
+		if !hit {
+			out.TokenUsage = tokenUsage(msg)
+		}
+		return out, nil
+	})
+	_, _ = callbacks.OnEndWithStreamOutput(ctx, cbStream)
+
+	return srs[1], nil
+}
+
+// GetType 返回组件类型名称，用于回调的 RunInfo。
+func (c *chatModel) GetType() string {
+	return "Cached"
+}
+
+// IsCallbacksEnabled 包装器自行触发回调，编排框架无需再包装。
+func (c *chatModel) IsCallbacksEnabled() bool {
+	return true
+}
+
+type toolCallingChatModel struct {
+	*chatModel
+}
+
+func (t *toolCallingChatModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	bound, err := t.model.(model.ToolCallingChatModel).WithTools(tools)
+	if err != nil {
+		return nil, err
+	}
+
+	nc := *t.chatModel
+	nc.model = bound
+	nc.tools = tools
+	return &toolCallingChatModel{chatModel: &nc}, nil
+}
+
+// cacheKey 以命名空间、规范化后的输入消息与影响输出的调用选项计算缓存键。
+func (c *chatModel) cacheKey(input []*schema.Message, options *model.Options) (string, error) {
+	tools, err := modelmsg.NormalizeTools(options.Tools)
+	if err != nil {
+		return "", err
+	}
+	return modelmsg.Hash(struct {
+		Namespace   string             `json:"namespace"`
+		Messages    []modelmsg.Message `json:"messages"`
+		Model       *string            `json:"model,omitempty"`
+		Temperature *float32           `json:"temperature,omitempty"`
+		TopP        *float32           `json:"top_p,omitempty"`
+		MaxTokens   *int               `json:"max_tokens,omitempty"`
+		Stop        []string           `json:"stop,omitempty"`
+		Tools       []modelmsg.Tool    `json:"tools,omitempty"`
+		ToolChoice  *schema.ToolChoice `json:"tool_choice,omitempty"`
+	}{
+		Namespace:   c.namespace,
+		Messages:    modelmsg.NormalizeMessages(input),
+		Model:       options.Model,
+		Temperature: options.Temperature,
+		TopP:        options.TopP,
+		MaxTokens:   options.MaxTokens,
+		Stop:        options.Stop,
+		Tools:       tools,
+		ToolChoice:  options.ToolChoice,
+	})
+}
+
+// lookup 读取缓存，跳过读取或读取失败时返回 nil，失败原因记录在 extra 中。
+func (c *chatModel) lookup(ctx context.Context, key string, opts []model.Option, extra map[string]any) *schema.Message {
+	if model.GetImplSpecificOptions(&cacheOptions{}, opts...).bypass {
+		return nil
+	}
+	msg, err := c.store.Get(ctx, key)
+	if err != nil {
+		extra[ExtraKeyCacheError] = err.Error()
+		return nil
+	}
+	return msg
+}
+
+// generate 调用被包装模型的 Generate。
+func (c *chatModel) generate(ctx context.Context, input []*schema.Message, opts []model.Option, cbInput *model.CallbackInput) (*schema.Message, error) {
+	return delegate.Invoke(ctx, c.model, &callbacks.RunInfo{Component: components.ComponentOfChatModel}, cbInput,
+		func(ctx context.Context) (*schema.Message, error) {
+			return c.model.Generate(ctx, input, opts...)
+		},
+		func(msg *schema.Message) callbacks.CallbackOutput {
+			return &model.CallbackOutput{Message: msg, Config: cbInput.Config, TokenUsage: tokenUsage(msg)}
+		})
+}
+
+// stream 调用被包装模型的 Stream。
+func (c *chatModel) stream(ctx context.Context, input []*schema.Message, opts []model.Option, cbInput *model.CallbackInput) (*schema.StreamReader[*schema.Message], error) {
+	return delegate.Stream(ctx, c.model, &callbacks.RunInfo{Component: components.ComponentOfChatModel}, cbInput,
+		func(ctx context.Context) (*schema.StreamReader[*schema.Message], error) {
+			return c.model.Stream(ctx, input, opts...)
+		},
+		func(msg *schema.Message) callbacks.CallbackOutput {
+			return &model.CallbackOutput{Message: msg, Config: cbInput.Config, TokenUsage: tokenUsage(msg)}
+		})
+}
+
+// storeOnDone 透传流式响应，完整读取后合并数据块写入缓存；流出错或被提前关闭时不写入。
+func (c *chatModel) storeOnDone(ctx context.Context, key string, sr *schema.StreamReader[*schema.Message]) *schema.StreamReader[*schema.Message] {
+	out, sw := schema.Pipe[*schema.Message](1)
+	go func() {
+		defer func() {
+			if panicErr := recover(); panicErr != nil {
+				_ = sw.Send(nil, safe.NewPanicErr(panicErr, debug.Stack()))
+			}
+			sr.Close()
+			sw.Close()
+		}()
+
+		var chunks []*schema.Message
+		for {
+			chunk, err := sr.Recv()
+			if errors.Is(err, io.EOF) {
+				if msg, err := schema.ConcatMessages(chunks); err == nil {
+					_ = c.store.Set(context.WithoutCancel(ctx), key, msg, c.ttl)
+				}
+				return
+			}
+			if err == nil {
+				chunks = append(chunks, chunk)
+			}
+			if sw.Send(chunk, err) || err != nil {
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+func tokenUsage(msg *schema.Message) *model.TokenUsage {
+	if msg == nil || msg.ResponseMeta == nil || msg.ResponseMeta.Usage == nil {
+		return nil
+	}
+	usage := msg.ResponseMeta.Usage
+	return &model.TokenUsage{
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		TotalTokens:      usage.TotalTokens,
+	}
+}
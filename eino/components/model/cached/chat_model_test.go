@@ -0,0 +1,258 @@
+package cached
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/favbox/eino/callbacks"
+	"github.com/favbox/eino/components/model"
+	"github.com/favbox/eino/components/model/modeltest"
+	"github.com/favbox/eino/schema"
+)
+
+func readAll(t *testing.T, sr *schema.StreamReader[*schema.Message]) *schema.Message {
+	defer sr.Close()
+	var chunks []*schema.Message
+	for {
+		chunk, err := sr.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		chunks = append(chunks, chunk)
+	}
+	msg, err := schema.ConcatMessages(chunks)
+	require.NoError(t, err)
+	return msg
+}
+
+// hitRecorder 记录缓存包装器每次调用上报的命中状态。
+type hitRecorder struct {
+	mu   sync.Mutex
+	hits []bool
+}
+
+func (r *hitRecorder) handler() callbacks.Handler {
+	record := func(info *callbacks.RunInfo, output callbacks.CallbackOutput) {
+		if info.Type != "Cached" {
+			return
+		}
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.hits = append(r.hits, model.ConvCallbackOutput(output).Extra[ExtraKeyCacheHit].(bool))
+	}
+	return callbacks.NewHandlerBuilder().
+		OnEndFn(func(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+			record(info, output)
+			return ctx
+		}).
+		OnEndWithStreamOutputFn(func(ctx context.Context, info *callbacks.RunInfo, output *schema.StreamReader[callbacks.CallbackOutput]) context.Context {
+			defer output.Close()
+			if chunk, err := output.Recv(); err == nil {
+				record(info, chunk)
+			}
+			for {
+				if _, err := output.Recv(); err != nil {
+					return ctx
+				}
+			}
+		}).
+		Build()
+}
+
+func (r *hitRecorder) get() []bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]bool(nil), r.hits...)
+}
+
+func TestChatModel(t *testing.T) {
+	input := []*schema.Message{schema.SystemMessage("你是助手"), schema.UserMessage("你好")}
+
+	t.Run("命中与选项区分", func(t *testing.T) {
+		upstream := modeltest.NewScriptedChatModel(&modeltest.ScriptedConfig{Responses: []*schema.Message{
+			schema.AssistantMessage("first", nil),
+			schema.AssistantMessage("second", nil),
+			schema.AssistantMessage("third", nil),
+		}})
+		cm, err := NewChatModel(context.Background(), &Config{Model: upstream, Namespace: "test"})
+		require.NoError(t, err)
+		rec := &hitRecorder{}
+		ctx := callbacks.InitCallbacks(context.Background(), nil, rec.handler())
+
+		msg, err := cm.Generate(ctx, input)
+		require.NoError(t, err)
+		assert.Equal(t, "first", msg.Content)
+
+		// 首尾空白不影响命中
+		msg, err = cm.Generate(ctx, []*schema.Message{schema.SystemMessage("你是助手 "), schema.UserMessage("你好")})
+		require.NoError(t, err)
+		assert.Equal(t, "first", msg.Content)
+
+		msg, err = cm.Generate(ctx, input, model.WithTemperature(0.1))
+		require.NoError(t, err)
+		assert.Equal(t, "second", msg.Content)
+
+		msg, err = cm.Generate(ctx, input, WithBypass())
+		require.NoError(t, err)
+		assert.Equal(t, "third", msg.Content)
+		msg, err = cm.Generate(ctx, input)
+		require.NoError(t, err)
+		assert.Equal(t, "third", msg.Content)
+
+		assert.Equal(t, []bool{false, true, false, false, true}, rec.get())
+		assert.Equal(t, 0, upstream.Remaining())
+	})
+
+	t.Run("流式命中重新拆分", func(t *testing.T) {
+		upstream := modeltest.NewScriptedChatModel(&modeltest.ScriptedConfig{
+			Responses: []*schema.Message{schema.AssistantMessage("hello world", nil)},
+			ChunkSize: 3,
+		})
+		cm, err := NewChatModel(context.Background(), &Config{Model: upstream, ChunkSize: 2})
+		require.NoError(t, err)
+		rec := &hitRecorder{}
+		ctx := callbacks.InitCallbacks(context.Background(), nil, rec.handler())
+
+		sr, err := cm.Stream(ctx, input)
+		require.NoError(t, err)
+		assert.Equal(t, "hello world", readAll(t, sr).Content)
+
+		sr, err = cm.Stream(ctx, input)
+		require.NoError(t, err)
+		first, err := sr.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, "he", first.Content)
+		sr.Close()
+
+		msg, err := cm.Generate(ctx, input)
+		require.NoError(t, err)
+		assert.Equal(t, "hello world", msg.Content)
+
+		assert.Eventually(t, func() bool { return len(rec.get()) == 3 }, time.Second, 10*time.Millisecond)
+		assert.Equal(t, []bool{false, true, true}, rec.get())
+	})
+
+	t.Run("工具参与缓存键", func(t *testing.T) {
+		upstream := modeltest.NewScriptedChatModel(&modeltest.ScriptedConfig{Responses: []*schema.Message{
+			schema.AssistantMessage("no tools", nil),
+			schema.AssistantMessage("with tools", nil),
+		}})
+		cm, err := NewChatModel(context.Background(), &Config{Model: upstream})
+		require.NoError(t, err)
+		tcm, ok := cm.(model.ToolCallingChatModel)
+		require.True(t, ok)
+		bound, err := tcm.WithTools([]*schema.ToolInfo{{Name: "search"}})
+		require.NoError(t, err)
+
+		msg, err := cm.Generate(context.Background(), input)
+		require.NoError(t, err)
+		assert.Equal(t, "no tools", msg.Content)
+		msg, err = bound.Generate(context.Background(), input)
+		require.NoError(t, err)
+		assert.Equal(t, "with tools", msg.Content)
+		msg, err = cm.Generate(context.Background(), input)
+		require.NoError(t, err)
+		assert.Equal(t, "no tools", msg.Content)
+	})
+
+	t.Run("多参数工具稳定命中缓存", func(t *testing.T) {
+		upstream := modeltest.NewScriptedChatModel(&modeltest.ScriptedConfig{Responses: []*schema.Message{
+			schema.AssistantMessage("first", nil),
+			schema.AssistantMessage("second", nil),
+		}})
+		cm, err := NewChatModel(context.Background(), &Config{Model: upstream})
+		require.NoError(t, err)
+		bound, err := cm.(model.ToolCallingChatModel).WithTools([]*schema.ToolInfo{{
+			Name: "search_order",
+			ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+				"order_id": {Type: schema.String, Required: true},
+				"user_id":  {Type: schema.String, Required: true},
+				"page":     {Type: schema.Integer, Required: true},
+				"status":   {Type: schema.String},
+			}),
+		}})
+		require.NoError(t, err)
+
+		for i := 0; i < 20; i++ {
+			msg, err := bound.Generate(context.Background(), input)
+			require.NoError(t, err)
+			assert.Equal(t, "first", msg.Content)
+		}
+		assert.Equal(t, 1, upstream.Remaining())
+	})
+
+	t.Run("模型出错不写入缓存", func(t *testing.T) {
+		upstream := modeltest.NewScriptedChatModel(nil)
+		upstream.EnqueueError(errors.New("boom"))
+		upstream.Enqueue(schema.AssistantMessage("ok", nil))
+		cm, err := NewChatModel(context.Background(), &Config{Model: upstream})
+		require.NoError(t, err)
+
+		_, err = cm.Generate(context.Background(), input)
+		assert.Error(t, err)
+		msg, err := cm.Generate(context.Background(), input)
+		require.NoError(t, err)
+		assert.Equal(t, "ok", msg.Content)
+	})
+}
+
+func TestStores(t *testing.T) {
+	ctx := context.Background()
+	fileStore, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+
+	for name, store := range map[string]interface {
+		Store
+		setNow(func() time.Time)
+	}{
+		"内存": &memoryStoreClock{NewMemoryStore()},
+		"文件": &fileStoreClock{fileStore},
+	} {
+		t.Run(name, func(t *testing.T) {
+			now := time.Now()
+			store.setNow(func() time.Time { return now })
+			key := "abcdef0123"
+
+			msg, err := store.Get(ctx, key)
+			require.NoError(t, err)
+			assert.Nil(t, msg)
+
+			require.NoError(t, store.Set(ctx, key, schema.AssistantMessage("cached", nil), time.Minute))
+			msg, err = store.Get(ctx, key)
+			require.NoError(t, err)
+			require.NotNil(t, msg)
+			assert.Equal(t, "cached", msg.Content)
+
+			now = now.Add(2 * time.Minute)
+			msg, err = store.Get(ctx, key)
+			require.NoError(t, err)
+			assert.Nil(t, msg)
+
+			require.NoError(t, store.Set(ctx, key, schema.AssistantMessage("forever", nil), 0))
+			now = now.Add(24 * time.Hour)
+			msg, err = store.Get(ctx, key)
+			require.NoError(t, err)
+			require.NotNil(t, msg)
+			assert.Equal(t, "forever", msg.Content)
+		})
+	}
+
+	_, err = fileStore.Get(ctx, "../escape")
+	assert.Error(t, err)
+}
+
+type memoryStoreClock struct{ *MemoryStore }
+
+func (s *memoryStoreClock) setNow(now func() time.Time) { s.now = now }
+
+type fileStoreClock struct{ *FileStore }
+
+func (s *fileStoreClock) setNow(now func() time.Time) { s.now = now }
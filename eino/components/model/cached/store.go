@@ -0,0 +1,139 @@
+package cached
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/favbox/eino/internal/fsutil"
+	"github.com/favbox/eino/schema"
+)
+
+// Store 响应缓存存储，键为十六进制的 SHA-256。
+type Store interface {
+	// Get 读取缓存的响应，未命中或已过期时返回 nil。
+	Get(ctx context.Context, key string) (*schema.Message, error)
+	// Set 写入响应，ttl 为 0 时永不过期。
+	Set(ctx context.Context, key string, msg *schema.Message, ttl time.Duration) error
+}
+
+// MemoryStore 进程内的缓存存储，过期条目在读取时清除，可并发使用。
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]*entry
+	now     func() time.Time
+}
+
+type entry struct {
+	Message   *schema.Message `json:"message"`
+	ExpiresAt time.Time       `json:"expires_at,omitempty"`
+}
+
+func (e *entry) expired(now time.Time) bool {
+	return !e.ExpiresAt.IsZero() && !now.Before(e.ExpiresAt)
+}
+
+// NewMemoryStore 创建内存缓存存储。
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]*entry), now: time.Now}
+}
+
+func (s *MemoryStore) Get(_ context.Context, key string) (*schema.Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[key]
+	if !ok {
+		return nil, nil
+	}
+	if e.expired(s.now()) {
+		delete(s.entries, key)
+		return nil, nil
+	}
+	cp := *e.Message
+	return &cp, nil
+}
+
+func (s *MemoryStore) Set(_ context.Context, key string, msg *schema.Message, ttl time.Duration) error {
+	if msg == nil {
+		return errors.New("cannot cache nil message")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cp := *msg
+	s.entries[key] = &entry{Message: &cp, ExpiresAt: expiresAt(s.now(), ttl)}
+	return nil
+}
+
+// FileStore 以 JSON 文件保存响应的缓存存储，每个键一个文件，适合在多次运行之间复用。
+type FileStore struct {
+	dir string
+	now func() time.Time
+}
+
+// NewFileStore 创建以 dir 为根目录的文件缓存存储，目录不存在时自动创建。
+func NewFileStore(dir string) (*FileStore, error) {
+	if dir == "" {
+		return nil, errors.New("file store dir is required")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create file store dir failed: %w", err)
+	}
+	return &FileStore{dir: dir, now: time.Now}, nil
+}
+
+func (s *FileStore) Get(_ context.Context, key string) (*schema.Message, error) {
+	path, err := fsutil.ShardedPath(s.dir, key, ".json")
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read cache file failed: %w", err)
+	}
+
+	e := &entry{}
+	if err = sonic.Unmarshal(data, e); err != nil {
+		return nil, fmt.Errorf("unmarshal cache file %s failed: %w", path, err)
+	}
+	if e.expired(s.now()) {
+		_ = os.Remove(path)
+		return nil, nil
+	}
+	return e.Message, nil
+}
+
+func (s *FileStore) Set(_ context.Context, key string, msg *schema.Message, ttl time.Duration) error {
+	if msg == nil {
+		return errors.New("cannot cache nil message")
+	}
+	path, err := fsutil.ShardedPath(s.dir, key, ".json")
+	if err != nil {
+		return err
+	}
+	data, err := sonic.Marshal(&entry{Message: msg, ExpiresAt: expiresAt(s.now(), ttl)})
+	if err != nil {
+		return fmt.Errorf("marshal cache entry failed: %w", err)
+	}
+	if err = fsutil.WriteFileAtomic(path, data); err != nil {
+		return fmt.Errorf("write cache file failed: %w", err)
+	}
+	return nil
+}
+
+func expiresAt(now time.Time, ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return now.Add(ttl)
+}
@@ -0,0 +1,15 @@
+package modeltest
+
+import (
+	"github.com/favbox/eino/internal/modelmsg"
+	"github.com/favbox/eino/schema"
+)
+
+// ChunkMessage 将完整消息拆分为流式数据块，拆分结果可由 schema.ConcatMessages 合并还原。
+//
+// chunkSize 为每个数据块中推理内容、正文或工具调用参数的最大字符数，小于等于 0 时整条消息作为单个数据块。
+// 推理内容先于正文输出，工具调用在正文之后按顺序输出；每个工具调用的首个数据块携带 ID、类型与函数名，
+// 并为未设置 Index 的工具调用按其位置补齐 Index。ResponseMeta、Extra 与多模态输出放在最后一个数据块中。
+func ChunkMessage(msg *schema.Message, chunkSize int) []*schema.Message {
+	return modelmsg.Chunk(msg, chunkSize)
+}
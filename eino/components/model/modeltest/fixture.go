@@ -0,0 +1,68 @@
+package modeltest
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/favbox/eino/internal/fsutil"
+	"github.com/favbox/eino/internal/modelmsg"
+	"github.com/favbox/eino/schema"
+)
+
+// fixture 夹具文件的内容。
+type fixture struct {
+	Exchanges []*exchange `json:"exchanges"`
+}
+
+// exchange 一次录制的请求与响应，Generate 录制 Output，Stream 录制 Chunks。
+type exchange struct {
+	Key    string            `json:"key"`
+	Input  []*schema.Message `json:"input"`
+	Tools  []string          `json:"tools,omitempty"`
+	Output *schema.Message   `json:"output,omitempty"`
+	Chunks []*schema.Message `json:"chunks,omitempty"`
+}
+
+func loadFixture(path string) (*fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read fixture failed: %w", err)
+	}
+	f := &fixture{}
+	if err = sonic.Unmarshal(data, f); err != nil {
+		return nil, fmt.Errorf("unmarshal fixture %s failed: %w", path, err)
+	}
+	return f, nil
+}
+
+func saveFixture(path string, f *fixture) error {
+	data, err := sonic.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal fixture failed: %w", err)
+	}
+	return fsutil.WriteFileAtomic(path, data)
+}
+
+// requestKey 计算规范化后的输入消息与工具的哈希，作为匹配录制的键。
+func requestKey(input []*schema.Message, tools []*schema.ToolInfo) (string, error) {
+	nts, err := modelmsg.NormalizeTools(tools)
+	if err != nil {
+		return "", err
+	}
+	return modelmsg.Hash(struct {
+		Messages []modelmsg.Message `json:"messages"`
+		Tools    []modelmsg.Tool    `json:"tools,omitempty"`
+	}{Messages: modelmsg.NormalizeMessages(input), Tools: nts})
+}
+
+func toolNames(tools []*schema.ToolInfo) []string {
+	var names []string
+	for _, tool := range tools {
+		if tool != nil {
+			names = append(names, tool.Name)
+		}
+	}
+	return names
+}
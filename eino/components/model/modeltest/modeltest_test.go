@@ -0,0 +1,191 @@
+package modeltest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/favbox/eino/components/model"
+	"github.com/favbox/eino/schema"
+)
+
+func toolCallMessage() *schema.Message {
+	msg := schema.AssistantMessage("让我查一下", []schema.ToolCall{
+		{ID: "call_1", Type: "function", Function: schema.FunctionCall{Name: "search", Arguments: `{"query":"eino"}`}},
+		{ID: "call_2", Type: "function", Function: schema.FunctionCall{Name: "weather", Arguments: `{"city":"北京"}`}},
+	})
+	msg.ResponseMeta = &schema.ResponseMeta{FinishReason: "tool_calls", Usage: &schema.TokenUsage{TotalTokens: 42}}
+	return msg
+}
+
+func readAll(t *testing.T, sr *schema.StreamReader[*schema.Message]) []*schema.Message {
+	defer sr.Close()
+	var chunks []*schema.Message
+	for {
+		chunk, err := sr.Recv()
+		if errors.Is(err, io.EOF) {
+			return chunks
+		}
+		require.NoError(t, err)
+		chunks = append(chunks, chunk)
+	}
+}
+
+func TestChunkMessage(t *testing.T) {
+	t.Run("拆分后可合并还原", func(t *testing.T) {
+		msg := toolCallMessage()
+		msg.ReasoningContent = "需要调用工具"
+		chunks := ChunkMessage(msg, 3)
+		assert.Greater(t, len(chunks), 5)
+		for _, c := range chunks {
+			assert.LessOrEqual(t, len([]rune(c.Content)), 3)
+		}
+
+		merged, err := schema.ConcatMessages(chunks)
+		require.NoError(t, err)
+		assert.Equal(t, msg.Content, merged.Content)
+		assert.Equal(t, msg.ReasoningContent, merged.ReasoningContent)
+		assert.Equal(t, msg.ResponseMeta, merged.ResponseMeta)
+		require.Len(t, merged.ToolCalls, 2)
+		for i, tc := range merged.ToolCalls {
+			assert.Equal(t, msg.ToolCalls[i].ID, tc.ID)
+			assert.Equal(t, msg.ToolCalls[i].Function, tc.Function)
+		}
+	})
+
+	t.Run("不拆分", func(t *testing.T) {
+		msg := schema.AssistantMessage("hello", nil)
+		chunks := ChunkMessage(msg, 0)
+		require.Len(t, chunks, 1)
+		assert.Equal(t, msg, chunks[0])
+	})
+
+	t.Run("空消息", func(t *testing.T) {
+		chunks := ChunkMessage(&schema.Message{Role: schema.Assistant}, 4)
+		require.Len(t, chunks, 1)
+		assert.Equal(t, schema.Assistant, chunks[0].Role)
+	})
+}
+
+func TestScriptedChatModel(t *testing.T) {
+	ctx := context.Background()
+	input := []*schema.Message{schema.UserMessage("hi")}
+	errBoom := errors.New("boom")
+
+	cm := NewScriptedChatModel(&ScriptedConfig{
+		Responses: []*schema.Message{toolCallMessage()},
+		ChunkSize: 2,
+	})
+	cm.Enqueue(schema.AssistantMessage("done", nil))
+	cm.EnqueueError(errBoom)
+	assert.Equal(t, 3, cm.Remaining())
+
+	tools := []*schema.ToolInfo{{Name: "search"}}
+	bound, err := cm.WithTools(tools)
+	require.NoError(t, err)
+
+	chunks := readAll(t, mustStream(t, bound, input))
+	assert.Greater(t, len(chunks), 1)
+	merged, err := schema.ConcatMessages(chunks)
+	require.NoError(t, err)
+	assert.Len(t, merged.ToolCalls, 2)
+
+	msg, err := cm.Generate(ctx, input, model.WithTemperature(0.5))
+	require.NoError(t, err)
+	assert.Equal(t, "done", msg.Content)
+
+	_, err = cm.Generate(ctx, input)
+	assert.ErrorIs(t, err, errBoom)
+	_, err = cm.Stream(ctx, input)
+	assert.ErrorIs(t, err, ErrScriptExhausted)
+
+	calls := cm.Calls()
+	require.Len(t, calls, 4)
+	assert.True(t, calls[0].Stream)
+	assert.Equal(t, tools, calls[0].Tools)
+	assert.Nil(t, calls[1].Tools)
+	assert.Equal(t, float32(0.5), *calls[1].Options.Temperature)
+	assert.Equal(t, input, calls[1].Input)
+}
+
+func mustStream(t *testing.T, cm model.BaseChatModel, input []*schema.Message) *schema.StreamReader[*schema.Message] {
+	sr, err := cm.Stream(context.Background(), input)
+	require.NoError(t, err)
+	return sr
+}
+
+func TestReplayChatModel(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "fixtures", "agent.json")
+	tools := []*schema.ToolInfo{{Name: "search", Desc: "搜索网页"}}
+	first := []*schema.Message{schema.SystemMessage("你是助手"), schema.UserMessage("eino 是什么？")}
+	second := append(append([]*schema.Message{}, first...), toolCallMessage(), schema.ToolMessage("一个 Go 框架", "call_1"))
+
+	t.Run("录制", func(t *testing.T) {
+		upstream := NewScriptedChatModel(&ScriptedConfig{
+			Responses: []*schema.Message{toolCallMessage(), schema.AssistantMessage("eino 是一个 Go 框架", nil)},
+			ChunkSize: 4,
+		})
+		rec, err := NewReplayChatModel(&ReplayConfig{Model: upstream, FixturePath: path, Mode: ModeAuto})
+		require.NoError(t, err)
+		assert.True(t, rec.Recording())
+		bound, err := rec.WithTools(tools)
+		require.NoError(t, err)
+
+		msg, err := bound.Generate(ctx, first)
+		require.NoError(t, err)
+		assert.Len(t, msg.ToolCalls, 2)
+
+		chunks := readAll(t, mustStream(t, bound, second))
+		merged, err := schema.ConcatMessages(chunks)
+		require.NoError(t, err)
+		assert.Equal(t, "eino 是一个 Go 框架", merged.Content)
+
+		_, err = os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, tools, upstream.Calls()[0].Tools)
+	})
+
+	t.Run("回放", func(t *testing.T) {
+		cm, err := NewReplayChatModel(&ReplayConfig{FixturePath: path, Mode: ModeAuto, ChunkSize: 3})
+		require.NoError(t, err)
+		assert.False(t, cm.Recording())
+		bound, err := cm.WithTools(tools)
+		require.NoError(t, err)
+
+		// 首尾空白与工具调用 ID 不影响匹配
+		normalized := []*schema.Message{schema.SystemMessage("你是助手\n"), schema.UserMessage("  eino 是什么？")}
+		chunks := readAll(t, mustStream(t, bound, normalized))
+		assert.Greater(t, len(chunks), 1)
+		merged, err := schema.ConcatMessages(chunks)
+		require.NoError(t, err)
+		assert.Len(t, merged.ToolCalls, 2)
+
+		changedID := append([]*schema.Message{}, second...)
+		tcMsg := toolCallMessage()
+		tcMsg.ToolCalls[0].ID = "call_x"
+		changedID[2] = tcMsg
+		msg, err := bound.Generate(ctx, changedID)
+		require.NoError(t, err)
+		assert.Equal(t, "eino 是一个 Go 框架", msg.Content)
+
+		// 工具不同则不匹配
+		_, err = cm.Generate(ctx, first)
+		assert.ErrorIs(t, err, ErrNoRecording)
+	})
+
+	t.Run("配置校验", func(t *testing.T) {
+		_, err := NewReplayChatModel(&ReplayConfig{FixturePath: filepath.Join(t.TempDir(), "missing.json")})
+		assert.Error(t, err)
+		_, err = NewReplayChatModel(&ReplayConfig{FixturePath: path, Mode: ModeRecord})
+		assert.Error(t, err)
+		_, err = NewReplayChatModel(&ReplayConfig{FixturePath: path, Mode: "bad"})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,244 @@
+package modeltest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime/debug"
+	"sync"
+
+	"github.com/favbox/eino/components/model"
+	"github.com/favbox/eino/internal/safe"
+	"github.com/favbox/eino/schema"
+)
+
+// ErrNoRecording 回放时夹具中没有与请求匹配的录制。
+var ErrNoRecording = errors.New("no recorded exchange matches the request")
+
+// Mode 录制回放模型的工作模式。
+type Mode string
+
+const (
+	// ModeReplay 仅回放夹具中的录制，未匹配时返回 ErrNoRecording。
+	ModeReplay Mode = "replay"
+	// ModeRecord 调用真实模型并录制，覆盖已有夹具。
+	ModeRecord Mode = "record"
+	// ModeAuto 夹具文件存在时回放，否则录制。
+	ModeAuto Mode = "auto"
+)
+
+// ReplayConfig 录制回放模型配置。
+type ReplayConfig struct {
+	// Model 录制时调用的真实模型，录制模式下必填。
+	Model model.ToolCallingChatModel
+	// FixturePath 夹具文件路径，必填。
+	FixturePath string
+	// Mode 工作模式，默认 ModeReplay。
+	Mode Mode
+	// ChunkSize 回放 Generate 录制的响应给 Stream 调用时的拆分大小，参见 ChunkMessage。
+	ChunkSize int
+}
+
+// ReplayChatModel 录制或回放模型交互的聊天模型，可并发使用。
+//
+// 请求按规范化后的输入消息与工具匹配：角色、名称、去除首尾空白的正文、多模态输入、
+// 工具调用的函数名与参数，以及工具的名称、描述与参数 Schema。相同请求的多次录制按顺序回放，
+// 回放完后重复使用最后一次录制。以 Generate 录制的响应可回放给 Stream，反之亦然。
+type ReplayChatModel struct {
+	state *replayState
+	model model.ToolCallingChatModel
+	tools []*schema.ToolInfo
+}
+
+type replayState struct {
+	mu        sync.Mutex
+	path      string
+	recording bool
+	chunkSize int
+	fixture   *fixture
+	byKey     map[string][]*exchange
+	cursor    map[string]int
+}
+
+// NewReplayChatModel 创建录制回放模型，回放模式下立即加载夹具文件。
+func NewReplayChatModel(config *ReplayConfig) (*ReplayChatModel, error) {
+	if config == nil {
+		return nil, errors.New("replay chat model config is required")
+	}
+	if config.FixturePath == "" {
+		return nil, errors.New("replay chat model 'FixturePath' is required")
+	}
+
+	mode := config.Mode
+	switch mode {
+	case "":
+		mode = ModeReplay
+	case ModeAuto:
+		mode = ModeRecord
+		if _, err := os.Stat(config.FixturePath); err == nil {
+			mode = ModeReplay
+		}
+	case ModeReplay, ModeRecord:
+	default:
+		return nil, fmt.Errorf("unknown replay chat model mode: %q", config.Mode)
+	}
+
+	state := &replayState{
+		path:      config.FixturePath,
+		recording: mode == ModeRecord,
+		chunkSize: config.ChunkSize,
+		fixture:   &fixture{},
+		byKey:     make(map[string][]*exchange),
+		cursor:    make(map[string]int),
+	}
+	if state.recording {
+		if config.Model == nil {
+			return nil, errors.New("replay chat model 'Model' is required in record mode")
+		}
+	} else {
+		f, err := loadFixture(config.FixturePath)
+		if err != nil {
+			return nil, err
+		}
+		state.fixture = f
+		for _, ex := range f.Exchanges {
+			state.byKey[ex.Key] = append(state.byKey[ex.Key], ex)
+		}
+	}
+
+	return &ReplayChatModel{state: state, model: config.Model}, nil
+}
+
+// Recording 返回当前是否处于录制模式。
+func (m *ReplayChatModel) Recording() bool {
+	return m.state.recording
+}
+
+func (m *ReplayChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	tools := model.GetCommonOptions(&model.Options{Tools: m.tools}, opts...).Tools
+	key, err := requestKey(input, tools)
+	if err != nil {
+		return nil, err
+	}
+
+	if !m.state.recording {
+		ex, err := m.state.lookup(key)
+		if err != nil {
+			return nil, err
+		}
+		if ex.Output != nil {
+			return ex.Output, nil
+		}
+		return schema.ConcatMessages(ex.Chunks)
+	}
+
+	msg, err := m.model.Generate(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err = m.state.record(&exchange{Key: key, Input: input, Tools: toolNames(tools), Output: msg}); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+func (m *ReplayChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	tools := model.GetCommonOptions(&model.Options{Tools: m.tools}, opts...).Tools
+	key, err := requestKey(input, tools)
+	if err != nil {
+		return nil, err
+	}
+
+	if !m.state.recording {
+		ex, err := m.state.lookup(key)
+		if err != nil {
+			return nil, err
+		}
+		if len(ex.Chunks) > 0 {
+			return schema.StreamReaderFromArray(ex.Chunks), nil
+		}
+		return schema.StreamReaderFromArray(ChunkMessage(ex.Output, m.state.chunkSize)), nil
+	}
+
+	sr, err := m.model.Stream(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.recordStream(sr, &exchange{Key: key, Input: input, Tools: toolNames(tools)}), nil
+}
+
+func (m *ReplayChatModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	nm := &ReplayChatModel{state: m.state, model: m.model, tools: tools}
+	if m.model != nil {
+		bound, err := m.model.WithTools(tools)
+		if err != nil {
+			return nil, err
+		}
+		nm.model = bound
+	}
+	return nm, nil
+}
+
+func (m *ReplayChatModel) GetType() string {
+	return "Replay"
+}
+
+// recordStream 透传流式响应，完整读取后将全部数据块写入夹具；流出错或被提前关闭时不录制。
+func (m *ReplayChatModel) recordStream(sr *schema.StreamReader[*schema.Message], ex *exchange) *schema.StreamReader[*schema.Message] {
+	out, sw := schema.Pipe[*schema.Message](1)
+	go func() {
+		defer func() {
+			if panicErr := recover(); panicErr != nil {
+				_ = sw.Send(nil, safe.NewPanicErr(panicErr, debug.Stack()))
+			}
+			sr.Close()
+			sw.Close()
+		}()
+
+		for {
+			chunk, err := sr.Recv()
+			if errors.Is(err, io.EOF) {
+				if err = m.state.record(ex); err != nil {
+					_ = sw.Send(nil, err)
+				}
+				return
+			}
+			if err == nil {
+				ex.Chunks = append(ex.Chunks, chunk)
+			}
+			if sw.Send(chunk, err) || err != nil {
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+func (s *replayState) lookup(key string) (*exchange, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	exs := s.byKey[key]
+	if len(exs) == 0 {
+		return nil, fmt.Errorf("%w: key %s in %s", ErrNoRecording, key, s.path)
+	}
+	i := min(s.cursor[key], len(exs)-1)
+	s.cursor[key]++
+	return exs[i], nil
+}
+
+func (s *replayState) record(ex *exchange) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fixture.Exchanges = append(s.fixture.Exchanges, ex)
+	if err := saveFixture(s.path, s.fixture); err != nil {
+		return fmt.Errorf("save fixture %s failed: %w", s.path, err)
+	}
+	return nil
+}
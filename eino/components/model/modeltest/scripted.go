@@ -0,0 +1,147 @@
+// Package modeltest 提供用于确定性测试的 model.ToolCallingChatModel 替身。
+//
+// ScriptedChatModel 按顺序返回预先排好的响应（文本或工具调用），流式调用时按 ChunkSize 拆分为数据块，
+// 并记录每次调用的输入，便于断言图与智能体的行为。
+//
+// ReplayChatModel 在录制模式下包装真实模型，将 Generate/Stream 的请求与响应写入夹具文件；
+// 在回放模式下按规范化后的输入消息与绑定工具匹配夹具中的响应，使回归测试不再依赖外部服务。
+//
+// 示例：
+//
+//	cm := modeltest.NewScriptedChatModel(&modeltest.ScriptedConfig{
+//		Responses: []*schema.Message{
+//			schema.AssistantMessage("", []schema.ToolCall{{ID: "call_1", Function: schema.FunctionCall{Name: "search", Arguments: `{"q":"eino"}`}}}),
+//			schema.AssistantMessage("done", nil),
+//		},
+//		ChunkSize: 4,
+//	})
+//	agent, _ := react.NewAgent(ctx, &react.AgentConfig{ToolCallingModel: cm, ...})
+package modeltest
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/favbox/eino/components/model"
+	"github.com/favbox/eino/schema"
+)
+
+// ErrScriptExhausted 脚本中的响应已全部返回后继续调用模型时返回的错误。
+var ErrScriptExhausted = errors.New("scripted chat model has no more responses")
+
+// ScriptedConfig 脚本模型配置。
+type ScriptedConfig struct {
+	// Responses 按调用顺序返回的响应。
+	Responses []*schema.Message
+	// ChunkSize 流式调用时每个数据块的最大字符数，小于等于 0 时整条消息作为单个数据块，参见 ChunkMessage。
+	ChunkSize int
+}
+
+// Call 记录对脚本模型的一次调用。
+type Call struct {
+	// Input 调用时的输入消息。
+	Input []*schema.Message
+	// Tools 通过 WithTools 绑定或 model.WithTools 选项指定的工具。
+	Tools []*schema.ToolInfo
+	// Options 调用选项中的通用配置。
+	Options *model.Options
+	// Stream 是否为流式调用。
+	Stream bool
+}
+
+// ScriptedChatModel 按脚本返回响应的聊天模型，可并发使用。
+//
+// 通过 WithTools 得到的实例与原实例共享同一份脚本与调用记录。
+type ScriptedChatModel struct {
+	script *script
+	tools  []*schema.ToolInfo
+}
+
+type script struct {
+	mu        sync.Mutex
+	steps     []step
+	calls     []*Call
+	chunkSize int
+}
+
+type step struct {
+	msg *schema.Message
+	err error
+}
+
+// NewScriptedChatModel 创建脚本模型，config 可为 nil。
+func NewScriptedChatModel(config *ScriptedConfig) *ScriptedChatModel {
+	if config == nil {
+		config = &ScriptedConfig{}
+	}
+	m := &ScriptedChatModel{script: &script{chunkSize: config.ChunkSize}}
+	m.Enqueue(config.Responses...)
+	return m
+}
+
+// Enqueue 在脚本末尾追加响应。
+func (m *ScriptedChatModel) Enqueue(msgs ...*schema.Message) {
+	m.script.mu.Lock()
+	defer m.script.mu.Unlock()
+	for _, msg := range msgs {
+		m.script.steps = append(m.script.steps, step{msg: msg})
+	}
+}
+
+// EnqueueError 在脚本末尾追加一次失败的调用，流式调用时该错误由 Stream 直接返回。
+func (m *ScriptedChatModel) EnqueueError(err error) {
+	m.script.mu.Lock()
+	defer m.script.mu.Unlock()
+	m.script.steps = append(m.script.steps, step{err: err})
+}
+
+// Calls 返回至今为止的调用记录。
+func (m *ScriptedChatModel) Calls() []*Call {
+	m.script.mu.Lock()
+	defer m.script.mu.Unlock()
+	return append([]*Call(nil), m.script.calls...)
+}
+
+// Remaining 返回脚本中尚未返回的响应数。
+func (m *ScriptedChatModel) Remaining() int {
+	m.script.mu.Lock()
+	defer m.script.mu.Unlock()
+	return len(m.script.steps)
+}
+
+func (m *ScriptedChatModel) Generate(_ context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	return m.next(input, opts, false)
+}
+
+func (m *ScriptedChatModel) Stream(_ context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	msg, err := m.next(input, opts, true)
+	if err != nil {
+		return nil, err
+	}
+	return schema.StreamReaderFromArray(ChunkMessage(msg, m.script.chunkSize)), nil
+}
+
+func (m *ScriptedChatModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	return &ScriptedChatModel{script: m.script, tools: tools}, nil
+}
+
+func (m *ScriptedChatModel) GetType() string {
+	return "Scripted"
+}
+
+// next 记录本次调用并取出脚本中的下一个响应。
+func (m *ScriptedChatModel) next(input []*schema.Message, opts []model.Option, stream bool) (*schema.Message, error) {
+	options := model.GetCommonOptions(&model.Options{Tools: m.tools}, opts...)
+
+	m.script.mu.Lock()
+	defer m.script.mu.Unlock()
+	m.script.calls = append(m.script.calls, &Call{Input: input, Tools: options.Tools, Options: options, Stream: stream})
+	if len(m.script.steps) == 0 {
+		return nil, ErrScriptExhausted
+	}
+	s := m.script.steps[0]
+	m.script.steps = m.script.steps[1:]
+
+	return s.msg, s.err
+}
@@ -0,0 +1,369 @@
+// Package resilient 提供带重试与降级能力的 model.ToolCallingChatModel 包装器。
+//
+// 每个模型最多尝试 MaxAttempts 次，两次尝试之间按指数退避并叠加随机抖动等待；
+// 当前模型的尝试用尽或遇到不可重试的错误时，依次降级到 Fallbacks 中的下一个模型。
+// 通过 WithTools 绑定的工具会同时绑定到主模型与全部降级模型。
+//
+// 流式调用只在收到首个数据块之前重试或降级，一旦首个数据块交付给调用方，
+// 后续的流错误将原样透传。
+//
+// 每次尝试都以被包装模型自身的类型触发 model.CallbackInput/CallbackOutput 回调，
+// 被包装模型未自行触发回调时由包装器补齐，并在 Extra 中记录尝试序号与模型下标。
+//
+// 示例：
+//
+//	cm, _ := resilient.NewChatModel(ctx, &resilient.Config{
+//		Model:          primary,
+//		Fallbacks:      []model.ToolCallingChatModel{backup},
+//		MaxAttempts:    3,
+//		InitialBackoff: 200 * time.Millisecond,
+//		Jitter:         0.2,
+//	})
+//	cm, _ = cm.WithTools(tools)
+package resilient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"runtime/debug"
+	"time"
+
+	"github.com/favbox/eino/callbacks"
+	"github.com/favbox/eino/components"
+	"github.com/favbox/eino/components/model"
+	"github.com/favbox/eino/internal/delegate"
+	"github.com/favbox/eino/internal/modelmsg"
+	"github.com/favbox/eino/internal/safe"
+	"github.com/favbox/eino/schema"
+)
+
+const (
+	// ExtraKeyAttempt 回调 Extra 中尝试序号的键，从 1 开始、跨模型累计，值为 int。
+	ExtraKeyAttempt = "resilient_attempt"
+	// ExtraKeyModelIndex 回调 Extra 中模型下标的键，0 为主模型，i 为 Fallbacks[i-1]，值为 int。
+	ExtraKeyModelIndex = "resilient_model_index"
+
+	defaultMaxAttempts    = 3
+	defaultInitialBackoff = 200 * time.Millisecond
+	defaultMaxBackoff     = 10 * time.Second
+	defaultMultiplier     = 2.0
+)
+
+// Config 包装器配置。
+type Config struct {
+	// Model 主模型，必填。
+	Model model.ToolCallingChatModel
+	// Fallbacks 按顺序使用的降级模型。
+	Fallbacks []model.ToolCallingChatModel
+
+	// MaxAttempts 每个模型的最大尝试次数（含首次），默认 3。
+	MaxAttempts int
+	// InitialBackoff 首次重试前的等待时间，默认 200ms。
+	InitialBackoff time.Duration
+	// MaxBackoff 单次等待时间的上限，默认 10s。
+	MaxBackoff time.Duration
+	// Multiplier 每次重试后等待时间的增长倍数，默认 2。
+	Multiplier float64
+	// Jitter 等待时间的随机抖动比例，取值 [0, 1]，实际等待时间在 backoff*(1±Jitter) 之间，默认不抖动。
+	Jitter float64
+
+	// IsRetryable 判断错误是否值得在同一模型上重试，默认除上下文取消与超时外的错误均可重试。
+	// 不可重试的错误不会中断降级。
+	IsRetryable func(err error) bool
+}
+
+// NewChatModel 创建带重试与降级能力的 ChatModel。
+func NewChatModel(_ context.Context, config *Config) (model.ToolCallingChatModel, error) {
+	if config == nil {
+		return nil, errors.New("resilient chat model config is required")
+	}
+	if config.Model == nil {
+		return nil, errors.New("resilient chat model 'Model' is required")
+	}
+	for i, fb := range config.Fallbacks {
+		if fb == nil {
+			return nil, fmt.Errorf("resilient chat model fallback[%d] is nil", i)
+		}
+	}
+	if config.MaxAttempts < 0 || config.InitialBackoff < 0 || config.MaxBackoff < 0 || config.Multiplier < 0 {
+		return nil, errors.New("resilient chat model 'MaxAttempts', 'InitialBackoff', 'MaxBackoff' and 'Multiplier' must not be negative")
+	}
+	if config.Jitter < 0 || config.Jitter > 1 {
+		return nil, errors.New("resilient chat model 'Jitter' must be in [0, 1]")
+	}
+
+	cm := &chatModel{
+		models:         append([]model.ToolCallingChatModel{config.Model}, config.Fallbacks...),
+		maxAttempts:    config.MaxAttempts,
+		initialBackoff: config.InitialBackoff,
+		maxBackoff:     config.MaxBackoff,
+		multiplier:     config.Multiplier,
+		jitter:         config.Jitter,
+		isRetryable:    config.IsRetryable,
+	}
+	if cm.maxAttempts == 0 {
+		cm.maxAttempts = defaultMaxAttempts
+	}
+	if cm.initialBackoff == 0 {
+		cm.initialBackoff = defaultInitialBackoff
+	}
+	if cm.maxBackoff == 0 {
+		cm.maxBackoff = defaultMaxBackoff
+	}
+	if cm.multiplier == 0 {
+		cm.multiplier = defaultMultiplier
+	}
+	if cm.isRetryable == nil {
+		cm.isRetryable = defaultIsRetryable
+	}
+
+	return cm, nil
+}
+
+type chatModel struct {
+	models         []model.ToolCallingChatModel
+	tools          []*schema.ToolInfo
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	multiplier     float64
+	jitter         float64
+	isRetryable    func(err error) bool
+}
+
+func (c *chatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (outMsg *schema.Message, err error) {
+	cbInput := c.callbackInput(input, opts)
+	ctx = callbacks.EnsureRunInfo(ctx, c.GetType(), components.ComponentOfChatModel)
+	ctx = callbacks.OnStart(ctx, cbInput)
+	defer func() {
+		if err != nil {
+			_ = callbacks.OnError(ctx, err)
+		}
+	}()
+
+	var attempt, modelIdx int
+	err = c.do(ctx, func(idx, n int) error {
+		msg, err := c.generate(ctx, idx, n, input, opts)
+		if err != nil {
+			return err
+		}
+		outMsg, attempt, modelIdx = msg, n, idx
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_ = callbacks.OnEnd(ctx, &model.CallbackOutput{
+		Message: outMsg,
+		Config:  cbInput.Config,
+		Extra:   map[string]any{ExtraKeyAttempt: attempt, ExtraKeyModelIndex: modelIdx},
+	})
+
+	return outMsg, nil
+}
+
+func (c *chatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (outStream *schema.StreamReader[*schema.Message], err error) {
+	cbInput := c.callbackInput(input, opts)
+	ctx = callbacks.EnsureRunInfo(ctx, c.GetType(), components.ComponentOfChatModel)
+	ctx = callbacks.OnStart(ctx, cbInput)
+	defer func() {
+		if err != nil {
+			_ = callbacks.OnError(ctx, err)
+		}
+	}()
+
+	var attempt, modelIdx int
+	err = c.do(ctx, func(idx, n int) error {
+		sr, err := c.stream(ctx, idx, n, input, opts)
+		if err != nil {
+			return err
+		}
+		// 首个数据块到达前的错误视为本次尝试失败
+		first, err := sr.Recv()
+		if err != nil && !errors.Is(err, io.EOF) {
+			sr.Close()
+			return err
+		}
+		outStream, attempt, modelIdx = prepend(first, err == nil, sr), n, idx
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	extra := map[string]any{ExtraKeyAttempt: attempt, ExtraKeyModelIndex: modelIdx}
+	srs := outStream.Copy(2)
+	cbStream := schema.StreamReaderWithConvert(srs[0], func(msg *schema.Message) (callbacks.CallbackOutput, error) {
+		return &model.CallbackOutput{Message: msg, Config: cbInput.Config, Extra: extra}, nil
+	})
+	_, _ = callbacks.OnEndWithStreamOutput(ctx, cbStream)
+
+	return srs[1], nil
+}
+
+func (c *chatModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	models := make([]model.ToolCallingChatModel, len(c.models))
+	for i, m := range c.models {
+		bound, err := m.WithTools(tools)
+		if err != nil {
+			return nil, fmt.Errorf("bind tools to model[%d] failed: %w", i, err)
+		}
+		models[i] = bound
+	}
+
+	nc := *c
+	nc.models = models
+	nc.tools = tools
+	return &nc, nil
+}
+
+// GetType 返回组件类型名称，用于回调的 RunInfo。
+func (c *chatModel) GetType() string {
+	return "Resilient"
+}
+
+// IsCallbacksEnabled 包装器自行触发回调，编排框架无需再包装。
+func (c *chatModel) IsCallbacksEnabled() bool {
+	return true
+}
+
+// do 依次在各模型上按重试策略执行 fn，idx 为模型下标，n 为跨模型累计的尝试序号。
+func (c *chatModel) do(ctx context.Context, fn func(idx, n int) error) error {
+	var (
+		n       int
+		lastErr error
+	)
+	for idx := range c.models {
+		for attempt := 0; attempt < c.maxAttempts; attempt++ {
+			if attempt > 0 {
+				if err := c.sleep(ctx, attempt); err != nil {
+					return interruptedError(n, err, lastErr)
+				}
+			}
+
+			n++
+			err := fn(idx, n)
+			if err == nil {
+				return nil
+			}
+			lastErr = err
+			if ctx.Err() != nil {
+				return interruptedError(n, ctx.Err(), lastErr)
+			}
+			if !c.isRetryable(err) {
+				break
+			}
+		}
+	}
+
+	return fmt.Errorf("resilient chat model failed after %d attempts: %w", n, lastErr)
+}
+
+// interruptedError 同时包装上下文错误与最后一次调用的错误，调用方可用 errors.Is 区分取消、超时与模型失败。
+func interruptedError(n int, ctxErr, lastErr error) error {
+	return fmt.Errorf("resilient chat model interrupted after %d attempts: %w (last error: %w)", n, ctxErr, lastErr)
+}
+
+// sleep 等待第 attempt 次重试（从 1 开始）前的退避时间，上下文结束时提前返回其错误。
+func (c *chatModel) sleep(ctx context.Context, attempt int) error {
+	timer := time.NewTimer(c.backoff(attempt))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (c *chatModel) backoff(attempt int) time.Duration {
+	d := float64(c.initialBackoff)
+	for i := 1; i < attempt && d < float64(c.maxBackoff); i++ {
+		d *= c.multiplier
+	}
+	d = min(d, float64(c.maxBackoff))
+	if c.jitter > 0 {
+		d *= 1 + c.jitter*(2*rand.Float64()-1)
+	}
+	return time.Duration(d)
+}
+
+// generate 在第 idx 个模型上执行一次 Generate，回调的 Extra 中记录尝试序号与模型下标。
+func (c *chatModel) generate(ctx context.Context, idx, n int, input []*schema.Message, opts []model.Option) (*schema.Message, error) {
+	m := c.models[idx]
+	cbInput := c.attemptCallbackInput(idx, n, input, opts)
+	return delegate.Invoke(ctx, m, &callbacks.RunInfo{Component: components.ComponentOfChatModel}, cbInput,
+		func(ctx context.Context) (*schema.Message, error) {
+			return m.Generate(ctx, input, opts...)
+		},
+		func(msg *schema.Message) callbacks.CallbackOutput {
+			return &model.CallbackOutput{Message: msg, Config: cbInput.Config, Extra: cbInput.Extra}
+		})
+}
+
+// stream 在第 idx 个模型上执行一次 Stream，回调的 Extra 中记录尝试序号与模型下标。
+func (c *chatModel) stream(ctx context.Context, idx, n int, input []*schema.Message, opts []model.Option) (*schema.StreamReader[*schema.Message], error) {
+	m := c.models[idx]
+	cbInput := c.attemptCallbackInput(idx, n, input, opts)
+	return delegate.Stream(ctx, m, &callbacks.RunInfo{Component: components.ComponentOfChatModel}, cbInput,
+		func(ctx context.Context) (*schema.StreamReader[*schema.Message], error) {
+			return m.Stream(ctx, input, opts...)
+		},
+		func(msg *schema.Message) callbacks.CallbackOutput {
+			return &model.CallbackOutput{Message: msg, Config: cbInput.Config, Extra: cbInput.Extra}
+		})
+}
+
+// callbackInput 构造回调输入，未指定工具时使用绑定的工具。
+func (c *chatModel) callbackInput(input []*schema.Message, opts []model.Option) *model.CallbackInput {
+	return modelmsg.CallbackInput(input, model.GetCommonOptions(&model.Options{Tools: c.tools}, opts...))
+}
+
+// attemptCallbackInput 构造第 idx 个模型第 n 次尝试的回调输入，Extra 中记录尝试序号与模型下标。
+func (c *chatModel) attemptCallbackInput(idx, n int, input []*schema.Message, opts []model.Option) *model.CallbackInput {
+	cbInput := c.callbackInput(input, opts)
+	cbInput.Extra = map[string]any{ExtraKeyAttempt: n, ExtraKeyModelIndex: idx}
+	return cbInput
+}
+
+// prepend 将已读取的首个数据块重新放回流的开头。
+func prepend(first *schema.Message, hasFirst bool, rest *schema.StreamReader[*schema.Message]) *schema.StreamReader[*schema.Message] {
+	if !hasFirst {
+		rest.Close()
+		return schema.StreamReaderFromArray[*schema.Message](nil)
+	}
+
+	sr, sw := schema.Pipe[*schema.Message](1)
+	go func() {
+		defer func() {
+			if panicErr := recover(); panicErr != nil {
+				_ = sw.Send(nil, safe.NewPanicErr(panicErr, debug.Stack()))
+			}
+			rest.Close()
+			sw.Close()
+		}()
+
+		if sw.Send(first, nil) {
+			return
+		}
+		for {
+			chunk, err := rest.Recv()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if sw.Send(chunk, err) || err != nil {
+				return
+			}
+		}
+	}()
+
+	return sr
+}
+
+func defaultIsRetryable(err error) bool {
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
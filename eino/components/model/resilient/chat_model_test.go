@@ -0,0 +1,315 @@
+package resilient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/favbox/eino/callbacks"
+	"github.com/favbox/eino/components/model"
+	mockModel "github.com/favbox/eino/internal/mock/components/model"
+	"github.com/favbox/eino/schema"
+)
+
+type attemptRecorder struct {
+	mu      sync.Mutex
+	starts  []*model.CallbackInput
+	ends    []*model.CallbackOutput
+	errs    []error
+	outerOK bool
+}
+
+func (r *attemptRecorder) handler() callbacks.Handler {
+	return callbacks.NewHandlerBuilder().
+		OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+			if info.Type == "Resilient" {
+				return ctx
+			}
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.starts = append(r.starts, model.ConvCallbackInput(input))
+			return ctx
+		}).
+		OnEndFn(func(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			if info.Type == "Resilient" {
+				r.outerOK = true
+				return ctx
+			}
+			r.ends = append(r.ends, model.ConvCallbackOutput(output))
+			return ctx
+		}).
+		OnEndWithStreamOutputFn(func(ctx context.Context, info *callbacks.RunInfo, output *schema.StreamReader[callbacks.CallbackOutput]) context.Context {
+			defer output.Close()
+			for {
+				chunk, err := output.Recv()
+				if err != nil {
+					r.mu.Lock()
+					if !errors.Is(err, io.EOF) && info.Type != "Resilient" {
+						r.errs = append(r.errs, err)
+					}
+					r.mu.Unlock()
+					return ctx
+				}
+				r.mu.Lock()
+				if info.Type == "Resilient" {
+					r.outerOK = true
+				} else {
+					r.ends = append(r.ends, model.ConvCallbackOutput(chunk))
+				}
+				r.mu.Unlock()
+			}
+		}).
+		OnErrorFn(func(ctx context.Context, info *callbacks.RunInfo, err error) context.Context {
+			if info.Type == "Resilient" {
+				return ctx
+			}
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.errs = append(r.errs, err)
+			return ctx
+		}).
+		Build()
+}
+
+func newTestModel(t *testing.T, primary model.ToolCallingChatModel, fallbacks ...model.ToolCallingChatModel) model.ToolCallingChatModel {
+	cm, err := NewChatModel(context.Background(), &Config{
+		Model:          primary,
+		Fallbacks:      fallbacks,
+		MaxAttempts:    2,
+		InitialBackoff: time.Millisecond,
+	})
+	require.NoError(t, err)
+	return cm
+}
+
+func TestGenerate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	input := []*schema.Message{schema.UserMessage("hi")}
+	errBoom := errors.New("boom")
+
+	t.Run("重试后成功", func(t *testing.T) {
+		primary := mockModel.NewMockToolCallingChatModel(ctrl)
+		gomock.InOrder(
+			primary.EXPECT().Generate(gomock.Any(), input, gomock.Any()).Return(nil, errBoom),
+			primary.EXPECT().Generate(gomock.Any(), input, gomock.Any()).Return(schema.AssistantMessage("ok", nil), nil),
+		)
+
+		rec := &attemptRecorder{}
+		ctx := callbacks.InitCallbacks(context.Background(), nil, rec.handler())
+		msg, err := newTestModel(t, primary).Generate(ctx, input)
+		require.NoError(t, err)
+		assert.Equal(t, "ok", msg.Content)
+
+		require.Len(t, rec.starts, 2)
+		assert.Equal(t, 1, rec.starts[0].Extra[ExtraKeyAttempt])
+		assert.Equal(t, 2, rec.starts[1].Extra[ExtraKeyAttempt])
+		assert.Equal(t, []error{errBoom}, rec.errs)
+		require.Len(t, rec.ends, 1)
+		assert.Equal(t, 2, rec.ends[0].Extra[ExtraKeyAttempt])
+		assert.Equal(t, 0, rec.ends[0].Extra[ExtraKeyModelIndex])
+		assert.True(t, rec.outerOK)
+	})
+
+	t.Run("主模型失败后降级", func(t *testing.T) {
+		primary := mockModel.NewMockToolCallingChatModel(ctrl)
+		fallback := mockModel.NewMockToolCallingChatModel(ctrl)
+		primary.EXPECT().Generate(gomock.Any(), input, gomock.Any()).Return(nil, errBoom).Times(2)
+		fallback.EXPECT().Generate(gomock.Any(), input, gomock.Any()).Return(schema.AssistantMessage("backup", nil), nil)
+
+		rec := &attemptRecorder{}
+		ctx := callbacks.InitCallbacks(context.Background(), nil, rec.handler())
+		msg, err := newTestModel(t, primary, fallback).Generate(ctx, input)
+		require.NoError(t, err)
+		assert.Equal(t, "backup", msg.Content)
+		require.Len(t, rec.ends, 1)
+		assert.Equal(t, 3, rec.ends[0].Extra[ExtraKeyAttempt])
+		assert.Equal(t, 1, rec.ends[0].Extra[ExtraKeyModelIndex])
+	})
+
+	t.Run("不可重试错误直接降级", func(t *testing.T) {
+		errFatal := errors.New("invalid request")
+		primary := mockModel.NewMockToolCallingChatModel(ctrl)
+		fallback := mockModel.NewMockToolCallingChatModel(ctrl)
+		primary.EXPECT().Generate(gomock.Any(), input, gomock.Any()).Return(nil, errFatal).Times(1)
+		fallback.EXPECT().Generate(gomock.Any(), input, gomock.Any()).Return(nil, errFatal).Times(1)
+
+		cm, err := NewChatModel(context.Background(), &Config{
+			Model:       primary,
+			Fallbacks:   []model.ToolCallingChatModel{fallback},
+			IsRetryable: func(err error) bool { return !errors.Is(err, errFatal) },
+		})
+		require.NoError(t, err)
+		_, err = cm.Generate(context.Background(), input)
+		assert.ErrorIs(t, err, errFatal)
+		assert.ErrorContains(t, err, "after 2 attempts")
+	})
+
+	t.Run("上下文取消时停止重试", func(t *testing.T) {
+		primary := mockModel.NewMockToolCallingChatModel(ctrl)
+		ctx, cancel := context.WithCancel(context.Background())
+		primary.EXPECT().Generate(gomock.Any(), input, gomock.Any()).DoAndReturn(
+			func(context.Context, []*schema.Message, ...model.Option) (*schema.Message, error) {
+				cancel()
+				return nil, errBoom
+			}).Times(1)
+
+		_, err := newTestModel(t, primary).Generate(ctx, input)
+		assert.ErrorIs(t, err, errBoom)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("退避等待中超时", func(t *testing.T) {
+		primary := mockModel.NewMockToolCallingChatModel(ctrl)
+		primary.EXPECT().Generate(gomock.Any(), input, gomock.Any()).Return(nil, errBoom).Times(1)
+		cm, err := NewChatModel(context.Background(), &Config{Model: primary, MaxAttempts: 2, InitialBackoff: time.Minute})
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err = cm.Generate(ctx, input)
+		assert.ErrorIs(t, err, errBoom)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestStream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	input := []*schema.Message{schema.UserMessage("hi")}
+	errBoom := errors.New("boom")
+
+	failingStream := func() *schema.StreamReader[*schema.Message] {
+		sr, sw := schema.Pipe[*schema.Message](1)
+		sw.Send(nil, errBoom)
+		sw.Close()
+		return sr
+	}
+
+	t.Run("首块前失败时重试", func(t *testing.T) {
+		primary := mockModel.NewMockToolCallingChatModel(ctrl)
+		gomock.InOrder(
+			primary.EXPECT().Stream(gomock.Any(), input, gomock.Any()).Return(failingStream(), nil),
+			primary.EXPECT().Stream(gomock.Any(), input, gomock.Any()).Return(
+				schema.StreamReaderFromArray([]*schema.Message{
+					schema.AssistantMessage("he", nil),
+					schema.AssistantMessage("llo", nil),
+				}), nil),
+		)
+
+		rec := &attemptRecorder{}
+		ctx := callbacks.InitCallbacks(context.Background(), nil, rec.handler())
+		sr, err := newTestModel(t, primary).Stream(ctx, input)
+		require.NoError(t, err)
+		var chunks []*schema.Message
+		for {
+			chunk, err := sr.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			require.NoError(t, err)
+			chunks = append(chunks, chunk)
+		}
+		sr.Close()
+		msg, err := schema.ConcatMessages(chunks)
+		require.NoError(t, err)
+		assert.Equal(t, "hello", msg.Content)
+
+		require.Len(t, rec.starts, 2)
+		assert.Equal(t, 2, rec.starts[1].Extra[ExtraKeyAttempt])
+	})
+
+	t.Run("首块后失败不再重试", func(t *testing.T) {
+		primary := mockModel.NewMockToolCallingChatModel(ctrl)
+		sr, sw := schema.Pipe[*schema.Message](2)
+		sw.Send(schema.AssistantMessage("he", nil), nil)
+		sw.Send(nil, errBoom)
+		sw.Close()
+		primary.EXPECT().Stream(gomock.Any(), input, gomock.Any()).Return(sr, nil).Times(1)
+
+		out, err := newTestModel(t, primary).Stream(context.Background(), input)
+		require.NoError(t, err)
+		defer out.Close()
+		chunk, err := out.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, "he", chunk.Content)
+		_, err = out.Recv()
+		assert.ErrorIs(t, err, errBoom)
+	})
+
+	t.Run("全部失败返回最后的错误", func(t *testing.T) {
+		primary := mockModel.NewMockToolCallingChatModel(ctrl)
+		fallback := mockModel.NewMockToolCallingChatModel(ctrl)
+		primary.EXPECT().Stream(gomock.Any(), input, gomock.Any()).Return(nil, errBoom).Times(2)
+		fallback.EXPECT().Stream(gomock.Any(), input, gomock.Any()).DoAndReturn(
+			func(context.Context, []*schema.Message, ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+				return failingStream(), nil
+			}).Times(2)
+
+		_, err := newTestModel(t, primary, fallback).Stream(context.Background(), input)
+		assert.ErrorIs(t, err, errBoom)
+		assert.ErrorContains(t, err, "after 4 attempts")
+	})
+}
+
+func TestWithTools(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	tools := []*schema.ToolInfo{{Name: "search"}}
+
+	t.Run("工具绑定到所有模型", func(t *testing.T) {
+		primary := mockModel.NewMockToolCallingChatModel(ctrl)
+		fallback := mockModel.NewMockToolCallingChatModel(ctrl)
+		boundPrimary := mockModel.NewMockToolCallingChatModel(ctrl)
+		boundFallback := mockModel.NewMockToolCallingChatModel(ctrl)
+		primary.EXPECT().WithTools(tools).Return(boundPrimary, nil)
+		fallback.EXPECT().WithTools(tools).Return(boundFallback, nil)
+		boundPrimary.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("boom")).Times(2)
+		boundFallback.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).Return(schema.AssistantMessage("ok", nil), nil)
+
+		var gotTools []*schema.ToolInfo
+		handler := callbacks.NewHandlerBuilder().
+			OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+				if info.Type == "Resilient" {
+					gotTools = model.ConvCallbackInput(input).Tools
+				}
+				return ctx
+			}).Build()
+
+		cm := newTestModel(t, primary, fallback)
+		bound, err := cm.WithTools(tools)
+		require.NoError(t, err)
+		ctx := callbacks.InitCallbacks(context.Background(), nil, handler)
+		msg, err := bound.Generate(ctx, []*schema.Message{schema.UserMessage("hi")})
+		require.NoError(t, err)
+		assert.Equal(t, "ok", msg.Content)
+		assert.Equal(t, tools, gotTools)
+	})
+
+	t.Run("绑定失败", func(t *testing.T) {
+		primary := mockModel.NewMockToolCallingChatModel(ctrl)
+		primary.EXPECT().WithTools(tools).Return(nil, errors.New("unsupported"))
+		_, err := newTestModel(t, primary).WithTools(tools)
+		assert.ErrorContains(t, err, "model[0]")
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	cm := &chatModel{initialBackoff: 100 * time.Millisecond, maxBackoff: 350 * time.Millisecond, multiplier: 2}
+	assert.Equal(t, 100*time.Millisecond, cm.backoff(1))
+	assert.Equal(t, 200*time.Millisecond, cm.backoff(2))
+	assert.Equal(t, 350*time.Millisecond, cm.backoff(3))
+
+	cm.jitter = 0.5
+	for range 20 {
+		d := cm.backoff(1)
+		assert.GreaterOrEqual(t, d, 50*time.Millisecond)
+		assert.LessOrEqual(t, d, 150*time.Millisecond)
+	}
+}
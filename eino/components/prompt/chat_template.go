@@ -110,6 +110,30 @@ func (t *DefaultChatTemplate) Format(ctx context.Context,
 	return result, nil
 }
 
+// Variables 静态提取模板所需的变量，包括 MessagesPlaceholder 的键与多模态输入中的文本变量。
+//
+// 示例：
+//
+//	template := prompt.FromMessages(schema.FString,
+//		schema.SystemMessage("你是{role}"),
+//		schema.MessagesPlaceholder("history", true),
+//		schema.UserMessage("{query}"),
+//	)
+//	vars, _ := template.Variables()
+//	// vars: history（消息，可选）、query、role
+func (t *DefaultChatTemplate) Variables() ([]schema.TemplateVariable, error) {
+	groups := make([][]schema.TemplateVariable, 0, len(t.templates))
+	for _, template := range t.templates {
+		vars, err := schema.ExtractTemplateVariables(template, t.formatType)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, vars)
+	}
+
+	return schema.MergeTemplateVariables(groups...), nil
+}
+
 // GetType 返回聊天模板的类型（“Default”）。
 //
 // 用于组件的识别和调试。
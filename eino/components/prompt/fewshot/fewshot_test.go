@@ -0,0 +1,170 @@
+package fewshot
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/favbox/eino/callbacks"
+	"github.com/favbox/eino/components/embedding"
+	"github.com/favbox/eino/components/prompt"
+	"github.com/favbox/eino/schema"
+)
+
+// fakeEmbedder 按预置的向量表向量化文本。
+type fakeEmbedder struct {
+	vectors map[string][]float64
+}
+
+func (f *fakeEmbedder) EmbedStrings(_ context.Context, texts []string, _ ...embedding.Option) ([][]float64, error) {
+	out := make([][]float64, 0, len(texts))
+	for _, text := range texts {
+		v, ok := f.vectors[text]
+		if !ok {
+			return nil, errors.New("unknown text: " + text)
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+var (
+	good  = &Example{Input: "很好吃", Output: "positive"}
+	tasty = &Example{Input: "太美味了", Output: "positive"}
+	bad   = &Example{Input: "难吃", Output: "negative"}
+	slow  = &Example{Input: "上菜太慢", Output: "negative"}
+
+	embedder = &fakeEmbedder{vectors: map[string][]float64{
+		"很好吃":   {1, 0, 0},
+		"太美味了":  {0.98, 0.02, 0},
+		"难吃":    {0, 1, 0},
+		"上菜太慢":  {0.1, 0.6, 0.8},
+		"味道真不错": {1, 0.01, 0},
+	}}
+)
+
+func TestSelectors(t *testing.T) {
+	ctx := context.Background()
+	examples := []*Example{good, tasty, bad, slow}
+
+	t.Run("固定选择器", func(t *testing.T) {
+		selected, err := NewFixedSelector(bad, good).Select(ctx, "")
+		assert.NoError(t, err)
+		assert.Equal(t, []*Example{bad, good}, selected)
+	})
+
+	t.Run("随机选择器可复现", func(t *testing.T) {
+		_, err := NewRandomSelector(&RandomSelectorConfig{})
+		assert.Error(t, err)
+
+		newSelector := func() *RandomSelector {
+			s, err := NewRandomSelector(&RandomSelectorConfig{Examples: examples, K: 2, Seed: 42})
+			assert.NoError(t, err)
+			return s
+		}
+		s1, s2 := newSelector(), newSelector()
+		for i := 0; i < 5; i++ {
+			a, err := s1.Select(ctx, "")
+			assert.NoError(t, err)
+			b, err := s2.Select(ctx, "")
+			assert.NoError(t, err)
+			assert.Len(t, a, 2)
+			assert.Equal(t, a, b)
+		}
+	})
+
+	t.Run("语义相似度选择器", func(t *testing.T) {
+		s, err := NewSemanticSelector(ctx, &SemanticSelectorConfig{Embedder: embedder, Examples: examples, K: 2})
+		assert.NoError(t, err)
+
+		selected, err := s.Select(ctx, "味道真不错")
+		assert.NoError(t, err)
+		// 最相似的排在最后
+		assert.Equal(t, []*Example{tasty, good}, selected)
+
+		_, err = s.Select(ctx, "")
+		assert.ErrorContains(t, err, "query is required")
+	})
+
+	t.Run("最大边际相关性选择器", func(t *testing.T) {
+		lambda := 0.3
+		s, err := NewMMRSelector(ctx, &MMRSelectorConfig{Embedder: embedder, Examples: examples, K: 2, Lambda: &lambda})
+		assert.NoError(t, err)
+
+		// 与 good 几乎重复的 tasty 被跳过，换成差异更大的示例
+		selected, err := s.Select(ctx, "味道真不错")
+		assert.NoError(t, err)
+		assert.Len(t, selected, 2)
+		assert.Equal(t, good, selected[1])
+		assert.NotContains(t, selected, tasty)
+
+		invalid := 1.5
+		_, err = NewMMRSelector(ctx, &MMRSelectorConfig{Embedder: embedder, Examples: examples, Lambda: &invalid})
+		assert.ErrorContains(t, err, "'Lambda' must be in [0, 1]")
+	})
+}
+
+func TestChatTemplate(t *testing.T) {
+	ctx := context.Background()
+
+	templates := []schema.MessagesTemplate{
+		schema.SystemMessage("将{target}分类为 positive 或 negative"),
+		schema.MessagesPlaceholder(DefaultExamplesKey, false),
+		schema.UserMessage("{query}"),
+	}
+
+	t.Run("缺少示例占位符", func(t *testing.T) {
+		_, err := New(&Config{Templates: templates[:1], Selector: NewFixedSelector(good)})
+		assert.ErrorContains(t, err, `messages placeholder with key "examples"`)
+	})
+
+	t.Run("示例渲染与回调", func(t *testing.T) {
+		s, err := NewSemanticSelector(ctx, &SemanticSelectorConfig{Embedder: embedder, Examples: []*Example{good, bad}, K: 1})
+		assert.NoError(t, err)
+		tpl, err := New(&Config{FormatType: schema.FString, Templates: templates, Selector: s})
+		assert.NoError(t, err)
+
+		vars, err := tpl.Variables()
+		assert.NoError(t, err)
+		assert.Equal(t, []schema.TemplateVariable{{Name: "query"}, {Name: "target"}}, vars)
+
+		var (
+			startExtra, endExtra map[string]any
+			componentType        string
+		)
+		handler := callbacks.NewHandlerBuilder().
+			OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+				componentType = info.Type
+				startExtra = prompt.ConvCallbackInput(input).Extra
+				return ctx
+			}).
+			OnEndFn(func(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+				endExtra = prompt.ConvCallbackOutput(output).Extra
+				return ctx
+			}).Build()
+
+		vs := map[string]any{"target": "评论", "query": "味道真不错"}
+		msgs, err := tpl.Format(callbacks.InitCallbacks(ctx, nil, handler), vs)
+		assert.NoError(t, err)
+		assert.Equal(t, []*schema.Message{
+			schema.SystemMessage("将评论分类为 positive 或 negative"),
+			schema.UserMessage("很好吃"),
+			schema.AssistantMessage("positive", nil),
+			schema.UserMessage("味道真不错"),
+		}, msgs)
+		assert.NotContains(t, vs, DefaultExamplesKey)
+
+		assert.Equal(t, "FewShot", componentType)
+		assert.Equal(t, map[string]any{ExtraKeySelector: "Semantic"}, startExtra)
+		assert.Equal(t, map[string]any{ExtraKeySelector: "Semantic", ExtraKeyExamples: []*Example{good}}, endExtra)
+	})
+
+	t.Run("查询变量类型错误", func(t *testing.T) {
+		tpl, err := New(&Config{Templates: templates, Selector: NewFixedSelector(good)})
+		assert.NoError(t, err)
+		_, err = tpl.Format(ctx, map[string]any{"target": "评论", "query": 1})
+		assert.ErrorContains(t, err, "must be a string")
+	})
+}
@@ -0,0 +1,337 @@
+package fewshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+
+	"github.com/favbox/eino/components/embedding"
+)
+
+// Example 一条少样本示例，渲染为一对 user/assistant 消息。
+type Example struct {
+	// Input 示例输入，渲染为用户消息。
+	Input string `json:"input"`
+	// Output 示例输出，渲染为助手消息。
+	Output string `json:"output"`
+}
+
+// Selector 示例选择器，根据本次请求的查询文本选出要注入的示例，返回顺序即渲染顺序。
+// 实现 components.Typer 的选择器，其类型会出现在回调 Extra 中。
+type Selector interface {
+	Select(ctx context.Context, query string) ([]*Example, error)
+}
+
+var (
+	_ Selector = &FixedSelector{}
+	_ Selector = &RandomSelector{}
+	_ Selector = &SemanticSelector{}
+	_ Selector = &MMRSelector{}
+)
+
+// FixedSelector 总是返回固定的示例。
+type FixedSelector struct {
+	examples []*Example
+}
+
+// NewFixedSelector 创建固定示例选择器。
+func NewFixedSelector(examples ...*Example) *FixedSelector {
+	return &FixedSelector{examples: examples}
+}
+
+// Select 按原顺序返回全部固定示例。
+func (s *FixedSelector) Select(_ context.Context, _ string) ([]*Example, error) {
+	return s.examples, nil
+}
+
+// GetType 返回选择器类型（“Fixed”）。
+func (s *FixedSelector) GetType() string {
+	return "Fixed"
+}
+
+// RandomSelectorConfig 随机选择器配置。
+type RandomSelectorConfig struct {
+	// Examples 候选示例，必填。
+	Examples []*Example
+	// K 每次选出的示例数量，默认 4，超过候选数量时返回全部候选。
+	K int
+	// Seed 随机种子，相同种子下的选择序列可复现。
+	Seed int64
+}
+
+// RandomSelector 从候选示例中随机选出 K 个，保持候选中的相对顺序。
+type RandomSelector struct {
+	examples []*Example
+	k        int
+
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// NewRandomSelector 创建带种子的随机选择器。
+func NewRandomSelector(config *RandomSelectorConfig) (*RandomSelector, error) {
+	if config == nil || len(config.Examples) == 0 {
+		return nil, errors.New("random selector 'Examples' is required")
+	}
+
+	return &RandomSelector{
+		examples: config.Examples,
+		k:        defaultK(config.K),
+		rnd:      rand.New(rand.NewSource(config.Seed)),
+	}, nil
+}
+
+// Select 随机选出 K 个示例。
+func (s *RandomSelector) Select(_ context.Context, _ string) ([]*Example, error) {
+	k := min(s.k, len(s.examples))
+
+	s.mu.Lock()
+	indexes := s.rnd.Perm(len(s.examples))[:k]
+	s.mu.Unlock()
+
+	sort.Ints(indexes)
+	selected := make([]*Example, 0, k)
+	for _, i := range indexes {
+		selected = append(selected, s.examples[i])
+	}
+
+	return selected, nil
+}
+
+// GetType 返回选择器类型（“Random”）。
+func (s *RandomSelector) GetType() string {
+	return "Random"
+}
+
+// SemanticSelectorConfig 语义相似度选择器配置。
+type SemanticSelectorConfig struct {
+	// Embedder 向量化示例与查询的嵌入模型，必填。
+	Embedder embedding.Embedder
+	// Examples 候选示例，必填，创建选择器时一次性向量化。
+	Examples []*Example
+	// K 每次选出的示例数量，默认 4。
+	K int
+	// ExampleText 返回示例参与相似度计算的文本，默认使用 Example.Input。
+	ExampleText func(example *Example) string
+}
+
+// SemanticSelector 按与查询的余弦相似度选出最相似的 K 个示例，最相似的排在最后，紧邻真实的用户输入。
+type SemanticSelector struct {
+	index *exampleIndex
+	k     int
+}
+
+// NewSemanticSelector 创建语义相似度选择器，创建时向量化所有候选示例。
+func NewSemanticSelector(ctx context.Context, config *SemanticSelectorConfig) (*SemanticSelector, error) {
+	if config == nil {
+		return nil, errors.New("semantic selector config is required")
+	}
+	index, err := newExampleIndex(ctx, config.Embedder, config.Examples, config.ExampleText)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SemanticSelector{index: index, k: defaultK(config.K)}, nil
+}
+
+// Select 选出与查询最相似的 K 个示例。
+func (s *SemanticSelector) Select(ctx context.Context, query string) ([]*Example, error) {
+	scores, err := s.index.similarities(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	order := make([]int, len(scores))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return scores[order[i]] > scores[order[j]] })
+	order = order[:min(s.k, len(order))]
+
+	selected := make([]*Example, 0, len(order))
+	for i := len(order) - 1; i >= 0; i-- {
+		selected = append(selected, s.index.examples[order[i]])
+	}
+
+	return selected, nil
+}
+
+// GetType 返回选择器类型（“Semantic”）。
+func (s *SemanticSelector) GetType() string {
+	return "Semantic"
+}
+
+// MMRSelectorConfig 最大边际相关性选择器配置。
+type MMRSelectorConfig struct {
+	// Embedder 向量化示例与查询的嵌入模型，必填。
+	Embedder embedding.Embedder
+	// Examples 候选示例，必填，创建选择器时一次性向量化。
+	Examples []*Example
+	// K 每次选出的示例数量，默认 4。
+	K int
+	// FetchK 参与多样性重排的候选数量（按相似度取前 FetchK 个），默认 K 的 4 倍。
+	FetchK int
+	// Lambda 相关性与多样性的权衡系数，取值 [0, 1]，越大越偏向相关性，默认 0.5。
+	Lambda *float64
+	// ExampleText 返回示例参与相似度计算的文本，默认使用 Example.Input。
+	ExampleText func(example *Example) string
+}
+
+// MMRSelector 使用最大边际相关性（Maximal Marginal Relevance）选出与查询相关且彼此不重复的示例，
+// 按选出顺序的逆序返回，使最相关的示例紧邻真实的用户输入。
+type MMRSelector struct {
+	index  *exampleIndex
+	k      int
+	fetchK int
+	lambda float64
+}
+
+// NewMMRSelector 创建最大边际相关性选择器，创建时向量化所有候选示例。
+func NewMMRSelector(ctx context.Context, config *MMRSelectorConfig) (*MMRSelector, error) {
+	if config == nil {
+		return nil, errors.New("mmr selector config is required")
+	}
+	index, err := newExampleIndex(ctx, config.Embedder, config.Examples, config.ExampleText)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &MMRSelector{index: index, k: defaultK(config.K), fetchK: config.FetchK, lambda: 0.5}
+	if s.fetchK <= 0 {
+		s.fetchK = s.k * 4
+	}
+	if config.Lambda != nil {
+		if *config.Lambda < 0 || *config.Lambda > 1 {
+			return nil, fmt.Errorf("mmr selector 'Lambda' must be in [0, 1], got %v", *config.Lambda)
+		}
+		s.lambda = *config.Lambda
+	}
+
+	return s, nil
+}
+
+// Select 按最大边际相关性选出 K 个示例。
+func (s *MMRSelector) Select(ctx context.Context, query string) ([]*Example, error) {
+	scores, err := s.index.similarities(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]int, len(scores))
+	for i := range candidates {
+		candidates[i] = i
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return scores[candidates[i]] > scores[candidates[j]] })
+	candidates = candidates[:min(s.fetchK, len(candidates))]
+
+	var picked []int
+	for len(picked) < s.k && len(candidates) > 0 {
+		best, bestScore := 0, math.Inf(-1)
+		for ci, c := range candidates {
+			redundancy := 0.0
+			if len(picked) > 0 {
+				redundancy = math.Inf(-1)
+				for _, p := range picked {
+					redundancy = math.Max(redundancy, cosineSimilarity(s.index.vectors[c], s.index.vectors[p]))
+				}
+			}
+			score := s.lambda*scores[c] - (1-s.lambda)*redundancy
+			if score > bestScore {
+				best, bestScore = ci, score
+			}
+		}
+		picked = append(picked, candidates[best])
+		candidates = append(candidates[:best], candidates[best+1:]...)
+	}
+
+	selected := make([]*Example, 0, len(picked))
+	for i := len(picked) - 1; i >= 0; i-- {
+		selected = append(selected, s.index.examples[picked[i]])
+	}
+
+	return selected, nil
+}
+
+// GetType 返回选择器类型（“MMR”）。
+func (s *MMRSelector) GetType() string {
+	return "MMR"
+}
+
+// exampleIndex 候选示例及其向量。
+type exampleIndex struct {
+	embedder embedding.Embedder
+	examples []*Example
+	vectors  [][]float64
+}
+
+func newExampleIndex(ctx context.Context, embedder embedding.Embedder, examples []*Example,
+	exampleText func(*Example) string) (*exampleIndex, error) {
+	if embedder == nil {
+		return nil, errors.New("selector 'Embedder' is required")
+	}
+	if len(examples) == 0 {
+		return nil, errors.New("selector 'Examples' is required")
+	}
+	if exampleText == nil {
+		exampleText = func(e *Example) string { return e.Input }
+	}
+
+	texts := make([]string, 0, len(examples))
+	for _, e := range examples {
+		texts = append(texts, exampleText(e))
+	}
+	vectors, err := embedder.EmbedStrings(ctx, texts)
+	if err != nil {
+		return nil, fmt.Errorf("embed examples failed: %w", err)
+	}
+	if len(vectors) != len(examples) {
+		return nil, fmt.Errorf("embedder returned %d vectors for %d examples", len(vectors), len(examples))
+	}
+
+	return &exampleIndex{embedder: embedder, examples: examples, vectors: vectors}, nil
+}
+
+// similarities 返回查询与每个候选示例的余弦相似度。
+func (idx *exampleIndex) similarities(ctx context.Context, query string) ([]float64, error) {
+	if query == "" {
+		return nil, errors.New("query is required for embedding based selector")
+	}
+	vectors, err := idx.embedder.EmbedStrings(ctx, []string{query})
+	if err != nil {
+		return nil, fmt.Errorf("embed query failed: %w", err)
+	}
+	if len(vectors) != 1 {
+		return nil, fmt.Errorf("embedder returned %d vectors for 1 query", len(vectors))
+	}
+
+	scores := make([]float64, len(idx.vectors))
+	for i, v := range idx.vectors {
+		scores[i] = cosineSimilarity(vectors[0], v)
+	}
+
+	return scores, nil
+}
+
+func cosineSimilarity(a, b []float64) float64 {
+	var dot, normA, normB float64
+	for i := 0; i < len(a) && i < len(b); i++ {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+func defaultK(k int) int {
+	if k <= 0 {
+		return 4
+	}
+	return k
+}
@@ -0,0 +1,212 @@
+// Package fewshot 提供注入少样本示例的 ChatTemplate。
+//
+// 模板在格式化时通过可插拔的 Selector 选出示例，渲染为交替的 user/assistant 消息，
+// 放在以 ExamplesKey 为键的 schema.MessagesPlaceholder 处。内置选择器：
+//   - FixedSelector：固定示例
+//   - RandomSelector：带种子的随机选择
+//   - SemanticSelector：基于 embedding.Embedder 的语义相似度
+//   - MMRSelector：最大边际相关性，兼顾相关性与多样性
+//
+// 选择器类型与选出的示例会写入回调 Extra（见 ExtraKeySelector、ExtraKeyExamples），便于排查模型看到了哪些示例。
+//
+// 示例：
+//
+//	selector, _ := fewshot.NewSemanticSelector(ctx, &fewshot.SemanticSelectorConfig{
+//		Embedder: embedder,
+//		Examples: examples,
+//		K:        3,
+//	})
+//	tpl, _ := fewshot.New(&fewshot.Config{
+//		FormatType: schema.FString,
+//		Templates: []schema.MessagesTemplate{
+//			schema.SystemMessage("将用户输入分类为 positive 或 negative"),
+//			schema.MessagesPlaceholder(fewshot.DefaultExamplesKey, false),
+//			schema.UserMessage("{query}"),
+//		},
+//		Selector: selector,
+//	})
+package fewshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/favbox/eino/callbacks"
+	"github.com/favbox/eino/components"
+	"github.com/favbox/eino/components/prompt"
+	"github.com/favbox/eino/schema"
+)
+
+const (
+	// DefaultExamplesKey 示例占位符的默认变量名。
+	DefaultExamplesKey = "examples"
+	// DefaultQueryKey 传给选择器的查询文本的默认变量名。
+	DefaultQueryKey = "query"
+
+	// ExtraKeySelector 回调 Extra 中的选择器类型键。
+	ExtraKeySelector = "fewshot_selector"
+	// ExtraKeyExamples 回调 Extra 中选出的示例键，值为 []*Example，仅在 OnEnd 中提供。
+	ExtraKeyExamples = "fewshot_examples"
+)
+
+var _ prompt.ChatTemplate = &ChatTemplate{}
+var _ prompt.VariablesExtractor = &ChatTemplate{}
+
+// Config 少样本模板配置。
+type Config struct {
+	// FormatType 消息模板的格式类型。
+	FormatType schema.FormatType
+	// Templates 消息模板，必须包含以 ExamplesKey 为键的 schema.MessagesPlaceholder。
+	Templates []schema.MessagesTemplate
+	// Selector 示例选择器，必填。
+	Selector Selector
+	// ExamplesKey 示例占位符的变量名，默认 DefaultExamplesKey。
+	ExamplesKey string
+	// QueryKey 查询文本的变量名，默认 DefaultQueryKey，变量值需为字符串，缺失时查询文本为空。
+	QueryKey string
+}
+
+// ChatTemplate 注入少样本示例的聊天模板。
+type ChatTemplate struct {
+	templates   []schema.MessagesTemplate
+	formatType  schema.FormatType
+	selector    Selector
+	examplesKey string
+	queryKey    string
+}
+
+// New 创建少样本聊天模板。
+func New(config *Config) (*ChatTemplate, error) {
+	if config == nil {
+		return nil, errors.New("fewshot config is required")
+	}
+	if config.Selector == nil {
+		return nil, errors.New("fewshot 'Selector' is required")
+	}
+
+	t := &ChatTemplate{
+		templates:   config.Templates,
+		formatType:  config.FormatType,
+		selector:    config.Selector,
+		examplesKey: config.ExamplesKey,
+		queryKey:    config.QueryKey,
+	}
+	if t.examplesKey == "" {
+		t.examplesKey = DefaultExamplesKey
+	}
+	if t.queryKey == "" {
+		t.queryKey = DefaultQueryKey
+	}
+
+	vars, err := prompt.FromMessages(t.formatType, t.templates...).Variables()
+	if err != nil {
+		return nil, fmt.Errorf("extract template variables failed: %w", err)
+	}
+	for _, v := range vars {
+		if v.Name == t.examplesKey && v.IsMessages {
+			return t, nil
+		}
+	}
+
+	return nil, fmt.Errorf("templates must contain a messages placeholder with key %q", t.examplesKey)
+}
+
+// Format 选出示例并格式化模板。
+func (t *ChatTemplate) Format(ctx context.Context, vs map[string]any, _ ...prompt.Option) (result []*schema.Message, err error) {
+	ctx = callbacks.EnsureRunInfo(ctx, t.GetType(), components.ComponentOfPrompt)
+	ctx = callbacks.OnStart(ctx, &prompt.CallbackInput{
+		Variables: vs,
+		Templates: t.templates,
+		Extra:     map[string]any{ExtraKeySelector: t.selectorType()},
+	})
+	defer func() {
+		if err != nil {
+			_ = callbacks.OnError(ctx, err)
+		}
+	}()
+
+	var query string
+	if v, ok := vs[t.queryKey]; ok {
+		if query, ok = v.(string); !ok {
+			return nil, fmt.Errorf("query variable %q must be a string, got %T", t.queryKey, v)
+		}
+	}
+	examples, err := t.selector.Select(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("select examples failed: %w", err)
+	}
+
+	// 复制变量，避免修改调用方的 map
+	variables := make(map[string]any, len(vs)+1)
+	for k, v := range vs {
+		variables[k] = v
+	}
+	variables[t.examplesKey] = ExamplesToMessages(examples)
+
+	result = make([]*schema.Message, 0, len(t.templates)+len(examples)*2)
+	for _, template := range t.templates {
+		msgs, err := template.Format(ctx, variables, t.formatType)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, msgs...)
+	}
+
+	_ = callbacks.OnEnd(ctx, &prompt.CallbackOutput{
+		Result:    result,
+		Templates: t.templates,
+		Extra: map[string]any{
+			ExtraKeySelector: t.selectorType(),
+			ExtraKeyExamples: examples,
+		},
+	})
+
+	return result, nil
+}
+
+// Variables 返回模板所需的变量，示例占位符由模板自身提供，不包含在内。
+func (t *ChatTemplate) Variables() ([]schema.TemplateVariable, error) {
+	vars, err := prompt.FromMessages(t.formatType, t.templates...).Variables()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]schema.TemplateVariable, 0, len(vars))
+	for _, v := range vars {
+		if v.Name != t.examplesKey {
+			result = append(result, v)
+		}
+	}
+
+	return result, nil
+}
+
+// GetType 返回模板类型（“FewShot”）。
+func (t *ChatTemplate) GetType() string {
+	return "FewShot"
+}
+
+// IsCallbacksEnabled 返回 true，由模板自行触发回调。
+func (t *ChatTemplate) IsCallbacksEnabled() bool {
+	return true
+}
+
+func (t *ChatTemplate) selectorType() string {
+	if typ, ok := components.GetType(t.selector); ok {
+		return typ
+	}
+	return fmt.Sprintf("%T", t.selector)
+}
+
+// ExamplesToMessages 将示例渲染为交替的 user/assistant 消息，内容不参与模板格式化。
+func ExamplesToMessages(examples []*Example) []*schema.Message {
+	msgs := make([]*schema.Message, 0, len(examples)*2)
+	for _, e := range examples {
+		if e == nil {
+			continue
+		}
+		msgs = append(msgs, schema.UserMessage(e.Input), schema.AssistantMessage(e.Output, nil))
+	}
+	return msgs
+}
@@ -7,9 +7,17 @@ import (
 )
 
 var _ ChatTemplate = &DefaultChatTemplate{}
+var _ VariablesExtractor = &DefaultChatTemplate{}
 
 // ChatTemplate 提示词模板接口，用于格式化模板生成消息列表。
 type ChatTemplate interface {
 	// Format 使用给定的变量格式化模板，生成消息列表。
 	Format(ctx context.Context, vs map[string]any, opts ...Option) ([]*schema.Message, error)
 }
+
+// VariablesExtractor 可选接口，能够静态给出所需变量的 ChatTemplate 实现它，
+// 以便在格式化前或图编译时（见 compose.WithStrictTemplateVariables）校验变量是否齐全。
+type VariablesExtractor interface {
+	// Variables 返回模板引用的全部变量。
+	Variables() ([]schema.TemplateVariable, error)
+}
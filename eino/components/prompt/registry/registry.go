@@ -0,0 +1,297 @@
+// Package registry 提供基于文件的提示词模板注册中心。
+//
+// 模板以 YAML 或 JSON 文件描述（见 TemplateSpec），包含消息角色、内容、格式类型、
+// 消息占位符与元数据，注册中心按名称与版本解析模板，支持通过 include 复用其他模板（partial），
+// 并返回可直接用于编排的 prompt.ChatTemplate。模板格式化时，名称与版本会写入
+// prompt.CallbackInput.Extra，便于链路追踪区分不同版本的提示词。
+//
+// 示例：
+//
+//	reg := registry.New()
+//	if err := reg.LoadDir("./prompts"); err != nil {
+//		return err
+//	}
+//	tpl, err := reg.Get("customer_service", "") // 版本为空时使用最新版本
+//	if err != nil {
+//		return err
+//	}
+//	chain := compose.NewChain[map[string]any, []*schema.Message]()
+//	chain.AppendChatTemplate(tpl)
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/favbox/eino/schema"
+)
+
+// Registry 提示词模板注册中心，并发安全。
+type Registry struct {
+	mu        sync.RWMutex
+	templates map[string]map[string]*TemplateSpec // name -> version -> spec
+}
+
+// New 创建空的模板注册中心。
+func New() *Registry {
+	return &Registry{templates: make(map[string]map[string]*TemplateSpec)}
+}
+
+// Register 注册模板声明，同名同版本（如 v1 与 1 视为同一版本）的模板只能注册一次。
+// 注册时校验声明结构与消息内容的模板语法，并检查与已注册模板之间 include 的格式类型是否一致；
+// include 的模板在 Get 时才完整解析，因此注册顺序无关。
+func (r *Registry) Register(spec *TemplateSpec) error {
+	if spec == nil {
+		return errors.New("template spec is nil")
+	}
+	if err := spec.validate(); err != nil {
+		return fmt.Errorf("invalid template %s: %w", refString(spec.Name, spec.Version), err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	versions, ok := r.templates[spec.Name]
+	if !ok {
+		versions = make(map[string]*TemplateSpec)
+		r.templates[spec.Name] = versions
+	}
+	for v := range versions {
+		if v == spec.Version {
+			return fmt.Errorf("template %s already registered", refString(spec.Name, spec.Version))
+		}
+		if compareVersions(v, spec.Version) == 0 {
+			return fmt.Errorf("template %s conflicts with registered version %q", refString(spec.Name, spec.Version), v)
+		}
+	}
+	versions[spec.Version] = spec
+
+	if err := r.checkIncludeFormats(spec); err != nil {
+		delete(versions, spec.Version)
+		if len(versions) == 0 {
+			delete(r.templates, spec.Name)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// checkIncludeFormats 检查 spec 与其 include 的模板、以及 include 了 spec 的模板之间格式类型是否一致。
+// 尚未注册的模板在 Get 时再检查。调用方需持有写锁。
+func (r *Registry) checkIncludeFormats(spec *TemplateSpec) error {
+	for _, versions := range r.templates {
+		for _, s := range versions {
+			for _, m := range s.Messages {
+				if m.Include == "" {
+					continue
+				}
+				included, err := r.resolve(parseRef(m.Include))
+				if err != nil || (s != spec && included != spec) {
+					continue
+				}
+				if err = checkIncludeFormat(s, included); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkIncludeFormat 检查被 include 的模板与 include 它的模板格式类型一致，未声明格式时视为 fstring。
+func checkIncludeFormat(spec, included *TemplateSpec) error {
+	formatType, _ := parseFormatType(spec.Format)
+	if includedType, _ := parseFormatType(included.Format); includedType != formatType {
+		return fmt.Errorf("included template %s has format %q, which differs from template %s",
+			refString(included.Name, included.Version), formatName(included.Format), refString(spec.Name, spec.Version))
+	}
+	return nil
+}
+
+// LoadFile 加载并注册单个模板文件。
+func (r *Registry) LoadFile(filePath string) error {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("read template file failed: %w", err)
+	}
+
+	return r.load(data, filePath)
+}
+
+// LoadDir 递归加载目录下所有 .yaml、.yml、.json 模板文件。
+func (r *Registry) LoadDir(dir string) error {
+	return r.LoadFS(os.DirFS(dir), ".")
+}
+
+// LoadFS 递归加载文件系统 root 目录下的所有模板文件，可配合 embed.FS 将模板打包进二进制。
+func (r *Registry) LoadFS(fsys fs.FS, root string) error {
+	return fs.WalkDir(fsys, root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !isSpecFile(p) {
+			return nil
+		}
+
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return fmt.Errorf("read template file failed: %w", err)
+		}
+		return r.load(data, p)
+	})
+}
+
+func (r *Registry) load(data []byte, filePath string) error {
+	spec, err := ParseSpec(data, path.Ext(filePath))
+	if err != nil {
+		return fmt.Errorf("load template file %s failed: %w", filePath, err)
+	}
+	if err = r.Register(spec); err != nil {
+		return fmt.Errorf("load template file %s failed: %w", filePath, err)
+	}
+
+	return nil
+}
+
+// Names 返回所有已注册的模板名称（包括 partial 模板），按名称排序。
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.templates))
+	for name := range r.templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Versions 返回模板的所有版本，按版本号从低到高排序。
+func (r *Registry) Versions(name string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	versions := make([]string, 0, len(r.templates[name]))
+	for v := range r.templates[name] {
+		versions = append(versions, v)
+	}
+	sort.Slice(versions, func(i, j int) bool { return compareVersions(versions[i], versions[j]) < 0 })
+
+	return versions
+}
+
+// Get 按名称与版本获取模板，version 为空时返回最新版本。
+// 返回的模板已展开所有 include，partial 模板不能直接获取。
+func (r *Registry) Get(name, version string) (*Template, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	spec, err := r.resolve(name, version)
+	if err != nil {
+		return nil, err
+	}
+	if spec.Partial {
+		return nil, fmt.Errorf("template %s is partial and can only be included", refString(spec.Name, spec.Version))
+	}
+
+	formatType, _ := parseFormatType(spec.Format)
+	templates, err := r.expand(spec, formatType, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Template{
+		name:        spec.Name,
+		version:     spec.Version,
+		description: spec.Description,
+		metadata:    spec.Metadata,
+		templates:   templates,
+		formatType:  formatType,
+	}, nil
+}
+
+// resolve 查找指定版本的模板声明，调用方需持有读锁。
+func (r *Registry) resolve(name, version string) (*TemplateSpec, error) {
+	versions, ok := r.templates[name]
+	if !ok {
+		return nil, fmt.Errorf("template %q not found", name)
+	}
+	if version != "" {
+		if spec, ok := versions[version]; ok {
+			return spec, nil
+		}
+		// 注册时已保证等价版本唯一，因此 1 可以找到 v1
+		for v, spec := range versions {
+			if compareVersions(v, version) == 0 {
+				return spec, nil
+			}
+		}
+		return nil, fmt.Errorf("template %s not found", refString(name, version))
+	}
+
+	var latest *TemplateSpec
+	for _, spec := range versions {
+		if latest == nil || compareVersions(spec.Version, latest.Version) > 0 {
+			latest = spec
+		}
+	}
+
+	return latest, nil
+}
+
+// expand 将模板声明展开为消息模板列表，递归处理 include 并检测循环引用。
+func (r *Registry) expand(spec *TemplateSpec, formatType schema.FormatType, stack []string) ([]schema.MessagesTemplate, error) {
+	ref := refString(spec.Name, spec.Version)
+	for _, s := range stack {
+		if s == ref {
+			return nil, fmt.Errorf("circular include detected: %s -> %s", strings.Join(stack, " -> "), ref)
+		}
+	}
+	stack = append(stack, ref)
+
+	templates := make([]schema.MessagesTemplate, 0, len(spec.Messages))
+	for _, m := range spec.Messages {
+		switch {
+		case m.Placeholder != "":
+			templates = append(templates, schema.MessagesPlaceholder(m.Placeholder, m.Optional))
+		case m.Include != "":
+			included, err := r.resolve(parseRef(m.Include))
+			if err != nil {
+				return nil, fmt.Errorf("resolve include %q of template %s failed: %w", m.Include, ref, err)
+			}
+			if includedType, _ := parseFormatType(included.Format); includedType != formatType {
+				return nil, fmt.Errorf("included template %s has format %q, which differs from template %s",
+					refString(included.Name, included.Version), formatName(included.Format), stack[0])
+			}
+			sub, err := r.expand(included, formatType, stack)
+			if err != nil {
+				return nil, err
+			}
+			templates = append(templates, sub...)
+		default:
+			msg, err := m.toMessage()
+			if err != nil {
+				return nil, err
+			}
+			templates = append(templates, msg)
+		}
+	}
+
+	return templates, nil
+}
+
+func refString(name, version string) string {
+	if version == "" {
+		return name
+	}
+	return name + "@" + version
+}
@@ -0,0 +1,161 @@
+package registry
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/favbox/eino/callbacks"
+	"github.com/favbox/eino/components/prompt"
+	"github.com/favbox/eino/schema"
+)
+
+func TestRegistry(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("从目录加载并按版本解析", func(t *testing.T) {
+		reg := New()
+		assert.NoError(t, reg.LoadDir("testdata"))
+		assert.Equal(t, []string{"customer_service", "safety_rules"}, reg.Names())
+		assert.Equal(t, []string{"v1", "v2"}, reg.Versions("customer_service"))
+
+		tpl, err := reg.Get("customer_service", "")
+		assert.NoError(t, err)
+		assert.Equal(t, "v2", tpl.Version())
+		assert.Equal(t, map[string]any{"owner": "support"}, tpl.Metadata())
+
+		history := []*schema.Message{schema.UserMessage("在吗"), schema.AssistantMessage("在的", nil)}
+		msgs, err := tpl.Format(ctx, map[string]any{"company": "favbox", "query": "怎么退货", "history": history})
+		assert.NoError(t, err)
+		assert.Equal(t, []*schema.Message{
+			schema.SystemMessage("不要泄露favbox的内部信息"),
+			schema.SystemMessage("你是favbox的客服助手"),
+			history[0], history[1],
+			schema.UserMessage("怎么退货"),
+		}, msgs)
+
+		vars, err := tpl.Variables()
+		assert.NoError(t, err)
+		assert.Equal(t, []schema.TemplateVariable{
+			{Name: "company"}, {Name: "history", IsMessages: true, Optional: true}, {Name: "query"},
+		}, vars)
+
+		tpl, err = reg.Get("customer_service", "v1")
+		assert.NoError(t, err)
+		msgs, err = tpl.Format(ctx, map[string]any{"company": "favbox", "query": "你好"})
+		assert.NoError(t, err)
+		assert.Len(t, msgs, 2)
+
+		_, err = reg.Get("customer_service", "v3")
+		assert.ErrorContains(t, err, "customer_service@v3 not found")
+		_, err = reg.Get("safety_rules", "")
+		assert.ErrorContains(t, err, "partial")
+	})
+
+	t.Run("回调携带模板名称与版本", func(t *testing.T) {
+		reg := New()
+		assert.NoError(t, reg.LoadDir("testdata"))
+		tpl, err := reg.Get("customer_service", "v1")
+		assert.NoError(t, err)
+
+		var startExtra, endExtra map[string]any
+		handler := callbacks.NewHandlerBuilder().
+			OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+				assert.Equal(t, "Registry", info.Type)
+				startExtra = prompt.ConvCallbackInput(input).Extra
+				return ctx
+			}).
+			OnEndFn(func(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+				endExtra = prompt.ConvCallbackOutput(output).Extra
+				return ctx
+			}).Build()
+		cbCtx := callbacks.InitCallbacks(ctx, nil, handler)
+
+		_, err = tpl.Format(cbCtx, map[string]any{"company": "favbox", "query": "你好"})
+		assert.NoError(t, err)
+		expected := map[string]any{
+			ExtraKeyTemplateName:     "customer_service",
+			ExtraKeyTemplateVersion:  "v1",
+			ExtraKeyTemplateMetadata: map[string]any{"owner": "support"},
+		}
+		assert.Equal(t, expected, startExtra)
+		assert.Equal(t, expected, endExtra)
+	})
+
+	t.Run("指定版本的引用与格式类型", func(t *testing.T) {
+		reg := New()
+		assert.NoError(t, reg.LoadFS(fstest.MapFS{
+			"base_v1.yml":  {Data: []byte("name: base\nversion: '1.2'\nformat: jinja2\npartial: true\nmessages:\n  - role: system\n    content: 'v1.2 {{ rule }}'\n")},
+			"base_v2.yml":  {Data: []byte("name: base\nversion: '1.10'\nformat: jinja2\npartial: true\nmessages:\n  - role: system\n    content: 'v1.10 {{ rule }}'\n")},
+			"main.json":    {Data: []byte(`{"name":"main","format":"jinja2","messages":[{"include":"base@1.2"},{"include":"base"},{"role":"user","content":"{{ query }}"}]}`)},
+			"ignored.md":   {Data: []byte("# 非模板文件")},
+			"other/x.yaml": {Data: []byte("name: other\nformat: go_template\nmessages:\n  - role: user\n    content: '{{.query}}'\n")},
+		}, "."))
+
+		tpl, err := reg.Get("main", "")
+		assert.NoError(t, err)
+		msgs, err := tpl.Format(ctx, map[string]any{"rule": "礼貌", "query": "你好"})
+		assert.NoError(t, err)
+		assert.Equal(t, []*schema.Message{
+			schema.SystemMessage("v1.2 礼貌"),
+			schema.SystemMessage("v1.10 礼貌"),
+			schema.UserMessage("你好"),
+		}, msgs)
+	})
+
+	t.Run("非法模板", func(t *testing.T) {
+		reg := New()
+		assert.ErrorContains(t, reg.Register(&TemplateSpec{Name: "a", Messages: []*MessageSpec{{Role: "user", Placeholder: "x"}}}),
+			"exactly one of")
+		assert.ErrorContains(t, reg.Register(&TemplateSpec{Name: "a", Messages: []*MessageSpec{{Role: "bot", Content: "hi"}}}),
+			"unknown message role")
+		assert.ErrorContains(t, reg.Register(&TemplateSpec{Name: "a", Format: "mustache", Messages: []*MessageSpec{{Role: "user"}}}),
+			"unknown template format")
+		assert.ErrorContains(t, reg.Register(&TemplateSpec{Name: "a", Messages: []*MessageSpec{{Role: "user", Content: "{name"}}}),
+			"invalid content")
+
+		assert.NoError(t, reg.Register(&TemplateSpec{Name: "a", Version: "v1", Messages: []*MessageSpec{{Role: "user", Content: "hi"}}}))
+		assert.ErrorContains(t, reg.Register(&TemplateSpec{Name: "a", Version: "v1", Messages: []*MessageSpec{{Role: "user", Content: "hi"}}}),
+			"already registered")
+		// 等价的版本号视为重复版本
+		assert.ErrorContains(t, reg.Register(&TemplateSpec{Name: "a", Version: "1", Messages: []*MessageSpec{{Role: "user", Content: "hi"}}}),
+			`template a@1 conflicts with registered version "v1"`)
+		tpl, err := reg.Get("a", "1")
+		assert.NoError(t, err)
+		assert.Equal(t, "v1", tpl.Version())
+
+		_, err = ParseSpec([]byte("name: a"), ".toml")
+		assert.ErrorContains(t, err, "unsupported template file extension")
+	})
+
+	t.Run("引用错误", func(t *testing.T) {
+		reg := New()
+		assert.NoError(t, reg.Register(&TemplateSpec{Name: "a", Messages: []*MessageSpec{{Include: "b"}}}))
+		assert.NoError(t, reg.Register(&TemplateSpec{Name: "b", Messages: []*MessageSpec{{Include: "a"}}}))
+		assert.NoError(t, reg.Register(&TemplateSpec{Name: "c", Messages: []*MessageSpec{{Include: "missing"}}}))
+		assert.NoError(t, reg.Register(&TemplateSpec{Name: "d", Format: "jinja2", Messages: []*MessageSpec{{Role: "user", Content: "hi"}}}))
+		// 未声明格式的模板视为 fstring，与已注册模板的格式不一致时在注册时即失败，不论注册顺序
+		assert.ErrorContains(t, reg.Register(&TemplateSpec{Name: "e", Messages: []*MessageSpec{{Include: "d"}}}),
+			`included template d has format "jinja2", which differs from template e`)
+		assert.NoError(t, reg.Register(&TemplateSpec{Name: "f", Format: "jinja2", Messages: []*MessageSpec{{Include: "g"}}}))
+		assert.ErrorContains(t, reg.Register(&TemplateSpec{Name: "g", Partial: true, Messages: []*MessageSpec{{Role: "user", Content: "hi"}}}),
+			`included template g has format "fstring", which differs from template f`)
+		assert.Equal(t, []string{"a", "b", "c", "d", "f"}, reg.Names())
+
+		_, err := reg.Get("a", "")
+		assert.ErrorContains(t, err, "circular include detected: a -> b -> a")
+		_, err = reg.Get("c", "")
+		assert.ErrorContains(t, err, `template "missing" not found`)
+	})
+}
+
+func TestCompareVersions(t *testing.T) {
+	assert.Equal(t, -1, compareVersions("v1", "v2"))
+	assert.Equal(t, 1, compareVersions("1.10", "1.9"))
+	assert.Equal(t, 0, compareVersions("v1.0", "1.0"))
+	assert.Equal(t, 1, compareVersions("1.1", "1"))
+	assert.Equal(t, -1, compareVersions("1.0.0-rc", "1.0.0"))
+	assert.Equal(t, -1, compareVersions("", "v1"))
+}
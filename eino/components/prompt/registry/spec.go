@@ -0,0 +1,235 @@
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/bytedance/sonic"
+	"gopkg.in/yaml.v3"
+
+	"github.com/favbox/eino/schema"
+)
+
+// TemplateSpec 模板文件的内容，支持 YAML 与 JSON 两种格式。
+//
+// 示例（YAML）：
+//
+//	name: customer_service
+//	version: v2
+//	format: fstring
+//	metadata:
+//	  owner: support-team
+//	messages:
+//	  - include: safety_rules@v1
+//	  - role: system
+//	    content: 你是{company}的客服助手
+//	  - placeholder: history
+//	    optional: true
+//	  - role: user
+//	    content: "{query}"
+type TemplateSpec struct {
+	// Name 模板名称，必填。
+	Name string `json:"name" yaml:"name"`
+	// Version 模板版本，如 v1、1.2.0；未声明版本的模板视为最低版本。
+	Version string `json:"version,omitempty" yaml:"version,omitempty"`
+	// Format 格式类型：fstring（默认）、go_template、jinja2。
+	Format string `json:"format,omitempty" yaml:"format,omitempty"`
+	// Description 模板说明。
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Partial 为 true 时模板只能被其他模板 include，不能直接获取。
+	Partial bool `json:"partial,omitempty" yaml:"partial,omitempty"`
+	// Metadata 自定义元数据，原样透传给 Template.Metadata。
+	Metadata map[string]any `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	// Messages 消息模板列表，按顺序渲染。
+	Messages []*MessageSpec `json:"messages" yaml:"messages"`
+}
+
+// MessageSpec 单条消息模板，Role、Placeholder 与 Include 三者必须且只能设置一个。
+type MessageSpec struct {
+	// Role 消息角色：system、user、assistant、tool。
+	Role string `json:"role,omitempty" yaml:"role,omitempty"`
+	// Content 消息内容，按模板的格式类型渲染。
+	Content string `json:"content,omitempty" yaml:"content,omitempty"`
+	// Name 消息的发送者名称，可选。
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+
+	// Placeholder 消息占位符的变量名，渲染时替换为变量中的 []*schema.Message。
+	Placeholder string `json:"placeholder,omitempty" yaml:"placeholder,omitempty"`
+	// Optional 占位符变量缺失时是否跳过。
+	Optional bool `json:"optional,omitempty" yaml:"optional,omitempty"`
+
+	// Include 引用其他模板的消息，格式为 name 或 name@version，省略版本时使用最新版本。
+	Include string `json:"include,omitempty" yaml:"include,omitempty"`
+}
+
+// ParseSpec 按文件扩展名（.yaml、.yml、.json）解析模板文件内容。
+func ParseSpec(data []byte, ext string) (*TemplateSpec, error) {
+	spec := &TemplateSpec{}
+	switch strings.ToLower(ext) {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, spec); err != nil {
+			return nil, fmt.Errorf("unmarshal yaml template failed: %w", err)
+		}
+	case ".json":
+		if err := sonic.Unmarshal(data, spec); err != nil {
+			return nil, fmt.Errorf("unmarshal json template failed: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported template file extension: %q", ext)
+	}
+
+	return spec, nil
+}
+
+// isSpecFile 判断文件是否为支持的模板文件。
+func isSpecFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	default:
+		return false
+	}
+}
+
+// validate 校验模板声明并检查消息内容的模板语法。
+func (s *TemplateSpec) validate() error {
+	if s.Name == "" {
+		return errors.New("template name is required")
+	}
+	if strings.Contains(s.Name, "@") {
+		return fmt.Errorf("template name %q must not contain '@'", s.Name)
+	}
+	if len(s.Messages) == 0 {
+		return errors.New("template messages are required")
+	}
+	formatType, err := parseFormatType(s.Format)
+	if err != nil {
+		return err
+	}
+
+	for i, m := range s.Messages {
+		if m == nil {
+			return fmt.Errorf("message[%d] is nil", i)
+		}
+		set := 0
+		for _, v := range []string{m.Role, m.Placeholder, m.Include} {
+			if v != "" {
+				set++
+			}
+		}
+		if set != 1 {
+			return fmt.Errorf("message[%d] must set exactly one of 'role', 'placeholder' and 'include'", i)
+		}
+		if m.Role == "" {
+			continue
+		}
+
+		msg, err := m.toMessage()
+		if err != nil {
+			return fmt.Errorf("message[%d]: %w", i, err)
+		}
+		if _, err = schema.ExtractTemplateVariables(msg, formatType); err != nil {
+			return fmt.Errorf("message[%d] has invalid content: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// toMessage 将角色消息转换为 schema.Message 模板。
+func (m *MessageSpec) toMessage() (*schema.Message, error) {
+	role := schema.RoleType(strings.ToLower(m.Role))
+	switch role {
+	case schema.System, schema.User, schema.Assistant, schema.Tool:
+	default:
+		return nil, fmt.Errorf("unknown message role: %q", m.Role)
+	}
+
+	return &schema.Message{Role: role, Content: m.Content, Name: m.Name}, nil
+}
+
+// parseFormatType 将格式名称转换为 schema.FormatType，空字符串默认为 FString。
+func parseFormatType(format string) (schema.FormatType, error) {
+	switch strings.ToLower(strings.ReplaceAll(format, "-", "_")) {
+	case "", "fstring", "f_string":
+		return schema.FString, nil
+	case "go_template", "gotemplate":
+		return schema.GoTemplate, nil
+	case "jinja2", "jinja":
+		return schema.Jinja2, nil
+	default:
+		return 0, fmt.Errorf("unknown template format: %q", format)
+	}
+}
+
+// formatName 返回用于错误信息的格式名称，未声明时为 fstring。
+func formatName(format string) string {
+	if format == "" {
+		return "fstring"
+	}
+	return format
+}
+
+// parseRef 解析 name@version 形式的模板引用。
+func parseRef(ref string) (name, version string) {
+	name, version, _ = strings.Cut(strings.TrimSpace(ref), "@")
+	return name, version
+}
+
+// compareVersions 比较两个版本号，返回 -1、0 或 1。
+// 版本号按 . 与 - 分段，数字段按数值比较，其余按字符串比较，忽略开头的 v。
+func compareVersions(a, b string) int {
+	split := func(v string) []string {
+		v = strings.TrimPrefix(strings.TrimPrefix(v, "v"), "V")
+		return strings.FieldsFunc(v, func(r rune) bool { return r == '.' || r == '-' })
+	}
+	as, bs := split(a), split(b)
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				return compareInt(an, bn)
+			}
+		case aErr == nil:
+			return 1 // 数字段高于预发布标识，如 1.0.0 > 1.0.0-rc
+		case bErr == nil:
+			return -1
+		default:
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c
+			}
+		}
+	}
+
+	// 前缀相同时，多出的数字段更高（1.1 > 1），多出的预发布标识更低（1.0-rc < 1.0）
+	switch {
+	case len(as) > len(bs):
+		if _, err := strconv.Atoi(as[len(bs)]); err != nil {
+			return -1
+		}
+		return 1
+	case len(as) < len(bs):
+		if _, err := strconv.Atoi(bs[len(as)]); err != nil {
+			return 1
+		}
+		return -1
+	default:
+		return 0
+	}
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,111 @@
+package registry
+
+import (
+	"context"
+
+	"github.com/favbox/eino/callbacks"
+	"github.com/favbox/eino/components"
+	"github.com/favbox/eino/components/prompt"
+	"github.com/favbox/eino/schema"
+)
+
+const (
+	// ExtraKeyTemplateName 回调 Extra 中的模板名称键。
+	ExtraKeyTemplateName = "template_name"
+	// ExtraKeyTemplateVersion 回调 Extra 中的模板版本键。
+	ExtraKeyTemplateVersion = "template_version"
+	// ExtraKeyTemplateMetadata 回调 Extra 中的模板元数据键，未声明元数据时不写入。
+	ExtraKeyTemplateMetadata = "template_metadata"
+)
+
+var _ prompt.ChatTemplate = &Template{}
+var _ prompt.VariablesExtractor = &Template{}
+
+// Template 由注册中心解析得到的聊天模板，include 已展开。
+type Template struct {
+	name        string
+	version     string
+	description string
+	metadata    map[string]any
+
+	templates  []schema.MessagesTemplate
+	formatType schema.FormatType
+}
+
+// Name 返回模板名称。
+func (t *Template) Name() string {
+	return t.name
+}
+
+// Version 返回模板版本。
+func (t *Template) Version() string {
+	return t.version
+}
+
+// Description 返回模板说明。
+func (t *Template) Description() string {
+	return t.description
+}
+
+// Metadata 返回模板文件中声明的元数据。
+func (t *Template) Metadata() map[string]any {
+	return t.metadata
+}
+
+// Format 格式化模板，OnStart 与 OnEnd 回调的 Extra 中携带模板名称、版本与元数据。
+func (t *Template) Format(ctx context.Context, vs map[string]any, _ ...prompt.Option) (result []*schema.Message, err error) {
+	ctx = callbacks.EnsureRunInfo(ctx, t.GetType(), components.ComponentOfPrompt)
+	ctx = callbacks.OnStart(ctx, &prompt.CallbackInput{
+		Variables: vs,
+		Templates: t.templates,
+		Extra:     t.callbackExtra(),
+	})
+	defer func() {
+		if err != nil {
+			_ = callbacks.OnError(ctx, err)
+		}
+	}()
+
+	result = make([]*schema.Message, 0, len(t.templates))
+	for _, template := range t.templates {
+		msgs, err := template.Format(ctx, vs, t.formatType)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, msgs...)
+	}
+
+	_ = callbacks.OnEnd(ctx, &prompt.CallbackOutput{
+		Result:    result,
+		Templates: t.templates,
+		Extra:     t.callbackExtra(),
+	})
+
+	return result, nil
+}
+
+// Variables 静态提取模板所需的变量，见 prompt.DefaultChatTemplate.Variables。
+func (t *Template) Variables() ([]schema.TemplateVariable, error) {
+	return prompt.FromMessages(t.formatType, t.templates...).Variables()
+}
+
+// GetType 返回模板类型（“Registry”）。
+func (t *Template) GetType() string {
+	return "Registry"
+}
+
+// IsCallbacksEnabled 返回 true，由模板自行触发回调。
+func (t *Template) IsCallbacksEnabled() bool {
+	return true
+}
+
+func (t *Template) callbackExtra() map[string]any {
+	extra := map[string]any{
+		ExtraKeyTemplateName:    t.name,
+		ExtraKeyTemplateVersion: t.version,
+	}
+	if len(t.metadata) > 0 {
+		extra[ExtraKeyTemplateMetadata] = t.metadata
+	}
+	return extra
+}
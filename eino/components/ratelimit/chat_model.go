@@ -0,0 +1,200 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime/debug"
+
+	"github.com/favbox/eino/components"
+	"github.com/favbox/eino/components/model"
+	"github.com/favbox/eino/internal/generic"
+	"github.com/favbox/eino/internal/safe"
+	"github.com/favbox/eino/schema"
+)
+
+// ChatModelConfig 聊天模型限流包装器配置。
+type ChatModelConfig struct {
+	// Model 被包装的聊天模型，必填。
+	// 若其同时实现 model.ToolCallingChatModel，返回的包装器也实现该接口。
+	Model model.BaseChatModel
+	// Limiter 限流器，必填。可与其他包装器共享。
+	Limiter *Limiter
+	// Tokenizer 默认估算使用的分词器，默认为 schema.EstimateTokenizer。设置 EstimateTokens 时不生效。
+	Tokenizer schema.Tokenizer
+	// EstimateTokens 调用前估算本次请求的令牌数，默认用 Tokenizer 统计输入消息与工具定义，并加上 MaxTokens 选项的值。
+	EstimateTokens func(input []*schema.Message, opts *model.Options) int
+}
+
+// NewChatModel 创建受限流器约束的聊天模型。
+//
+// 包装器不改变回调行为：其类型与回调开关均沿用被包装的模型。
+func NewChatModel(_ context.Context, config *ChatModelConfig) (model.BaseChatModel, error) {
+	if config == nil {
+		return nil, errors.New("rate limited chat model config is required")
+	}
+	if config.Model == nil {
+		return nil, errors.New("rate limited chat model 'Model' is required")
+	}
+	if config.Limiter == nil {
+		return nil, errors.New("rate limited chat model 'Limiter' is required")
+	}
+
+	cm := &chatModel{
+		model:    config.Model,
+		limiter:  config.Limiter,
+		estimate: newChatTokensEstimator(config.Tokenizer),
+	}
+	if estimate := config.EstimateTokens; estimate != nil {
+		cm.estimate = func(_ context.Context, input []*schema.Message, opts *model.Options) (int, error) {
+			return estimate(input, opts), nil
+		}
+	}
+	if _, ok := config.Model.(model.ToolCallingChatModel); ok {
+		return &toolCallingChatModel{chatModel: cm}, nil
+	}
+
+	return cm, nil
+}
+
+type chatModel struct {
+	model    model.BaseChatModel
+	limiter  *Limiter
+	estimate func(ctx context.Context, input []*schema.Message, opts *model.Options) (int, error)
+}
+
+// acquire 估算本次请求的令牌数并向限流器申请额度。
+func (c *chatModel) acquire(ctx context.Context, input []*schema.Message, opts []model.Option) (func(usedTokens int), error) {
+	tokens, err := c.estimate(ctx, input, model.GetCommonOptions(&model.Options{}, opts...))
+	if err != nil {
+		return nil, fmt.Errorf("estimate tokens failed: %w", err)
+	}
+	return c.limiter.Acquire(ctx, tokens)
+}
+
+func (c *chatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	release, err := c.acquire(ctx, input, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := c.model.Generate(ctx, input, opts...)
+	release(usedTokens(msg))
+
+	return msg, err
+}
+
+func (c *chatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	release, err := c.acquire(ctx, input, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	sr, err := c.model.Stream(ctx, input, opts...)
+	if err != nil {
+		release(-1)
+		return nil, err
+	}
+
+	return releaseOnDone(sr, release), nil
+}
+
+func (c *chatModel) GetType() string {
+	return typeOf(c.model)
+}
+
+func (c *chatModel) IsCallbacksEnabled() bool {
+	return components.IsCallbacksEnabled(c.model)
+}
+
+type toolCallingChatModel struct {
+	*chatModel
+}
+
+func (t *toolCallingChatModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	bound, err := t.model.(model.ToolCallingChatModel).WithTools(tools)
+	if err != nil {
+		return nil, err
+	}
+
+	nc := *t.chatModel
+	nc.model = bound
+	return &toolCallingChatModel{chatModel: &nc}, nil
+}
+
+// releaseOnDone 在流读取完毕或被关闭时归还额度，并以流中最后出现的令牌用量修正预占值。
+func releaseOnDone(sr *schema.StreamReader[*schema.Message], release func(usedTokens int)) *schema.StreamReader[*schema.Message] {
+	out, sw := schema.Pipe[*schema.Message](1)
+	go func() {
+		used := -1
+		defer func() {
+			if panicErr := recover(); panicErr != nil {
+				_ = sw.Send(nil, safe.NewPanicErr(panicErr, debug.Stack()))
+			}
+			sr.Close()
+			sw.Close()
+			release(used)
+		}()
+
+		for {
+			chunk, err := sr.Recv()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if n := usedTokens(chunk); n >= 0 {
+				used = n
+			}
+			if sw.Send(chunk, err) || err != nil {
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// usedTokens 返回消息中的实际令牌用量，未知时返回 -1。
+func usedTokens(msg *schema.Message) int {
+	if msg == nil || msg.ResponseMeta == nil || msg.ResponseMeta.Usage == nil {
+		return -1
+	}
+	usage := msg.ResponseMeta.Usage
+	if usage.TotalTokens > 0 {
+		return usage.TotalTokens
+	}
+	return usage.PromptTokens + usage.CompletionTokens
+}
+
+// newChatTokensEstimator 返回用 tokenizer 统计输入消息与工具定义的估算函数，结果加上 MaxTokens 选项的值。
+func newChatTokensEstimator(tokenizer schema.Tokenizer) func(ctx context.Context, input []*schema.Message, opts *model.Options) (int, error) {
+	if tokenizer == nil {
+		tokenizer = schema.NewEstimateTokenizer(nil)
+	}
+	return func(ctx context.Context, input []*schema.Message, opts *model.Options) (int, error) {
+		tokens, err := schema.CountMessagesTokens(ctx, tokenizer, input)
+		if err != nil {
+			return 0, err
+		}
+		for _, tool := range opts.Tools {
+			n, err := tokenizer.CountTokens(ctx, schema.SystemMessage(tool.Name+"\n"+tool.Desc))
+			if err != nil {
+				return 0, err
+			}
+			tokens += n
+		}
+		if opts.MaxTokens != nil {
+			tokens += *opts.MaxTokens
+		}
+		return tokens, nil
+	}
+}
+
+// typeOf 返回被包装组件的类型名，未实现 components.Typer 时使用其 Go 类型名。
+func typeOf(component any) string {
+	if typ, ok := components.GetType(component); ok {
+		return typ
+	}
+	return generic.ParseTypeName(reflect.ValueOf(component))
+}
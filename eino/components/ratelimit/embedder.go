@@ -0,0 +1,98 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/favbox/eino/components"
+	"github.com/favbox/eino/components/embedding"
+	"github.com/favbox/eino/schema"
+)
+
+// EmbedderConfig 嵌入模型限流包装器配置。
+type EmbedderConfig struct {
+	// Embedder 被包装的嵌入模型，必填。
+	Embedder embedding.Embedder
+	// Limiter 限流器，必填。可与其他包装器共享。
+	Limiter *Limiter
+	// Tokenizer 默认估算使用的分词器，默认为 schema.EstimateTokenizer。设置 EstimateTokens 时不生效。
+	Tokenizer schema.Tokenizer
+	// EstimateTokens 调用前估算本次请求的令牌数，默认用 Tokenizer 逐条统计文本。
+	// Embedder 接口不返回实际用量，预占的令牌数即视为实际消耗。
+	EstimateTokens func(texts []string) int
+}
+
+// NewEmbedder 创建受限流器约束的嵌入模型，每次 EmbedStrings 调用计为一次请求。
+//
+// 包装器不改变回调行为：其类型与回调开关均沿用被包装的嵌入模型。
+func NewEmbedder(_ context.Context, config *EmbedderConfig) (embedding.Embedder, error) {
+	if config == nil {
+		return nil, errors.New("rate limited embedder config is required")
+	}
+	if config.Embedder == nil {
+		return nil, errors.New("rate limited embedder 'Embedder' is required")
+	}
+	if config.Limiter == nil {
+		return nil, errors.New("rate limited embedder 'Limiter' is required")
+	}
+
+	e := &embedder{
+		embedder: config.Embedder,
+		limiter:  config.Limiter,
+		estimate: newEmbeddingTokensEstimator(config.Tokenizer),
+	}
+	if estimate := config.EstimateTokens; estimate != nil {
+		e.estimate = func(_ context.Context, texts []string) (int, error) {
+			return estimate(texts), nil
+		}
+	}
+
+	return e, nil
+}
+
+type embedder struct {
+	embedder embedding.Embedder
+	limiter  *Limiter
+	estimate func(ctx context.Context, texts []string) (int, error)
+}
+
+func (e *embedder) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	tokens, err := e.estimate(ctx, texts)
+	if err != nil {
+		return nil, fmt.Errorf("estimate tokens failed: %w", err)
+	}
+	release, err := e.limiter.Acquire(ctx, tokens)
+	if err != nil {
+		return nil, err
+	}
+	defer release(-1)
+
+	return e.embedder.EmbedStrings(ctx, texts, opts...)
+}
+
+func (e *embedder) GetType() string {
+	return typeOf(e.embedder)
+}
+
+func (e *embedder) IsCallbacksEnabled() bool {
+	return components.IsCallbacksEnabled(e.embedder)
+}
+
+// newEmbeddingTokensEstimator 返回用 tokenizer 逐条统计文本的估算函数。
+func newEmbeddingTokensEstimator(tokenizer schema.Tokenizer) func(ctx context.Context, texts []string) (int, error) {
+	if tokenizer == nil {
+		tokenizer = schema.NewEstimateTokenizer(nil)
+	}
+	return func(ctx context.Context, texts []string) (int, error) {
+		var tokens int
+		for _, text := range texts {
+			n, err := tokenizer.CountTokens(ctx, &schema.Message{Content: text})
+			if err != nil {
+				return 0, err
+			}
+			tokens += n
+		}
+		return tokens, nil
+	}
+}
@@ -0,0 +1,192 @@
+// Package ratelimit 提供客户端限流能力，以及 model.BaseChatModel 与 embedding.Embedder 的限流包装器。
+//
+// Limiter 同时限制每分钟请求数、每分钟令牌数与最大并发请求数，可在多个包装器、多个图之间共享，
+// 从而让并行分支（如 Chain.AppendParallel、adk.NewParallelAgent、并行执行的 ToolsNode）
+// 共同遵守同一份供应商配额。
+//
+// 令牌数在调用前按估算值预占，调用结束后依据 schema.Message.ResponseMeta.Usage 的实际用量多退少补。
+// 等待额度时遵循上下文的取消与截止时间：若所需等待时间超过截止时间，立即返回包装了
+// context.DeadlineExceeded 的错误，而不是空等到超时。
+//
+// 示例：
+//
+//	limiter, _ := ratelimit.NewLimiter(&ratelimit.Config{
+//		RequestsPerMinute: 500,
+//		TokensPerMinute:   200000,
+//		MaxConcurrency:    8,
+//	})
+//	cm, _ := ratelimit.NewChatModel(ctx, &ratelimit.ChatModelConfig{Model: openaiModel, Limiter: limiter})
+//	emb, _ := ratelimit.NewEmbedder(ctx, &ratelimit.EmbedderConfig{Embedder: openaiEmbedder, Limiter: limiter})
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// Config 限流器配置，各项为 0 时表示不限制。
+type Config struct {
+	// RequestsPerMinute 每分钟最大请求数。
+	RequestsPerMinute int
+	// TokensPerMinute 每分钟最大令牌数。单次请求的预估令牌数超过该值时按该值计。
+	TokensPerMinute int
+	// MaxConcurrency 同时进行的最大请求数。
+	MaxConcurrency int
+}
+
+// Limiter 客户端限流器，并发安全。
+//
+// 请求数与令牌数各使用一个容量为每分钟配额、匀速补充的令牌桶，允许短时突发。
+type Limiter struct {
+	mu       sync.Mutex
+	requests *bucket
+	tokens   *bucket
+	sem      chan struct{}
+	now      func() time.Time
+}
+
+// NewLimiter 创建限流器。
+func NewLimiter(config *Config) (*Limiter, error) {
+	if config == nil {
+		return nil, errors.New("rate limiter config is required")
+	}
+	if config.RequestsPerMinute < 0 || config.TokensPerMinute < 0 || config.MaxConcurrency < 0 {
+		return nil, errors.New("rate limiter 'RequestsPerMinute', 'TokensPerMinute' and 'MaxConcurrency' must not be negative")
+	}
+
+	l := &Limiter{now: time.Now}
+	now := l.now()
+	if config.RequestsPerMinute > 0 {
+		l.requests = newBucket(config.RequestsPerMinute, now)
+	}
+	if config.TokensPerMinute > 0 {
+		l.tokens = newBucket(config.TokensPerMinute, now)
+	}
+	if config.MaxConcurrency > 0 {
+		l.sem = make(chan struct{}, config.MaxConcurrency)
+	}
+
+	return l, nil
+}
+
+// Acquire 为一次预估消耗 tokens 个令牌的请求获取额度，阻塞直到额度可用或上下文结束。
+//
+// 成功时返回的 release 必须在请求结束后调用且仅调用一次，用于归还并发名额，
+// 并以实际令牌用量 usedTokens 修正预占值；usedTokens 小于 0 表示实际用量未知，保留预估值。
+func (l *Limiter) Acquire(ctx context.Context, tokens int) (release func(usedTokens int), err error) {
+	if l.sem != nil {
+		select {
+		case l.sem <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		defer func() {
+			if err != nil {
+				<-l.sem
+			}
+		}()
+	}
+
+	reserved, err := l.wait(ctx, tokens)
+	if err != nil {
+		return nil, err
+	}
+
+	var once sync.Once
+	return func(usedTokens int) {
+		once.Do(func() {
+			if usedTokens >= 0 && l.tokens != nil {
+				l.mu.Lock()
+				l.tokens.adjust(float64(reserved-usedTokens), l.now())
+				l.mu.Unlock()
+			}
+			if l.sem != nil {
+				<-l.sem
+			}
+		})
+	}, nil
+}
+
+// wait 等待请求桶与令牌桶同时满足需求后一并扣减，返回实际预占的令牌数。
+func (l *Limiter) wait(ctx context.Context, tokens int) (int, error) {
+	if l.tokens != nil {
+		tokens = min(max(tokens, 0), l.tokens.capacity)
+	}
+
+	for {
+		l.mu.Lock()
+		now := l.now()
+		var delay time.Duration
+		if l.requests != nil {
+			delay = max(delay, l.requests.delay(1, now))
+		}
+		if l.tokens != nil {
+			delay = max(delay, l.tokens.delay(float64(tokens), now))
+		}
+		if delay == 0 {
+			if l.requests != nil {
+				l.requests.available--
+			}
+			if l.tokens != nil {
+				l.tokens.available -= float64(tokens)
+			}
+			l.mu.Unlock()
+			return tokens, nil
+		}
+		l.mu.Unlock()
+
+		if deadline, ok := ctx.Deadline(); ok && now.Add(delay).After(deadline) {
+			return 0, fmt.Errorf("rate limit wait of %v exceeds context deadline: %w", delay, context.DeadlineExceeded)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return 0, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// bucket 匀速补充的令牌桶，available 可因实际用量超出预估而为负。
+type bucket struct {
+	capacity  int
+	perNanos  float64
+	available float64
+	last      time.Time
+}
+
+func newBucket(perMinute int, now time.Time) *bucket {
+	return &bucket{
+		capacity:  perMinute,
+		perNanos:  float64(perMinute) / float64(time.Minute),
+		available: float64(perMinute),
+		last:      now,
+	}
+}
+
+func (b *bucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.available = math.Min(float64(b.capacity), b.available+float64(elapsed)*b.perNanos)
+		b.last = now
+	}
+}
+
+// delay 返回桶内余量达到 n 还需等待的时间，余量充足时返回 0。
+func (b *bucket) delay(n float64, now time.Time) time.Duration {
+	b.refill(now)
+	if b.available >= n {
+		return 0
+	}
+	return time.Duration(math.Ceil((n - b.available) / b.perNanos))
+}
+
+func (b *bucket) adjust(delta float64, now time.Time) {
+	b.refill(now)
+	b.available = math.Min(float64(b.capacity), b.available+delta)
+}
@@ -0,0 +1,285 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/favbox/eino/components"
+	"github.com/favbox/eino/components/embedding"
+	"github.com/favbox/eino/components/model"
+	mockModel "github.com/favbox/eino/internal/mock/components/model"
+	"github.com/favbox/eino/schema"
+)
+
+func TestLimiter(t *testing.T) {
+	t.Run("配置校验", func(t *testing.T) {
+		_, err := NewLimiter(nil)
+		assert.Error(t, err)
+		_, err = NewLimiter(&Config{RequestsPerMinute: -1})
+		assert.Error(t, err)
+	})
+
+	t.Run("请求数耗尽后等待补充", func(t *testing.T) {
+		l, err := NewLimiter(&Config{RequestsPerMinute: 1200}) // 每 50ms 补充 1 个
+		require.NoError(t, err)
+		ctx := context.Background()
+		for range 1200 {
+			release, err := l.Acquire(ctx, 0)
+			require.NoError(t, err)
+			release(-1)
+		}
+
+		start := time.Now()
+		release, err := l.Acquire(ctx, 0)
+		require.NoError(t, err)
+		release(-1)
+		assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+	})
+
+	t.Run("等待超过截止时间立即失败", func(t *testing.T) {
+		l, err := NewLimiter(&Config{RequestsPerMinute: 1})
+		require.NoError(t, err)
+		release, err := l.Acquire(context.Background(), 0)
+		require.NoError(t, err)
+		release(-1)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		start := time.Now()
+		_, err = l.Acquire(ctx, 0)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), 100*time.Millisecond)
+	})
+
+	t.Run("按实际用量修正令牌", func(t *testing.T) {
+		l, err := NewLimiter(&Config{TokensPerMinute: 600})
+		require.NoError(t, err)
+		ctx := context.Background()
+
+		// 超出容量的预估按容量计
+		release, err := l.Acquire(ctx, 1000)
+		require.NoError(t, err)
+		release(300)
+
+		release, err = l.Acquire(ctx, 300)
+		require.NoError(t, err)
+		release(-1)
+
+		shortCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+		_, err = l.Acquire(shortCtx, 100)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("实际用量超出预估时透支", func(t *testing.T) {
+		l, err := NewLimiter(&Config{TokensPerMinute: 600})
+		require.NoError(t, err)
+		release, err := l.Acquire(context.Background(), 100)
+		require.NoError(t, err)
+		release(900)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		_, err = l.Acquire(ctx, 1)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("并发数限制", func(t *testing.T) {
+		l, err := NewLimiter(&Config{MaxConcurrency: 1})
+		require.NoError(t, err)
+		release, err := l.Acquire(context.Background(), 0)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err = l.Acquire(ctx, 0)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		release(-1)
+		release(-1) // 重复调用无副作用
+		release, err = l.Acquire(context.Background(), 0)
+		require.NoError(t, err)
+		release(-1)
+	})
+
+	t.Run("多协程共享", func(t *testing.T) {
+		l, err := NewLimiter(&Config{RequestsPerMinute: 6000, MaxConcurrency: 2})
+		require.NoError(t, err)
+		var (
+			wg       sync.WaitGroup
+			mu       sync.Mutex
+			inFlight int
+			peak     int
+		)
+		for range 20 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				release, err := l.Acquire(context.Background(), 0)
+				if err != nil {
+					return
+				}
+				mu.Lock()
+				inFlight++
+				peak = max(peak, inFlight)
+				mu.Unlock()
+				time.Sleep(time.Millisecond)
+				mu.Lock()
+				inFlight--
+				mu.Unlock()
+				release(-1)
+			}()
+		}
+		wg.Wait()
+		assert.LessOrEqual(t, peak, 2)
+	})
+}
+
+type fakeEmbedder struct {
+	calls int
+}
+
+func (f *fakeEmbedder) EmbedStrings(_ context.Context, texts []string, _ ...embedding.Option) ([][]float64, error) {
+	f.calls++
+	return make([][]float64, len(texts)), nil
+}
+
+// failingTokenizer 总是返回错误的分词器。
+type failingTokenizer struct{}
+
+func (failingTokenizer) CountTokens(context.Context, *schema.Message) (int, error) {
+	return 0, errors.New("tokenizer unavailable")
+}
+
+func TestChatModel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	input := []*schema.Message{schema.UserMessage("hello")}
+	withUsage := func(content string, total int) *schema.Message {
+		msg := schema.AssistantMessage(content, nil)
+		msg.ResponseMeta = &schema.ResponseMeta{Usage: &schema.TokenUsage{TotalTokens: total}}
+		return msg
+	}
+
+	t.Run("保留工具调用能力", func(t *testing.T) {
+		l, _ := NewLimiter(&Config{MaxConcurrency: 1})
+		inner := mockModel.NewMockToolCallingChatModel(ctrl)
+		bound := mockModel.NewMockToolCallingChatModel(ctrl)
+		inner.EXPECT().WithTools(gomock.Any()).Return(bound, nil)
+		bound.EXPECT().Generate(gomock.Any(), input, gomock.Any()).Return(withUsage("ok", 10), nil)
+
+		cm, err := NewChatModel(context.Background(), &ChatModelConfig{Model: inner, Limiter: l})
+		require.NoError(t, err)
+		tcm, ok := cm.(model.ToolCallingChatModel)
+		require.True(t, ok)
+		tcm, err = tcm.WithTools([]*schema.ToolInfo{{Name: "search"}})
+		require.NoError(t, err)
+		msg, err := tcm.Generate(context.Background(), input)
+		require.NoError(t, err)
+		assert.Equal(t, "ok", msg.Content)
+		assert.False(t, components.IsCallbacksEnabled(tcm))
+	})
+
+	t.Run("仅基础模型", func(t *testing.T) {
+		l, _ := NewLimiter(&Config{MaxConcurrency: 1})
+		cm, err := NewChatModel(context.Background(), &ChatModelConfig{Model: mockModel.NewMockBaseChatModel(ctrl), Limiter: l})
+		require.NoError(t, err)
+		_, ok := cm.(model.ToolCallingChatModel)
+		assert.False(t, ok)
+	})
+
+	t.Run("使用实际用量修正令牌", func(t *testing.T) {
+		l, _ := NewLimiter(&Config{TokensPerMinute: 600})
+		inner := mockModel.NewMockBaseChatModel(ctrl)
+		inner.EXPECT().Generate(gomock.Any(), input, gomock.Any()).Return(withUsage("ok", 600), nil)
+
+		cm, err := NewChatModel(context.Background(), &ChatModelConfig{Model: inner, Limiter: l})
+		require.NoError(t, err)
+		_, err = cm.Generate(context.Background(), input)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		_, err = cm.Generate(ctx, input, model.WithMaxTokens(100))
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("默认按中英文分别估算令牌", func(t *testing.T) {
+		estimate := newChatTokensEstimator(nil)
+		tokens, err := estimate(context.Background(), []*schema.Message{schema.UserMessage(strings.Repeat("中", 100))},
+			model.GetCommonOptions(&model.Options{}, model.WithMaxTokens(10)))
+		require.NoError(t, err)
+		// 每个中文字符 1 个令牌，加上每条消息 4 个令牌的开销与 MaxTokens
+		assert.Equal(t, 114, tokens)
+
+		l, _ := NewLimiter(&Config{TokensPerMinute: 100})
+		inner := mockModel.NewMockBaseChatModel(ctrl)
+		cm, err := NewChatModel(context.Background(), &ChatModelConfig{Model: inner, Limiter: l, Tokenizer: failingTokenizer{}})
+		require.NoError(t, err)
+		_, err = cm.Generate(context.Background(), input)
+		assert.ErrorContains(t, err, "estimate tokens failed")
+	})
+
+	t.Run("流结束后释放并发名额", func(t *testing.T) {
+		l, _ := NewLimiter(&Config{MaxConcurrency: 1})
+		inner := mockModel.NewMockBaseChatModel(ctrl)
+		inner.EXPECT().Stream(gomock.Any(), input, gomock.Any()).Return(
+			schema.StreamReaderFromArray([]*schema.Message{
+				schema.AssistantMessage("he", nil),
+				withUsage("llo", 5),
+			}), nil)
+
+		cm, err := NewChatModel(context.Background(), &ChatModelConfig{Model: inner, Limiter: l})
+		require.NoError(t, err)
+		sr, err := cm.Stream(context.Background(), input)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err = l.Acquire(ctx, 0)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		var content string
+		for {
+			chunk, err := sr.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			require.NoError(t, err)
+			content += chunk.Content
+		}
+		sr.Close()
+		assert.Equal(t, "hello", content)
+
+		release, err := l.Acquire(context.Background(), 0)
+		require.NoError(t, err)
+		release(-1)
+	})
+}
+
+func TestEmbedder(t *testing.T) {
+	l, err := NewLimiter(&Config{RequestsPerMinute: 1})
+	require.NoError(t, err)
+	inner := &fakeEmbedder{}
+	emb, err := NewEmbedder(context.Background(), &EmbedderConfig{Embedder: inner, Limiter: l})
+	require.NoError(t, err)
+
+	vectors, err := emb.EmbedStrings(context.Background(), []string{"a", "b"})
+	require.NoError(t, err)
+	assert.Len(t, vectors, 2)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = emb.EmbedStrings(ctx, []string{"c"})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, inner.calls)
+	assert.Equal(t, "fakeEmbedder", emb.(components.Typer).GetType())
+}
@@ -0,0 +1,82 @@
+package reranker
+
+import (
+	"github.com/favbox/eino/callbacks"
+	"github.com/favbox/eino/schema"
+)
+
+// CallbackInput 定义了重排序器回调的输入参数。
+//
+// 在重排序器的 OnStart 回调中使用，包含查询、候选文档和配置参数。
+type CallbackInput struct {
+	// Query 是重排序的查询字符串。
+	Query string
+
+	// Docs 是待重排序的候选文档，通常来自检索器的召回结果。
+	Docs []*schema.Document
+
+	// TopN 是重排序后返回的文档数量上限，为 0 表示不限制。
+	TopN int
+
+	// ScoreThreshold 是重排序分数阈值，为 nil 表示不过滤。
+	ScoreThreshold *float64
+
+	// Model 是使用的重排序模型名称。
+	Model string
+
+	// Extra 是重排序操作的额外参数。
+	Extra map[string]any
+}
+
+// CallbackOutput 定义了重排序器回调的输出结果。
+//
+// 在重排序器的 OnEnd 回调中使用，包含重排序后的文档。
+type CallbackOutput struct {
+	// Docs 是重排序后的文档，按分数降序排列，分数可通过 Document.Score 获取。
+	Docs []*schema.Document
+
+	// Extra 是重排序结果的额外信息，如 token 用量、耗时等。
+	Extra map[string]any
+}
+
+// ConvCallbackInput 将通用回调输入转换为重排序器特定的回调输入。
+//
+// 转换逻辑：
+//   - *CallbackInput：直接返回（组件内触发）
+//   - *Request：包装为 CallbackInput（图节点注入）
+//   - 其他类型：返回 nil
+func ConvCallbackInput(src callbacks.CallbackInput) *CallbackInput {
+	switch t := src.(type) {
+	case *CallbackInput:
+		return t
+	case *Request:
+		if t == nil {
+			return &CallbackInput{}
+		}
+		return &CallbackInput{
+			Query: t.Query,
+			Docs:  t.Docs,
+		}
+	default:
+		return nil
+	}
+}
+
+// ConvCallbackOutput 将通用回调输出转换为重排序器特定的回调输出。
+//
+// 转换逻辑：
+//   - *CallbackOutput：直接返回（组件内触发）
+//   - []*schema.Document：包装为 CallbackOutput（图节点注入）
+//   - 其他类型：返回 nil
+func ConvCallbackOutput(src callbacks.CallbackOutput) *CallbackOutput {
+	switch t := src.(type) {
+	case *CallbackOutput:
+		return t
+	case []*schema.Document:
+		return &CallbackOutput{
+			Docs: t,
+		}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,20 @@
+package reranker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/favbox/eino/schema"
+)
+
+func TestConvReranker(t *testing.T) {
+	assert.NotNil(t, ConvCallbackInput(&CallbackInput{}))
+	assert.Equal(t, &CallbackInput{Query: "q", Docs: []*schema.Document{{ID: "1"}}},
+		ConvCallbackInput(&Request{Query: "q", Docs: []*schema.Document{{ID: "1"}}}))
+	assert.Nil(t, ConvCallbackInput("asd"))
+
+	assert.NotNil(t, ConvCallbackOutput(&CallbackOutput{}))
+	assert.NotNil(t, ConvCallbackOutput([]*schema.Document{}))
+	assert.Nil(t, ConvCallbackOutput("asd"))
+}
@@ -0,0 +1,16 @@
+// Package reranker 提供了重排序组件的核心接口和类型定义。
+//
+// 重排序组件用于在检索之后，根据查询对候选文档重新打分排序，
+// 通常使用交叉编码器（cross-encoder）或专用的重排序模型，精度高于向量检索的粗排。
+//
+// 主要接口：
+//   - Reranker：重排序接口，定义了 Rerank 方法
+//
+// 典型使用流程：
+//  1. 检索器召回候选文档
+//  2. 调用 Rerank 方法，传入查询与候选文档
+//  3. 使用重排序后的前 TopN 个文档构造提示词
+//
+// 在编排中使用时，节点输入为 *Request，输出为 []*schema.Document，
+// 见 compose.Graph.AddRerankerNode、compose.Chain.AppendReranker 与 compose.Workflow.AddRerankerNode。
+package reranker
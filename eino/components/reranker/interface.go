@@ -0,0 +1,43 @@
+package reranker
+
+import (
+	"context"
+
+	"github.com/favbox/eino/schema"
+)
+
+// Reranker 接口定义了重排序器的核心能力。
+type Reranker interface {
+	// Rerank 根据查询对文档重新打分并排序。
+	//
+	// 参数：
+	//   - ctx: 上下文信息，用于取消、超时和传递请求相关数据
+	//   - query: 查询字符串
+	//   - docs: 待重排序的候选文档
+	//   - opts: 可选的配置参数（如返回数量、分数阈值等）
+	//
+	// 返回：
+	//   - []*schema.Document: 按相关性降序排列的文档，分数通过 Document.WithScore 设置
+	//   - error: 重排序过程中的错误（如果有）
+	//
+	// 使用示例：
+	//
+	// 	docs, err := retriever.Retrieve(ctx, query)
+	// 	if err != nil {...}
+	// 	docs, err = reranker.Rerank(ctx, query, docs, reranker.WithTopN(3))
+	Rerank(ctx context.Context, query string, docs []*schema.Document, opts ...Option) ([]*schema.Document, error)
+}
+
+// Request 是重排序节点在图编排中的输入。
+//
+// 在 Workflow 中可以通过字段映射分别从不同前驱获取查询与文档：
+//
+//	wf.AddRerankerNode("rerank", r).
+//		AddInput(compose.START, compose.MapFields("Query", "Query")).
+//		AddInput("retriever", compose.ToField("Docs"))
+type Request struct {
+	// Query 查询字符串。
+	Query string
+	// Docs 待重排序的候选文档。
+	Docs []*schema.Document
+}
@@ -0,0 +1,95 @@
+package reranker
+
+// Options 定义了重排序器的通用配置选项。
+type Options struct {
+	// TopN 指定重排序后返回的文档数量上限。
+	TopN *int
+
+	// ScoreThreshold 设置重排序分数阈值，低于该阈值的文档不会被返回。
+	ScoreThreshold *float64
+
+	// Model 指定使用的重排序模型名称。
+	Model *string
+}
+
+// WithTopN 设置重排序后返回的文档数量上限。
+func WithTopN(topN int) Option {
+	return Option{
+		apply: func(opts *Options) {
+			opts.TopN = &topN
+		},
+	}
+}
+
+// WithScoreThreshold 设置重排序分数阈值。
+func WithScoreThreshold(threshold float64) Option {
+	return Option{
+		apply: func(opts *Options) {
+			opts.ScoreThreshold = &threshold
+		},
+	}
+}
+
+// WithModel 设置重排序模型名称。
+func WithModel(model string) Option {
+	return Option{
+		apply: func(opts *Options) {
+			opts.Model = &model
+		},
+	}
+}
+
+// Option 是重排序器的调用选项。
+type Option struct {
+	apply func(opts *Options)
+
+	implSpecificOptFn any
+}
+
+// GetCommonOptions 从选项列表中提取通用选项，base 用于提供默认值。
+//
+// 示例：
+//
+//	func (r *myReranker) Rerank(ctx context.Context, query string, docs []*schema.Document, opts ...reranker.Option) ([]*schema.Document, error) {
+//		options := reranker.GetCommonOptions(&reranker.Options{TopN: &r.topN}, opts...)
+//		// 使用 options.TopN 等
+//	}
+func GetCommonOptions(base *Options, opts ...Option) *Options {
+	if base == nil {
+		base = &Options{}
+	}
+
+	for i := range opts {
+		if opts[i].apply != nil {
+			opts[i].apply(base)
+		}
+	}
+
+	return base
+}
+
+// WrapImplSpecificOptFn 将实现特定的选项函数包装为通用 Option。
+func WrapImplSpecificOptFn[T any](optFn func(*T)) Option {
+	return Option{
+		implSpecificOptFn: optFn,
+	}
+}
+
+// GetImplSpecificOptions 从选项列表中提取实现特定的选项，base 用于提供默认值。
+func GetImplSpecificOptions[T any](base *T, opts ...Option) *T {
+	if base == nil {
+		base = new(T)
+	}
+
+	for i := range opts {
+		opt := opts[i]
+		if opt.implSpecificOptFn != nil {
+			optFn, ok := opt.implSpecificOptFn.(func(*T))
+			if ok {
+				optFn(base)
+			}
+		}
+	}
+
+	return base
+}
@@ -0,0 +1,30 @@
+package reranker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type implOptions struct {
+	ReturnDocuments bool
+}
+
+func TestOptions(t *testing.T) {
+	defaultTopN := 10
+	opts := []Option{
+		WithTopN(3),
+		WithScoreThreshold(0.5),
+		WithModel("rerank-v1"),
+		WrapImplSpecificOptFn(func(o *implOptions) { o.ReturnDocuments = true }),
+	}
+
+	common := GetCommonOptions(&Options{TopN: &defaultTopN}, opts...)
+	assert.Equal(t, 3, *common.TopN)
+	assert.Equal(t, 0.5, *common.ScoreThreshold)
+	assert.Equal(t, "rerank-v1", *common.Model)
+	assert.Equal(t, 10, defaultTopN)
+
+	impl := GetImplSpecificOptions(&implOptions{}, opts...)
+	assert.True(t, impl.ReturnDocuments)
+}
@@ -35,5 +35,5 @@ type Retriever interface {
 	// 	// 在图编排中使用
 	// 	graph := compose.NewGraph[inputType, outputType](compose.RunTpeDAG)
 	// 	graph.AddRetrieverNode("retriever_node_key", retriever)
-	Retrieve(ctx context.Context, query string, opts ...Option) (*schema.StreamReader[string], error)
+	Retrieve(ctx context.Context, query string, opts ...Option) ([]*schema.Document, error)
 }
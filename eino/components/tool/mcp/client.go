@@ -0,0 +1,384 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/bytedance/sonic"
+	"github.com/eino-contrib/jsonschema"
+
+	"github.com/favbox/eino/schema"
+)
+
+// ErrClientClosed 客户端已关闭或连接已断开。
+var ErrClientClosed = errors.New("mcp client closed")
+
+// ClientConfig MCP 客户端配置。
+type ClientConfig struct {
+	// Transport 与服务端的连接，必填，可使用 NewStdioTransport 或 NewHTTPTransport 创建。
+	Transport Transport
+	// Name 上报给服务端的客户端名称，默认为 eino。
+	Name string
+	// Version 上报给服务端的客户端版本，默认为 1.0.0。
+	Version string
+	// OnToolsChanged 收到服务端工具列表变更通知时调用，此时 ListTools 的缓存已失效。
+	OnToolsChanged func(ctx context.Context)
+}
+
+// Client MCP 客户端，完成初始化握手后可列出并调用服务端的工具。多个 goroutine 可并发使用。
+type Client struct {
+	transport      Transport
+	onToolsChanged func(ctx context.Context)
+
+	nextID   atomic.Int64
+	mu       sync.Mutex
+	pending  map[string]chan *jsonrpcMessage
+	progress map[string]func(*Progress)
+	closed   bool
+	closeErr error
+
+	server       implementation
+	instructions string
+
+	toolsMu sync.Mutex
+	tools   []*schema.ToolInfo
+}
+
+// Progress 服务端在工具执行过程中上报的进度。
+type Progress struct {
+	// Progress 当前进度。
+	Progress float64
+	// Total 总量，未知时为 0。
+	Total float64
+	// Message 进度描述。
+	Message string
+}
+
+// NewClient 建立连接并与服务端完成初始化握手。
+func NewClient(ctx context.Context, config *ClientConfig) (*Client, error) {
+	if config == nil {
+		return nil, errors.New("mcp client config is required")
+	}
+	if config.Transport == nil {
+		return nil, errors.New("mcp client 'Transport' is required")
+	}
+
+	c := &Client{
+		transport:      config.Transport,
+		onToolsChanged: config.OnToolsChanged,
+		pending:        make(map[string]chan *jsonrpcMessage),
+		progress:       make(map[string]func(*Progress)),
+	}
+	if err := c.transport.Start(ctx, c.handleMessage, c.handleClose); err != nil {
+		return nil, fmt.Errorf("start mcp transport failed: %w", err)
+	}
+
+	info := implementation{Name: config.Name, Version: config.Version}
+	if info.Name == "" {
+		info.Name = "eino"
+	}
+	if info.Version == "" {
+		info.Version = "1.0.0"
+	}
+
+	var result initializeResult
+	err := c.call(ctx, methodInitialize, &initializeParams{
+		ProtocolVersion: ProtocolVersion,
+		Capabilities:    map[string]any{},
+		ClientInfo:      info,
+	}, &result, nil)
+	if err != nil {
+		_ = c.Close()
+		return nil, fmt.Errorf("initialize mcp session failed: %w", err)
+	}
+	c.server, c.instructions = result.ServerInfo, result.Instructions
+
+	if err = c.notify(ctx, notifyInitialized, nil); err != nil {
+		_ = c.Close()
+		return nil, fmt.Errorf("initialize mcp session failed: %w", err)
+	}
+
+	return c, nil
+}
+
+// ServerInfo 返回服务端在初始化时上报的名称与版本。
+func (c *Client) ServerInfo() (name, version string) {
+	return c.server.Name, c.server.Version
+}
+
+// Instructions 返回服务端在初始化时提供的使用说明，可作为系统提示词的一部分。
+func (c *Client) Instructions() string {
+	return c.instructions
+}
+
+// Ping 检查服务端是否可用。
+func (c *Client) Ping(ctx context.Context) error {
+	return c.call(ctx, methodPing, nil, nil, nil)
+}
+
+// ListTools 列出服务端的全部工具，输入参数的 JSON Schema 转换为 schema.ParamsOneOf。
+//
+// 结果会被缓存，直到收到工具列表变更通知。
+func (c *Client) ListTools(ctx context.Context) ([]*schema.ToolInfo, error) {
+	c.toolsMu.Lock()
+	defer c.toolsMu.Unlock()
+	if c.tools != nil {
+		return c.tools, nil
+	}
+
+	infos := make([]*schema.ToolInfo, 0)
+	cursor := ""
+	for {
+		var result listToolsResult
+		if err := c.call(ctx, methodToolsList, &listToolsParams{Cursor: cursor}, &result, nil); err != nil {
+			return nil, fmt.Errorf("list mcp tools failed: %w", err)
+		}
+		for _, def := range result.Tools {
+			info, err := toToolInfo(def)
+			if err != nil {
+				return nil, err
+			}
+			infos = append(infos, info)
+		}
+		if result.NextCursor == "" {
+			break
+		}
+		cursor = result.NextCursor
+	}
+
+	c.tools = infos
+	return infos, nil
+}
+
+// CallTool 以 JSON 格式的参数调用服务端工具。onProgress 非空时接收服务端上报的执行进度。
+//
+// 工具执行出错时服务端通常返回 IsError 为 true 的结果而非错误，err 仅表示协议或连接层面的失败。
+func (c *Client) CallTool(ctx context.Context, name, argumentsInJSON string, onProgress func(*Progress)) (*CallToolResult, error) {
+	params := &callToolParams{Name: name}
+	if argumentsInJSON != "" {
+		params.Arguments = []byte(argumentsInJSON)
+	}
+
+	var result CallToolResult
+	if err := c.call(ctx, methodToolsCall, params, &result, onProgress); err != nil {
+		return nil, fmt.Errorf("call mcp tool %s failed: %w", name, err)
+	}
+	return &result, nil
+}
+
+// Close 关闭与服务端的连接，进行中的调用返回 ErrClientClosed。
+func (c *Client) Close() error {
+	return c.transport.Close()
+}
+
+// call 发送请求并等待响应。ctx 结束时向服务端发送取消通知。
+func (c *Client) call(ctx context.Context, method string, params, result any, onProgress func(*Progress)) error {
+	id := idOf(c.nextID.Add(1))
+	msg := &jsonrpcMessage{JSONRPC: jsonrpcVersion, ID: id, Method: method}
+
+	if onProgress != nil {
+		// 请求 ID 同时作为进度令牌
+		if p, ok := params.(*callToolParams); ok {
+			p.Meta = &requestMeta{ProgressToken: id}
+		}
+	}
+	if params != nil {
+		raw, err := sonic.Marshal(params)
+		if err != nil {
+			return fmt.Errorf("marshal %s params failed: %w", method, err)
+		}
+		msg.Params = raw
+	}
+	raw, err := sonic.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshal %s request failed: %w", method, err)
+	}
+
+	ch := make(chan *jsonrpcMessage, 1)
+	key := string(id)
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return c.closedErr()
+	}
+	c.pending[key] = ch
+	if onProgress != nil {
+		c.progress[key] = onProgress
+	}
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, key)
+		delete(c.progress, key)
+		c.mu.Unlock()
+	}()
+
+	if err = c.transport.Send(ctx, raw); err != nil {
+		return err
+	}
+
+	select {
+	case resp, ok := <-ch:
+		if !ok {
+			return c.closedErr()
+		}
+		if resp.Error != nil {
+			return resp.Error
+		}
+		if result == nil || len(resp.Result) == 0 {
+			return nil
+		}
+		if err = sonic.Unmarshal(resp.Result, result); err != nil {
+			return fmt.Errorf("unmarshal %s result failed: %w", method, err)
+		}
+		return nil
+	case <-ctx.Done():
+		_ = c.notify(context.WithoutCancel(ctx), notifyCancelled, &cancelledParams{RequestID: id, Reason: ctx.Err().Error()})
+		return ctx.Err()
+	}
+}
+
+func (c *Client) notify(ctx context.Context, method string, params any) error {
+	msg := &jsonrpcMessage{JSONRPC: jsonrpcVersion, Method: method}
+	if params != nil {
+		raw, err := sonic.Marshal(params)
+		if err != nil {
+			return fmt.Errorf("marshal %s params failed: %w", method, err)
+		}
+		msg.Params = raw
+	}
+	raw, err := sonic.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshal %s notification failed: %w", method, err)
+	}
+	return c.transport.Send(ctx, raw)
+}
+
+func (c *Client) reply(msg *jsonrpcMessage) {
+	raw, err := sonic.Marshal(msg)
+	if err != nil {
+		return
+	}
+	_ = c.transport.Send(context.Background(), raw)
+}
+
+// handleMessage 分发服务端发来的消息，支持 JSON-RPC 批量消息。
+func (c *Client) handleMessage(raw []byte) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) > 0 && raw[0] == '[' {
+		var batch []*jsonrpcMessage
+		if err := sonic.Unmarshal(raw, &batch); err != nil {
+			return
+		}
+		for _, msg := range batch {
+			c.dispatch(msg)
+		}
+		return
+	}
+
+	var msg jsonrpcMessage
+	if err := sonic.Unmarshal(raw, &msg); err != nil {
+		return
+	}
+	c.dispatch(&msg)
+}
+
+func (c *Client) dispatch(msg *jsonrpcMessage) {
+	switch {
+	case msg.isRequest():
+		// 服务端请求在独立的 goroutine 中应答，避免阻塞读取
+		go c.handleRequest(msg)
+	case msg.isNotification():
+		c.handleNotification(msg)
+	default:
+		c.mu.Lock()
+		ch, ok := c.pending[string(msg.ID)]
+		if ok {
+			delete(c.pending, string(msg.ID))
+		}
+		c.mu.Unlock()
+		if ok {
+			ch <- msg
+		}
+	}
+}
+
+func (c *Client) handleRequest(req *jsonrpcMessage) {
+	resp := &jsonrpcMessage{JSONRPC: jsonrpcVersion, ID: req.ID}
+	if req.Method == methodPing {
+		resp.Result = []byte("{}")
+	} else {
+		resp.Error = &rpcError{Code: codeMethodNotFound, Message: "method not found: " + req.Method}
+	}
+	c.reply(resp)
+}
+
+func (c *Client) handleNotification(msg *jsonrpcMessage) {
+	switch msg.Method {
+	case notifyProgress:
+		var p progressParams
+		if err := sonic.Unmarshal(msg.Params, &p); err != nil {
+			return
+		}
+		c.mu.Lock()
+		fn := c.progress[string(p.ProgressToken)]
+		c.mu.Unlock()
+		if fn != nil {
+			fn(&Progress{Progress: p.Progress, Total: p.Total, Message: p.Message})
+		}
+	case notifyToolsListChanged:
+		go func() {
+			c.toolsMu.Lock()
+			c.tools = nil
+			c.toolsMu.Unlock()
+			if c.onToolsChanged != nil {
+				c.onToolsChanged(context.Background())
+			}
+		}()
+	}
+}
+
+// handleClose 在连接断开时结束所有进行中的调用。
+func (c *Client) handleClose(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return
+	}
+	c.closed, c.closeErr = true, err
+	for key, ch := range c.pending {
+		close(ch)
+		delete(c.pending, key)
+	}
+}
+
+func (c *Client) closedErr() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closeErr != nil {
+		return fmt.Errorf("%w: %w", ErrClientClosed, c.closeErr)
+	}
+	return ErrClientClosed
+}
+
+// toToolInfo 将 MCP 工具定义转换为 schema.ToolInfo。
+func toToolInfo(def *toolDefinition) (*schema.ToolInfo, error) {
+	info := &schema.ToolInfo{Name: def.Name, Desc: def.Description}
+	if info.Desc == "" {
+		info.Desc = def.Title
+	}
+	if len(def.InputSchema) == 0 || string(def.InputSchema) == "null" {
+		return info, nil
+	}
+
+	sc := &jsonschema.Schema{}
+	if err := sonic.Unmarshal(def.InputSchema, sc); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal input schema of mcp tool[%s]: %w", def.Name, err)
+	}
+	info.ParamsOneOf = schema.NewParamsOneOfByJSONSchema(sc)
+	return info, nil
+}
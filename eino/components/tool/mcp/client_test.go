@@ -0,0 +1,338 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubServer 测试用的最小 MCP 服务端逻辑，与具体传输无关。
+type stubServer struct {
+	mu        sync.Mutex
+	tools     []*toolDefinition
+	cancelled []string
+	methods   []string
+}
+
+func newStubServer() *stubServer {
+	return &stubServer{tools: []*toolDefinition{
+		{
+			Name:        "echo",
+			Description: "echo the text",
+			InputSchema: json.RawMessage(`{"type":"object","properties":{"text":{"type":"string","description":"text to echo"}},"required":["text"]}`),
+		},
+		{
+			Name:        "image",
+			Title:       "Render image",
+			InputSchema: json.RawMessage(`{"type":"object"}`),
+		},
+		{
+			Name:        "count",
+			Description: "report progress",
+			InputSchema: json.RawMessage(`{"type":"object"}`),
+		},
+		{
+			Name:        "slow",
+			Description: "never returns",
+			InputSchema: json.RawMessage(`{"type":"object"}`),
+		},
+	}}
+}
+
+func (s *stubServer) setTools(tools []*toolDefinition) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tools = tools
+}
+
+func (s *stubServer) record(msg *jsonrpcMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.methods = append(s.methods, msg.Method)
+	if msg.Method == notifyCancelled {
+		var p cancelledParams
+		_ = json.Unmarshal(msg.Params, &p)
+		s.cancelled = append(s.cancelled, string(p.RequestID))
+	}
+}
+
+// handle 处理一条请求。progress 用于在响应前发送进度通知；返回 nil 表示不响应。
+func (s *stubServer) handle(req *jsonrpcMessage, progress func(*jsonrpcMessage)) *jsonrpcMessage {
+	if !req.isRequest() {
+		return nil
+	}
+
+	resp := &jsonrpcMessage{JSONRPC: jsonrpcVersion, ID: req.ID}
+	setResult := func(v any) {
+		resp.Result, _ = json.Marshal(v)
+	}
+
+	switch req.Method {
+	case methodInitialize:
+		setResult(&initializeResult{
+			ProtocolVersion: ProtocolVersion,
+			Capabilities:    serverCapabilities{Tools: &toolsCapability{ListChanged: true}},
+			ServerInfo:      implementation{Name: "stub", Version: "0.1.0"},
+			Instructions:    "use echo",
+		})
+	case methodPing:
+		setResult(struct{}{})
+	case methodToolsList:
+		var p listToolsParams
+		_ = json.Unmarshal(req.Params, &p)
+		s.mu.Lock()
+		tools := s.tools
+		s.mu.Unlock()
+		// 分两页返回，验证客户端的翻页逻辑
+		if p.Cursor == "" && len(tools) > 1 {
+			setResult(&listToolsResult{Tools: tools[:1], NextCursor: "page-2"})
+		} else if p.Cursor == "page-2" {
+			setResult(&listToolsResult{Tools: tools[1:]})
+		} else {
+			setResult(&listToolsResult{Tools: tools})
+		}
+	case methodToolsCall:
+		var p callToolParams
+		_ = json.Unmarshal(req.Params, &p)
+		switch p.Name {
+		case "echo":
+			var args struct {
+				Text string `json:"text"`
+			}
+			if err := json.Unmarshal(p.Arguments, &args); err != nil || args.Text == "" {
+				setResult(&CallToolResult{Content: []Content{TextContent("text is required")}, IsError: true})
+				break
+			}
+			setResult(&CallToolResult{Content: []Content{TextContent(args.Text)}})
+		case "image":
+			setResult(&CallToolResult{Content: []Content{
+				TextContent("rendered"),
+				{Type: "image", MIMEType: "image/png", Data: "iVBORw0KGgo="},
+			}})
+		case "count":
+			if p.Meta != nil && progress != nil {
+				for i := 1; i <= 3; i++ {
+					params, _ := json.Marshal(&progressParams{ProgressToken: p.Meta.ProgressToken, Progress: float64(i), Total: 3})
+					progress(&jsonrpcMessage{JSONRPC: jsonrpcVersion, Method: notifyProgress, Params: params})
+				}
+			}
+			setResult(&CallToolResult{Content: []Content{TextContent("done")}})
+		case "slow":
+			return nil
+		default:
+			resp.Error = &rpcError{Code: codeInvalidParams, Message: "unknown tool: " + p.Name}
+		}
+	default:
+		resp.Error = &rpcError{Code: codeMethodNotFound, Message: "method not found"}
+	}
+	return resp
+}
+
+// pipeServer 通过内存管道以换行分隔的 JSON 与客户端通信，模拟 stdio 服务端。
+type pipeServer struct {
+	stub    *stubServer
+	writeMu sync.Mutex
+	w       io.WriteCloser
+}
+
+func newPipeTransport(t *testing.T, stub *stubServer) (Transport, *pipeServer) {
+	clientR, serverW := io.Pipe()
+	serverR, clientW := io.Pipe()
+	ps := &pipeServer{stub: stub, w: serverW}
+
+	go func() {
+		defer serverW.Close()
+		br := bufio.NewReader(serverR)
+		for {
+			line, err := br.ReadBytes('\n')
+			if err != nil {
+				return
+			}
+			var msg jsonrpcMessage
+			if err = json.Unmarshal(bytes.TrimSpace(line), &msg); err != nil {
+				t.Errorf("stub server received invalid message: %s", line)
+				continue
+			}
+			// 按到达顺序记录，再并发处理
+			stub.record(&msg)
+			go func() {
+				if resp := stub.handle(&msg, ps.send); resp != nil {
+					ps.send(resp)
+				}
+			}()
+		}
+	}()
+
+	return NewIOTransport(clientR, clientW), ps
+}
+
+func (p *pipeServer) send(msg *jsonrpcMessage) {
+	raw, _ := json.Marshal(msg)
+	p.writeMu.Lock()
+	defer p.writeMu.Unlock()
+	_, _ = p.w.Write(append(raw, '\n'))
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("initialize and list tools", func(t *testing.T) {
+		stub := newStubServer()
+		transport, _ := newPipeTransport(t, stub)
+		cli, err := NewClient(ctx, &ClientConfig{Transport: transport})
+		require.NoError(t, err)
+		defer cli.Close()
+
+		name, version := cli.ServerInfo()
+		assert.Equal(t, "stub", name)
+		assert.Equal(t, "0.1.0", version)
+		assert.Equal(t, "use echo", cli.Instructions())
+		assert.NoError(t, cli.Ping(ctx))
+
+		infos, err := cli.ListTools(ctx)
+		require.NoError(t, err)
+		require.Len(t, infos, 4)
+		assert.Equal(t, "echo", infos[0].Name)
+		assert.Equal(t, "echo the text", infos[0].Desc)
+		assert.Equal(t, "Render image", infos[1].Desc)
+
+		sc, err := infos[0].ParamsOneOf.ToJSONSchema()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"text"}, sc.Required)
+		prop, ok := sc.Properties.Get("text")
+		require.True(t, ok)
+		assert.Equal(t, "string", prop.Type)
+		assert.Equal(t, "text to echo", prop.Description)
+
+		// 第二次获取命中缓存
+		_, err = cli.ListTools(ctx)
+		require.NoError(t, err)
+		stub.mu.Lock()
+		defer stub.mu.Unlock()
+		assert.Equal(t, []string{methodInitialize, notifyInitialized, methodPing, methodToolsList, methodToolsList}, stub.methods)
+	})
+
+	t.Run("call tool", func(t *testing.T) {
+		transport, _ := newPipeTransport(t, newStubServer())
+		cli, err := NewClient(ctx, &ClientConfig{Transport: transport})
+		require.NoError(t, err)
+		defer cli.Close()
+
+		result, err := cli.CallTool(ctx, "echo", `{"text":"hello"}`, nil)
+		require.NoError(t, err)
+		assert.False(t, result.IsError)
+		assert.Equal(t, []Content{TextContent("hello")}, result.Content)
+
+		result, err = cli.CallTool(ctx, "echo", `{}`, nil)
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+
+		_, err = cli.CallTool(ctx, "missing", `{}`, nil)
+		var rpcErr *rpcError
+		require.ErrorAs(t, err, &rpcErr)
+		assert.Equal(t, codeInvalidParams, rpcErr.Code)
+	})
+
+	t.Run("progress", func(t *testing.T) {
+		transport, _ := newPipeTransport(t, newStubServer())
+		cli, err := NewClient(ctx, &ClientConfig{Transport: transport})
+		require.NoError(t, err)
+		defer cli.Close()
+
+		var got []float64
+		result, err := cli.CallTool(ctx, "count", `{}`, func(p *Progress) {
+			got = append(got, p.Progress)
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "done", result.Content[0].Text)
+		assert.Equal(t, []float64{1, 2, 3}, got)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		stub := newStubServer()
+		transport, _ := newPipeTransport(t, stub)
+		cli, err := NewClient(ctx, &ClientConfig{Transport: transport})
+		require.NoError(t, err)
+		defer cli.Close()
+
+		callCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		_, err = cli.CallTool(callCtx, "slow", `{}`, nil)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		assert.Eventually(t, func() bool {
+			stub.mu.Lock()
+			defer stub.mu.Unlock()
+			return len(stub.cancelled) == 1
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("tools list changed", func(t *testing.T) {
+		stub := newStubServer()
+		transport, server := newPipeTransport(t, stub)
+		changed := make(chan struct{}, 1)
+		cli, err := NewClient(ctx, &ClientConfig{
+			Transport: transport,
+			OnToolsChanged: func(ctx context.Context) {
+				changed <- struct{}{}
+			},
+		})
+		require.NoError(t, err)
+		defer cli.Close()
+
+		infos, err := cli.ListTools(ctx)
+		require.NoError(t, err)
+		assert.Len(t, infos, 4)
+
+		stub.setTools(stub.tools[:1])
+		server.send(&jsonrpcMessage{JSONRPC: jsonrpcVersion, Method: notifyToolsListChanged})
+		select {
+		case <-changed:
+		case <-time.After(time.Second):
+			t.Fatal("tools list changed notification not handled")
+		}
+
+		infos, err = cli.ListTools(ctx)
+		require.NoError(t, err)
+		assert.Len(t, infos, 1)
+	})
+
+	t.Run("server closed", func(t *testing.T) {
+		transport, server := newPipeTransport(t, newStubServer())
+		cli, err := NewClient(ctx, &ClientConfig{Transport: transport})
+		require.NoError(t, err)
+
+		done := make(chan error, 1)
+		go func() {
+			_, err := cli.CallTool(ctx, "slow", `{}`, nil)
+			done <- err
+		}()
+		time.Sleep(20 * time.Millisecond)
+		_ = server.w.Close()
+
+		select {
+		case err = <-done:
+			assert.True(t, errors.Is(err, ErrClientClosed))
+		case <-time.After(time.Second):
+			t.Fatal("pending call not released after server closed")
+		}
+		_, err = cli.CallTool(ctx, "echo", `{"text":"a"}`, nil)
+		assert.ErrorIs(t, err, ErrClientClosed)
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := NewClient(ctx, nil)
+		assert.Error(t, err)
+		_, err = NewClient(ctx, &ClientConfig{})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,29 @@
+// Package mcp 提供 Model Context Protocol（MCP）的客户端与服务端：
+// 客户端将 MCP 服务端的工具适配为 eino 工具，服务端将 eino 工具与智能体发布给 MCP 客户端。
+//
+// 客户端通过 Transport 与服务端通信，内置两种传输：
+//   - NewStdioTransport：以子进程方式启动服务端，通过标准输入输出交换换行分隔的 JSON-RPC 消息
+//   - NewHTTPTransport：通过 Streamable HTTP 连接远程服务端
+//
+// GetTools 列出服务端的工具，将其输入参数的 JSON Schema 转换为 schema.ToolInfo，
+// 并以 tool.InvokableTool 的形式代理 tools/call 调用。
+//
+// 示例：
+//
+//	transport, _ := mcp.NewStdioTransport(&mcp.StdioConfig{Command: "my-mcp-server"})
+//	cli, _ := mcp.NewClient(ctx, &mcp.ClientConfig{Transport: transport})
+//	defer cli.Close()
+//
+//	tools, _ := mcp.GetTools(ctx, &mcp.ToolsConfig{Client: cli})
+//	toolsNode, _ := compose.NewToolNode(ctx, &compose.ToolsNodeConfig{Tools: tools})
+//
+// NewServer 则反过来发布 []tool.BaseTool，其 ParamsOneOf 转换为工具的输入 JSON Schema。
+// Server.ServeStdio 以标准输入输出提供服务，Server 本身也是 http.Handler，可挂载为 Streamable HTTP 端点。
+//
+// 示例：
+//
+//	srv, _ := mcp.NewServer(ctx, &mcp.ServerConfig{
+//		Tools: []tool.BaseTool{weatherTool, adk.NewAgentTool(ctx, researchAgent)},
+//	})
+//	http.Handle("/mcp", srv)
+package mcp
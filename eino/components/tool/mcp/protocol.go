@@ -0,0 +1,171 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// ProtocolVersion 本包实现的 MCP 协议版本。
+const ProtocolVersion = "2025-06-18"
+
+const (
+	jsonrpcVersion = "2.0"
+
+	methodInitialize       = "initialize"
+	methodPing             = "ping"
+	methodToolsList        = "tools/list"
+	methodToolsCall        = "tools/call"
+	notifyInitialized      = "notifications/initialized"
+	notifyCancelled        = "notifications/cancelled"
+	notifyProgress         = "notifications/progress"
+	notifyToolsListChanged = "notifications/tools/list_changed"
+
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+// Content 工具调用结果中的一段内容。
+type Content struct {
+	// Type 内容类型：text、image、audio、resource_link 或 resource。
+	Type string `json:"type"`
+	// Text 文本内容，Type 为 text 时使用。
+	Text string `json:"text,omitempty"`
+	// Data Base64 编码的二进制数据，Type 为 image 或 audio 时使用。
+	Data string `json:"data,omitempty"`
+	// MIMEType 二进制数据或资源的 MIME 类型。
+	MIMEType string `json:"mimeType,omitempty"`
+	// URI 资源地址，Type 为 resource_link 时使用。
+	URI string `json:"uri,omitempty"`
+	// Name 资源名称，Type 为 resource_link 时使用。
+	Name string `json:"name,omitempty"`
+	// Resource 内嵌的资源，Type 为 resource 时使用。
+	Resource *ResourceContents `json:"resource,omitempty"`
+}
+
+// ResourceContents 内嵌资源的内容，Text 与 Blob 二选一。
+type ResourceContents struct {
+	URI      string `json:"uri"`
+	MIMEType string `json:"mimeType,omitempty"`
+	Text     string `json:"text,omitempty"`
+	Blob     string `json:"blob,omitempty"`
+}
+
+// CallToolResult tools/call 的结果。
+type CallToolResult struct {
+	// Content 非结构化的结果内容。
+	Content []Content `json:"content"`
+	// StructuredContent 结构化的结果内容。
+	StructuredContent json.RawMessage `json:"structuredContent,omitempty"`
+	// IsError 工具执行是否出错，出错原因在 Content 中。
+	IsError bool `json:"isError,omitempty"`
+}
+
+// TextContent 创建文本内容。
+func TextContent(text string) Content {
+	return Content{Type: "text", Text: text}
+}
+
+// implementation 客户端或服务端的名称与版本。
+type implementation struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type initializeParams struct {
+	ProtocolVersion string         `json:"protocolVersion"`
+	Capabilities    map[string]any `json:"capabilities"`
+	ClientInfo      implementation `json:"clientInfo"`
+}
+
+type initializeResult struct {
+	ProtocolVersion string             `json:"protocolVersion"`
+	Capabilities    serverCapabilities `json:"capabilities"`
+	ServerInfo      implementation     `json:"serverInfo"`
+	Instructions    string             `json:"instructions,omitempty"`
+}
+
+type serverCapabilities struct {
+	Tools *toolsCapability `json:"tools,omitempty"`
+}
+
+type toolsCapability struct {
+	ListChanged bool `json:"listChanged,omitempty"`
+}
+
+// toolDefinition tools/list 返回的工具定义。
+type toolDefinition struct {
+	Name        string          `json:"name"`
+	Title       string          `json:"title,omitempty"`
+	Description string          `json:"description,omitempty"`
+	InputSchema json.RawMessage `json:"inputSchema"`
+}
+
+type listToolsParams struct {
+	Cursor string `json:"cursor,omitempty"`
+}
+
+type listToolsResult struct {
+	Tools      []*toolDefinition `json:"tools"`
+	NextCursor string            `json:"nextCursor,omitempty"`
+}
+
+type callToolParams struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+	Meta      *requestMeta    `json:"_meta,omitempty"`
+}
+
+type requestMeta struct {
+	ProgressToken json.RawMessage `json:"progressToken,omitempty"`
+}
+
+type progressParams struct {
+	ProgressToken json.RawMessage `json:"progressToken"`
+	Progress      float64         `json:"progress"`
+	Total         float64         `json:"total,omitempty"`
+	Message       string          `json:"message,omitempty"`
+}
+
+type cancelledParams struct {
+	RequestID json.RawMessage `json:"requestId"`
+	Reason    string          `json:"reason,omitempty"`
+}
+
+// jsonrpcMessage JSON-RPC 2.0 的请求、通知或响应。
+//
+// 有 Method 与 ID 的是请求，只有 Method 的是通知，没有 Method 的是响应。
+type jsonrpcMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+func (m *jsonrpcMessage) isRequest() bool {
+	return m.Method != "" && len(m.ID) > 0
+}
+
+func (m *jsonrpcMessage) isNotification() bool {
+	return m.Method != "" && len(m.ID) == 0
+}
+
+// rpcError JSON-RPC 错误对象。
+type rpcError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("jsonrpc error %d: %s", e.Code, e.Message)
+}
+
+func idOf(n int64) json.RawMessage {
+	return json.RawMessage(strconv.FormatInt(n, 10))
+}
@@ -0,0 +1,470 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime/debug"
+	"strings"
+	"sync"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/favbox/eino/components/tool"
+	"github.com/favbox/eino/internal/safe"
+	"github.com/favbox/eino/schema"
+)
+
+// supportedProtocolVersions 服务端接受的协议版本，客户端请求其中之一时按其版本应答，否则应答 ProtocolVersion。
+var supportedProtocolVersions = []string{ProtocolVersion, "2025-03-26", "2024-11-05"}
+
+// ServerConfig MCP 服务端配置。
+type ServerConfig struct {
+	// Tools 对外发布的工具，必填。工具需实现 tool.InvokableTool 或 tool.StreamableTool，
+	// 智能体可通过 adk.NewAgentTool 转换为工具后发布。
+	Tools []tool.BaseTool
+	// Name 上报给客户端的服务端名称，默认为 eino。
+	Name string
+	// Version 上报给客户端的服务端版本，默认为 1.0.0。
+	Version string
+	// Instructions 上报给客户端的使用说明。
+	Instructions string
+}
+
+// Server 将 eino 工具发布为 MCP 服务端，可通过 Serve 以标准输入输出提供服务，或作为 http.Handler 以 Streamable HTTP 提供服务。
+//
+// 工具调用在独立的 goroutine 中执行，其 ctx 在客户端发送取消通知、HTTP 请求断开或服务结束时取消。
+// 同时实现 tool.StreamableTool 的工具在客户端请求进度时以 StreamableRun 执行，每个数据块作为一条进度通知转发，
+// 结果为全部数据块的拼接；其余情况优先使用 InvokableRun。工具返回的错误以 IsError 为 true 的结果告知客户端。
+type Server struct {
+	info         implementation
+	instructions string
+
+	mu    sync.RWMutex
+	tools map[string]*serverTool
+	defs  []*toolDefinition
+
+	sessionsMu sync.Mutex
+	sessions   map[*session]struct{}
+	httpByID   map[string]*httpSession
+}
+
+type serverTool struct {
+	invokable  tool.InvokableTool
+	streamable tool.StreamableTool
+}
+
+// NewServer 创建 MCP 服务端。
+func NewServer(ctx context.Context, config *ServerConfig) (*Server, error) {
+	if config == nil {
+		return nil, errors.New("mcp server config is required")
+	}
+	if len(config.Tools) == 0 {
+		return nil, errors.New("mcp server 'Tools' is required")
+	}
+
+	s := &Server{
+		info:         implementation{Name: config.Name, Version: config.Version},
+		instructions: config.Instructions,
+		sessions:     make(map[*session]struct{}),
+		httpByID:     make(map[string]*httpSession),
+	}
+	if s.info.Name == "" {
+		s.info.Name = "eino"
+	}
+	if s.info.Version == "" {
+		s.info.Version = "1.0.0"
+	}
+	if err := s.setTools(ctx, config.Tools); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// SetTools 替换发布的工具，并向所有已连接的客户端发送工具列表变更通知。
+func (s *Server) SetTools(ctx context.Context, tools []tool.BaseTool) error {
+	if err := s.setTools(ctx, tools); err != nil {
+		return err
+	}
+
+	msg, err := sonic.Marshal(&jsonrpcMessage{JSONRPC: jsonrpcVersion, Method: notifyToolsListChanged})
+	if err != nil {
+		return err
+	}
+	s.sessionsMu.Lock()
+	sessions := make([]*session, 0, len(s.sessions))
+	for ss := range s.sessions {
+		sessions = append(sessions, ss)
+	}
+	s.sessionsMu.Unlock()
+
+	for _, ss := range sessions {
+		ss.push(msg)
+	}
+	return nil
+}
+
+func (s *Server) setTools(ctx context.Context, tools []tool.BaseTool) error {
+	byName := make(map[string]*serverTool, len(tools))
+	defs := make([]*toolDefinition, 0, len(tools))
+	for _, t := range tools {
+		info, err := t.Info(ctx)
+		if err != nil {
+			return fmt.Errorf("get tool info failed: %w", err)
+		}
+		if _, ok := byName[info.Name]; ok {
+			return fmt.Errorf("duplicate mcp tool name: %s", info.Name)
+		}
+
+		st := &serverTool{}
+		st.invokable, _ = t.(tool.InvokableTool)
+		st.streamable, _ = t.(tool.StreamableTool)
+		if st.invokable == nil && st.streamable == nil {
+			return fmt.Errorf("mcp tool %s is neither invokable nor streamable", info.Name)
+		}
+
+		def, err := toToolDefinition(info)
+		if err != nil {
+			return err
+		}
+		byName[info.Name] = st
+		defs = append(defs, def)
+	}
+
+	s.mu.Lock()
+	s.tools, s.defs = byName, defs
+	s.mu.Unlock()
+	return nil
+}
+
+// ServeStdio 通过当前进程的标准输入输出提供服务，直到标准输入关闭或 ctx 结束。
+func (s *Server) ServeStdio(ctx context.Context) error {
+	return s.Serve(ctx, os.Stdin, os.Stdout)
+}
+
+// Serve 从 r 读取换行分隔的 JSON-RPC 消息并将响应写入 w，直到 r 读完或 ctx 结束。
+// 返回前会取消所有进行中的工具调用。
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+
+	var writeMu sync.Mutex
+	write := func(msg []byte) {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		_, _ = w.Write(append(msg, '\n'))
+	}
+
+	ss := s.newSession(write)
+	var wg sync.WaitGroup
+	defer func() {
+		// 先取消进行中的工具调用，再等待它们退出
+		cancel()
+		s.closeSession(ss)
+		wg.Wait()
+	}()
+
+	lines := make(chan []byte)
+	readErr := make(chan error, 1)
+	go func() {
+		defer func() {
+			if panicErr := recover(); panicErr != nil {
+				readErr <- safe.NewPanicErr(panicErr, debug.Stack())
+			}
+		}()
+		br := bufio.NewReader(r)
+		for {
+			line, err := br.ReadBytes('\n')
+			if line = bytes.TrimSpace(line); len(line) > 0 {
+				select {
+				case lines <- line:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					err = nil
+				}
+				readErr <- err
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case line := <-lines:
+			msg, errResp := parseMessage(line)
+			if errResp != nil {
+				write(errResp)
+				continue
+			}
+			if !msg.isRequest() {
+				ss.handleNotification(msg)
+				continue
+			}
+			// 在读取下一条消息前登记请求，紧随其后的取消通知才能生效
+			reqCtx, done := ss.track(ctx, msg)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer done()
+				if resp := ss.respond(reqCtx, msg, write); resp != nil {
+					write(resp)
+				}
+			}()
+		case err := <-readErr:
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// session 一个客户端连接的状态。
+type session struct {
+	server *Server
+	// push 发送服务端主动推送的消息，没有可用的推送通道时丢弃。
+	push func(msg []byte)
+
+	mu       sync.Mutex
+	inflight map[string]context.CancelFunc
+}
+
+func (s *Server) newSession(push func(msg []byte)) *session {
+	ss := &session{server: s, push: push, inflight: make(map[string]context.CancelFunc)}
+	s.sessionsMu.Lock()
+	s.sessions[ss] = struct{}{}
+	s.sessionsMu.Unlock()
+	return ss
+}
+
+func (s *Server) closeSession(ss *session) {
+	s.sessionsMu.Lock()
+	delete(s.sessions, ss)
+	s.sessionsMu.Unlock()
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	for _, cancel := range ss.inflight {
+		cancel()
+	}
+}
+
+func (ss *session) handleNotification(msg *jsonrpcMessage) {
+	if msg.Method != notifyCancelled {
+		return
+	}
+	var p cancelledParams
+	if err := sonic.Unmarshal(msg.Params, &p); err != nil {
+		return
+	}
+	ss.mu.Lock()
+	cancel := ss.inflight[string(p.RequestID)]
+	ss.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+}
+
+// track 为请求派生可取消的 ctx 并登记为进行中，使之后到达的取消通知能够找到它。done 用于结束登记。
+func (ss *session) track(ctx context.Context, req *jsonrpcMessage) (_ context.Context, done func()) {
+	key := string(req.ID)
+	ctx, cancel := context.WithCancel(ctx)
+	ss.mu.Lock()
+	ss.inflight[key] = cancel
+	ss.mu.Unlock()
+	return ctx, func() {
+		ss.mu.Lock()
+		delete(ss.inflight, key)
+		ss.mu.Unlock()
+		cancel()
+	}
+}
+
+// handleRequest 处理一条请求并返回序列化后的响应，请求被取消时返回 nil。notify 用于发送与该请求相关的进度通知。
+func (ss *session) handleRequest(ctx context.Context, req *jsonrpcMessage, notify func(msg []byte)) []byte {
+	ctx, done := ss.track(ctx, req)
+	defer done()
+	return ss.respond(ctx, req, notify)
+}
+
+// respond 执行已登记的请求并返回序列化后的响应，请求被取消时返回 nil。
+func (ss *session) respond(ctx context.Context, req *jsonrpcMessage, notify func(msg []byte)) []byte {
+	result, rpcErr := ss.server.dispatch(ctx, req, notify)
+	if ctx.Err() != nil {
+		// 已取消的请求不再响应
+		return nil
+	}
+
+	resp := &jsonrpcMessage{JSONRPC: jsonrpcVersion, ID: req.ID, Error: rpcErr}
+	if rpcErr == nil {
+		raw, err := sonic.Marshal(result)
+		if err != nil {
+			resp.Error = &rpcError{Code: codeInternalError, Message: err.Error()}
+		} else {
+			resp.Result = raw
+		}
+	}
+	raw, err := sonic.Marshal(resp)
+	if err != nil {
+		return nil
+	}
+	return raw
+}
+
+func (s *Server) dispatch(ctx context.Context, req *jsonrpcMessage, notify func(msg []byte)) (any, *rpcError) {
+	switch req.Method {
+	case methodInitialize:
+		var p initializeParams
+		if err := sonic.Unmarshal(req.Params, &p); err != nil {
+			return nil, &rpcError{Code: codeInvalidParams, Message: err.Error()}
+		}
+		version := ProtocolVersion
+		for _, v := range supportedProtocolVersions {
+			if v == p.ProtocolVersion {
+				version = v
+			}
+		}
+		return &initializeResult{
+			ProtocolVersion: version,
+			Capabilities:    serverCapabilities{Tools: &toolsCapability{ListChanged: true}},
+			ServerInfo:      s.info,
+			Instructions:    s.instructions,
+		}, nil
+	case methodPing:
+		return struct{}{}, nil
+	case methodToolsList:
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		return &listToolsResult{Tools: s.defs}, nil
+	case methodToolsCall:
+		var p callToolParams
+		if err := sonic.Unmarshal(req.Params, &p); err != nil {
+			return nil, &rpcError{Code: codeInvalidParams, Message: err.Error()}
+		}
+		s.mu.RLock()
+		st := s.tools[p.Name]
+		s.mu.RUnlock()
+		if st == nil {
+			return nil, &rpcError{Code: codeInvalidParams, Message: "unknown tool: " + p.Name}
+		}
+
+		var progressToken []byte
+		if p.Meta != nil {
+			progressToken = p.Meta.ProgressToken
+		}
+		return st.call(ctx, string(p.Arguments), progressToken, notify), nil
+	default:
+		return nil, &rpcError{Code: codeMethodNotFound, Message: "method not found: " + req.Method}
+	}
+}
+
+// call 执行工具，错误与 panic 转换为 IsError 的结果。
+func (st *serverTool) call(ctx context.Context, arguments string, progressToken []byte, notify func(msg []byte)) (result *CallToolResult) {
+	defer func() {
+		if panicErr := recover(); panicErr != nil {
+			result = errorResult(safe.NewPanicErr(panicErr, debug.Stack()))
+		}
+	}()
+	if arguments == "" || arguments == "null" {
+		arguments = "{}"
+	}
+
+	if st.streamable != nil && (st.invokable == nil || len(progressToken) > 0) {
+		output, err := st.stream(ctx, arguments, progressToken, notify)
+		if err != nil {
+			return errorResult(err)
+		}
+		return &CallToolResult{Content: []Content{TextContent(output)}}
+	}
+
+	output, err := st.invokable.InvokableRun(ctx, arguments)
+	if err != nil {
+		return errorResult(err)
+	}
+	return &CallToolResult{Content: []Content{TextContent(output)}}
+}
+
+// stream 以 StreamableRun 执行工具，每个数据块作为一条进度通知转发，返回全部数据块的拼接。
+func (st *serverTool) stream(ctx context.Context, arguments string, progressToken []byte, notify func(msg []byte)) (string, error) {
+	sr, err := st.streamable.StreamableRun(ctx, arguments)
+	if err != nil {
+		return "", err
+	}
+	defer sr.Close()
+
+	var sb strings.Builder
+	for i := 1; ; i++ {
+		chunk, err := sr.Recv()
+		if errors.Is(err, io.EOF) {
+			return sb.String(), nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		sb.WriteString(chunk)
+
+		if len(progressToken) == 0 {
+			continue
+		}
+		params, err := sonic.Marshal(&progressParams{ProgressToken: progressToken, Progress: float64(i), Message: chunk})
+		if err != nil {
+			return "", err
+		}
+		msg, err := sonic.Marshal(&jsonrpcMessage{JSONRPC: jsonrpcVersion, Method: notifyProgress, Params: params})
+		if err != nil {
+			return "", err
+		}
+		notify(msg)
+	}
+}
+
+func errorResult(err error) *CallToolResult {
+	return &CallToolResult{Content: []Content{TextContent(err.Error())}, IsError: true}
+}
+
+// parseMessage 解析一条消息，无法解析或为批量消息时返回应答给客户端的错误响应。
+func parseMessage(raw []byte) (*jsonrpcMessage, []byte) {
+	var msg jsonrpcMessage
+	var rpcErr *rpcError
+	if len(raw) > 0 && raw[0] == '[' {
+		rpcErr = &rpcError{Code: codeInvalidRequest, Message: "batch requests are not supported"}
+	} else if err := sonic.Unmarshal(raw, &msg); err != nil {
+		rpcErr = &rpcError{Code: codeParseError, Message: err.Error()}
+	} else if msg.Method == "" && len(msg.ID) == 0 {
+		rpcErr = &rpcError{Code: codeInvalidRequest, Message: "invalid jsonrpc message"}
+	}
+	if rpcErr == nil {
+		return &msg, nil
+	}
+
+	resp, _ := sonic.Marshal(&jsonrpcMessage{JSONRPC: jsonrpcVersion, ID: []byte("null"), Error: rpcErr})
+	return nil, resp
+}
+
+// toToolDefinition 将 schema.ToolInfo 转换为 MCP 工具定义，没有参数时使用空对象 Schema。
+func toToolDefinition(info *schema.ToolInfo) (*toolDefinition, error) {
+	def := &toolDefinition{Name: info.Name, Description: info.Desc, InputSchema: []byte(`{"type":"object"}`)}
+
+	sc, err := info.ParamsOneOf.ToJSONSchema()
+	if err != nil {
+		return nil, fmt.Errorf("convert params of mcp tool[%s] to json schema failed: %w", info.Name, err)
+	}
+	if sc == nil {
+		return def, nil
+	}
+	raw, err := sonic.Marshal(sc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal json schema of mcp tool[%s]: %w", info.Name, err)
+	}
+	def.InputSchema = raw
+	return def, nil
+}
@@ -0,0 +1,182 @@
+package mcp
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// httpSession Streamable HTTP 会话，服务端推送的消息通过 GET 建立的 SSE 流发送。
+type httpSession struct {
+	*session
+	sid string
+
+	mu     sync.Mutex
+	stream chan []byte
+}
+
+// ServeHTTP 以 Streamable HTTP 提供服务，需挂载在单个 MCP 端点上。
+//
+// initialize 请求会创建会话并在 Mcp-Session-Id 响应头中返回会话 ID，之后的请求需携带该请求头。
+// tools/call 以 SSE 流响应，以便在结果之前发送进度通知；其余请求以 JSON 响应。
+// GET 请求建立接收服务端推送的 SSE 流，DELETE 请求结束会话并取消其进行中的工具调用。
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		s.handlePost(w, r)
+	case http.MethodGet:
+		s.handleListen(w, r)
+	case http.MethodDelete:
+		ss := s.lookupHTTPSession(r)
+		if ss == nil {
+			http.Error(w, "session not found", http.StatusNotFound)
+			return
+		}
+		s.sessionsMu.Lock()
+		delete(s.httpByID, ss.sid)
+		s.sessionsMu.Unlock()
+		s.closeSession(ss.session)
+	default:
+		w.Header().Set("Allow", "GET, POST, DELETE")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handlePost(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	msg, errResp := parseMessage(body)
+	if errResp != nil {
+		w.Header().Set("Content-Type", contentTypeJSON)
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write(errResp)
+		return
+	}
+
+	var ss *httpSession
+	if msg.Method == methodInitialize {
+		ss = s.newHTTPSession()
+		w.Header().Set(headerSessionID, ss.sid)
+	} else if ss = s.lookupHTTPSession(r); ss == nil {
+		http.Error(w, "session not found", http.StatusNotFound)
+		return
+	}
+
+	if !msg.isRequest() {
+		ss.handleNotification(msg)
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	if msg.Method != methodToolsCall {
+		resp := ss.handleRequest(r.Context(), msg, func([]byte) {})
+		if resp == nil {
+			return
+		}
+		w.Header().Set("Content-Type", contentTypeJSON)
+		_, _ = w.Write(resp)
+		return
+	}
+
+	flusher, _ := w.(http.Flusher)
+	var writeMu sync.Mutex
+	write := func(msg []byte) {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		_, _ = fmt.Fprintf(w, "event: message\ndata: %s\n\n", msg)
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	w.Header().Set("Content-Type", contentTypeSSE)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	if flusher != nil {
+		flusher.Flush()
+	}
+	// 客户端断开请求时 r.Context() 取消，工具调用随之取消
+	if resp := ss.handleRequest(r.Context(), msg, write); resp != nil {
+		write(resp)
+	}
+}
+
+// handleListen 建立接收服务端推送消息的 SSE 流，同一会话只保留最新的一条流。
+func (s *Server) handleListen(w http.ResponseWriter, r *http.Request) {
+	ss := s.lookupHTTPSession(r)
+	if ss == nil {
+		http.Error(w, "session not found", http.StatusNotFound)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	stream := make(chan []byte, 16)
+	ss.mu.Lock()
+	ss.stream = stream
+	ss.mu.Unlock()
+	defer func() {
+		ss.mu.Lock()
+		if ss.stream == stream {
+			ss.stream = nil
+		}
+		ss.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", contentTypeSSE)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case msg := <-stream:
+			_, _ = fmt.Fprintf(w, "event: message\ndata: %s\n\n", msg)
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func (s *Server) newHTTPSession() *httpSession {
+	hs := &httpSession{sid: uuid.NewString()}
+	hs.session = s.newSession(hs.send)
+
+	s.sessionsMu.Lock()
+	s.httpByID[hs.sid] = hs
+	s.sessionsMu.Unlock()
+	return hs
+}
+
+func (s *Server) lookupHTTPSession(r *http.Request) *httpSession {
+	sid := r.Header.Get(headerSessionID)
+	if sid == "" {
+		return nil
+	}
+	s.sessionsMu.Lock()
+	defer s.sessionsMu.Unlock()
+	return s.httpByID[sid]
+}
+
+// send 将消息写入当前的推送流，没有推送流或其已满时丢弃。
+func (hs *httpSession) send(msg []byte) {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	if hs.stream == nil {
+		return
+	}
+	select {
+	case hs.stream <- msg:
+	default:
+	}
+}
@@ -0,0 +1,315 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/favbox/eino/adk"
+	"github.com/favbox/eino/components/tool"
+	"github.com/favbox/eino/components/tool/utils"
+	"github.com/favbox/eino/schema"
+)
+
+type echoInput struct {
+	Text string `json:"text" jsonschema:"description=text to echo"`
+}
+
+// replyAgent 将输入原样加上前缀返回的智能体。
+type replyAgent struct{}
+
+func (a *replyAgent) Name(_ context.Context) string {
+	return "reply_agent"
+}
+
+func (a *replyAgent) Description(_ context.Context) string {
+	return "replies to the request"
+}
+
+func (a *replyAgent) Run(_ context.Context, input *adk.AgentInput, _ ...adk.AgentRunOption) *adk.AsyncIterator[*adk.AgentEvent] {
+	iter, gen := adk.NewAsyncIteratorPair[*adk.AgentEvent]()
+	gen.Send(adk.EventFromMessage(schema.AssistantMessage("agent: "+input.Messages[0].Content, nil), nil, schema.Assistant, ""))
+	gen.Close()
+	return iter
+}
+
+// testTools 返回服务端测试用的工具，cancelled 在 block 工具的 ctx 被取消时收到通知。
+func testTools(t *testing.T, cancelled chan<- error) []tool.BaseTool {
+	echo, err := utils.InferTool("echo", "echo the text", func(_ context.Context, in *echoInput) (string, error) {
+		if in.Text == "" {
+			return "", errors.New("text is required")
+		}
+		return in.Text, nil
+	})
+	require.NoError(t, err)
+
+	words, err := utils.InferStreamTool("words", "stream the words of text", func(_ context.Context, in *echoInput) (*schema.StreamReader[string], error) {
+		return schema.StreamReaderFromArray(strings.Fields(in.Text)), nil
+	})
+	require.NoError(t, err)
+
+	block, err := utils.InferTool("block", "block until cancelled", func(ctx context.Context, _ struct{}) (string, error) {
+		<-ctx.Done()
+		cancelled <- ctx.Err()
+		return "", ctx.Err()
+	})
+	require.NoError(t, err)
+
+	return []tool.BaseTool{echo, words, block, adk.NewAgentTool(context.Background(), &replyAgent{})}
+}
+
+// newStdioPair 通过内存管道连接服务端与客户端。
+func newStdioPair(t *testing.T, ctx context.Context, srv *Server, onToolsChanged func(context.Context)) (*Client, <-chan error) {
+	clientR, serverW := io.Pipe()
+	serverR, clientW := io.Pipe()
+
+	served := make(chan error, 1)
+	go func() {
+		err := srv.Serve(ctx, serverR, serverW)
+		_ = serverW.Close()
+		served <- err
+	}()
+
+	cli, err := NewClient(ctx, &ClientConfig{
+		Transport:      NewIOTransport(clientR, clientW),
+		OnToolsChanged: onToolsChanged,
+	})
+	require.NoError(t, err)
+	return cli, served
+}
+
+func testServerRoundTrip(t *testing.T, cli *Client, cancelled <-chan error) {
+	ctx := context.Background()
+
+	name, version := cli.ServerInfo()
+	assert.Equal(t, "test-server", name)
+	assert.Equal(t, "1.0.0", version)
+
+	infos, err := cli.ListTools(ctx)
+	require.NoError(t, err)
+	require.Len(t, infos, 4)
+	assert.Equal(t, "echo", infos[0].Name)
+	assert.Equal(t, "reply_agent", infos[3].Name)
+	sc, err := infos[0].ParamsOneOf.ToJSONSchema()
+	require.NoError(t, err)
+	prop, ok := sc.Properties.Get("text")
+	require.True(t, ok)
+	assert.Equal(t, "text to echo", prop.Description)
+
+	tools, err := GetTools(ctx, &ToolsConfig{Client: cli})
+	require.NoError(t, err)
+
+	out, err := tools[0].(tool.InvokableTool).InvokableRun(ctx, `{"text":"hello"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", out)
+
+	result, err := cli.CallTool(ctx, "echo", `{}`, nil)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].Text, "text is required")
+
+	var chunks []string
+	out, err = tools[1].(tool.InvokableTool).InvokableRun(ctx, `{"text":"a b c"}`, WithProgressHandler(func(_ context.Context, p *Progress) {
+		chunks = append(chunks, p.Message)
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "abc", out)
+	assert.Equal(t, []string{"a", "b", "c"}, chunks)
+
+	// 未请求进度时流式工具同样返回拼接结果
+	out, err = tools[1].(tool.InvokableTool).InvokableRun(ctx, `{"text":"x y"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "xy", out)
+
+	out, err = tools[3].(tool.InvokableTool).InvokableRun(ctx, `{"request":"hi"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "agent: hi", out)
+
+	_, err = cli.CallTool(ctx, "missing", `{}`, nil)
+	var rpcErr *rpcError
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, codeInvalidParams, rpcErr.Code)
+
+	// 客户端取消调用后，服务端工具的 ctx 随之取消
+	callCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	_, err = cli.CallTool(callCtx, "block", `{}`, nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	select {
+	case err = <-cancelled:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("tool context not cancelled")
+	}
+}
+
+func TestServerStdio(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cancelled := make(chan error, 1)
+	srv, err := NewServer(ctx, &ServerConfig{Tools: testTools(t, cancelled), Name: "test-server"})
+	require.NoError(t, err)
+
+	cli, served := newStdioPair(t, ctx, srv, nil)
+	testServerRoundTrip(t, cli, cancelled)
+
+	t.Run("tools list changed", func(t *testing.T) {
+		changed := make(chan struct{}, 1)
+		cli2, _ := newStdioPair(t, ctx, srv, func(context.Context) {
+			changed <- struct{}{}
+		})
+		defer cli2.Close()
+
+		require.NoError(t, srv.SetTools(ctx, testTools(t, cancelled)[:1]))
+		select {
+		case <-changed:
+		case <-time.After(time.Second):
+			t.Fatal("tools list changed notification not received")
+		}
+		infos, err := cli2.ListTools(ctx)
+		require.NoError(t, err)
+		assert.Len(t, infos, 1)
+	})
+
+	require.NoError(t, cli.Close())
+	select {
+	case err = <-served:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("server not stopped after client closed")
+	}
+}
+
+func TestServeCancelsInflight(t *testing.T) {
+	ctx := context.Background()
+	cancelled := make(chan error, 1)
+	srv, err := NewServer(ctx, &ServerConfig{Tools: testTools(t, cancelled)})
+	require.NoError(t, err)
+	call := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"block","arguments":{}}}` + "\n"
+
+	t.Run("input closed", func(t *testing.T) {
+		// 输入读完时 Serve 取消进行中的工具调用后返回，而不是等待其自行结束
+		served := make(chan error, 1)
+		go func() {
+			served <- srv.Serve(ctx, strings.NewReader(call), io.Discard)
+		}()
+		select {
+		case err = <-cancelled:
+			assert.ErrorIs(t, err, context.Canceled)
+		case <-time.After(time.Second):
+			t.Fatal("tool context not cancelled")
+		}
+		select {
+		case err = <-served:
+			assert.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("server not stopped after input closed")
+		}
+	})
+
+	t.Run("cancelled right after request", func(t *testing.T) {
+		r, w := io.Pipe()
+		defer w.Close()
+		go func() {
+			_ = srv.Serve(ctx, r, io.Discard)
+		}()
+		_, err := io.WriteString(w, call+`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":1}}`+"\n")
+		require.NoError(t, err)
+		select {
+		case err = <-cancelled:
+			assert.ErrorIs(t, err, context.Canceled)
+		case <-time.After(time.Second):
+			t.Fatal("tool context not cancelled")
+		}
+	})
+}
+
+func TestServerHTTP(t *testing.T) {
+	ctx := context.Background()
+	cancelled := make(chan error, 1)
+	srv, err := NewServer(ctx, &ServerConfig{Tools: testTools(t, cancelled), Name: "test-server"})
+	require.NoError(t, err)
+	hs := httptest.NewServer(srv)
+	defer hs.Close()
+
+	transport, err := NewHTTPTransport(&HTTPConfig{Endpoint: hs.URL})
+	require.NoError(t, err)
+	changed := make(chan struct{}, 1)
+	cli, err := NewClient(ctx, &ClientConfig{
+		Transport: transport,
+		OnToolsChanged: func(context.Context) {
+			changed <- struct{}{}
+		},
+	})
+	require.NoError(t, err)
+
+	testServerRoundTrip(t, cli, cancelled)
+
+	// 等待 GET 推送流建立后再变更工具
+	assert.Eventually(t, func() bool {
+		srv.sessionsMu.Lock()
+		defer srv.sessionsMu.Unlock()
+		for _, ss := range srv.httpByID {
+			ss.mu.Lock()
+			listening := ss.stream != nil
+			ss.mu.Unlock()
+			if listening {
+				return true
+			}
+		}
+		return false
+	}, time.Second, 10*time.Millisecond)
+	require.NoError(t, srv.SetTools(ctx, testTools(t, cancelled)[:2]))
+	select {
+	case <-changed:
+	case <-time.After(time.Second):
+		t.Fatal("tools list changed notification not received")
+	}
+
+	require.NoError(t, cli.Close())
+	srv.sessionsMu.Lock()
+	assert.Empty(t, srv.httpByID)
+	srv.sessionsMu.Unlock()
+
+	t.Run("invalid requests", func(t *testing.T) {
+		resp, err := http.Post(hs.URL, contentTypeJSON, strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		resp, err = http.Post(hs.URL, contentTypeJSON, strings.NewReader(`[{"jsonrpc":"2.0","id":1,"method":"ping"}]`))
+		require.NoError(t, err)
+		var msg jsonrpcMessage
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&msg))
+		_ = resp.Body.Close()
+		assert.Equal(t, codeInvalidRequest, msg.Error.Code)
+
+		req, _ := http.NewRequest(http.MethodPut, hs.URL, nil)
+		resp, err = http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	})
+}
+
+func TestNewServer(t *testing.T) {
+	ctx := context.Background()
+	_, err := NewServer(ctx, nil)
+	assert.Error(t, err)
+	_, err = NewServer(ctx, &ServerConfig{})
+	assert.Error(t, err)
+
+	tools := testTools(t, make(chan error, 1))
+	_, err = NewServer(ctx, &ServerConfig{Tools: []tool.BaseTool{tools[0], tools[0]}})
+	assert.EqualError(t, err, "duplicate mcp tool name: echo")
+}
@@ -0,0 +1,160 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/favbox/eino/components/tool"
+	"github.com/favbox/eino/schema"
+)
+
+// ResultHandler 将 tools/call 的结果转换为工具输出。
+type ResultHandler func(ctx context.Context, name string, result *CallToolResult) (string, error)
+
+// ToolsConfig 从 MCP 服务端获取工具的配置。
+type ToolsConfig struct {
+	// Client 已完成初始化的 MCP 客户端，必填。
+	Client *Client
+	// ToolNameList 只获取这些名称的工具，为空时获取全部工具。
+	ToolNameList []string
+	// ResultHandler 将调用结果转换为工具输出，默认为 FormatResult。
+	ResultHandler ResultHandler
+}
+
+// mcpToolOptions 是 MCP 工具的实现特定选项。
+type mcpToolOptions struct {
+	onProgress func(ctx context.Context, p *Progress)
+}
+
+// WithProgressHandler 接收服务端在本次调用中上报的执行进度。fn 在读取消息的 goroutine 中同步调用，不应阻塞。
+func WithProgressHandler(fn func(ctx context.Context, p *Progress)) tool.Option {
+	return tool.WrapImplSpecificOptFn(func(o *mcpToolOptions) {
+		o.onProgress = fn
+	})
+}
+
+// GetTools 列出 MCP 服务端的工具，并将其包装为 tool.InvokableTool。
+//
+// 工具的 Info 是获取时的快照，服务端工具列表变更后（参见 ClientConfig.OnToolsChanged）需要重新调用 GetTools。
+func GetTools(ctx context.Context, config *ToolsConfig) ([]tool.BaseTool, error) {
+	if config == nil {
+		return nil, errors.New("mcp tools config is required")
+	}
+	if config.Client == nil {
+		return nil, errors.New("mcp tools 'Client' is required")
+	}
+
+	infos, err := config.Client.ListTools(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	handler := config.ResultHandler
+	if handler == nil {
+		handler = func(_ context.Context, _ string, result *CallToolResult) (string, error) {
+			return FormatResult(result), nil
+		}
+	}
+
+	var wanted map[string]bool
+	if len(config.ToolNameList) > 0 {
+		wanted = make(map[string]bool, len(config.ToolNameList))
+		for _, name := range config.ToolNameList {
+			wanted[name] = true
+		}
+	}
+
+	tools := make([]tool.BaseTool, 0, len(infos))
+	for _, info := range infos {
+		if wanted != nil && !wanted[info.Name] {
+			continue
+		}
+		tools = append(tools, &mcpTool{client: config.Client, info: info, handler: handler})
+	}
+
+	for name := range wanted {
+		found := false
+		for _, info := range infos {
+			if info.Name == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("mcp tool %s not found", name)
+		}
+	}
+
+	return tools, nil
+}
+
+type mcpTool struct {
+	client  *Client
+	info    *schema.ToolInfo
+	handler ResultHandler
+}
+
+func (t *mcpTool) Info(_ context.Context) (*schema.ToolInfo, error) {
+	return t.info, nil
+}
+
+func (t *mcpTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	options := tool.GetImplSpecificOptions(&mcpToolOptions{}, opts...)
+
+	var onProgress func(*Progress)
+	if options.onProgress != nil {
+		onProgress = func(p *Progress) {
+			options.onProgress(ctx, p)
+		}
+	}
+
+	result, err := t.client.CallTool(ctx, t.info.Name, argumentsInJSON, onProgress)
+	if err != nil {
+		return "", err
+	}
+	return t.handler(ctx, t.info.Name, result)
+}
+
+// FormatResult 将调用结果格式化为文本，是 GetTools 的默认结果转换方式。
+//
+// 文本内容原样保留，图片与音频转换为 Markdown 形式的 data URI，资源链接转换为 Markdown 链接，
+// 内嵌资源取其文本或 data URI，各段以换行连接。没有非结构化内容时返回 StructuredContent。
+// IsError 为 true 的结果同样作为输出返回，以便模型根据错误信息修正调用。
+func FormatResult(result *CallToolResult) string {
+	if result == nil {
+		return ""
+	}
+	if len(result.Content) == 0 {
+		return string(result.StructuredContent)
+	}
+
+	parts := make([]string, 0, len(result.Content))
+	for _, c := range result.Content {
+		switch c.Type {
+		case "text":
+			parts = append(parts, c.Text)
+		case "image":
+			parts = append(parts, fmt.Sprintf("![image](data:%s;base64,%s)", c.MIMEType, c.Data))
+		case "audio":
+			parts = append(parts, fmt.Sprintf("[audio](data:%s;base64,%s)", c.MIMEType, c.Data))
+		case "resource_link":
+			name := c.Name
+			if name == "" {
+				name = c.URI
+			}
+			parts = append(parts, fmt.Sprintf("[%s](%s)", name, c.URI))
+		case "resource":
+			if c.Resource == nil {
+				continue
+			}
+			if c.Resource.Text != "" || c.Resource.Blob == "" {
+				parts = append(parts, c.Resource.Text)
+			} else {
+				parts = append(parts, fmt.Sprintf("[%s](data:%s;base64,%s)", c.Resource.URI, c.Resource.MIMEType, c.Resource.Blob))
+			}
+		}
+	}
+	return strings.Join(parts, "\n")
+}
@@ -0,0 +1,100 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/favbox/eino/components/tool"
+)
+
+func TestGetTools(t *testing.T) {
+	ctx := context.Background()
+	transport, _ := newPipeTransport(t, newStubServer())
+	cli, err := NewClient(ctx, &ClientConfig{Transport: transport})
+	require.NoError(t, err)
+	defer cli.Close()
+
+	t.Run("all tools", func(t *testing.T) {
+		tools, err := GetTools(ctx, &ToolsConfig{Client: cli})
+		require.NoError(t, err)
+		require.Len(t, tools, 4)
+
+		info, err := tools[0].Info(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "echo", info.Name)
+
+		it, ok := tools[0].(tool.InvokableTool)
+		require.True(t, ok)
+		out, err := it.InvokableRun(ctx, `{"text":"hi"}`)
+		require.NoError(t, err)
+		assert.Equal(t, "hi", out)
+
+		out, err = it.InvokableRun(ctx, `{}`)
+		require.NoError(t, err)
+		assert.Equal(t, "text is required", out)
+
+		out, err = tools[1].(tool.InvokableTool).InvokableRun(ctx, `{}`)
+		require.NoError(t, err)
+		assert.Equal(t, "rendered\n![image](data:image/png;base64,iVBORw0KGgo=)", out)
+	})
+
+	t.Run("name list and result handler", func(t *testing.T) {
+		tools, err := GetTools(ctx, &ToolsConfig{
+			Client:       cli,
+			ToolNameList: []string{"echo"},
+			ResultHandler: func(_ context.Context, name string, result *CallToolResult) (string, error) {
+				if result.IsError {
+					return "", fmt.Errorf("%s: %s", name, result.Content[0].Text)
+				}
+				return FormatResult(result), nil
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, tools, 1)
+
+		_, err = tools[0].(tool.InvokableTool).InvokableRun(ctx, `{}`)
+		assert.EqualError(t, err, "echo: text is required")
+
+		_, err = GetTools(ctx, &ToolsConfig{Client: cli, ToolNameList: []string{"missing"}})
+		assert.EqualError(t, err, "mcp tool missing not found")
+	})
+
+	t.Run("progress option", func(t *testing.T) {
+		tools, err := GetTools(ctx, &ToolsConfig{Client: cli, ToolNameList: []string{"count"}})
+		require.NoError(t, err)
+
+		var got []*Progress
+		out, err := tools[0].(tool.InvokableTool).InvokableRun(ctx, `{}`, WithProgressHandler(func(_ context.Context, p *Progress) {
+			got = append(got, p)
+		}))
+		require.NoError(t, err)
+		assert.Equal(t, "done", out)
+		require.Len(t, got, 3)
+		assert.Equal(t, &Progress{Progress: 3, Total: 3}, got[2])
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := GetTools(ctx, nil)
+		assert.Error(t, err)
+		_, err = GetTools(ctx, &ToolsConfig{})
+		assert.Error(t, err)
+	})
+}
+
+func TestFormatResult(t *testing.T) {
+	assert.Equal(t, "", FormatResult(nil))
+	assert.Equal(t, `{"a":1}`, FormatResult(&CallToolResult{StructuredContent: json.RawMessage(`{"a":1}`)}))
+	assert.Equal(t, "a\n[audio](data:audio/wav;base64,AAA=)\n[doc](file:///doc.md)\ninline\n[file:///b.bin](data:application/octet-stream;base64,AQI=)",
+		FormatResult(&CallToolResult{Content: []Content{
+			TextContent("a"),
+			{Type: "audio", MIMEType: "audio/wav", Data: "AAA="},
+			{Type: "resource_link", URI: "file:///doc.md", Name: "doc"},
+			{Type: "resource", Resource: &ResourceContents{URI: "file:///a.txt", Text: "inline"}},
+			{Type: "resource", Resource: &ResourceContents{URI: "file:///b.bin", MIMEType: "application/octet-stream", Blob: "AQI="}},
+		}}))
+}
@@ -0,0 +1,169 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/favbox/eino/internal/safe"
+)
+
+// Transport 承载 JSON-RPC 消息的双向连接。
+type Transport interface {
+	// Start 建立连接并开始接收消息。每条收到的消息（可能是 JSON-RPC 批量数组）都交给 onMessage，
+	// 连接断开时调用一次 onClose，正常关闭时其参数为 nil。
+	Start(ctx context.Context, onMessage func(msg []byte), onClose func(err error)) error
+	// Send 发送一条消息。
+	Send(ctx context.Context, msg []byte) error
+	// Close 关闭连接。
+	Close() error
+}
+
+// NewIOTransport 创建以换行分隔 JSON 消息的传输，适用于进程内管道或已建立的标准输入输出。
+// Close 时会关闭 w，若 r 实现了 io.Closer 也会一并关闭。
+func NewIOTransport(r io.Reader, w io.WriteCloser) Transport {
+	return &ioTransport{r: r, w: w}
+}
+
+type ioTransport struct {
+	r       io.Reader
+	w       io.WriteCloser
+	writeMu sync.Mutex
+	once    sync.Once
+}
+
+func (t *ioTransport) Start(_ context.Context, onMessage func(msg []byte), onClose func(err error)) error {
+	go func() {
+		var err error
+		defer func() {
+			if panicErr := recover(); panicErr != nil {
+				err = safe.NewPanicErr(panicErr, debug.Stack())
+			}
+			onClose(err)
+		}()
+
+		br := bufio.NewReader(t.r)
+		for {
+			line, readErr := br.ReadBytes('\n')
+			if line = bytes.TrimSpace(line); len(line) > 0 {
+				onMessage(line)
+			}
+			if readErr != nil {
+				if !errors.Is(readErr, io.EOF) && !errors.Is(readErr, io.ErrClosedPipe) && !errors.Is(readErr, os.ErrClosed) {
+					err = readErr
+				}
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func (t *ioTransport) Send(_ context.Context, msg []byte) error {
+	if bytes.IndexByte(msg, '\n') >= 0 {
+		return errors.New("mcp message must not contain newlines")
+	}
+
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	if _, err := t.w.Write(append(msg, '\n')); err != nil {
+		return fmt.Errorf("write mcp message failed: %w", err)
+	}
+	return nil
+}
+
+func (t *ioTransport) Close() error {
+	var err error
+	t.once.Do(func() {
+		err = t.w.Close()
+		if rc, ok := t.r.(io.Closer); ok {
+			_ = rc.Close()
+		}
+	})
+	return err
+}
+
+// StdioConfig 以子进程方式启动 MCP 服务端的配置。
+type StdioConfig struct {
+	// Command 可执行文件，必填。
+	Command string
+	// Args 命令行参数。
+	Args []string
+	// Env 附加的环境变量，格式为 KEY=VALUE，会追加到当前进程的环境变量之后。
+	Env []string
+	// Dir 工作目录，默认为当前目录。
+	Dir string
+	// Stderr 子进程标准错误的去向，默认丢弃。
+	Stderr io.Writer
+}
+
+// NewStdioTransport 创建通过子进程标准输入输出通信的传输，子进程在 Start 时启动，Close 时结束。
+func NewStdioTransport(config *StdioConfig) (Transport, error) {
+	if config == nil || config.Command == "" {
+		return nil, errors.New("mcp stdio transport 'Command' is required")
+	}
+	return &stdioTransport{config: config}, nil
+}
+
+type stdioTransport struct {
+	config *StdioConfig
+	cmd    *exec.Cmd
+	io     Transport
+	done   chan struct{}
+}
+
+func (t *stdioTransport) Start(ctx context.Context, onMessage func(msg []byte), onClose func(err error)) error {
+	cmd := exec.Command(t.config.Command, t.config.Args...)
+	cmd.Dir = t.config.Dir
+	cmd.Env = append(os.Environ(), t.config.Env...)
+	cmd.Stderr = t.config.Stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err = cmd.Start(); err != nil {
+		return fmt.Errorf("start mcp server %s failed: %w", t.config.Command, err)
+	}
+
+	t.cmd, t.done = cmd, make(chan struct{})
+	t.io = NewIOTransport(stdout, stdin)
+	return t.io.Start(ctx, onMessage, func(err error) {
+		close(t.done)
+		onClose(err)
+	})
+}
+
+func (t *stdioTransport) Send(ctx context.Context, msg []byte) error {
+	if t.io == nil {
+		return errors.New("mcp stdio transport not started")
+	}
+	return t.io.Send(ctx, msg)
+}
+
+// Close 关闭子进程的标准输入，若其未在 5 秒内退出则强制结束。
+func (t *stdioTransport) Close() error {
+	if t.io == nil {
+		return nil
+	}
+	_ = t.io.Close()
+	select {
+	case <-t.done:
+	case <-time.After(5 * time.Second):
+		_ = t.cmd.Process.Kill()
+	}
+	_ = t.cmd.Wait()
+	return nil
+}
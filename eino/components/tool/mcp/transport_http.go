@@ -0,0 +1,339 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/favbox/eino/internal/safe"
+)
+
+const (
+	headerSessionID       = "Mcp-Session-Id"
+	headerProtocolVersion = "MCP-Protocol-Version"
+
+	contentTypeJSON = "application/json"
+	contentTypeSSE  = "text/event-stream"
+)
+
+// HTTPConfig 通过 Streamable HTTP 连接 MCP 服务端的配置。
+type HTTPConfig struct {
+	// Endpoint MCP 端点地址，必填。
+	Endpoint string
+	// HTTPClient 发送请求使用的客户端，默认为 http.DefaultClient。
+	HTTPClient *http.Client
+	// Header 每个请求附加的请求头，可用于鉴权。
+	Header http.Header
+	// DisableListen 为 true 时不通过 GET 建立服务端推送流，此时只能在请求的响应流中收到服务端通知。
+	DisableListen bool
+}
+
+// NewHTTPTransport 创建 Streamable HTTP 传输。
+//
+// 每条消息以一个 POST 请求发送，响应为单条 JSON 或 SSE 流。服务端在初始化响应中返回会话 ID 后，
+// 除非设置了 DisableListen，否则会额外发起 GET 请求监听服务端主动推送的消息。Close 时会以 DELETE 请求结束会话。
+func NewHTTPTransport(config *HTTPConfig) (Transport, error) {
+	if config == nil || config.Endpoint == "" {
+		return nil, errors.New("mcp http transport 'Endpoint' is required")
+	}
+
+	client := config.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &httpTransport{config: config, client: client}, nil
+}
+
+type httpTransport struct {
+	config *HTTPConfig
+	client *http.Client
+
+	ctx       context.Context
+	cancel    context.CancelFunc
+	onMessage func(msg []byte)
+	onClose   func(err error)
+
+	mu        sync.Mutex
+	sessionID string
+	listening bool
+	once      sync.Once
+}
+
+func (t *httpTransport) Start(_ context.Context, onMessage func(msg []byte), onClose func(err error)) error {
+	t.ctx, t.cancel = context.WithCancel(context.Background())
+	t.onMessage, t.onClose = onMessage, onClose
+	return nil
+}
+
+func (t *httpTransport) Send(ctx context.Context, msg []byte) error {
+	if t.ctx == nil {
+		return errors.New("mcp http transport not started")
+	}
+	if t.ctx.Err() != nil {
+		return errors.New("mcp http transport closed")
+	}
+
+	// 请求流的生命周期跟随调用方的 ctx，同时在传输关闭时中断
+	reqCtx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(t.ctx, cancel)
+	release := func() {
+		stop()
+		cancel()
+	}
+
+	req, err := t.newRequest(reqCtx, http.MethodPost, bytes.NewReader(msg))
+	if err != nil {
+		release()
+		return err
+	}
+	req.Header.Set("Content-Type", contentTypeJSON)
+	req.Header.Set("Accept", contentTypeJSON+", "+contentTypeSSE)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		release()
+		return fmt.Errorf("post mcp message failed: %w", err)
+	}
+
+	if sid := resp.Header.Get(headerSessionID); sid != "" {
+		t.setSession(sid)
+	}
+
+	if resp.StatusCode == http.StatusAccepted {
+		_ = resp.Body.Close()
+		release()
+		return nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer release()
+		return responseError(resp)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	switch mediaType {
+	case contentTypeJSON:
+		defer release()
+		body, readErr := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if readErr != nil {
+			return fmt.Errorf("read mcp response failed: %w", readErr)
+		}
+		if body = bytes.TrimSpace(body); len(body) > 0 {
+			t.onMessage(body)
+		}
+		return nil
+	case contentTypeSSE:
+		go func() {
+			defer release()
+			t.readStream(resp.Body, pendingRequestID(msg))
+		}()
+		return nil
+	default:
+		_ = resp.Body.Close()
+		release()
+		return fmt.Errorf("unexpected mcp response content type: %q", resp.Header.Get("Content-Type"))
+	}
+}
+
+// Close 以 DELETE 请求结束会话并中断所有进行中的请求。
+func (t *httpTransport) Close() error {
+	if t.ctx == nil {
+		return nil
+	}
+	t.once.Do(func() {
+		t.mu.Lock()
+		sid := t.sessionID
+		t.mu.Unlock()
+		if sid != "" {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			if req, err := t.newRequest(ctx, http.MethodDelete, nil); err == nil {
+				if resp, err := t.client.Do(req); err == nil {
+					_ = resp.Body.Close()
+				}
+			}
+			cancel()
+		}
+		t.cancel()
+		t.onClose(nil)
+	})
+	return nil
+}
+
+func (t *httpTransport) newRequest(ctx context.Context, method string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, t.config.Endpoint, body)
+	if err != nil {
+		return nil, fmt.Errorf("create mcp http request failed: %w", err)
+	}
+	for k, vs := range t.config.Header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	req.Header.Set(headerProtocolVersion, ProtocolVersion)
+
+	t.mu.Lock()
+	if t.sessionID != "" {
+		req.Header.Set(headerSessionID, t.sessionID)
+	}
+	t.mu.Unlock()
+	return req, nil
+}
+
+// setSession 记录会话 ID，首次获得时开始监听服务端推送。
+func (t *httpTransport) setSession(sid string) {
+	t.mu.Lock()
+	t.sessionID = sid
+	listen := !t.listening && !t.config.DisableListen
+	t.listening = true
+	t.mu.Unlock()
+
+	if listen {
+		go t.listen()
+	}
+}
+
+// listen 通过 GET 请求接收服务端主动推送的消息，服务端不支持时（405）直接返回。
+func (t *httpTransport) listen() {
+	req, err := t.newRequest(t.ctx, http.MethodGet, nil)
+	if err != nil {
+		return
+	}
+	req.Header.Set("Accept", contentTypeSSE)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return
+	}
+	t.readStream(resp.Body, nil)
+}
+
+// readStream 逐条转发 SSE 流中的消息。若流在请求 reqID 的响应到达前结束，则补发一条错误响应，避免调用方一直等待。
+func (t *httpTransport) readStream(body io.ReadCloser, reqID []byte) {
+	answered := len(reqID) == 0
+	var err error
+	defer func() {
+		if panicErr := recover(); panicErr != nil {
+			err = safe.NewPanicErr(panicErr, debug.Stack())
+		}
+		_ = body.Close()
+		if answered {
+			return
+		}
+		reason := "stream closed before response"
+		if err != nil {
+			reason = err.Error()
+		}
+		if resp, mErr := sonic.Marshal(&jsonrpcMessage{
+			JSONRPC: jsonrpcVersion,
+			ID:      reqID,
+			Error:   &rpcError{Code: codeInternalError, Message: reason},
+		}); mErr == nil {
+			t.onMessage(resp)
+		}
+	}()
+
+	err = readSSE(body, func(event, data string) {
+		if event != "" && event != "message" {
+			return
+		}
+		msg := []byte(data)
+		if !answered && respondsTo(msg, reqID) {
+			answered = true
+		}
+		t.onMessage(msg)
+	})
+	if err != nil && t.ctx.Err() != nil {
+		err = nil
+	}
+}
+
+// readSSE 解析 text/event-stream，每个事件调用一次 fn，多行 data 以换行拼接。
+func readSSE(r io.Reader, fn func(event, data string)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	var event string
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if len(data) > 0 {
+				fn(event, strings.Join(data, "\n"))
+			}
+			event, data = "", data[:0]
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			event = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+	if len(data) > 0 {
+		fn(event, strings.Join(data, "\n"))
+	}
+	return scanner.Err()
+}
+
+// pendingRequestID 返回消息作为请求时的 ID，通知、响应或批量消息返回 nil。
+func pendingRequestID(msg []byte) []byte {
+	var m jsonrpcMessage
+	if err := sonic.Unmarshal(msg, &m); err != nil || !m.isRequest() {
+		return nil
+	}
+	return m.ID
+}
+
+// respondsTo 判断消息（或批量消息中的任意一条）是否为 ID 为 reqID 的响应。
+func respondsTo(msg []byte, reqID []byte) bool {
+	var batch []*jsonrpcMessage
+	if trimmed := bytes.TrimSpace(msg); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := sonic.Unmarshal(trimmed, &batch); err != nil {
+			return false
+		}
+	} else {
+		var m jsonrpcMessage
+		if err := sonic.Unmarshal(trimmed, &m); err != nil {
+			return false
+		}
+		batch = append(batch, &m)
+	}
+
+	for _, m := range batch {
+		if m.Method == "" && bytes.Equal(m.ID, reqID) {
+			return true
+		}
+	}
+	return false
+}
+
+func responseError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	_ = resp.Body.Close()
+	if len(body) == 0 {
+		return fmt.Errorf("mcp server responded with status %d", resp.StatusCode)
+	}
+	return fmt.Errorf("mcp server responded with status %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+}
@@ -0,0 +1,179 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// httpStub 以 Streamable HTTP 暴露 stubServer：tools/call 以 SSE 响应，其余请求以 JSON 响应。
+type httpStub struct {
+	stub *stubServer
+
+	mu      sync.Mutex
+	push    chan *jsonrpcMessage
+	deleted bool
+	auth    []string
+}
+
+func newHTTPStub() (*httpStub, *httptest.Server) {
+	h := &httpStub{stub: newStubServer(), push: make(chan *jsonrpcMessage, 8)}
+	return h, httptest.NewServer(h)
+}
+
+func (h *httpStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	h.auth = append(h.auth, r.Header.Get("Authorization"))
+	h.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodDelete:
+		h.mu.Lock()
+		h.deleted = r.Header.Get(headerSessionID) == "session-1"
+		h.mu.Unlock()
+		return
+	case http.MethodGet:
+		w.Header().Set("Content-Type", contentTypeSSE)
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		for {
+			select {
+			case msg := <-h.push:
+				writeEvent(w, msg)
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}
+
+	body, _ := io.ReadAll(r.Body)
+	var req jsonrpcMessage
+	if err := json.Unmarshal(body, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Method == methodInitialize {
+		w.Header().Set(headerSessionID, "session-1")
+	} else if r.Header.Get(headerSessionID) != "session-1" {
+		http.Error(w, "missing session", http.StatusBadRequest)
+		return
+	}
+	h.stub.record(&req)
+	if !req.isRequest() {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	if req.Method != methodToolsCall {
+		resp := h.stub.handle(&req, nil)
+		w.Header().Set("Content-Type", contentTypeJSON)
+		_ = json.NewEncoder(w).Encode(resp)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentTypeSSE)
+	w.WriteHeader(http.StatusOK)
+	resp := h.stub.handle(&req, func(msg *jsonrpcMessage) {
+		writeEvent(w, msg)
+	})
+	if resp != nil {
+		writeEvent(w, resp)
+	}
+}
+
+func writeEvent(w http.ResponseWriter, msg *jsonrpcMessage) {
+	raw, _ := json.Marshal(msg)
+	_, _ = fmt.Fprintf(w, "event: message\ndata: %s\n\n", raw)
+	w.(http.Flusher).Flush()
+}
+
+func TestHTTPTransport(t *testing.T) {
+	ctx := context.Background()
+	h, srv := newHTTPStub()
+	defer srv.Close()
+
+	transport, err := NewHTTPTransport(&HTTPConfig{
+		Endpoint: srv.URL,
+		Header:   http.Header{"Authorization": []string{"Bearer token"}},
+	})
+	require.NoError(t, err)
+
+	changed := make(chan struct{}, 1)
+	cli, err := NewClient(ctx, &ClientConfig{
+		Transport: transport,
+		OnToolsChanged: func(ctx context.Context) {
+			changed <- struct{}{}
+		},
+	})
+	require.NoError(t, err)
+
+	tools, err := GetTools(ctx, &ToolsConfig{Client: cli})
+	require.NoError(t, err)
+	require.Len(t, tools, 4)
+
+	result, err := cli.CallTool(ctx, "echo", `{"text":"over http"}`, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "over http", result.Content[0].Text)
+
+	var progress []float64
+	result, err = cli.CallTool(ctx, "count", `{}`, func(p *Progress) {
+		progress = append(progress, p.Progress)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "done", result.Content[0].Text)
+	assert.Equal(t, []float64{1, 2, 3}, progress)
+
+	// 服务端未响应即关闭 SSE 流时，调用以错误结束而非一直等待
+	_, err = cli.CallTool(ctx, "slow", `{}`, nil)
+	assert.ErrorContains(t, err, "stream closed before response")
+
+	// 工具列表变更通知通过 GET 监听流送达
+	h.push <- &jsonrpcMessage{JSONRPC: jsonrpcVersion, Method: notifyToolsListChanged}
+	select {
+	case <-changed:
+	case <-time.After(time.Second):
+		t.Fatal("tools list changed notification not received")
+	}
+
+	require.NoError(t, cli.Close())
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	assert.True(t, h.deleted)
+	for _, auth := range h.auth {
+		assert.Equal(t, "Bearer token", auth)
+	}
+}
+
+func TestHTTPTransportError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	transport, err := NewHTTPTransport(&HTTPConfig{Endpoint: srv.URL})
+	require.NoError(t, err)
+	_, err = NewClient(context.Background(), &ClientConfig{Transport: transport})
+	assert.ErrorContains(t, err, "status 401: unauthorized")
+
+	_, err = NewHTTPTransport(&HTTPConfig{})
+	assert.Error(t, err)
+}
+
+func TestReadSSE(t *testing.T) {
+	var events []string
+	err := readSSE(strings.NewReader(": comment\nevent: message\ndata: {\"a\":1}\n\ndata: line1\ndata: line2\nid: 3\n\nevent: ping\ndata: x"), func(event, data string) {
+		events = append(events, event+"|"+data)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"message|{\"a\":1}", "|line1\nline2", "ping|x"}, events)
+}
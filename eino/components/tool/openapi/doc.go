@@ -0,0 +1,18 @@
+// Package openapi 根据 OpenAPI 3 文档在运行时生成工具，每个接口操作对应一个 tool.InvokableTool。
+//
+// 工具名为操作的 operationId，参数为一个对象：路径、查询与请求头参数各为一个字段，
+// 请求体位于 body 字段，参数的 JSON Schema 取自文档（已展开 $ref 引用）。不同位置的同名参数以位置为前缀
+// 区分，如 query_id、header_id；无法解析或 operationId 重复的操作会被跳过，原因记录在 Document.Errors 中。
+// 调用时按参数位置组装 HTTP 请求，经 RequestEditors（如 BearerAuth）处理后通过 HTTPClient 发送，
+// 响应体可按 ResponseFields 投影并按 MaxResponseBytes 截断后作为工具输出。
+//
+// 示例：
+//
+//	spec, _ := os.ReadFile("petstore.yaml")
+//	tools, _ := openapi.NewTools(ctx, &openapi.Config{
+//		Spec:             spec,
+//		RequestEditors:   []openapi.RequestEditor{openapi.BearerAuth(token)},
+//		ResponseFields:   map[string][]string{"listPets": {"id", "name"}},
+//		MaxResponseBytes: 4096,
+//	})
+package openapi
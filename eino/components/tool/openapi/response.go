@@ -0,0 +1,76 @@
+package openapi
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bytedance/sonic"
+)
+
+// ProjectJSON 只保留 JSON 中指定路径的字段，路径以点分隔，遇到数组时作用于每个元素。
+//
+// 例如对 [{"id":1,"owner":{"name":"a","age":3}}] 保留 id 与 owner.name，得到 [{"id":1,"owner":{"name":"a"}}]。
+// 不存在的路径会被忽略。
+func ProjectJSON(data []byte, fields []string) (string, error) {
+	var v any
+	if err := sonic.Unmarshal(data, &v); err != nil {
+		return "", err
+	}
+
+	paths := make([][]string, 0, len(fields))
+	for _, f := range fields {
+		paths = append(paths, strings.Split(f, "."))
+	}
+	return sonic.MarshalString(project(v, paths))
+}
+
+func project(v any, paths [][]string) any {
+	switch t := v.(type) {
+	case []any:
+		out := make([]any, len(t))
+		for i, item := range t {
+			out[i] = project(item, paths)
+		}
+		return out
+	case map[string]any:
+		// 按首段分组，其余部分递归投影；某个字段只要有一条路径止于此处就整体保留
+		children := make(map[string][][]string)
+		whole := make(map[string]bool)
+		for _, p := range paths {
+			if len(p) == 0 {
+				continue
+			}
+			if len(p) == 1 {
+				whole[p[0]] = true
+			} else {
+				children[p[0]] = append(children[p[0]], p[1:])
+			}
+		}
+
+		out := make(map[string]any)
+		for k, val := range t {
+			if whole[k] {
+				out[k] = val
+			} else if sub, ok := children[k]; ok {
+				out[k] = project(val, sub)
+			}
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// Truncate 将 s 截断为不超过 maxBytes 字节（不拆分 UTF-8 字符），并追加被截断字节数的提示。maxBytes 小于等于 0 时原样返回。
+func Truncate(s string, maxBytes int) string {
+	if maxBytes <= 0 || len(s) <= maxBytes {
+		return s
+	}
+
+	cut := maxBytes
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "...(truncated " + strconv.Itoa(len(s)-cut) + " bytes)"
+}
@@ -0,0 +1,528 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bytedance/sonic"
+	"github.com/eino-contrib/jsonschema"
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+	"gopkg.in/yaml.v3"
+
+	"github.com/favbox/eino/schema"
+)
+
+// 参数位置。
+const (
+	InPath   = "path"
+	InQuery  = "query"
+	InHeader = "header"
+)
+
+// BodyArgument 请求体在工具参数中的字段名。
+const BodyArgument = "body"
+
+var httpMethods = []string{
+	http.MethodGet, http.MethodPut, http.MethodPost, http.MethodDelete,
+	http.MethodOptions, http.MethodHead, http.MethodPatch, http.MethodTrace,
+}
+
+// Operation OpenAPI 文档中的一个接口操作。
+type Operation struct {
+	// ID operationId，未声明时由方法与路径生成，如 get_pets_petId。
+	ID string
+	// Method 大写的 HTTP 方法。
+	Method string
+	// Path 路径模板，如 /pets/{petId}。
+	Path string
+	// Summary 操作摘要。
+	Summary string
+	// Description 操作说明。
+	Description string
+	// Tags 操作标签。
+	Tags []string
+	// Parameters 路径、查询与请求头参数，已合并路径级参数。Cookie 参数不受支持，会被忽略。
+	Parameters []*Parameter
+	// Body 请求体，没有时为 nil。
+	Body *RequestBody
+}
+
+// Parameter 操作的一个参数。
+type Parameter struct {
+	// Name 参数名。
+	Name string
+	// Argument 参数在工具参数中的字段名，通常与 Name 相同。
+	// 同名参数出现在多个位置或与请求体字段 body 重名时，以位置为前缀区分，如 header_X-Id、query_id。
+	Argument string
+	// In 参数位置：path、query 或 header。
+	In string
+	// Required 是否必填，路径参数总是必填。
+	Required bool
+	// Description 参数说明。
+	Description string
+	// Schema 参数的 JSON Schema，已展开本地引用。
+	Schema map[string]any
+}
+
+// RequestBody 操作的请求体。
+type RequestBody struct {
+	// ContentType 请求体的媒体类型，优先选择 application/json。
+	ContentType string
+	// Required 是否必填。
+	Required bool
+	// Description 请求体说明。
+	Description string
+	// Schema 请求体的 JSON Schema，已展开本地引用。
+	Schema map[string]any
+}
+
+// Document 解析后的 OpenAPI 3 文档。
+type Document struct {
+	// Title 文档标题。
+	Title string
+	// Servers 服务地址列表。
+	Servers []string
+	// Operations 全部操作，按路径与方法排序。
+	Operations []*Operation
+	// Errors 无法解析而被跳过的操作及其原因，如引用缺失、operationId 重复等。
+	Errors []error
+}
+
+// ParseDocument 解析 JSON 或 YAML 格式的 OpenAPI 3 文档，并展开文档内的 $ref 引用。
+//
+// 循环引用在第二次出现时替换为不含约束的空 Schema，外部文件引用不受支持。
+// 单个操作无法解析时跳过该操作并记录到 Document.Errors，不影响其余操作。
+func ParseDocument(data []byte) (*Document, error) {
+	var raw map[string]any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("unmarshal openapi document failed: %w", err)
+	}
+	if raw == nil {
+		return nil, errors.New("openapi document is empty")
+	}
+	if version, _ := raw["openapi"].(string); !strings.HasPrefix(version, "3.") {
+		return nil, fmt.Errorf("unsupported openapi version: %v", raw["openapi"])
+	}
+
+	r := &resolver{root: raw, visiting: make(map[string]bool)}
+	doc := &Document{}
+	if info, ok := raw["info"].(map[string]any); ok {
+		doc.Title, _ = info["title"].(string)
+	}
+	for _, s := range asSlice(raw["servers"]) {
+		if server, ok := s.(map[string]any); ok {
+			if u, _ := server["url"].(string); u != "" {
+				doc.Servers = append(doc.Servers, u)
+			}
+		}
+	}
+
+	paths, _ := raw["paths"].(map[string]any)
+	pathKeys := make([]string, 0, len(paths))
+	for p := range paths {
+		pathKeys = append(pathKeys, p)
+	}
+	sort.Strings(pathKeys)
+
+	ids := make(map[string]string)
+	for _, p := range pathKeys {
+		// 路径项只做浅层展开，操作内的引用由各操作自行展开，一处引用缺失不影响同一路径下的其他操作
+		item, err := r.resolvePathItem(paths[p])
+		if err != nil {
+			doc.Errors = append(doc.Errors, fmt.Errorf("resolve path %s failed: %w", p, err))
+			continue
+		}
+		for _, method := range httpMethods {
+			opRaw, ok := item[strings.ToLower(method)].(map[string]any)
+			if !ok {
+				continue
+			}
+			op, err := r.parseOperation(method, p, asSlice(item["parameters"]), opRaw)
+			if err != nil {
+				doc.Errors = append(doc.Errors, fmt.Errorf("parse operation %s %s failed: %w", method, p, err))
+				continue
+			}
+			// operationId 即工具名，重复时只保留第一个
+			if prev, ok := ids[op.ID]; ok {
+				doc.Errors = append(doc.Errors, fmt.Errorf("parse operation %s %s failed: duplicate operationId %s (also used by %s)", method, p, op.ID, prev))
+				continue
+			}
+			ids[op.ID] = method + " " + p
+			doc.Operations = append(doc.Operations, op)
+		}
+	}
+
+	return doc, nil
+}
+
+func (r *resolver) parseOperation(method, path string, pathParams []any, raw map[string]any) (*Operation, error) {
+	op := &Operation{Method: method, Path: path}
+	op.ID, _ = raw["operationId"].(string)
+	if op.ID == "" {
+		op.ID = defaultOperationID(method, path)
+	}
+	op.Summary, _ = raw["summary"].(string)
+	op.Description, _ = raw["description"].(string)
+	for _, t := range asSlice(raw["tags"]) {
+		if tag, ok := t.(string); ok {
+			op.Tags = append(op.Tags, tag)
+		}
+	}
+
+	// 操作级参数覆盖同名同位置的路径级参数
+	var params []*Parameter
+	index := make(map[string]int)
+	for _, p := range append(append([]any{}, pathParams...), asSlice(raw["parameters"])...) {
+		param, err := r.parseParameter(p)
+		if err != nil {
+			return nil, err
+		}
+		if param == nil {
+			continue
+		}
+		key := param.In + ":" + param.Name
+		if i, ok := index[key]; ok {
+			params[i] = param
+			continue
+		}
+		index[key] = len(params)
+		params = append(params, param)
+	}
+
+	if raw["requestBody"] != nil {
+		body, err := r.resolveMap(raw["requestBody"])
+		if err != nil {
+			return nil, err
+		}
+		op.Body = parseRequestBody(body)
+	}
+
+	// 不同位置的同名参数，以及与请求体重名的参数，以位置为前缀作为工具参数名
+	counts := make(map[string]int, len(params))
+	for _, p := range params {
+		counts[p.Name]++
+	}
+	seen := make(map[string]bool, len(params)+1)
+	if op.Body != nil {
+		seen[BodyArgument] = true
+	}
+	for _, p := range params {
+		p.Argument = p.Name
+		if counts[p.Name] > 1 || (op.Body != nil && p.Name == BodyArgument) {
+			p.Argument = p.In + "_" + p.Name
+		}
+		if seen[p.Argument] {
+			return nil, fmt.Errorf("duplicate parameter name: %s", p.Argument)
+		}
+		seen[p.Argument] = true
+	}
+	op.Parameters = params
+
+	return op, nil
+}
+
+// argument 返回参数在工具参数中的字段名，未设置 Argument 时使用 Name。
+func (p *Parameter) argument() string {
+	if p.Argument != "" {
+		return p.Argument
+	}
+	return p.Name
+}
+
+func (r *resolver) parseParameter(raw any) (*Parameter, error) {
+	m, err := r.resolveMap(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Parameter{}
+	p.Name, _ = m["name"].(string)
+	p.In, _ = m["in"].(string)
+	p.Required, _ = m["required"].(bool)
+	p.Description, _ = m["description"].(string)
+	if p.Name == "" {
+		return nil, errors.New("parameter name is required")
+	}
+
+	switch p.In {
+	case InPath:
+		p.Required = true
+	case InQuery:
+	case InHeader:
+		// 这些请求头由 OpenAPI 规范定义为忽略，由客户端或鉴权钩子设置
+		switch strings.ToLower(p.Name) {
+		case "accept", "content-type", "authorization":
+			return nil, nil
+		}
+	default:
+		return nil, nil
+	}
+
+	p.Schema, _ = m["schema"].(map[string]any)
+	if p.Schema == nil {
+		// content 形式的参数取第一个媒体类型的 Schema
+		if content, ok := m["content"].(map[string]any); ok {
+			for _, mt := range sortedKeys(content) {
+				if media, ok := content[mt].(map[string]any); ok {
+					p.Schema, _ = media["schema"].(map[string]any)
+					break
+				}
+			}
+		}
+	}
+	if p.Schema == nil {
+		p.Schema = map[string]any{"type": "string"}
+	}
+	return p, nil
+}
+
+func parseRequestBody(raw map[string]any) *RequestBody {
+	content, _ := raw["content"].(map[string]any)
+	if len(content) == 0 {
+		return nil
+	}
+
+	body := &RequestBody{}
+	body.Required, _ = raw["required"].(bool)
+	body.Description, _ = raw["description"].(string)
+
+	types := sortedKeys(content)
+	body.ContentType = types[0]
+	for _, mt := range types {
+		if mt == "application/json" || strings.HasSuffix(mt, "+json") {
+			body.ContentType = mt
+			break
+		}
+	}
+	if media, ok := content[body.ContentType].(map[string]any); ok {
+		body.Schema, _ = media["schema"].(map[string]any)
+	}
+	if body.Schema == nil {
+		body.Schema = map[string]any{}
+	}
+	return body
+}
+
+// ToolInfo 返回操作对应的工具信息。
+//
+// 工具名为 operationId，参数为一个对象：路径、查询与请求头参数各为一个字段，请求体位于 body 字段。
+func (op *Operation) ToolInfo() (*schema.ToolInfo, error) {
+	desc := op.Summary
+	if op.Description != "" {
+		if desc != "" {
+			desc += "\n\n"
+		}
+		desc += op.Description
+	}
+	if desc == "" {
+		desc = op.Method + " " + op.Path
+	}
+
+	sc := &jsonschema.Schema{
+		Type:       string(schema.Object),
+		Properties: orderedmap.New[string, *jsonschema.Schema](),
+		Required:   make([]string, 0),
+	}
+	for _, p := range op.Parameters {
+		ps, err := toJSONSchema(p.Schema)
+		if err != nil {
+			return nil, fmt.Errorf("convert schema of parameter %s failed: %w", p.Name, err)
+		}
+		if ps.Description == "" {
+			ps.Description = p.Description
+		}
+		sc.Properties.Set(p.argument(), ps)
+		if p.Required {
+			sc.Required = append(sc.Required, p.argument())
+		}
+	}
+	if op.Body != nil {
+		bs, err := toJSONSchema(op.Body.Schema)
+		if err != nil {
+			return nil, fmt.Errorf("convert schema of request body failed: %w", err)
+		}
+		if bs.Description == "" {
+			bs.Description = op.Body.Description
+		}
+		sc.Properties.Set(BodyArgument, bs)
+		if op.Body.Required {
+			sc.Required = append(sc.Required, BodyArgument)
+		}
+	}
+
+	return &schema.ToolInfo{
+		Name:        op.ID,
+		Desc:        desc,
+		ParamsOneOf: schema.NewParamsOneOfByJSONSchema(sc),
+	}, nil
+}
+
+// toJSONSchema 将 OpenAPI Schema 转换为 JSON Schema，nullable 转换为包含 null 的类型数组，example 转换为 examples。
+func toJSONSchema(m map[string]any) (*jsonschema.Schema, error) {
+	raw, err := sonic.Marshal(normalizeSchema(m))
+	if err != nil {
+		return nil, err
+	}
+	sc := &jsonschema.Schema{}
+	if err = sonic.Unmarshal(raw, sc); err != nil {
+		return nil, err
+	}
+	return sc, nil
+}
+
+func normalizeSchema(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, val := range t {
+			switch k {
+			case "nullable", "example", "xml", "externalDocs", "discriminator":
+				continue
+			}
+			out[k] = normalizeSchema(val)
+		}
+		if nullable, _ := t["nullable"].(bool); nullable {
+			if typ, ok := out["type"].(string); ok {
+				out["type"] = []any{typ, "null"}
+			}
+		}
+		if example, ok := t["example"]; ok {
+			if _, exists := out["examples"]; !exists {
+				out["examples"] = []any{example}
+			}
+		}
+		return out
+	case []any:
+		out := make([]any, len(t))
+		for i, val := range t {
+			out[i] = normalizeSchema(val)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// resolver 展开文档内的 $ref 引用。
+type resolver struct {
+	root     map[string]any
+	visiting map[string]bool
+}
+
+func (r *resolver) resolveMap(v any) (map[string]any, error) {
+	resolved, err := r.resolve(v)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := resolved.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected object, got %T", resolved)
+	}
+	return m, nil
+}
+
+// resolvePathItem 展开路径项自身的 $ref，不展开其内部的引用。
+func (r *resolver) resolvePathItem(v any) (map[string]any, error) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected object, got %T", v)
+	}
+	ref, ok := m["$ref"].(string)
+	if !ok {
+		return m, nil
+	}
+	target, err := r.lookup(ref)
+	if err != nil {
+		return nil, err
+	}
+	if m, ok = target.(map[string]any); !ok {
+		return nil, fmt.Errorf("expected object, got %T", target)
+	}
+	return m, nil
+}
+
+// resolve 返回展开了所有引用的副本。
+func (r *resolver) resolve(v any) (any, error) {
+	switch t := v.(type) {
+	case map[string]any:
+		if ref, ok := t["$ref"].(string); ok {
+			if r.visiting[ref] {
+				return map[string]any{}, nil
+			}
+			target, err := r.lookup(ref)
+			if err != nil {
+				return nil, err
+			}
+			r.visiting[ref] = true
+			defer delete(r.visiting, ref)
+			return r.resolve(target)
+		}
+
+		out := make(map[string]any, len(t))
+		for k, val := range t {
+			resolved, err := r.resolve(val)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = resolved
+		}
+		return out, nil
+	case []any:
+		out := make([]any, len(t))
+		for i, val := range t {
+			resolved, err := r.resolve(val)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = resolved
+		}
+		return out, nil
+	default:
+		return v, nil
+	}
+}
+
+// lookup 按 JSON Pointer 查找文档内的引用目标。
+func (r *resolver) lookup(ref string) (any, error) {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf("unsupported $ref: %s", ref)
+	}
+
+	var cur any = r.root
+	for _, token := range strings.Split(ref[2:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("$ref not found: %s", ref)
+		}
+		if cur, ok = m[token]; !ok {
+			return nil, fmt.Errorf("$ref not found: %s", ref)
+		}
+	}
+	return cur, nil
+}
+
+var nonIdentChars = regexp.MustCompile(`[^a-zA-Z0-9_]+`)
+
+// defaultOperationID 由方法与路径生成操作 ID，如 GET /pets/{petId} 生成 get_pets_petId。
+func defaultOperationID(method, path string) string {
+	return strings.ToLower(method) + "_" + strings.Trim(nonIdentChars.ReplaceAllString(path, "_"), "_")
+}
+
+func asSlice(v any) []any {
+	s, _ := v.([]any)
+	return s
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,154 @@
+package openapi
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadPetstore(t *testing.T) []byte {
+	data, err := os.ReadFile("testdata/petstore.yaml")
+	require.NoError(t, err)
+	return data
+}
+
+func TestParseDocument(t *testing.T) {
+	doc, err := ParseDocument(loadPetstore(t))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Petstore", doc.Title)
+	assert.Equal(t, []string{"http://petstore.example.com/v1"}, doc.Servers)
+
+	ids := make([]string, 0, len(doc.Operations))
+	for _, op := range doc.Operations {
+		ids = append(ids, op.ID)
+	}
+	assert.Equal(t, []string{"listPets", "createPet", "get_pets_petId", "deletePet"}, ids)
+	assert.Empty(t, doc.Errors)
+
+	list := doc.Operations[0]
+	assert.Equal(t, "GET", list.Method)
+	assert.Equal(t, []string{"pets"}, list.Tags)
+	require.Len(t, list.Parameters, 2)
+	assert.Equal(t, &Parameter{
+		Name:        "limit",
+		Argument:    "limit",
+		In:          InQuery,
+		Description: "Max items to return",
+		Schema:      map[string]any{"type": "integer", "maximum": 100},
+	}, list.Parameters[0])
+
+	// Authorization 请求头参数被忽略，请求体引用被展开
+	create := doc.Operations[1]
+	require.Len(t, create.Parameters, 1)
+	assert.Equal(t, "X-Request-Id", create.Parameters[0].Name)
+	require.NotNil(t, create.Body)
+	assert.Equal(t, "application/json", create.Body.ContentType)
+	assert.True(t, create.Body.Required)
+	assert.Equal(t, "object", create.Body.Schema["type"])
+
+	// 路径级参数合并到操作中，且总是必填
+	get := doc.Operations[2]
+	require.Len(t, get.Parameters, 1)
+	assert.Equal(t, "petId", get.Parameters[0].Name)
+	assert.True(t, get.Parameters[0].Required)
+
+	// Cookie 参数被忽略
+	assert.Len(t, doc.Operations[3].Parameters, 1)
+}
+
+func TestParseDocumentError(t *testing.T) {
+	_, err := ParseDocument([]byte(`swagger: "2.0"`))
+	assert.ErrorContains(t, err, "unsupported openapi version")
+
+	_, err = ParseDocument([]byte(`openapi: "3.0.0"
+paths: [`))
+	assert.ErrorContains(t, err, "unmarshal openapi document failed")
+}
+
+func TestParseDocumentSkipsBadOperations(t *testing.T) {
+	doc, err := ParseDocument([]byte(`{"openapi":"3.1.0","paths":{
+		"/a":{"get":{"operationId":"a","parameters":[{"$ref":"#/components/parameters/Missing"}]},
+		      "post":{"operationId":"b"}},
+		"/b":{"get":{"operationId":"b"}},
+		"/c":{"get":{"operationId":"c","parameters":[{"name":"query_id","in":"query"},{"name":"id","in":"query"},{"name":"id","in":"header"}]}}}}`))
+	require.NoError(t, err)
+
+	// 引用缺失与 operationId 重复的操作被跳过，其余操作正常生成
+	ids := make([]string, 0, len(doc.Operations))
+	for _, op := range doc.Operations {
+		ids = append(ids, op.ID)
+	}
+	assert.Equal(t, []string{"b"}, ids)
+	require.Len(t, doc.Errors, 3)
+	assert.ErrorContains(t, doc.Errors[0], "$ref not found: #/components/parameters/Missing")
+	assert.ErrorContains(t, doc.Errors[1], "duplicate operationId b (also used by POST /a)")
+	assert.ErrorContains(t, doc.Errors[2], "duplicate parameter name: query_id")
+}
+
+func TestParseDocumentParameterNamespacing(t *testing.T) {
+	doc, err := ParseDocument([]byte(`{"openapi":"3.1.0","paths":{"/a/{id}":{"post":{"parameters":[
+		{"name":"id","in":"path"},{"name":"id","in":"query"},{"name":"body","in":"query"},{"name":"X-Id","in":"header"}],
+		"requestBody":{"content":{"application/json":{"schema":{"type":"object"}}}}}}}}`))
+	require.NoError(t, err)
+	require.Empty(t, doc.Errors)
+
+	args := make([]string, 0, len(doc.Operations[0].Parameters))
+	for _, p := range doc.Operations[0].Parameters {
+		args = append(args, p.Argument)
+	}
+	assert.Equal(t, []string{"path_id", "query_id", "query_body", "X-Id"}, args)
+
+	info, err := doc.Operations[0].ToolInfo()
+	require.NoError(t, err)
+	sc, err := info.ParamsOneOf.ToJSONSchema()
+	require.NoError(t, err)
+	keys := make([]string, 0, sc.Properties.Len())
+	for pair := sc.Properties.Oldest(); pair != nil; pair = pair.Next() {
+		keys = append(keys, pair.Key)
+	}
+	assert.Equal(t, []string{"path_id", "query_id", "query_body", "X-Id", BodyArgument}, keys)
+	assert.Equal(t, []string{"path_id"}, sc.Required)
+}
+
+func TestOperationToolInfo(t *testing.T) {
+	doc, err := ParseDocument(loadPetstore(t))
+	require.NoError(t, err)
+
+	info, err := doc.Operations[0].ToolInfo()
+	require.NoError(t, err)
+	assert.Equal(t, "listPets", info.Name)
+	assert.Equal(t, "List pets\n\nReturns pets filtered by tag.", info.Desc)
+
+	sc, err := info.ParamsOneOf.ToJSONSchema()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"limit", "tags"}, []string{sc.Properties.Oldest().Key, sc.Properties.Newest().Key})
+	tags, _ := sc.Properties.Get("tags")
+	assert.Equal(t, "array", tags.Type)
+	assert.Equal(t, "string", tags.Items.Type)
+	assert.Equal(t, "Tags to filter by", tags.Description)
+	assert.Empty(t, sc.Required)
+
+	info, err = doc.Operations[1].ToolInfo()
+	require.NoError(t, err)
+	sc, err = info.ParamsOneOf.ToJSONSchema()
+	require.NoError(t, err)
+	assert.Equal(t, []string{BodyArgument}, sc.Required)
+	body, _ := sc.Properties.Get(BodyArgument)
+	assert.Equal(t, []string{"name"}, body.Required)
+	name, _ := body.Properties.Get("name")
+	assert.Equal(t, []any{"Kitty"}, name.Examples)
+	tag, _ := body.Properties.Get("tag")
+	assert.Equal(t, []string{"string", "null"}, tag.TypeEnhanced)
+
+	// 循环引用在第二次出现时截断为空 Schema
+	owner, _ := body.Properties.Get("owner")
+	pets, _ := owner.Properties.Get("pets")
+	assert.Empty(t, pets.Items.Type)
+
+	info, err = doc.Operations[3].ToolInfo()
+	require.NoError(t, err)
+	assert.Equal(t, "DELETE /pets/{petId}", info.Desc)
+}
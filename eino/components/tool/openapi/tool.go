@@ -0,0 +1,310 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/favbox/eino/components/tool"
+	"github.com/favbox/eino/schema"
+)
+
+// RequestEditor 在请求发送前修改请求，通常用于设置鉴权信息。
+type RequestEditor func(ctx context.Context, op *Operation, req *http.Request) error
+
+// ResponseHandler 将 HTTP 响应转换为工具输出。body 为完整读取的响应体。
+type ResponseHandler func(ctx context.Context, op *Operation, resp *http.Response, body []byte) (string, error)
+
+// Config 从 OpenAPI 文档生成工具的配置。
+type Config struct {
+	// Spec JSON 或 YAML 格式的 OpenAPI 3 文档，与 Document 二选一。
+	// 无法解析的操作会被跳过，需要检查跳过原因时可先调用 ParseDocument 并查看 Document.Errors。
+	Spec []byte
+	// Document 已解析的文档，与 Spec 二选一。
+	Document *Document
+	// BaseURL 服务地址，默认使用文档 servers 中的第一个地址。
+	BaseURL string
+	// HTTPClient 发送请求使用的客户端，默认为 http.DefaultClient。
+	HTTPClient *http.Client
+	// RequestEditors 依次作用于每个请求，可使用 BearerAuth、HeaderAuth 或自定义实现。
+	RequestEditors []RequestEditor
+	// OperationFilter 返回 false 的操作不会生成工具，默认生成全部操作。
+	OperationFilter func(op *Operation) bool
+	// ResponseFields 按 operationId 配置输出保留的 JSON 字段路径，如 {"listPets": {"id", "name", "owner.name"}}。
+	// 路径遇到数组时作用于每个元素；响应不是 JSON 时忽略。
+	ResponseFields map[string][]string
+	// MaxResponseBytes 输出的最大字节数，超出部分截断并追加提示，小于等于 0 时不截断。
+	MaxResponseBytes int
+	// ResponseHandler 自定义响应转换，设置后 ResponseFields 与 MaxResponseBytes 不再生效。
+	// 默认状态码非 2xx 时返回错误，否则返回投影、截断后的响应体。
+	ResponseHandler ResponseHandler
+}
+
+// BearerAuth 返回设置 Authorization: Bearer 请求头的 RequestEditor。
+func BearerAuth(token string) RequestEditor {
+	return HeaderAuth("Authorization", "Bearer "+token)
+}
+
+// HeaderAuth 返回设置指定请求头的 RequestEditor，可用于 API Key 鉴权。
+func HeaderAuth(name, value string) RequestEditor {
+	return func(_ context.Context, _ *Operation, req *http.Request) error {
+		req.Header.Set(name, value)
+		return nil
+	}
+}
+
+// NewTools 为文档中的每个操作生成一个 tool.InvokableTool。
+func NewTools(_ context.Context, config *Config) ([]tool.InvokableTool, error) {
+	if config == nil {
+		return nil, errors.New("openapi tools config is required")
+	}
+
+	doc := config.Document
+	if doc == nil {
+		if len(config.Spec) == 0 {
+			return nil, errors.New("openapi tools 'Spec' or 'Document' is required")
+		}
+		var err error
+		if doc, err = ParseDocument(config.Spec); err != nil {
+			return nil, err
+		}
+	}
+
+	baseURL := config.BaseURL
+	if baseURL == "" && len(doc.Servers) > 0 {
+		baseURL = doc.Servers[0]
+	}
+	if _, err := url.Parse(baseURL); err != nil || !strings.HasPrefix(baseURL, "http") {
+		return nil, fmt.Errorf("openapi tools 'BaseURL' is invalid: %q", baseURL)
+	}
+
+	client := config.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	tools := make([]tool.InvokableTool, 0, len(doc.Operations))
+	names := make(map[string]bool, len(doc.Operations))
+	for _, op := range doc.Operations {
+		if config.OperationFilter != nil && !config.OperationFilter(op) {
+			continue
+		}
+		if names[op.ID] {
+			return nil, fmt.Errorf("duplicate operation id: %s", op.ID)
+		}
+		names[op.ID] = true
+		info, err := op.ToolInfo()
+		if err != nil {
+			return nil, fmt.Errorf("build tool info of operation %s failed: %w", op.ID, err)
+		}
+
+		handler := config.ResponseHandler
+		if handler == nil {
+			handler = defaultResponseHandler(config.ResponseFields[op.ID], config.MaxResponseBytes)
+		}
+		tools = append(tools, &operationTool{
+			op:      op,
+			info:    info,
+			baseURL: strings.TrimSuffix(baseURL, "/"),
+			client:  client,
+			editors: config.RequestEditors,
+			handler: handler,
+		})
+	}
+
+	return tools, nil
+}
+
+type operationTool struct {
+	op      *Operation
+	info    *schema.ToolInfo
+	baseURL string
+	client  *http.Client
+	editors []RequestEditor
+	handler ResponseHandler
+}
+
+func (t *operationTool) Info(_ context.Context) (*schema.ToolInfo, error) {
+	return t.info, nil
+}
+
+func (t *operationTool) InvokableRun(ctx context.Context, argumentsInJSON string, _ ...tool.Option) (string, error) {
+	args := make(map[string]any)
+	if strings.TrimSpace(argumentsInJSON) != "" {
+		if err := sonic.UnmarshalString(argumentsInJSON, &args); err != nil {
+			return "", fmt.Errorf("[OpenAPI] failed to unmarshal arguments, toolName=%s, err=%w", t.op.ID, err)
+		}
+	}
+
+	req, err := t.buildRequest(ctx, args)
+	if err != nil {
+		return "", fmt.Errorf("[OpenAPI] failed to build request, toolName=%s, err=%w", t.op.ID, err)
+	}
+	for _, edit := range t.editors {
+		if err = edit(ctx, t.op, req); err != nil {
+			return "", fmt.Errorf("[OpenAPI] failed to edit request, toolName=%s, err=%w", t.op.ID, err)
+		}
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("[OpenAPI] failed to send request, toolName=%s, err=%w", t.op.ID, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("[OpenAPI] failed to read response, toolName=%s, err=%w", t.op.ID, err)
+	}
+	return t.handler(ctx, t.op, resp, body)
+}
+
+// GetType 返回工具实现的类型名称。
+func (t *operationTool) GetType() string {
+	return "OpenAPI"
+}
+
+// buildRequest 按参数位置将工具参数填入路径、查询串、请求头与请求体。
+func (t *operationTool) buildRequest(ctx context.Context, args map[string]any) (*http.Request, error) {
+	path := t.op.Path
+	query := url.Values{}
+	header := http.Header{}
+
+	for _, p := range t.op.Parameters {
+		v, ok := args[p.argument()]
+		if !ok || v == nil {
+			if p.Required {
+				return nil, fmt.Errorf("missing required parameter: %s", p.argument())
+			}
+			continue
+		}
+
+		switch p.In {
+		case InPath:
+			s, err := formatValue(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid parameter %s: %w", p.Name, err)
+			}
+			path = strings.ReplaceAll(path, "{"+p.Name+"}", url.PathEscape(s))
+		case InQuery:
+			// 数组按 form 风格展开为重复的查询参数
+			values, isArray := v.([]any)
+			if !isArray {
+				values = []any{v}
+			}
+			for _, item := range values {
+				s, err := formatValue(item)
+				if err != nil {
+					return nil, fmt.Errorf("invalid parameter %s: %w", p.Name, err)
+				}
+				query.Add(p.Name, s)
+			}
+		case InHeader:
+			s, err := formatValue(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid parameter %s: %w", p.Name, err)
+			}
+			header.Set(p.Name, s)
+		}
+	}
+
+	var body io.Reader
+	if t.op.Body != nil {
+		v, ok := args[BodyArgument]
+		if ok && v != nil {
+			raw, err := encodeBody(t.op.Body.ContentType, v)
+			if err != nil {
+				return nil, err
+			}
+			body = bytes.NewReader(raw)
+			header.Set("Content-Type", t.op.Body.ContentType)
+		} else if t.op.Body.Required {
+			return nil, fmt.Errorf("missing required parameter: %s", BodyArgument)
+		}
+	}
+
+	u := t.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, t.op.Method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, vs := range header {
+		req.Header[k] = vs
+	}
+	req.Header.Set("Accept", "application/json, */*")
+	return req, nil
+}
+
+// encodeBody 按媒体类型编码请求体，支持 JSON、表单与纯文本。
+func encodeBody(contentType string, v any) ([]byte, error) {
+	switch {
+	case contentType == "application/x-www-form-urlencoded":
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("form body must be an object, got %T", v)
+		}
+		form := url.Values{}
+		for k, val := range m {
+			s, err := formatValue(val)
+			if err != nil {
+				return nil, fmt.Errorf("invalid form field %s: %w", k, err)
+			}
+			form.Set(k, s)
+		}
+		return []byte(form.Encode()), nil
+	case strings.HasPrefix(contentType, "text/"):
+		s, err := formatValue(v)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(s), nil
+	default:
+		raw, err := sonic.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("marshal request body failed: %w", err)
+		}
+		return raw, nil
+	}
+}
+
+// formatValue 将标量参数格式化为字符串，对象与数组编码为 JSON。
+func formatValue(v any) (string, error) {
+	switch t := v.(type) {
+	case string:
+		return t, nil
+	case bool:
+		return strconv.FormatBool(t), nil
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64), nil
+	default:
+		return sonic.MarshalString(v)
+	}
+}
+
+func defaultResponseHandler(fields []string, maxBytes int) ResponseHandler {
+	return func(_ context.Context, op *Operation, resp *http.Response, body []byte) (string, error) {
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return "", fmt.Errorf("[OpenAPI] %s %s responded with status %d: %s",
+				op.Method, op.Path, resp.StatusCode, Truncate(string(body), 512))
+		}
+
+		out := string(body)
+		if len(fields) > 0 {
+			if projected, err := ProjectJSON(body, fields); err == nil {
+				out = projected
+			}
+		}
+		return Truncate(out, maxBytes), nil
+	}
+}
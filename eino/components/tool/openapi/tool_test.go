@@ -0,0 +1,200 @@
+package openapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/favbox/eino/components/tool"
+)
+
+func newPetServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, `{"error":"unauthorized"}`, http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/pets":
+			assert.Equal(t, "2", r.URL.Query().Get("limit"))
+			assert.Equal(t, []string{"cat", "dog"}, r.URL.Query()["tags"])
+			_, _ = io.WriteString(w, `[{"id":1,"name":"Kitty","owner":{"name":"Ann","age":30}},{"id":2,"name":"Rex","owner":{"name":"Bob","age":40}}]`)
+		case r.Method == http.MethodPost && r.URL.Path == "/pets":
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			assert.Equal(t, "req-1", r.Header.Get("X-Request-Id"))
+			body, _ := io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = fmt.Fprintf(w, `{"created":%s}`, body)
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/pets/"):
+			if r.URL.Path != "/pets/7" {
+				http.Error(w, `{"error":"not found"}`, http.StatusNotFound)
+				return
+			}
+			_, _ = io.WriteString(w, `{"id":7,"name":"猫咪猫咪猫咪"}`)
+		default:
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+		}
+	}))
+}
+
+func toolsByName(t *testing.T, tools []tool.InvokableTool) map[string]tool.InvokableTool {
+	byName := make(map[string]tool.InvokableTool, len(tools))
+	for _, it := range tools {
+		info, err := it.Info(context.Background())
+		require.NoError(t, err)
+		byName[info.Name] = it
+	}
+	return byName
+}
+
+func TestNewTools(t *testing.T) {
+	ctx := context.Background()
+	srv := newPetServer(t)
+	defer srv.Close()
+
+	tools, err := NewTools(ctx, &Config{
+		Spec:           loadPetstore(t),
+		BaseURL:        srv.URL,
+		HTTPClient:     srv.Client(),
+		RequestEditors: []RequestEditor{BearerAuth("secret")},
+		ResponseFields: map[string][]string{"listPets": {"id", "owner.name"}},
+		OperationFilter: func(op *Operation) bool {
+			return op.ID != "deletePet"
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, tools, 3)
+	byName := toolsByName(t, tools)
+
+	t.Run("query parameters and projection", func(t *testing.T) {
+		out, err := byName["listPets"].InvokableRun(ctx, `{"limit":2,"tags":["cat","dog"]}`)
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"id":1,"owner":{"name":"Ann"}},{"id":2,"owner":{"name":"Bob"}}]`, out)
+	})
+
+	t.Run("header and body", func(t *testing.T) {
+		out, err := byName["createPet"].InvokableRun(ctx, `{"X-Request-Id":"req-1","body":{"name":"Tom"}}`)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"created":{"name":"Tom"}}`, out)
+
+		_, err = byName["createPet"].InvokableRun(ctx, `{}`)
+		assert.ErrorContains(t, err, "missing required parameter: body")
+	})
+
+	t.Run("path parameter and status error", func(t *testing.T) {
+		out, err := byName["get_pets_petId"].InvokableRun(ctx, `{"petId":7}`)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"id":7,"name":"猫咪猫咪猫咪"}`, out)
+
+		_, err = byName["get_pets_petId"].InvokableRun(ctx, `{"petId":8}`)
+		assert.ErrorContains(t, err, "GET /pets/{petId} responded with status 404")
+
+		_, err = byName["get_pets_petId"].InvokableRun(ctx, `{}`)
+		assert.ErrorContains(t, err, "missing required parameter: petId")
+	})
+}
+
+func TestNewToolsResponseOptions(t *testing.T) {
+	ctx := context.Background()
+	srv := newPetServer(t)
+	defer srv.Close()
+	doc, err := ParseDocument(loadPetstore(t))
+	require.NoError(t, err)
+
+	t.Run("truncate", func(t *testing.T) {
+		tools, err := NewTools(ctx, &Config{
+			Document:         doc,
+			BaseURL:          srv.URL + "/",
+			RequestEditors:   []RequestEditor{HeaderAuth("Authorization", "Bearer secret")},
+			MaxResponseBytes: 20,
+		})
+		require.NoError(t, err)
+		out, err := toolsByName(t, tools)["get_pets_petId"].InvokableRun(ctx, `{"petId":"7"}`)
+		require.NoError(t, err)
+		assert.Equal(t, `{"id":7,"name":"猫`+"...(truncated 17 bytes)", out)
+	})
+
+	t.Run("custom response handler", func(t *testing.T) {
+		tools, err := NewTools(ctx, &Config{
+			Document: doc,
+			BaseURL:  srv.URL,
+			ResponseHandler: func(_ context.Context, op *Operation, resp *http.Response, body []byte) (string, error) {
+				return fmt.Sprintf("%s %d %s", op.ID, resp.StatusCode, body), nil
+			},
+		})
+		require.NoError(t, err)
+		out, err := toolsByName(t, tools)["listPets"].InvokableRun(ctx, `{}`)
+		require.NoError(t, err)
+		assert.Equal(t, "listPets 401 {\"error\":\"unauthorized\"}\n", out)
+	})
+
+	t.Run("request editor error", func(t *testing.T) {
+		tools, err := NewTools(ctx, &Config{
+			Document: doc,
+			BaseURL:  srv.URL,
+			RequestEditors: []RequestEditor{func(context.Context, *Operation, *http.Request) error {
+				return errors.New("no token")
+			}},
+		})
+		require.NoError(t, err)
+		_, err = toolsByName(t, tools)["listPets"].InvokableRun(ctx, `{}`)
+		assert.ErrorContains(t, err, "no token")
+	})
+}
+
+func TestNewToolsConfigError(t *testing.T) {
+	ctx := context.Background()
+	_, err := NewTools(ctx, nil)
+	assert.Error(t, err)
+	_, err = NewTools(ctx, &Config{})
+	assert.Error(t, err)
+	_, err = NewTools(ctx, &Config{Spec: []byte(`{"openapi":"3.0.0","paths":{}}`)})
+	assert.ErrorContains(t, err, "'BaseURL' is invalid")
+
+	op := &Operation{ID: "dup", Method: http.MethodGet, Path: "/a"}
+	_, err = NewTools(ctx, &Config{Document: &Document{Operations: []*Operation{op, op}}, BaseURL: "http://localhost"})
+	assert.ErrorContains(t, err, "duplicate operation id: dup")
+}
+
+func TestNewToolsNamespacedArguments(t *testing.T) {
+	ctx := context.Background()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, "%s %s %s", r.URL.Path, r.URL.RawQuery, r.Header.Get("id"))
+	}))
+	defer srv.Close()
+
+	tools, err := NewTools(ctx, &Config{
+		Spec:    []byte(`{"openapi":"3.0.0","paths":{"/items/{id}":{"get":{"operationId":"getItem","parameters":[{"name":"id","in":"path"},{"name":"id","in":"query"},{"name":"id","in":"header"}]}}}}`),
+		BaseURL: srv.URL,
+	})
+	require.NoError(t, err)
+	out, err := tools[0].InvokableRun(ctx, `{"path_id":"a b","query_id":2,"header_id":"h"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "/items/a b id=2 h", out)
+}
+
+func TestProjectJSON(t *testing.T) {
+	out, err := ProjectJSON([]byte(`{"data":[{"id":1,"meta":{"a":1,"b":2}},{"id":2}],"total":2}`), []string{"data.id", "data.meta.b", "missing"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"data":[{"id":1,"meta":{"b":2}},{"id":2}]}`, out)
+
+	_, err = ProjectJSON([]byte(`not json`), []string{"a"})
+	assert.Error(t, err)
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "abc", Truncate("abc", 0))
+	assert.Equal(t, "abc", Truncate("abc", 3))
+	assert.Equal(t, "ab...(truncated 1 bytes)", Truncate("abc", 2))
+	assert.Equal(t, "...(truncated 3 bytes)", Truncate("中", 2))
+}
@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"strings"
+
+	"github.com/bytedance/sonic"
+	"github.com/eino-contrib/jsonschema"
+
+	"github.com/favbox/eino/internal/generic"
+	"github.com/favbox/eino/schema"
+)
+
+// GoStruct2ParamsOneOf 通过反射 Go 结构体推断工具参数描述。
+//
+// 字段名取自 json 标签，字段说明、枚举等约束取自 jsonschema 标签，例如：
+//
+//	type Input struct {
+//		City string `json:"city" jsonschema:"description=城市名称"`
+//		Unit string `json:"unit,omitempty" jsonschema:"enum=celsius,enum=fahrenheit"`
+//	}
+//
+// 未标注 omitempty 的字段或 jsonschema 标签中含 required 的字段视为必填。
+func GoStruct2ParamsOneOf[T any](opts ...Option) (*schema.ParamsOneOf, error) {
+	return goStruct2ParamsOneOf[T](opts...)
+}
+
+// GoStruct2ToolInfo 通过反射 Go 结构体推断完整的工具信息。
+func GoStruct2ToolInfo[T any](toolName, toolDesc string, opts ...Option) (*schema.ToolInfo, error) {
+	return goStruct2ToolInfo[T](toolName, toolDesc, opts...)
+}
+
+// goStruct2ToolInfo 组装工具名称、描述与推断出的参数描述。
+func goStruct2ToolInfo[T any](toolName, toolDesc string, opts ...Option) (*schema.ToolInfo, error) {
+	paramsOneOf, err := goStruct2ParamsOneOf[T](opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &schema.ToolInfo{
+		Name:        toolName,
+		Desc:        toolDesc,
+		ParamsOneOf: paramsOneOf,
+	}, nil
+}
+
+// goStruct2ParamsOneOf 使用 jsonschema 反射器生成内联（不使用 $ref）的参数 Schema。
+func goStruct2ParamsOneOf[T any](opts ...Option) (*schema.ParamsOneOf, error) {
+	options := getToolOptions(opts...)
+
+	r := &jsonschema.Reflector{
+		Anonymous:      true,
+		DoNotReference: true,
+	}
+	if options.scModifier != nil {
+		r.SchemaModifier = jsonschema.SchemaModifierFn(options.scModifier)
+	}
+
+	js := r.Reflect(generic.NewInstance[T]())
+	js.Version = ""
+
+	return schema.NewParamsOneOfByJSONSchema(js), nil
+}
+
+// marshalString 序列化工具输出，string 类型直接返回。
+func marshalString(resp any) (string, error) {
+	if rs, ok := resp.(string); ok {
+		return rs, nil
+	}
+	return sonic.MarshalString(resp)
+}
+
+// snakeToCamel 将蛇形命名转换为驼峰命名，用作工具实现的类型名称。
+func snakeToCamel(s string) string {
+	if s == "" {
+		return ""
+	}
+
+	parts := strings.Split(s, "_")
+
+	var sb strings.Builder
+	for _, part := range parts {
+		if part == "" {
+			continue
+		}
+		sb.WriteString(strings.ToUpper(part[:1]))
+		sb.WriteString(part[1:])
+	}
+
+	return sb.String()
+}
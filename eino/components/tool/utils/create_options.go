@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/eino-contrib/jsonschema"
+)
+
+// UnmarshalArguments 自定义参数反序列化函数。
+// 用于将模型给出的 JSON 参数字符串转换为工具函数的输入类型，返回值必须可断言为该输入类型。
+type UnmarshalArguments func(ctx context.Context, arguments string) (any, error)
+
+// MarshalOutput 自定义输出序列化函数。
+// 用于将工具函数的返回值转换为交给模型的字符串。
+type MarshalOutput func(ctx context.Context, output any) (string, error)
+
+// SchemaModifierFn 自定义结构体标签解析函数。
+// 在 jsonschema 反射生成每个字段（以及根对象）的 Schema 后调用，可以据此读取自定义标签并修改 Schema。
+// 根对象的 jsonTagName 为 "_root"，tag 为空。
+type SchemaModifierFn func(jsonTagName string, t reflect.Type, tag reflect.StructTag, schema *jsonschema.Schema)
+
+// toolOptions 工具创建选项。
+type toolOptions struct {
+	um         UnmarshalArguments
+	m          MarshalOutput
+	scModifier SchemaModifierFn
+}
+
+// Option 工具创建选项函数。
+type Option func(o *toolOptions)
+
+// WithUnmarshalArguments 设置自定义参数反序列化函数。
+// 未设置时使用 sonic 将参数反序列化为输入类型。
+func WithUnmarshalArguments(um UnmarshalArguments) Option {
+	return func(o *toolOptions) {
+		o.um = um
+	}
+}
+
+// WithMarshalOutput 设置自定义输出序列化函数。
+// 未设置时输出为 string 则原样返回，否则使用 sonic 序列化为 JSON。
+func WithMarshalOutput(m MarshalOutput) Option {
+	return func(o *toolOptions) {
+		o.m = m
+	}
+}
+
+// WithSchemaModifier 设置自定义结构体标签解析函数，用于调整推断出的参数 Schema。
+func WithSchemaModifier(modifier SchemaModifierFn) Option {
+	return func(o *toolOptions) {
+		o.scModifier = modifier
+	}
+}
+
+// getToolOptions 应用选项并返回工具创建选项。
+func getToolOptions(opts ...Option) *toolOptions {
+	to := &toolOptions{}
+	for _, opt := range opts {
+		opt(to)
+	}
+	return to
+}
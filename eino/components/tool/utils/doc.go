@@ -0,0 +1,9 @@
+// Package utils 提供基于 Go 函数创建工具的辅助方法。
+//
+// 通过反射函数的输入结构体（json 与 jsonschema 标签）推断 schema.ToolInfo，
+// 并自动完成参数反序列化与输出序列化，无需手写 Info 与 JSON 编解码逻辑。包括：
+//   - InferTool / InferOptionableTool：创建 tool.InvokableTool
+//   - InferStreamTool / InferOptionableStreamTool：创建 tool.StreamableTool
+//   - NewTool / NewStreamTool：使用已有的 ToolInfo 创建工具
+//   - GoStruct2ParamsOneOf / GoStruct2ToolInfo：仅推断参数描述
+package utils
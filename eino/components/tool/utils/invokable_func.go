@@ -0,0 +1,143 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/favbox/eino/components/tool"
+	"github.com/favbox/eino/internal/generic"
+	"github.com/favbox/eino/schema"
+)
+
+// InvokeFunc 同步工具函数类型。
+type InvokeFunc[T, D any] func(ctx context.Context, input T) (output D, err error)
+
+// OptionableInvokeFunc 可接收工具调用选项的同步工具函数类型。
+type OptionableInvokeFunc[T, D any] func(ctx context.Context, input T, opts ...tool.Option) (output D, err error)
+
+// InferTool 根据函数的输入类型推断工具信息，创建 InvokableTool。
+//
+// 输入类型 T 通常为结构体，其 json 与 jsonschema 标签决定参数 Schema，详见 GoStruct2ParamsOneOf。
+// 调用时参数 JSON 会自动反序列化为 T，返回值 D 会自动序列化为字符串。
+//
+// 示例：
+//
+//	type WeatherInput struct {
+//		City string `json:"city" jsonschema:"description=城市名称"`
+//	}
+//
+//	weatherTool, err := utils.InferTool("get_weather", "查询城市天气",
+//		func(ctx context.Context, in *WeatherInput) (string, error) {
+//			return "晴", nil
+//		})
+func InferTool[T, D any](toolName, toolDesc string, i InvokeFunc[T, D], opts ...Option) (tool.InvokableTool, error) {
+	ti, err := goStruct2ToolInfo[T](toolName, toolDesc, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTool(ti, i, opts...), nil
+}
+
+// InferOptionableTool 与 InferTool 相同，但工具函数可以接收工具调用选项。
+func InferOptionableTool[T, D any](toolName, toolDesc string, i OptionableInvokeFunc[T, D], opts ...Option) (tool.InvokableTool, error) {
+	ti, err := goStruct2ToolInfo[T](toolName, toolDesc, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return newOptionableTool(ti, i, opts...), nil
+}
+
+// NewTool 使用给定的工具信息创建 InvokableTool，输入输出均为 JSON 格式。
+func NewTool[T, D any](desc *schema.ToolInfo, i InvokeFunc[T, D], opts ...Option) tool.InvokableTool {
+	return newOptionableTool(desc, func(ctx context.Context, input T, _ ...tool.Option) (D, error) {
+		return i(ctx, input)
+	}, opts...)
+}
+
+// newOptionableTool 创建可接收工具调用选项的 InvokableTool。
+func newOptionableTool[T, D any](desc *schema.ToolInfo, i OptionableInvokeFunc[T, D], opts ...Option) tool.InvokableTool {
+	to := getToolOptions(opts...)
+
+	return &invokableTool[T, D]{
+		info: desc,
+		um:   to.um,
+		m:    to.m,
+		Fn:   i,
+	}
+}
+
+// invokableTool 基于函数的 InvokableTool 实现。
+type invokableTool[T, D any] struct {
+	info *schema.ToolInfo
+
+	um UnmarshalArguments
+	m  MarshalOutput
+
+	Fn OptionableInvokeFunc[T, D]
+}
+
+// Info 返回工具信息。
+func (i *invokableTool[T, D]) Info(_ context.Context) (*schema.ToolInfo, error) {
+	return i.info, nil
+}
+
+// InvokableRun 反序列化参数、调用工具函数并序列化输出。
+func (i *invokableTool[T, D]) InvokableRun(ctx context.Context, arguments string, opts ...tool.Option) (output string, err error) {
+	var inst T
+	if i.um != nil {
+		var val any
+		val, err = i.um(ctx, arguments)
+		if err != nil {
+			return "", fmt.Errorf("[LocalFunc] failed to unmarshal arguments, toolName=%s, err=%w", i.getToolName(), err)
+		}
+		gt, ok := val.(T)
+		if !ok {
+			return "", fmt.Errorf("[LocalFunc] invalid type, toolName=%s, expected=%T, given=%T", i.getToolName(), inst, val)
+		}
+		inst = gt
+	} else {
+		inst = generic.NewInstance[T]()
+
+		err = sonic.UnmarshalString(arguments, &inst)
+		if err != nil {
+			return "", fmt.Errorf("[LocalFunc] failed to unmarshal arguments in json, toolName=%s, err=%w", i.getToolName(), err)
+		}
+	}
+
+	resp, err := i.Fn(ctx, inst, opts...)
+	if err != nil {
+		return "", fmt.Errorf("[LocalFunc] failed to invoke tool, toolName=%s, err=%w", i.getToolName(), err)
+	}
+
+	if i.m != nil {
+		output, err = i.m(ctx, resp)
+		if err != nil {
+			return "", fmt.Errorf("[LocalFunc] failed to marshal output, toolName=%s, err=%w", i.getToolName(), err)
+		}
+	} else {
+		output, err = marshalString(resp)
+		if err != nil {
+			return "", fmt.Errorf("[LocalFunc] failed to marshal output in json, toolName=%s, err=%w", i.getToolName(), err)
+		}
+	}
+
+	return output, nil
+}
+
+// GetType 返回工具实现的类型名称，由工具名转为驼峰形式得到。
+func (i *invokableTool[T, D]) GetType() string {
+	return snakeToCamel(i.getToolName())
+}
+
+// getToolName 返回工具名称。
+func (i *invokableTool[T, D]) getToolName() string {
+	if i.info == nil {
+		return ""
+	}
+
+	return i.info.Name
+}
@@ -0,0 +1,180 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/eino-contrib/jsonschema"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/favbox/eino/components"
+	"github.com/favbox/eino/components/tool"
+)
+
+type weatherInput struct {
+	City string `json:"city" jsonschema:"description=城市名称"`
+	Unit string `json:"unit,omitempty" jsonschema:"description=温度单位,enum=celsius,enum=fahrenheit"`
+	Days int    `json:"days,omitempty" jsonschema:"required"`
+}
+
+type weatherOutput struct {
+	City        string `json:"city"`
+	Temperature int    `json:"temperature"`
+}
+
+// 验证从输入结构体推断的参数 Schema：描述、枚举与必填字段
+func TestInferTool_Info(t *testing.T) {
+	tl, err := InferTool("get_weather", "查询天气", func(ctx context.Context, in *weatherInput) (*weatherOutput, error) {
+		return nil, nil
+	})
+	assert.NoError(t, err)
+
+	info, err := tl.Info(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "get_weather", info.Name)
+	assert.Equal(t, "查询天气", info.Desc)
+
+	js, err := info.ToJSONSchema()
+	assert.NoError(t, err)
+	assert.Equal(t, "object", js.Type)
+	assert.Empty(t, js.Version)
+	assert.ElementsMatch(t, []string{"city", "days"}, js.Required)
+
+	city, ok := js.Properties.Get("city")
+	assert.True(t, ok)
+	assert.Equal(t, "string", city.Type)
+	assert.Equal(t, "城市名称", city.Description)
+
+	unit, ok := js.Properties.Get("unit")
+	assert.True(t, ok)
+	assert.Equal(t, []any{"celsius", "fahrenheit"}, unit.Enum)
+
+	days, ok := js.Properties.Get("days")
+	assert.True(t, ok)
+	assert.Equal(t, "integer", days.Type)
+}
+
+// 验证参数反序列化、输出序列化以及错误包装
+func TestInferTool_InvokableRun(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("结构体输入与结构体输出", func(t *testing.T) {
+		tl, err := InferTool("get_weather", "查询天气", func(ctx context.Context, in *weatherInput) (*weatherOutput, error) {
+			return &weatherOutput{City: in.City, Temperature: 25}, nil
+		})
+		assert.NoError(t, err)
+
+		out, err := tl.InvokableRun(ctx, `{"city":"北京"}`)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"city":"北京","temperature":25}`, out)
+	})
+
+	t.Run("字符串输出原样返回", func(t *testing.T) {
+		tl, err := InferTool("echo", "回显", func(ctx context.Context, in weatherInput) (string, error) {
+			return in.City, nil
+		})
+		assert.NoError(t, err)
+
+		out, err := tl.InvokableRun(ctx, `{"city":"上海"}`)
+		assert.NoError(t, err)
+		assert.Equal(t, "上海", out)
+	})
+
+	t.Run("非法参数", func(t *testing.T) {
+		tl, err := InferTool("echo", "回显", func(ctx context.Context, in weatherInput) (string, error) {
+			return in.City, nil
+		})
+		assert.NoError(t, err)
+
+		_, err = tl.InvokableRun(ctx, `{"city":`)
+		assert.ErrorContains(t, err, "failed to unmarshal arguments in json")
+	})
+
+	t.Run("工具函数返回错误", func(t *testing.T) {
+		bizErr := errors.New("biz error")
+		tl, err := InferTool("echo", "回显", func(ctx context.Context, in weatherInput) (string, error) {
+			return "", bizErr
+		})
+		assert.NoError(t, err)
+
+		_, err = tl.InvokableRun(ctx, `{}`)
+		assert.ErrorIs(t, err, bizErr)
+	})
+
+	t.Run("自定义编解码", func(t *testing.T) {
+		tl, err := InferTool("echo", "回显", func(ctx context.Context, in weatherInput) (*weatherOutput, error) {
+			return &weatherOutput{City: in.City}, nil
+		},
+			WithUnmarshalArguments(func(ctx context.Context, arguments string) (any, error) {
+				return weatherInput{City: arguments}, nil
+			}),
+			WithMarshalOutput(func(ctx context.Context, output any) (string, error) {
+				return fmt.Sprintf("city=%s", output.(*weatherOutput).City), nil
+			}),
+		)
+		assert.NoError(t, err)
+
+		out, err := tl.InvokableRun(ctx, "raw")
+		assert.NoError(t, err)
+		assert.Equal(t, "city=raw", out)
+	})
+
+	t.Run("自定义反序列化返回错误类型", func(t *testing.T) {
+		tl, err := InferTool("echo", "回显", func(ctx context.Context, in weatherInput) (string, error) {
+			return in.City, nil
+		}, WithUnmarshalArguments(func(ctx context.Context, arguments string) (any, error) {
+			return 1, nil
+		}))
+		assert.NoError(t, err)
+
+		_, err = tl.InvokableRun(ctx, "raw")
+		assert.ErrorContains(t, err, "invalid type")
+	})
+}
+
+// 验证工具调用选项透传给工具函数
+func TestInferOptionableTool(t *testing.T) {
+	type toolOpts struct {
+		prefix string
+	}
+	withPrefix := func(p string) tool.Option {
+		return tool.WrapImplSpecificOptFn(func(o *toolOpts) {
+			o.prefix = p
+		})
+	}
+
+	tl, err := InferOptionableTool("echo", "回显", func(ctx context.Context, in weatherInput, opts ...tool.Option) (string, error) {
+		o := tool.GetImplSpecificOptions(&toolOpts{}, opts...)
+		return o.prefix + in.City, nil
+	})
+	assert.NoError(t, err)
+
+	out, err := tl.InvokableRun(context.Background(), `{"city":"杭州"}`, withPrefix("城市："))
+	assert.NoError(t, err)
+	assert.Equal(t, "城市：杭州", out)
+}
+
+// 验证自定义 Schema 修改函数与类型名称推断
+func TestInferTool_SchemaModifierAndType(t *testing.T) {
+	tl, err := InferTool("get_weather_info", "查询天气", func(ctx context.Context, in weatherInput) (string, error) {
+		return "", nil
+	}, WithSchemaModifier(func(jsonTagName string, _ reflect.Type, _ reflect.StructTag, schema *jsonschema.Schema) {
+		if jsonTagName == "_root" {
+			schema.Description = "天气查询参数"
+		}
+	}))
+	assert.NoError(t, err)
+
+	info, err := tl.Info(context.Background())
+	assert.NoError(t, err)
+	js, err := info.ToJSONSchema()
+	assert.NoError(t, err)
+	assert.Equal(t, "天气查询参数", js.Description)
+
+	typ, ok := components.GetType(tl)
+	assert.True(t, ok)
+	assert.Equal(t, "GetWeatherInfo", typ)
+}
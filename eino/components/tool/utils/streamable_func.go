@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/favbox/eino/components/tool"
+	"github.com/favbox/eino/internal/generic"
+	"github.com/favbox/eino/schema"
+)
+
+// StreamFunc 流式工具函数类型。
+type StreamFunc[T, D any] func(ctx context.Context, input T) (output *schema.StreamReader[D], err error)
+
+// OptionableStreamFunc 可接收工具调用选项的流式工具函数类型。
+type OptionableStreamFunc[T, D any] func(ctx context.Context, input T, opts ...tool.Option) (output *schema.StreamReader[D], err error)
+
+// InferStreamTool 根据函数的输入类型推断工具信息，创建 StreamableTool。
+//
+// 参数推断规则与 InferTool 相同；输出流中的每个 D 都会被序列化为字符串块。
+func InferStreamTool[T, D any](toolName, toolDesc string, s StreamFunc[T, D], opts ...Option) (tool.StreamableTool, error) {
+	ti, err := goStruct2ToolInfo[T](toolName, toolDesc, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewStreamTool(ti, s, opts...), nil
+}
+
+// InferOptionableStreamTool 与 InferStreamTool 相同，但工具函数可以接收工具调用选项。
+func InferOptionableStreamTool[T, D any](toolName, toolDesc string, s OptionableStreamFunc[T, D], opts ...Option) (tool.StreamableTool, error) {
+	ti, err := goStruct2ToolInfo[T](toolName, toolDesc, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return newOptionableStreamTool(ti, s, opts...), nil
+}
+
+// NewStreamTool 使用给定的工具信息创建 StreamableTool。
+func NewStreamTool[T, D any](desc *schema.ToolInfo, s StreamFunc[T, D], opts ...Option) tool.StreamableTool {
+	return newOptionableStreamTool(desc, func(ctx context.Context, input T, _ ...tool.Option) (*schema.StreamReader[D], error) {
+		return s(ctx, input)
+	}, opts...)
+}
+
+// newOptionableStreamTool 创建可接收工具调用选项的 StreamableTool。
+func newOptionableStreamTool[T, D any](desc *schema.ToolInfo, s OptionableStreamFunc[T, D], opts ...Option) tool.StreamableTool {
+	to := getToolOptions(opts...)
+
+	return &streamableTool[T, D]{
+		info: desc,
+		um:   to.um,
+		m:    to.m,
+		Fn:   s,
+	}
+}
+
+// streamableTool 基于函数的 StreamableTool 实现。
+type streamableTool[T, D any] struct {
+	info *schema.ToolInfo
+
+	um UnmarshalArguments
+	m  MarshalOutput
+
+	Fn OptionableStreamFunc[T, D]
+}
+
+// Info 返回工具信息。
+func (s *streamableTool[T, D]) Info(_ context.Context) (*schema.ToolInfo, error) {
+	return s.info, nil
+}
+
+// StreamableRun 反序列化参数、调用工具函数，并将输出流逐块序列化为字符串流。
+func (s *streamableTool[T, D]) StreamableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (outStream *schema.StreamReader[string], err error) {
+	var inst T
+	if s.um != nil {
+		var val any
+		val, err = s.um(ctx, argumentsInJSON)
+		if err != nil {
+			return nil, fmt.Errorf("[LocalStreamFunc] failed to unmarshal arguments, toolName=%s, err=%w", s.getToolName(), err)
+		}
+		gt, ok := val.(T)
+		if !ok {
+			return nil, fmt.Errorf("[LocalStreamFunc] invalid type, toolName=%s, expected=%T, given=%T", s.getToolName(), inst, val)
+		}
+		inst = gt
+	} else {
+		inst = generic.NewInstance[T]()
+
+		err = sonic.UnmarshalString(argumentsInJSON, &inst)
+		if err != nil {
+			return nil, fmt.Errorf("[LocalStreamFunc] failed to unmarshal arguments in json, toolName=%s, err=%w", s.getToolName(), err)
+		}
+	}
+
+	streamD, err := s.Fn(ctx, inst, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("[LocalStreamFunc] failed to invoke tool, toolName=%s, err=%w", s.getToolName(), err)
+	}
+
+	outStream = schema.StreamReaderWithConvert(streamD, func(d D) (string, error) {
+		var out string
+		var e error
+		if s.m != nil {
+			out, e = s.m(ctx, d)
+			if e != nil {
+				return "", fmt.Errorf("[LocalStreamFunc] failed to marshal output, toolName=%s, err=%w", s.getToolName(), e)
+			}
+		} else {
+			out, e = marshalString(d)
+			if e != nil {
+				return "", fmt.Errorf("[LocalStreamFunc] failed to marshal output in json, toolName=%s, err=%w", s.getToolName(), e)
+			}
+		}
+
+		return out, nil
+	})
+
+	return outStream, nil
+}
+
+// GetType 返回工具实现的类型名称，由工具名转为驼峰形式得到。
+func (s *streamableTool[T, D]) GetType() string {
+	return snakeToCamel(s.getToolName())
+}
+
+// getToolName 返回工具名称。
+func (s *streamableTool[T, D]) getToolName() string {
+	if s.info == nil {
+		return ""
+	}
+
+	return s.info.Name
+}
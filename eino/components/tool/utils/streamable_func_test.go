@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/favbox/eino/schema"
+)
+
+// 验证流式工具的参数推断与逐块输出序列化
+func TestInferStreamTool(t *testing.T) {
+	ctx := context.Background()
+
+	st, err := InferStreamTool("weather_stream", "流式查询天气", func(ctx context.Context, in *weatherInput) (*schema.StreamReader[*weatherOutput], error) {
+		return schema.StreamReaderFromArray([]*weatherOutput{
+			{City: in.City, Temperature: 20},
+			{City: in.City, Temperature: 21},
+		}), nil
+	})
+	assert.NoError(t, err)
+
+	info, err := st.Info(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "weather_stream", info.Name)
+	js, err := info.ToJSONSchema()
+	assert.NoError(t, err)
+	_, ok := js.Properties.Get("city")
+	assert.True(t, ok)
+
+	sr, err := st.StreamableRun(ctx, `{"city":"北京"}`)
+	assert.NoError(t, err)
+	defer sr.Close()
+
+	var chunks []string
+	for {
+		chunk, err := sr.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		assert.NoError(t, err)
+		chunks = append(chunks, chunk)
+	}
+	assert.Len(t, chunks, 2)
+	assert.JSONEq(t, `{"city":"北京","temperature":20}`, chunks[0])
+	assert.JSONEq(t, `{"city":"北京","temperature":21}`, chunks[1])
+
+	_, err = st.StreamableRun(ctx, `[`)
+	assert.ErrorContains(t, err, "failed to unmarshal arguments in json")
+}
@@ -68,4 +68,5 @@ const (
 	ComponentOfEmbedding   Component = "Embedding"           // 嵌入模型组件
 	ComponentOfRetriever   Component = "Retriever"           // 检索器组件
 	ComponentOfIndexer     Component = "Indexer"             // 索引器组件
+	ComponentOfReranker    Component = "Reranker"            // 重排序组件
 )
@@ -0,0 +1,133 @@
+package memory
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// filter 由 retriever.WithDSLInfo 解析得到的元数据过滤条件，语法见包文档。
+type filter []fieldCondition
+
+type fieldCondition struct {
+	field string
+	op    string
+	value any
+}
+
+var filterOperators = map[string]bool{
+	"$eq": true, "$ne": true, "$gt": true, "$gte": true, "$lt": true, "$lte": true,
+	"$in": true, "$nin": true, "$exists": true,
+}
+
+func parseFilter(dsl map[string]any) (filter, error) {
+	fields := make([]string, 0, len(dsl))
+	for field := range dsl {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	var f filter
+	for _, field := range fields {
+		ops, ok := dsl[field].(map[string]any)
+		if !ok {
+			f = append(f, fieldCondition{field: field, op: "$eq", value: dsl[field]})
+			continue
+		}
+		for op, value := range ops {
+			if !filterOperators[op] {
+				return nil, fmt.Errorf("unsupported filter operator %q on field %q", op, field)
+			}
+			switch op {
+			case "$in", "$nin":
+				if kind := reflect.ValueOf(value).Kind(); kind != reflect.Slice && kind != reflect.Array {
+					return nil, fmt.Errorf("filter operator %q on field %q requires a list, got %T", op, field, value)
+				}
+			case "$exists":
+				if _, ok := value.(bool); !ok {
+					return nil, fmt.Errorf("filter operator %q on field %q requires a bool, got %T", op, field, value)
+				}
+			case "$gt", "$gte", "$lt", "$lte":
+				if _, ok := toFloat(value); !ok {
+					return nil, fmt.Errorf("filter operator %q on field %q requires a number, got %T", op, field, value)
+				}
+			}
+			f = append(f, fieldCondition{field: field, op: op, value: value})
+		}
+	}
+
+	return f, nil
+}
+
+// match 判断元数据是否满足所有条件。
+func (f filter) match(metadata map[string]any) bool {
+	for _, c := range f {
+		if !c.match(metadata) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c fieldCondition) match(metadata map[string]any) bool {
+	actual, exists := metadata[c.field]
+	switch c.op {
+	case "$exists":
+		return exists == c.value.(bool)
+	case "$eq":
+		return exists && valueEqual(actual, c.value)
+	case "$ne":
+		return !exists || !valueEqual(actual, c.value)
+	case "$in", "$nin":
+		in := false
+		if exists {
+			list := reflect.ValueOf(c.value)
+			for i := 0; i < list.Len(); i++ {
+				if valueEqual(actual, list.Index(i).Interface()) {
+					in = true
+					break
+				}
+			}
+		}
+		return in == (c.op == "$in")
+	default:
+		a, ok := toFloat(actual)
+		if !exists || !ok {
+			return false
+		}
+		b, _ := toFloat(c.value)
+		switch c.op {
+		case "$gt":
+			return a > b
+		case "$gte":
+			return a >= b
+		case "$lt":
+			return a < b
+		default:
+			return a <= b
+		}
+	}
+}
+
+// valueEqual 比较两个值，数值按大小比较以兼容 int 与 float64（如快照反序列化后的数值）。
+func valueEqual(a, b any) bool {
+	if fa, ok := toFloat(a); ok {
+		fb, ok := toFloat(b)
+		return ok && fa == fb
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func toFloat(v any) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"fmt"
+	"math"
+)
+
+// Metric 向量打分方式，分数越高越相关。
+type Metric string
+
+const (
+	// MetricCosine 余弦相似度，取值 [-1, 1]。
+	MetricCosine Metric = "cosine"
+	// MetricDotProduct 点积。
+	MetricDotProduct Metric = "dot_product"
+	// MetricL2 基于欧氏距离 d 的分数 1/(1+d)，取值 (0, 1]。
+	MetricL2 Metric = "l2"
+)
+
+// score 计算两个稠密向量的分数。
+func (m Metric) score(a, b []float64) (float64, error) {
+	if len(a) != len(b) {
+		return 0, fmt.Errorf("vector dimension mismatch: %d != %d", len(a), len(b))
+	}
+
+	var dot, normA, normB, dist float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+		dist += (a[i] - b[i]) * (a[i] - b[i])
+	}
+
+	return m.combine(dot, normA, normB, dist)
+}
+
+// sparseScore 计算两个稀疏向量的分数，缺失的维度视为 0。
+func (m Metric) sparseScore(a, b map[int]float64) (float64, error) {
+	var dot, normA, normB, dist float64
+	for i, va := range a {
+		vb := b[i]
+		dot += va * vb
+		normA += va * va
+		dist += (va - vb) * (va - vb)
+	}
+	for i, vb := range b {
+		normB += vb * vb
+		if _, ok := a[i]; !ok {
+			dist += vb * vb
+		}
+	}
+
+	return m.combine(dot, normA, normB, dist)
+}
+
+func (m Metric) combine(dot, normA, normB, dist float64) (float64, error) {
+	switch m {
+	case MetricCosine:
+		if normA == 0 || normB == 0 {
+			return 0, nil
+		}
+		return dot / (math.Sqrt(normA) * math.Sqrt(normB)), nil
+	case MetricDotProduct:
+		return dot, nil
+	case MetricL2:
+		return 1 / (1 + math.Sqrt(dist)), nil
+	default:
+		return 0, fmt.Errorf("unknown metric: %q", string(m))
+	}
+}
@@ -0,0 +1,22 @@
+package memory
+
+import "github.com/favbox/eino/components/retriever"
+
+type retrieveOptions struct {
+	denseVector  []float64
+	sparseVector map[int]float64
+}
+
+// WithQueryDenseVector 直接指定查询的稠密向量，跳过查询向量化。
+func WithQueryDenseVector(vector []float64) retriever.Option {
+	return retriever.WrapImplSpecificOptFn(func(o *retrieveOptions) {
+		o.denseVector = vector
+	})
+}
+
+// WithQuerySparseVector 指定查询的稀疏向量，与存储了稀疏向量的文档计算稀疏分数。
+func WithQuerySparseVector(vector map[int]float64) retriever.Option {
+	return retriever.WrapImplSpecificOptFn(func(o *retrieveOptions) {
+		o.sparseVector = vector
+	})
+}
@@ -0,0 +1,145 @@
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/favbox/eino/internal/fsutil"
+	"github.com/favbox/eino/schema"
+)
+
+const snapshotVersion = 1
+
+// snapshot 快照文件内容。
+type snapshot struct {
+	Version   int              `json:"version"`
+	Documents []*snapshotEntry `json:"documents"`
+}
+
+// snapshotEntry 快照中的一篇文档。向量与子索引只保存在独立字段中，
+// Document 的元数据不再重复保存，加载时通过 WithDenseVector 等方法写回。
+type snapshotEntry struct {
+	Document     *schema.Document `json:"document"`
+	DenseVector  []float64        `json:"dense_vector,omitempty"`
+	SparseVector map[int]float64  `json:"sparse_vector,omitempty"`
+	SubIndexes   []string         `json:"sub_indexes,omitempty"`
+}
+
+// Save 将所有文档写入 Config.SnapshotPath。
+// 先写入临时文件再重命名，写入中途失败不会破坏已有快照；并发调用依次执行，文件总是最后一次调用时的内容。
+// 元数据经 JSON 序列化后类型可能变化，例如数值变为 float64、切片变为 []any。
+func (s *Store) Save() error {
+	if s.snapshotPath == "" {
+		return errors.New("'SnapshotPath' is not configured")
+	}
+
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+
+	s.mu.RLock()
+	snap := &snapshot{Version: snapshotVersion, Documents: make([]*snapshotEntry, 0, len(s.order))}
+	for _, id := range s.order {
+		e := s.entries[id]
+		subIndexes := make([]string, 0, len(e.subIndexes))
+		for sub := range e.subIndexes {
+			subIndexes = append(subIndexes, sub)
+		}
+		sort.Strings(subIndexes)
+		snap.Documents = append(snap.Documents, &snapshotEntry{
+			Document:     snapshotDocument(e.doc),
+			DenseVector:  e.dense,
+			SparseVector: e.sparse,
+			SubIndexes:   subIndexes,
+		})
+	}
+	data, err := sonic.Marshal(snap)
+	s.mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("marshal snapshot failed: %w", err)
+	}
+
+	if err = fsutil.WriteFileAtomic(s.snapshotPath, data); err != nil {
+		return fmt.Errorf("write snapshot file failed: %w", err)
+	}
+
+	return nil
+}
+
+// load 加载快照，文件不存在时视为空存储。
+func (s *Store) load(path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read snapshot file failed: %w", err)
+	}
+
+	snap := &snapshot{}
+	if err = sonic.Unmarshal(data, snap); err != nil {
+		return fmt.Errorf("unmarshal snapshot failed: %w", err)
+	}
+	if snap.Version != snapshotVersion {
+		return fmt.Errorf("unsupported snapshot version: %d", snap.Version)
+	}
+
+	for i, se := range snap.Documents {
+		if se == nil || se.Document == nil || se.Document.ID == "" {
+			return fmt.Errorf("invalid snapshot document at %d", i)
+		}
+		doc := se.Document
+		if se.DenseVector != nil {
+			doc.WithDenseVector(se.DenseVector)
+		}
+		if se.SparseVector != nil {
+			doc.WithSparseVector(se.SparseVector)
+		}
+		if len(se.SubIndexes) > 0 {
+			doc.WithSubIndexes(se.SubIndexes)
+		}
+		e := &entry{
+			doc:        doc,
+			dense:      se.DenseVector,
+			sparse:     se.SparseVector,
+			subIndexes: make(map[string]bool, len(se.SubIndexes)),
+		}
+		for _, sub := range se.SubIndexes {
+			e.subIndexes[sub] = true
+		}
+		if _, ok := s.entries[e.doc.ID]; !ok {
+			s.order = append(s.order, e.doc.ID)
+		}
+		s.entries[e.doc.ID] = e
+	}
+
+	return nil
+}
+
+// reservedMetaDataKeys 向量与子索引在文档元数据中的键，由 schema.Document 的访问方法写入。
+var reservedMetaDataKeys = func() map[string]bool {
+	probe := (&schema.Document{}).WithDenseVector(nil).WithSparseVector(nil).WithSubIndexes(nil)
+	keys := make(map[string]bool, len(probe.MetaData))
+	for k := range probe.MetaData {
+		keys[k] = true
+	}
+	return keys
+}()
+
+// snapshotDocument 返回去掉向量与子索引元数据的文档副本，这些数据由 snapshotEntry 的独立字段保存。
+func snapshotDocument(doc *schema.Document) *schema.Document {
+	cp := &schema.Document{ID: doc.ID, Content: doc.Content}
+	for k, v := range doc.MetaData {
+		if reservedMetaDataKeys[k] {
+			continue
+		}
+		if cp.MetaData == nil {
+			cp.MetaData = make(map[string]any, len(doc.MetaData))
+		}
+		cp.MetaData[k] = v
+	}
+	return cp
+}
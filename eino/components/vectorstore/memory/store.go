@@ -0,0 +1,432 @@
+// Package memory 提供同时实现 indexer.Indexer 与 retriever.Retriever 的内存向量存储。
+//
+// 适用于单元测试、本地演示与小规模数据，无需依赖外部向量数据库：
+//   - 使用 Document.DenseVector / SparseVector，缺少稠密向量时通过 Embedder 向量化文档内容
+//   - 支持余弦相似度、点积与 L2 距离三种打分方式，检索结果通过 Document.WithScore 携带分数
+//   - 支持 indexer.WithEmbedding、indexer.WithSubIndexes、retriever.WithTopK、retriever.WithScoreThreshold、
+//     retriever.WithSubIndex、retriever.WithEmbedding 与 retriever.WithDSLInfo（元数据过滤）
+//   - 可选地将快照持久化到文件
+//
+// retriever.WithDSLInfo 的键为元数据字段名，值为期望值或操作符映射，各条件之间为且关系：
+//
+//	{"lang": "zh"}                        // 等于
+//	{"year": {"$gte": 2020, "$lt": 2025}} // 数值比较：$gt、$gte、$lt、$lte
+//	{"tag": {"$in": ["faq", "guide"]}}    // $in、$nin
+//	{"author": {"$ne": "bot"}}            // $eq、$ne
+//	{"draft": {"$exists": false}}         // 字段是否存在
+//
+// 示例：
+//
+//	store, _ := memory.New(ctx, &memory.Config{Embedding: embedder})
+//	ids, _ := store.Store(ctx, docs, indexer.WithSubIndexes([]string{"faq"}))
+//	docs, _ := store.Retrieve(ctx, "如何退货",
+//		retriever.WithTopK(3),
+//		retriever.WithSubIndex("faq"),
+//		retriever.WithDSLInfo(map[string]any{"lang": "zh", "year": map[string]any{"$gte": 2024}}),
+//	)
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/bytedance/sonic"
+	"github.com/google/uuid"
+
+	"github.com/favbox/eino/callbacks"
+	"github.com/favbox/eino/components"
+	"github.com/favbox/eino/components/embedding"
+	"github.com/favbox/eino/components/indexer"
+	"github.com/favbox/eino/components/retriever"
+	"github.com/favbox/eino/schema"
+)
+
+var (
+	_ indexer.Indexer     = &Store{}
+	_ retriever.Retriever = &Store{}
+)
+
+const defaultTopK = 5
+
+// Config 内存向量存储配置。
+type Config struct {
+	// Embedding 默认的嵌入模型，可被 indexer.WithEmbedding 与 retriever.WithEmbedding 覆盖。
+	// 文档与查询都已提供向量时可以为空。
+	Embedding embedding.Embedder
+
+	// Metric 打分方式，默认 MetricCosine。
+	Metric Metric
+
+	// TopK 默认返回的文档数量，默认 5，可被 retriever.WithTopK 覆盖。
+	TopK int
+
+	// ScoreThreshold 默认的最低分数，可被 retriever.WithScoreThreshold 覆盖。
+	ScoreThreshold *float64
+
+	// SparseWeight 文档与查询同时具有稠密和稀疏向量时稀疏分数的权重，取值 [0, 1]，
+	// 最终分数为 (1-SparseWeight)*稠密分数 + SparseWeight*稀疏分数，默认 0 即只使用稠密分数。
+	SparseWeight float64
+
+	// SnapshotPath 快照文件路径，非空时创建存储会加载已有快照，Save 写入该文件。
+	SnapshotPath string
+
+	// AutoSave 为 true 时每次 Store 与 Delete 后自动写入快照，需要配置 SnapshotPath。
+	AutoSave bool
+}
+
+// Store 内存向量存储，并发安全。
+type Store struct {
+	embedding      embedding.Embedder
+	metric         Metric
+	topK           int
+	scoreThreshold *float64
+	sparseWeight   float64
+	snapshotPath   string
+	autoSave       bool
+
+	// saveMu 串行化快照写入，避免较旧的快照覆盖较新的快照
+	saveMu  sync.Mutex
+	mu      sync.RWMutex
+	entries map[string]*entry
+	order   []string // 文档写入顺序，分数相同时按写入顺序返回
+}
+
+// entry 存储的文档及其向量。
+type entry struct {
+	doc        *schema.Document
+	dense      []float64
+	sparse     map[int]float64
+	subIndexes map[string]bool
+}
+
+// New 创建内存向量存储，配置了 SnapshotPath 且文件存在时加载快照。
+func New(_ context.Context, config *Config) (*Store, error) {
+	if config == nil {
+		config = &Config{}
+	}
+	if config.SparseWeight < 0 || config.SparseWeight > 1 {
+		return nil, fmt.Errorf("'SparseWeight' must be in [0, 1], got %v", config.SparseWeight)
+	}
+	if config.AutoSave && config.SnapshotPath == "" {
+		return nil, errors.New("'SnapshotPath' is required when 'AutoSave' is enabled")
+	}
+
+	s := &Store{
+		embedding:      config.Embedding,
+		metric:         config.Metric,
+		topK:           config.TopK,
+		scoreThreshold: config.ScoreThreshold,
+		sparseWeight:   config.SparseWeight,
+		snapshotPath:   config.SnapshotPath,
+		autoSave:       config.AutoSave,
+		entries:        make(map[string]*entry),
+	}
+	if s.metric == "" {
+		s.metric = MetricCosine
+	}
+	if _, err := s.metric.score(nil, nil); err != nil {
+		return nil, err
+	}
+	if s.topK <= 0 {
+		s.topK = defaultTopK
+	}
+
+	if s.snapshotPath != "" {
+		if err := s.load(s.snapshotPath); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// Store 写入文档，ID 为空时自动生成，ID 已存在时覆盖。
+// 文档缺少稠密向量且配置了嵌入模型时，向量化文档内容；
+// 文档所属的子索引为 indexer.WithSubIndexes 与 Document.SubIndexes 的并集。
+func (s *Store) Store(ctx context.Context, docs []*schema.Document, opts ...indexer.Option) (ids []string, err error) {
+	options := indexer.GetCommonOptions(&indexer.Options{Embedding: s.embedding}, opts...)
+
+	ctx = callbacks.EnsureRunInfo(ctx, s.GetType(), components.ComponentOfIndexer)
+	ctx = callbacks.OnStart(ctx, &indexer.CallbackInput{Docs: docs})
+	defer func() {
+		if err != nil {
+			_ = callbacks.OnError(ctx, err)
+		}
+	}()
+
+	entries, err := s.buildEntries(ctx, docs, options)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	ids = make([]string, 0, len(entries))
+	for _, e := range entries {
+		if _, ok := s.entries[e.doc.ID]; !ok {
+			s.order = append(s.order, e.doc.ID)
+		}
+		s.entries[e.doc.ID] = e
+		ids = append(ids, e.doc.ID)
+	}
+	s.mu.Unlock()
+
+	if s.autoSave {
+		if err = s.Save(); err != nil {
+			return nil, err
+		}
+	}
+
+	_ = callbacks.OnEnd(ctx, &indexer.CallbackOutput{IDs: ids})
+
+	return ids, nil
+}
+
+func (s *Store) buildEntries(ctx context.Context, docs []*schema.Document, options *indexer.Options) ([]*entry, error) {
+	entries := make([]*entry, 0, len(docs))
+	var toEmbed []int
+	for i, doc := range docs {
+		if doc == nil {
+			return nil, fmt.Errorf("document[%d] is nil", i)
+		}
+
+		e := &entry{
+			doc:        copyDocument(doc),
+			dense:      doc.DenseVector(),
+			sparse:     doc.SparseVector(),
+			subIndexes: make(map[string]bool),
+		}
+		if e.doc.ID == "" {
+			e.doc.ID = uuid.NewString()
+		}
+		for _, sub := range options.SubIndexes {
+			e.subIndexes[sub] = true
+		}
+		for _, sub := range doc.SubIndexes() {
+			e.subIndexes[sub] = true
+		}
+		if e.dense == nil {
+			toEmbed = append(toEmbed, i)
+		}
+		entries = append(entries, e)
+	}
+
+	if len(toEmbed) == 0 {
+		return entries, nil
+	}
+	if options.Embedding == nil {
+		for _, i := range toEmbed {
+			if entries[i].sparse == nil {
+				return nil, fmt.Errorf("document[%s] has no vector and no embedding is configured", entries[i].doc.ID)
+			}
+		}
+		return entries, nil
+	}
+
+	texts := make([]string, 0, len(toEmbed))
+	for _, i := range toEmbed {
+		texts = append(texts, entries[i].doc.Content)
+	}
+	vectors, err := options.Embedding.EmbedStrings(ctx, texts)
+	if err != nil {
+		return nil, fmt.Errorf("embed documents failed: %w", err)
+	}
+	if len(vectors) != len(texts) {
+		return nil, fmt.Errorf("embedder returned %d vectors for %d documents", len(vectors), len(texts))
+	}
+	for j, i := range toEmbed {
+		entries[i].dense = vectors[j]
+	}
+
+	return entries, nil
+}
+
+// Retrieve 检索与查询最相关的文档，按分数降序返回。
+// 查询稠密向量优先使用 WithQueryDenseVector 提供的值，否则通过嵌入模型向量化查询文本；
+// 稀疏向量只能通过 WithQuerySparseVector 提供。
+// retriever.WithIndex 对内存存储没有意义，会被忽略。
+func (s *Store) Retrieve(ctx context.Context, query string, opts ...retriever.Option) (docs []*schema.Document, err error) {
+	options := retriever.GetCommonOptions(&retriever.Options{
+		TopK:           &s.topK,
+		ScoreThreshold: s.scoreThreshold,
+		Embedding:      s.embedding,
+	}, opts...)
+	implOptions := retriever.GetImplSpecificOptions(&retrieveOptions{}, opts...)
+
+	ctx = callbacks.EnsureRunInfo(ctx, s.GetType(), components.ComponentOfRetriever)
+	input := &retriever.CallbackInput{
+		Query:          query,
+		TopK:           *options.TopK,
+		ScoreThreshold: options.ScoreThreshold,
+	}
+	if len(options.DSLInfo) > 0 {
+		input.Filter, _ = sonic.MarshalString(options.DSLInfo)
+	}
+	ctx = callbacks.OnStart(ctx, input)
+	defer func() {
+		if err != nil {
+			_ = callbacks.OnError(ctx, err)
+		}
+	}()
+
+	filter, err := parseFilter(options.DSLInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	dense, sparse := implOptions.denseVector, implOptions.sparseVector
+	if dense == nil && sparse == nil && options.Embedding == nil {
+		return nil, errors.New("no embedding is configured and no query vector is provided")
+	}
+	if dense == nil && options.Embedding != nil {
+		vectors, err := options.Embedding.EmbedStrings(ctx, []string{query})
+		if err != nil {
+			return nil, fmt.Errorf("embed query failed: %w", err)
+		}
+		if len(vectors) != 1 {
+			return nil, fmt.Errorf("embedder returned %d vectors for 1 query", len(vectors))
+		}
+		dense = vectors[0]
+	}
+
+	type scored struct {
+		doc   *schema.Document
+		score float64
+	}
+	var results []scored
+
+	s.mu.RLock()
+	for _, id := range s.order {
+		e := s.entries[id]
+		if options.SubIndex != nil && !e.subIndexes[*options.SubIndex] {
+			continue
+		}
+		if !filter.match(e.doc.MetaData) {
+			continue
+		}
+		score, ok, err := s.score(e, dense, sparse)
+		if err != nil {
+			s.mu.RUnlock()
+			return nil, fmt.Errorf("score document[%s] failed: %w", id, err)
+		}
+		if !ok {
+			continue
+		}
+		if options.ScoreThreshold != nil && score < *options.ScoreThreshold {
+			continue
+		}
+		results = append(results, scored{doc: e.doc, score: score})
+	}
+	s.mu.RUnlock()
+
+	sort.SliceStable(results, func(i, j int) bool { return results[i].score > results[j].score })
+	if topK := *options.TopK; topK > 0 && len(results) > topK {
+		results = results[:topK]
+	}
+
+	docs = make([]*schema.Document, 0, len(results))
+	for _, r := range results {
+		docs = append(docs, copyDocument(r.doc).WithScore(r.score))
+	}
+
+	_ = callbacks.OnEnd(ctx, &retriever.CallbackOutput{Docs: docs})
+
+	return docs, nil
+}
+
+// score 计算文档得分，文档与查询没有共同类型的向量时返回 false。
+func (s *Store) score(e *entry, dense []float64, sparse map[int]float64) (float64, bool, error) {
+	hasDense := dense != nil && e.dense != nil
+	hasSparse := sparse != nil && e.sparse != nil
+	switch {
+	case hasDense && hasSparse && s.sparseWeight > 0:
+		denseScore, err := s.metric.score(dense, e.dense)
+		if err != nil {
+			return 0, false, err
+		}
+		sparseScore, err := s.metric.sparseScore(sparse, e.sparse)
+		if err != nil {
+			return 0, false, err
+		}
+		return (1-s.sparseWeight)*denseScore + s.sparseWeight*sparseScore, true, nil
+	case hasDense:
+		score, err := s.metric.score(dense, e.dense)
+		return score, err == nil, err
+	case hasSparse:
+		score, err := s.metric.sparseScore(sparse, e.sparse)
+		return score, err == nil, err
+	default:
+		return 0, false, nil
+	}
+}
+
+// Delete 删除指定 ID 的文档，不存在的 ID 会被忽略。
+func (s *Store) Delete(_ context.Context, ids ...string) error {
+	s.mu.Lock()
+	removed := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if _, ok := s.entries[id]; ok {
+			delete(s.entries, id)
+			removed[id] = true
+		}
+	}
+	if len(removed) > 0 {
+		order := s.order[:0]
+		for _, id := range s.order {
+			if !removed[id] {
+				order = append(order, id)
+			}
+		}
+		s.order = order
+	}
+	s.mu.Unlock()
+
+	if s.autoSave && len(removed) > 0 {
+		return s.Save()
+	}
+	return nil
+}
+
+// Get 按 ID 获取文档副本。
+func (s *Store) Get(id string) (*schema.Document, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, ok := s.entries[id]
+	if !ok {
+		return nil, false
+	}
+	return copyDocument(e.doc), true
+}
+
+// Len 返回存储的文档数量。
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.entries)
+}
+
+// GetType 返回组件类型（“Memory”）。
+func (s *Store) GetType() string {
+	return "Memory"
+}
+
+// IsCallbacksEnabled 返回 true，由存储自行触发回调。
+func (s *Store) IsCallbacksEnabled() bool {
+	return true
+}
+
+// copyDocument 浅拷贝文档及其元数据，避免调用方修改存储中的文档。
+func copyDocument(doc *schema.Document) *schema.Document {
+	cp := &schema.Document{ID: doc.ID, Content: doc.Content}
+	if doc.MetaData != nil {
+		cp.MetaData = make(map[string]any, len(doc.MetaData))
+		for k, v := range doc.MetaData {
+			cp.MetaData[k] = v
+		}
+	}
+	return cp
+}
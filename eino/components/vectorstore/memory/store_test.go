@@ -0,0 +1,242 @@
+package memory
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/favbox/eino/callbacks"
+	"github.com/favbox/eino/components/embedding"
+	"github.com/favbox/eino/components/indexer"
+	"github.com/favbox/eino/components/retriever"
+	"github.com/favbox/eino/compose"
+	"github.com/favbox/eino/schema"
+)
+
+// fakeEmbedder 按预置的向量表向量化文本。
+type fakeEmbedder map[string][]float64
+
+func (f fakeEmbedder) EmbedStrings(_ context.Context, texts []string, _ ...embedding.Option) ([][]float64, error) {
+	out := make([][]float64, 0, len(texts))
+	for _, text := range texts {
+		out = append(out, f[text])
+	}
+	return out, nil
+}
+
+var embedder = fakeEmbedder{
+	"苹果":  {1, 0},
+	"香蕉":  {0.8, 0.6},
+	"汽车":  {0, 1},
+	"水果?": {1, 0.1},
+}
+
+func newStore(t *testing.T, config *Config) *Store {
+	store, err := New(context.Background(), config)
+	assert.NoError(t, err)
+
+	_, err = store.Store(context.Background(), []*schema.Document{
+		{ID: "apple", Content: "苹果", MetaData: map[string]any{"kind": "fruit", "price": 5}},
+		{ID: "banana", Content: "香蕉", MetaData: map[string]any{"kind": "fruit", "price": 3}},
+		{ID: "car", Content: "汽车", MetaData: map[string]any{"kind": "vehicle"}},
+	}, indexer.WithSubIndexes([]string{"all"}))
+	assert.NoError(t, err)
+	return store
+}
+
+func ids(docs []*schema.Document) []string {
+	out := make([]string, 0, len(docs))
+	for _, d := range docs {
+		out = append(out, d.ID)
+	}
+	return out
+}
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("打分方式", func(t *testing.T) {
+		for _, metric := range []Metric{MetricCosine, MetricDotProduct, MetricL2} {
+			store := newStore(t, &Config{Embedding: embedder, Metric: metric})
+			docs, err := store.Retrieve(ctx, "水果?")
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"apple", "banana", "car"}, ids(docs), metric)
+			assert.Greater(t, docs[0].Score(), docs[1].Score())
+		}
+
+		_, err := New(ctx, &Config{Metric: "manhattan"})
+		assert.ErrorContains(t, err, "unknown metric")
+	})
+
+	t.Run("检索选项", func(t *testing.T) {
+		store := newStore(t, &Config{Embedding: embedder})
+		_, err := store.Store(ctx, []*schema.Document{{ID: "pear", Content: "苹果"}}, indexer.WithSubIndexes([]string{"extra"}))
+		assert.NoError(t, err)
+		assert.Equal(t, 4, store.Len())
+
+		docs, err := store.Retrieve(ctx, "水果?", retriever.WithTopK(1))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"apple"}, ids(docs))
+
+		docs, err = store.Retrieve(ctx, "水果?", retriever.WithScoreThreshold(0.5))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"apple", "pear", "banana"}, ids(docs))
+
+		docs, err = store.Retrieve(ctx, "水果?", retriever.WithSubIndex("extra"))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"pear"}, ids(docs))
+
+		docs, err = store.Retrieve(ctx, "水果?", retriever.WithDSLInfo(map[string]any{"kind": "fruit", "price": map[string]any{"$lt": 4}}))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"banana"}, ids(docs))
+
+		docs, err = store.Retrieve(ctx, "水果?", retriever.WithDSLInfo(map[string]any{
+			"kind": map[string]any{"$in": []string{"vehicle", "toy"}},
+		}))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"car"}, ids(docs))
+
+		docs, err = store.Retrieve(ctx, "水果?", retriever.WithDSLInfo(map[string]any{"kind": map[string]any{"$exists": false}}))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"pear"}, ids(docs))
+
+		_, err = store.Retrieve(ctx, "水果?", retriever.WithDSLInfo(map[string]any{"kind": map[string]any{"$regex": "f"}}))
+		assert.ErrorContains(t, err, "unsupported filter operator")
+
+		// 返回副本，修改结果不影响存储
+		docs[0].MetaData["kind"] = "changed"
+		doc, ok := store.Get("car")
+		assert.True(t, ok)
+		assert.Equal(t, "vehicle", doc.MetaData["kind"])
+	})
+
+	t.Run("已有向量与稀疏向量", func(t *testing.T) {
+		store, err := New(ctx, &Config{SparseWeight: 0.5})
+		assert.NoError(t, err)
+
+		_, err = store.Store(ctx, []*schema.Document{{ID: "x", Content: "无向量"}})
+		assert.ErrorContains(t, err, "no embedding is configured")
+
+		_, err = store.Store(ctx, []*schema.Document{
+			(&schema.Document{ID: "dense"}).WithDenseVector([]float64{1, 0}),
+			(&schema.Document{ID: "sparse"}).WithSparseVector(map[int]float64{7: 1}),
+			(&schema.Document{ID: "both"}).WithDenseVector([]float64{0, 1}).WithSparseVector(map[int]float64{7: 1}),
+		})
+		assert.NoError(t, err)
+
+		_, err = store.Retrieve(ctx, "query")
+		assert.ErrorContains(t, err, "no query vector")
+
+		docs, err := store.Retrieve(ctx, "", WithQueryDenseVector([]float64{1, 0}))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"dense", "both"}, ids(docs))
+
+		docs, err = store.Retrieve(ctx, "", WithQuerySparseVector(map[int]float64{7: 2, 9: 1}))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"sparse", "both"}, ids(docs))
+
+		docs, err = store.Retrieve(ctx, "", WithQueryDenseVector([]float64{1, 0}), WithQuerySparseVector(map[int]float64{7: 1}))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"dense", "sparse", "both"}, ids(docs))
+		assert.InDelta(t, 0.5, docs[2].Score(), 1e-9)
+	})
+
+	t.Run("快照持久化", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "store.json")
+		store := newStore(t, &Config{Embedding: embedder, SnapshotPath: path, AutoSave: true})
+		assert.NoError(t, store.Delete(ctx, "car"))
+
+		restored, err := New(ctx, &Config{Embedding: embedder, SnapshotPath: path})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, restored.Len())
+
+		docs, err := restored.Retrieve(ctx, "水果?", retriever.WithSubIndex("all"),
+			retriever.WithDSLInfo(map[string]any{"price": 5}))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"apple"}, ids(docs))
+
+		_, err = New(ctx, &Config{AutoSave: true})
+		assert.ErrorContains(t, err, "'SnapshotPath' is required")
+	})
+
+	t.Run("快照恢复文档向量", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "store.json")
+		store, err := New(ctx, &Config{SnapshotPath: path, AutoSave: true})
+		assert.NoError(t, err)
+		doc := (&schema.Document{ID: "v", Content: "向量"}).
+			WithDenseVector([]float64{0.6, 0.8}).
+			WithSparseVector(map[int]float64{3: 1})
+		_, err = store.Store(ctx, []*schema.Document{doc}, indexer.WithSubIndexes([]string{"all"}))
+		assert.NoError(t, err)
+
+		// 向量只在快照中保存一份
+		data, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.NotContains(t, string(data), "_dense_vector")
+		assert.NotContains(t, string(data), "_sparse_vector")
+
+		restored, err := New(ctx, &Config{SnapshotPath: path})
+		assert.NoError(t, err)
+		got, ok := restored.Get("v")
+		assert.True(t, ok)
+		assert.Equal(t, []float64{0.6, 0.8}, got.DenseVector())
+		assert.Equal(t, map[int]float64{3: 1}, got.SparseVector())
+		assert.Equal(t, []string{"all"}, got.SubIndexes())
+	})
+
+	t.Run("并发自动保存", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "store.json")
+		store, err := New(ctx, &Config{Embedding: embedder, SnapshotPath: path, AutoSave: true})
+		assert.NoError(t, err)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := store.Store(ctx, []*schema.Document{{ID: strconv.Itoa(i), Content: "苹果"}})
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+
+		restored, err := New(ctx, &Config{Embedding: embedder, SnapshotPath: path})
+		assert.NoError(t, err)
+		assert.Equal(t, 20, restored.Len())
+	})
+
+	t.Run("回调与图编排", func(t *testing.T) {
+		store := newStore(t, &Config{Embedding: embedder})
+
+		var input *retriever.CallbackInput
+		var output *retriever.CallbackOutput
+		handler := callbacks.NewHandlerBuilder().
+			OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, in callbacks.CallbackInput) context.Context {
+				input = retriever.ConvCallbackInput(in)
+				return ctx
+			}).
+			OnEndFn(func(ctx context.Context, info *callbacks.RunInfo, out callbacks.CallbackOutput) context.Context {
+				output = retriever.ConvCallbackOutput(out)
+				return ctx
+			}).Build()
+
+		chain := compose.NewChain[string, []*schema.Document]()
+		chain.AppendRetriever(store)
+		r, err := chain.Compile(ctx)
+		assert.NoError(t, err)
+
+		docs, err := r.Invoke(ctx, "水果?", compose.WithCallbacks(handler),
+			compose.WithRetrieverOption(retriever.WithTopK(2), retriever.WithDSLInfo(map[string]any{"kind": "fruit"})))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"apple", "banana"}, ids(docs))
+		assert.Equal(t, "水果?", input.Query)
+		assert.Equal(t, 2, input.TopK)
+		assert.Equal(t, `{"kind":"fruit"}`, input.Filter)
+		assert.Equal(t, docs, output.Docs)
+	})
+}
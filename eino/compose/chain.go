@@ -37,6 +37,7 @@ import (
 	"github.com/favbox/eino/components/indexer"
 	"github.com/favbox/eino/components/model"
 	"github.com/favbox/eino/components/prompt"
+	"github.com/favbox/eino/components/reranker"
 	"github.com/favbox/eino/components/retriever"
 	"github.com/favbox/eino/internal/generic"
 	"github.com/favbox/eino/internal/gmap"
@@ -282,6 +283,21 @@ func (c *Chain[I, O]) AppendRetriever(node retriever.Retriever, opts ...GraphAdd
 	return c
 }
 
+// AppendReranker 向链中添加 Reranker 节点，上一节点的输出需为 *reranker.Request。
+//
+// 示例：
+//
+//	chain.AppendLambda(compose.InvokableLambda(func(ctx context.Context, query string) (*reranker.Request, error) {
+//		docs, err := r.Retrieve(ctx, query)
+//		return &reranker.Request{Query: query, Docs: docs}, err
+//	}))
+//	chain.AppendReranker(rr)
+func (c *Chain[I, O]) AppendReranker(node reranker.Reranker, opts ...GraphAddNodeOpt) *Chain[I, O] {
+	gNode, options := toRerankerNode(node, opts...)
+	c.addNode(gNode, options)
+	return c
+}
+
 // AppendLoader 向链中添加 document.Loader 节点。
 //
 // 示例：
@@ -9,6 +9,7 @@ import (
 	"github.com/favbox/eino/components/indexer"
 	"github.com/favbox/eino/components/model"
 	"github.com/favbox/eino/components/prompt"
+	"github.com/favbox/eino/components/reranker"
 	"github.com/favbox/eino/components/retriever"
 	"github.com/favbox/eino/internal/generic"
 	"github.com/favbox/eino/schema"
@@ -251,6 +252,16 @@ func (cb *ChainBranch) AddRetriever(key string, node retriever.Retriever, opts .
 	return cb.addNode(key, gNode, options)
 }
 
+// AddReranker 向分支添加 Reranker 节点。
+//
+// 示例：
+//
+//	cb.AddReranker("reranker_node_key", rr)
+func (cb *ChainBranch) AddReranker(key string, node reranker.Reranker, opts ...GraphAddNodeOpt) *ChainBranch {
+	gNode, options := toRerankerNode(node, opts...)
+	return cb.addNode(key, gNode, options)
+}
+
 // AddLoader 向分支添加 Loader 节点。
 //
 // 示例：
@@ -26,6 +26,7 @@ import (
 	"github.com/favbox/eino/components/indexer"
 	"github.com/favbox/eino/components/model"
 	"github.com/favbox/eino/components/prompt"
+	"github.com/favbox/eino/components/reranker"
 	"github.com/favbox/eino/components/retriever"
 )
 
@@ -143,6 +144,16 @@ func (p *Parallel) AddRetriever(outputKey string, node retriever.Retriever, opts
 	return p.addNode(outputKey, gNode, options)
 }
 
+// AddReranker 向 Parallel 添加 Reranker 节点。
+//
+// 示例：
+//
+//	p.AddReranker("output_key01", rr)
+func (p *Parallel) AddReranker(outputKey string, node reranker.Reranker, opts ...GraphAddNodeOpt) *Parallel {
+	gNode, options := toRerankerNode(node, append(opts, WithOutputKey(outputKey))...)
+	return p.addNode(outputKey, gNode, options)
+}
+
 // AddLoader 向 Parallel 添加 Loader 节点。
 //
 // 示例：
@@ -1,13 +1,18 @@
 package compose
 
 import (
+	"context"
+	"errors"
+
 	"github.com/favbox/eino/components"
 	"github.com/favbox/eino/components/document"
 	"github.com/favbox/eino/components/embedding"
 	"github.com/favbox/eino/components/indexer"
 	"github.com/favbox/eino/components/model"
 	"github.com/favbox/eino/components/prompt"
+	"github.com/favbox/eino/components/reranker"
 	"github.com/favbox/eino/components/retriever"
+	"github.com/favbox/eino/schema"
 )
 
 /*
@@ -15,7 +20,7 @@ import (
  *
  * 核心组件：
  *   - toComponentNode: 通用组件转换器（Invoke/Stream/Collect/Transform）
- *   - 8种具体组件转换器：Embedder/Retriever/Loader/Indexer/ChatModel/Prompt/Transformer/ToolsNode
+ *   - 9种具体组件转换器：Embedder/Retriever/Reranker/Loader/Indexer/ChatModel/Prompt/Transformer/ToolsNode
  *   - 特殊节点转换器：Lambda/AnyGraph/Passthrough
  *   - toNode: 底层 graphNode 构造器
  *
@@ -32,7 +37,7 @@ import (
  *
  * 使用场景：
  *   - 将模型组件（ChatModel, Embedding）转换为图节点
- *   - 将工具组件（Retriever, Indexer）转换为图节点
+ *   - 将工具组件（Retriever, Reranker, Indexer）转换为图节点
  *   - 将提示词组件（Prompt）转换为图节点
  *   - 将文档处理组件（Loader, Transformer）转换为图节点
  *   - 将自定义 Lambda 和 AnyGraph 转换为图节点
@@ -88,6 +93,23 @@ func toRetrieverNode(node retriever.Retriever, opts ...GraphAddNodeOpt) (*graphN
 		opts...)
 }
 
+// toRerankerNode 重排序器转换器，节点输入为 *reranker.Request
+func toRerankerNode(node reranker.Reranker, opts ...GraphAddNodeOpt) (*graphNode, *graphAddNodeOpts) {
+	return toComponentNode(
+		node,
+		components.ComponentOfReranker,
+		func(ctx context.Context, req *reranker.Request, opts ...reranker.Option) ([]*schema.Document, error) {
+			if req == nil {
+				return nil, errors.New("reranker request is nil")
+			}
+			return node.Rerank(ctx, req.Query, req.Docs, opts...)
+		},
+		nil,
+		nil,
+		nil,
+		opts...)
+}
+
 // toLoaderNode 文档加载器转换器
 func toLoaderNode(node document.Loader, opts ...GraphAddNodeOpt) (*graphNode, *graphAddNodeOpts) {
 	return toComponentNode(
@@ -0,0 +1,89 @@
+package compose
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/favbox/eino/components/reranker"
+	"github.com/favbox/eino/schema"
+)
+
+// keywordReranker 按文档内容包含查询的次数打分。
+type keywordReranker struct{}
+
+func (keywordReranker) Rerank(_ context.Context, query string, docs []*schema.Document, opts ...reranker.Option) ([]*schema.Document, error) {
+	options := reranker.GetCommonOptions(&reranker.Options{}, opts...)
+	out := make([]*schema.Document, 0, len(docs))
+	for _, d := range docs {
+		out = append(out, (&schema.Document{ID: d.ID, Content: d.Content}).WithScore(float64(strings.Count(d.Content, query))))
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Score() > out[j].Score() })
+	if options.TopN != nil && len(out) > *options.TopN {
+		out = out[:*options.TopN]
+	}
+	return out, nil
+}
+
+func TestRerankerNode(t *testing.T) {
+	ctx := context.Background()
+	docs := []*schema.Document{
+		{ID: "1", Content: "eino"},
+		{ID: "2", Content: "eino eino"},
+		{ID: "3", Content: "go"},
+	}
+	ids := func(docs []*schema.Document) []string {
+		out := make([]string, 0, len(docs))
+		for _, d := range docs {
+			out = append(out, d.ID)
+		}
+		return out
+	}
+
+	t.Run("链式编排", func(t *testing.T) {
+		chain := NewChain[*reranker.Request, []*schema.Document]()
+		chain.AppendReranker(keywordReranker{})
+		r, err := chain.Compile(ctx)
+		assert.NoError(t, err)
+
+		out, err := r.Invoke(ctx, &reranker.Request{Query: "eino", Docs: docs}, WithRerankerOption(reranker.WithTopN(2)))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"2", "1"}, ids(out))
+
+		_, err = r.Invoke(ctx, nil)
+		assert.ErrorContains(t, err, "reranker request is nil")
+	})
+
+	t.Run("工作流字段映射", func(t *testing.T) {
+		type input struct {
+			Question string
+			Docs     []*schema.Document
+		}
+		wf := NewWorkflow[input, []*schema.Document]()
+		wf.AddRerankerNode("rerank", keywordReranker{}).
+			AddInput(START, MapFields("Question", "Query"), MapFields("Docs", "Docs"))
+		wf.End().AddInput("rerank")
+		r, err := wf.Compile(ctx)
+		assert.NoError(t, err)
+
+		out, err := r.Invoke(ctx, input{Question: "go", Docs: docs})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"3", "1", "2"}, ids(out))
+	})
+
+	t.Run("图编排", func(t *testing.T) {
+		g := NewGraph[*reranker.Request, []*schema.Document]()
+		assert.NoError(t, g.AddRerankerNode("rerank", keywordReranker{}))
+		assert.NoError(t, g.AddEdge(START, "rerank"))
+		assert.NoError(t, g.AddEdge("rerank", END))
+		r, err := g.Compile(ctx)
+		assert.NoError(t, err)
+
+		out, err := r.Invoke(ctx, &reranker.Request{Query: "eino", Docs: docs}, WithRerankerOption(reranker.WithTopN(1)))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"2"}, ids(out))
+	})
+}
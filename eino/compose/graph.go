@@ -12,6 +12,7 @@ import (
 	"github.com/favbox/eino/components/indexer"
 	"github.com/favbox/eino/components/model"
 	"github.com/favbox/eino/components/prompt"
+	"github.com/favbox/eino/components/reranker"
 	"github.com/favbox/eino/components/retriever"
 	"github.com/favbox/eino/internal/generic"
 	"github.com/favbox/eino/internal/gmap"
@@ -300,6 +301,15 @@ func (g *graph) AddRetrieverNode(key string, node retriever.Retriever, opts ...G
 	return g.addNode(key, gNode, options)
 }
 
+// ========== 组件节点添加方法：重排序器 ==========
+
+// AddRerankerNode 添加重排序器节点到图中，节点输入为 *reranker.Request，输出为 []*schema.Document。
+// 设计意图：将重排序器组件封装为图节点，支持在检索之后对候选文档精排
+func (g *graph) AddRerankerNode(key string, node reranker.Reranker, opts ...GraphAddNodeOpt) error {
+	gNode, options := toRerankerNode(node, opts...)
+	return g.addNode(key, gNode, options)
+}
+
 // ========== 组件节点添加方法：文档加载器 ==========
 
 // AddLoaderNode 添加文档加载器节点到图中。
@@ -687,6 +697,13 @@ func (g *graph) compile(ctx context.Context, opt *graphCompileOptions) (*composa
 		g.handlerPreNode[key] = append(g.handlerPreNode[key], g.getNodeGenericHelper(key).inputFieldMappingConverter)
 	}
 
+	// 严格模式下校验 ChatTemplate 节点的变量能否由前驱提供
+	if opt != nil && opt.strictTemplateVariables {
+		if err := g.checkTemplateVariables(); err != nil {
+			return nil, err
+		}
+	}
+
 	// ========== 步骤5: 节点编译 ==========
 	// 将每个节点编译为可执行的 chanCall
 	key2SubGraphs := g.beforeChildGraphsCompile(opt)
@@ -34,6 +34,7 @@ import (
 	"github.com/favbox/eino/components/indexer"
 	"github.com/favbox/eino/components/model"
 	"github.com/favbox/eino/components/prompt"
+	"github.com/favbox/eino/components/reranker"
 	"github.com/favbox/eino/components/retriever"
 )
 
@@ -167,6 +168,16 @@ func WithRetrieverOption(opts ...retriever.Option) Option {
 	return withComponentOption(opts...)
 }
 
+// WithRerankerOption 创建重排序器组件的选项。
+//
+// 示例：
+//
+//	rerankerOption := compose.WithRerankerOption(reranker.WithTopN(3))
+//	runnable.Invoke(ctx, "input", rerankerOption)
+func WithRerankerOption(opts ...reranker.Option) Option {
+	return withComponentOption(opts...)
+}
+
 // WithLoaderOption 创建文档加载器组件的选项。
 //
 // 示例：
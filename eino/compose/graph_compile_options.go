@@ -38,6 +38,9 @@ type graphCompileOptions struct {
 
 	// 扇入合并配置：管理多输入源的合并策略
 	mergeConfigs map[string]FanInMergeConfig
+
+	// 严格模板变量校验：编译时检查 ChatTemplate 节点所需变量能否由前驱提供
+	strictTemplateVariables bool
 }
 
 // newGraphCompileOptions 创建图编译选项对象 - 应用所有函数式选项
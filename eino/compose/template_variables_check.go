@@ -0,0 +1,191 @@
+package compose
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/favbox/eino/components"
+	"github.com/favbox/eino/components/prompt"
+	"github.com/favbox/eino/schema"
+)
+
+// WithStrictTemplateVariables 开启 ChatTemplate 变量的编译期严格校验。
+// 对实现了 prompt.VariablesExtractor 的 ChatTemplate 节点（如 prompt.DefaultChatTemplate），
+// 编译时根据前驱节点的输出类型、WithOutputKey 与字段映射推断可提供的变量：
+//   - 必需变量无法由任何前驱提供时编译失败
+//   - MessagesPlaceholder 变量的来源类型无法赋值给 []*schema.Message 时编译失败
+//
+// 前驱输出为 map[string]any、any 等无法静态确定键的类型时视为可以提供任意变量；
+// 配置了 WithInputKey 或 StatePreHandler 的节点输入可能在运行时改写，不做校验。
+// 仅对当前图生效，子图需要在各自的编译选项中开启。
+func WithStrictTemplateVariables() GraphCompileOption {
+	return func(o *graphCompileOptions) {
+		o.strictTemplateVariables = true
+	}
+}
+
+// variableSource 可向 ChatTemplate 节点提供变量的来源。
+type variableSource struct {
+	// key 非空时只提供该变量，valueType 为其值类型；为空时 valueType 为整个输入的类型。
+	key       string
+	valueType reflect.Type
+}
+
+var messagesType = reflect.TypeOf([]*schema.Message{})
+
+// provides 判断来源能否提供指定变量。
+func (s *variableSource) provides(v schema.TemplateVariable) bool {
+	if s.key != "" {
+		return s.key == v.Name && valueCompatible(s.valueType, v)
+	}
+
+	typ := s.valueType
+	if typ == nil || typ.Kind() == reflect.Interface {
+		return true
+	}
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() == reflect.Map && strType.ConvertibleTo(typ.Key()) {
+		return valueCompatible(typ.Elem(), v)
+	}
+
+	return false
+}
+
+// valueCompatible 判断变量值类型是否可用于格式化该变量，类型未知时视为兼容。
+func valueCompatible(typ reflect.Type, v schema.TemplateVariable) bool {
+	if !v.IsMessages || typ == nil || typ.Kind() == reflect.Interface {
+		return true
+	}
+	return typ.AssignableTo(messagesType)
+}
+
+// checkTemplateVariables 校验图中所有 ChatTemplate 节点的变量是否都能由前驱提供。
+func (g *graph) checkTemplateVariables() error {
+	keys := make([]string, 0, len(g.nodes))
+	for key := range g.nodes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	predecessors := g.dataPredecessors()
+	for _, key := range keys {
+		node := g.nodes[key]
+		if node.executorMeta == nil || node.executorMeta.component != components.ComponentOfPrompt {
+			continue
+		}
+		if len(node.nodeInfo.inputKey) > 0 || node.nodeInfo.preProcessor != nil {
+			continue
+		}
+		extractor, ok := node.instance.(prompt.VariablesExtractor)
+		if !ok {
+			continue
+		}
+
+		vars, err := extractor.Variables()
+		if err != nil {
+			return fmt.Errorf("strict template variables check failed for node[%s]: %w", key, err)
+		}
+
+		sources := g.templateVariableSources(key, predecessors[key])
+		var missing []string
+		for _, v := range vars {
+			if v.Optional {
+				continue
+			}
+			provided := false
+			for i := range sources {
+				if sources[i].provides(v) {
+					provided = true
+					break
+				}
+			}
+			if !provided {
+				missing = append(missing, v.Name)
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("strict template variables check failed for node[%s]: variables [%s] cannot be supplied by predecessors",
+				key, strings.Join(missing, ", "))
+		}
+	}
+
+	return nil
+}
+
+// templateVariableSources 根据字段映射、前驱输出键与输出类型收集节点的变量来源。
+func (g *graph) templateVariableSources(key string, preds []string) []variableSource {
+	var sources []variableSource
+
+	mapped := make(map[string]bool)
+	for _, m := range g.fieldMappingRecords[key] {
+		mapped[m.fromNodeKey] = true
+
+		var fromType reflect.Type
+		if base := g.nodeOutputTypeOrNil(m.fromNodeKey); base != nil && m.customExtractor == nil {
+			fromType, _, _ = checkAndExtractFieldType(m.FromPath(), base)
+		}
+		if to := m.ToPath(); len(to) > 0 {
+			if len(to) > 1 {
+				// 映射到变量的内部字段，变量值为运行时构造的容器
+				fromType = nil
+			}
+			sources = append(sources, variableSource{key: to[0], valueType: fromType})
+			continue
+		}
+		sources = append(sources, variableSource{valueType: fromType})
+	}
+
+	for _, pred := range preds {
+		if mapped[pred] {
+			continue
+		}
+		if n, ok := g.nodes[pred]; ok && n.nodeInfo != nil && len(n.nodeInfo.outputKey) > 0 {
+			var valueType reflect.Type
+			if n.cr != nil {
+				valueType = n.cr.outputType
+			}
+			sources = append(sources, variableSource{key: n.nodeInfo.outputKey, valueType: valueType})
+			continue
+		}
+		sources = append(sources, variableSource{valueType: g.nodeOutputTypeOrNil(pred)})
+	}
+
+	return sources
+}
+
+// nodeOutputTypeOrNil 返回节点输出类型，节点不存在时返回 nil。
+func (g *graph) nodeOutputTypeOrNil(name string) reflect.Type {
+	if name == START || name == END {
+		return g.getNodeOutputType(name)
+	}
+	if _, ok := g.nodes[name]; !ok {
+		return nil
+	}
+	return g.getNodeOutputType(name)
+}
+
+// dataPredecessors 汇总数据边与分支形成的数据前驱。
+func (g *graph) dataPredecessors() map[string][]string {
+	preds := make(map[string][]string)
+	for start, ends := range g.dataEdges {
+		for _, end := range ends {
+			preds[end] = append(preds[end], start)
+		}
+	}
+	for start, branches := range g.branches {
+		for _, branch := range branches {
+			if branch.noDataFlow {
+				continue
+			}
+			for end := range branch.endNodes {
+				preds[end] = append(preds[end], start)
+			}
+		}
+	}
+
+	return preds
+}
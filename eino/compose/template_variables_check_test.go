@@ -0,0 +1,108 @@
+package compose
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/favbox/eino/components/prompt"
+	"github.com/favbox/eino/schema"
+)
+
+type templateInput struct {
+	Query   string
+	Role    string
+	History []*schema.Message
+	Count   int
+}
+
+func TestStrictTemplateVariables(t *testing.T) {
+	ctx := context.Background()
+
+	newTemplate := func() *prompt.DefaultChatTemplate {
+		return prompt.FromMessages(schema.FString,
+			schema.SystemMessage("你是{role}"),
+			schema.MessagesPlaceholder("history", false),
+			schema.MessagesPlaceholder("examples", true),
+			schema.UserMessage("{query}"),
+		)
+	}
+
+	t.Run("输入为 map[string]any 时无法静态判断", func(t *testing.T) {
+		chain := NewChain[map[string]any, []*schema.Message]()
+		chain.AppendChatTemplate(newTemplate())
+		_, err := chain.Compile(ctx, WithStrictTemplateVariables())
+		assert.NoError(t, err)
+	})
+
+	t.Run("值类型无法提供消息占位符", func(t *testing.T) {
+		g := NewGraph[string, []*schema.Message]()
+		assert.NoError(t, g.AddLambdaNode("history", InvokableLambda(func(_ context.Context, in string) (string, error) {
+			return in, nil
+		}), WithOutputKey("history")))
+		assert.NoError(t, g.AddChatTemplateNode("tpl", prompt.FromMessages(schema.FString,
+			schema.MessagesPlaceholder("history", false),
+		)))
+		assert.NoError(t, g.AddEdge(START, "history"))
+		assert.NoError(t, g.AddEdge("history", "tpl"))
+		assert.NoError(t, g.AddEdge("tpl", END))
+
+		_, err := g.Compile(ctx)
+		assert.NoError(t, err)
+
+		_, err = g.Compile(ctx, WithStrictTemplateVariables())
+		assert.ErrorContains(t, err, "variables [history] cannot be supplied")
+	})
+
+	t.Run("工作流字段映射", func(t *testing.T) {
+		build := func(mappings ...*FieldMapping) *Workflow[templateInput, []*schema.Message] {
+			wf := NewWorkflow[templateInput, []*schema.Message]()
+			wf.AddChatTemplateNode("tpl", newTemplate()).AddInput(START, mappings...)
+			wf.End().AddInput("tpl")
+			return wf
+		}
+
+		_, err := build(MapFields("Query", "query"), MapFields("History", "history")).
+			Compile(ctx, WithStrictTemplateVariables())
+		assert.ErrorContains(t, err, "variables [role] cannot be supplied")
+
+		_, err = build(MapFields("Query", "query"), MapFields("Count", "history"), MapFields("Role", "role")).
+			Compile(ctx, WithStrictTemplateVariables())
+		assert.ErrorContains(t, err, "variables [history] cannot be supplied")
+
+		_, err = build(MapFields("Query", "query"), MapFields("History", "history"), MapFields("Role", "role")).
+			Compile(ctx, WithStrictTemplateVariables())
+		assert.NoError(t, err)
+
+		// 静态值同样可以提供变量
+		wf := NewWorkflow[templateInput, []*schema.Message]()
+		wf.AddChatTemplateNode("tpl", newTemplate()).
+			AddInput(START, MapFields("Query", "query"), MapFields("History", "history")).
+			SetStaticValue(FieldPath{"role"}, "助手")
+		wf.End().AddInput("tpl")
+		_, err = wf.Compile(ctx, WithStrictTemplateVariables())
+		assert.NoError(t, err)
+	})
+
+	t.Run("前驱节点输出键", func(t *testing.T) {
+		build := func(tpl prompt.ChatTemplate) *Graph[string, []*schema.Message] {
+			g := NewGraph[string, []*schema.Message]()
+			assert.NoError(t, g.AddLambdaNode("query", InvokableLambda(func(_ context.Context, in string) (string, error) {
+				return in, nil
+			}), WithOutputKey("query")))
+			assert.NoError(t, g.AddChatTemplateNode("tpl", tpl))
+			assert.NoError(t, g.AddEdge(START, "query"))
+			assert.NoError(t, g.AddEdge("query", "tpl"))
+			assert.NoError(t, g.AddEdge("tpl", END))
+			return g
+		}
+
+		_, err := build(prompt.FromMessages(schema.FString, schema.UserMessage("{query}"))).
+			Compile(ctx, WithStrictTemplateVariables())
+		assert.NoError(t, err)
+
+		_, err = build(newTemplate()).Compile(ctx, WithStrictTemplateVariables())
+		assert.ErrorContains(t, err, "variables [history, role] cannot be supplied")
+	})
+}
@@ -0,0 +1,368 @@
+package compose
+
+/*
+ * tool_arguments_validation.go - 工具调用参数校验，在执行前按工具声明的参数 Schema 检查模型生成的参数
+ *
+ * 核心组件：
+ *   - ToolArgumentsValidationConfig: 参数校验配置，可选修复常见的 LLM JSON 缺陷
+ *   - ToolArgumentsValidationError: 结构化的校验失败信息
+ *
+ * 设计特点：
+ *   - 校验失败时不返回错误，而是将结构化的失败说明作为工具消息返回给模型，便于 ReAct 循环自我纠正
+ *   - 仅覆盖类型、必填字段、枚举及 allOf/anyOf/oneOf 组合，不实现完整的 JSON Schema 规范
+ */
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+
+	"github.com/bytedance/sonic"
+	"github.com/eino-contrib/jsonschema"
+
+	"github.com/favbox/eino/components"
+	"github.com/favbox/eino/schema"
+)
+
+// ToolArgumentsValidationConfig 工具参数校验配置。
+// 设置到 ToolsNodeConfig.ArgumentsValidation 后，ToolsNode 会在执行工具前
+// 按 ToolInfo.ParamsOneOf 声明的 Schema 校验参数，未声明参数的工具不做校验。
+type ToolArgumentsValidationConfig struct {
+	// RepairJSON 为 true 时，参数不是合法 JSON 会先尝试修复常见缺陷再校验：
+	// markdown 代码块包裹、尾随逗号、单引号字符串以及空参数。
+	// 修复成功的参数会替代原始参数传递给工具。
+	RepairJSON bool
+
+	// InvalidArgumentsHandler 校验失败时生成返回给模型的工具消息内容。
+	// 此字段可选，未设置时返回 ToolArgumentsValidationError.Message 生成的 JSON 描述。
+	// 返回错误时 ToolsNode 将以该错误结束执行。
+	InvalidArgumentsHandler func(ctx context.Context, err *ToolArgumentsValidationError) (string, error)
+}
+
+// ToolArgumentsValidationError 工具参数校验失败的详细信息。
+type ToolArgumentsValidationError struct {
+	ToolName   string   // 工具名称
+	Arguments  string   // 校验的参数（启用修复时为修复后的参数）
+	Violations []string // 违反 Schema 的具体描述
+}
+
+// Error 实现 error 接口。
+func (e *ToolArgumentsValidationError) Error() string {
+	return fmt.Sprintf("invalid arguments for tool[%s]: %s", e.ToolName, strings.Join(e.Violations, "; "))
+}
+
+// Message 生成返回给模型的结构化失败说明（JSON 格式）。
+func (e *ToolArgumentsValidationError) Message() string {
+	msg, err := sonic.MarshalString(map[string]any{
+		"error":      "invalid_arguments",
+		"tool":       e.ToolName,
+		"violations": e.Violations,
+		"hint":       "fix the arguments according to the tool's parameters schema and call the tool again",
+	})
+	if err != nil {
+		return e.Error()
+	}
+	return msg
+}
+
+// validateToolArguments 按工具的参数 Schema 校验参数，返回（可能修复过的）参数。
+func validateToolArguments(conf *ToolArgumentsValidationConfig, info *schema.ToolInfo, arguments string) (string, *ToolArgumentsValidationError) {
+	if info == nil || info.ParamsOneOf == nil {
+		return arguments, nil
+	}
+
+	sc, err := info.ToJSONSchema()
+	if err != nil || sc == nil {
+		return arguments, nil
+	}
+
+	var value any
+	if err = sonic.UnmarshalString(arguments, &value); err != nil {
+		repaired, ok := "", false
+		if conf.RepairJSON {
+			repaired, ok = repairToolArguments(arguments)
+		}
+		if !ok {
+			return arguments, &ToolArgumentsValidationError{
+				ToolName:   info.Name,
+				Arguments:  arguments,
+				Violations: []string{fmt.Sprintf("arguments are not valid JSON: %v", err)},
+			}
+		}
+		arguments = repaired
+		_ = sonic.UnmarshalString(arguments, &value)
+	}
+
+	var violations []string
+	validateJSONValue("$", value, sc, &violations)
+	if len(violations) > 0 {
+		return arguments, &ToolArgumentsValidationError{
+			ToolName:   info.Name,
+			Arguments:  arguments,
+			Violations: violations,
+		}
+	}
+
+	return arguments, nil
+}
+
+// newInvalidArgumentsTask 创建参数校验失败的工具调用任务，其输出为返回给模型的失败说明。
+func newInvalidArgumentsTask(callID string, verr *ToolArgumentsValidationError,
+	handler func(ctx context.Context, err *ToolArgumentsValidationError) (string, error)) toolCallTask {
+
+	endpoint := func(ctx context.Context, _ *ToolInput) (*ToolOutput, error) {
+		if handler == nil {
+			return &ToolOutput{Result: verr.Message()}, nil
+		}
+		result, err := handler(ctx, verr)
+		if err != nil {
+			return nil, err
+		}
+		return &ToolOutput{Result: result}, nil
+	}
+	return toolCallTask{
+		endpoint:       endpoint,
+		streamEndpoint: invokableToStreamable(endpoint),
+		meta: &executorMeta{
+			component:                  components.ComponentOfTool,
+			isComponentCallbackEnabled: false,
+			componentImplType:          "InvalidArguments",
+		},
+		name:   verr.ToolName,
+		arg:    verr.Arguments,
+		callID: callID,
+	}
+}
+
+// validateJSONValue 递归校验 JSON 值，违反项追加到 violations。
+func validateJSONValue(path string, value any, sc *jsonschema.Schema, violations *[]string) {
+	if sc == nil {
+		return
+	}
+
+	for _, sub := range sc.AllOf {
+		validateJSONValue(path, value, sub, violations)
+	}
+	if len(sc.AnyOf) > 0 && !matchAny(path, value, sc.AnyOf) {
+		*violations = append(*violations, fmt.Sprintf("%s: value does not match any of the allowed schemas", path))
+	}
+	if len(sc.OneOf) > 0 && !matchAny(path, value, sc.OneOf) {
+		*violations = append(*violations, fmt.Sprintf("%s: value does not match any of the allowed schemas", path))
+	}
+
+	types := sc.TypeEnhanced
+	if sc.Type != "" {
+		types = []string{sc.Type}
+	}
+	if len(types) > 0 && !matchTypes(value, types) {
+		*violations = append(*violations, fmt.Sprintf("%s: expected %s, got %s", path, strings.Join(types, " or "), jsonTypeOf(value)))
+		return
+	}
+
+	if len(sc.Enum) > 0 && !inEnum(value, sc.Enum) {
+		*violations = append(*violations, fmt.Sprintf("%s: value %v is not one of %v", path, value, sc.Enum))
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		for _, name := range sc.Required {
+			if _, ok := v[name]; !ok {
+				*violations = append(*violations, fmt.Sprintf("%s: missing required field %q", path, name))
+			}
+		}
+		if sc.Properties != nil {
+			for pair := sc.Properties.Oldest(); pair != nil; pair = pair.Next() {
+				if fv, ok := v[pair.Key]; ok {
+					validateJSONValue(path+"."+pair.Key, fv, pair.Value, violations)
+				}
+			}
+		}
+	case []any:
+		for i, item := range v {
+			validateJSONValue(fmt.Sprintf("%s[%d]", path, i), item, sc.Items, violations)
+		}
+	}
+}
+
+// matchAny 判断值是否满足任一子 Schema。
+func matchAny(path string, value any, schemas []*jsonschema.Schema) bool {
+	for _, sub := range schemas {
+		var vs []string
+		validateJSONValue(path, value, sub, &vs)
+		if len(vs) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// matchTypes 判断值是否满足任一 JSON Schema 类型。
+func matchTypes(value any, types []string) bool {
+	for _, t := range types {
+		switch t {
+		case string(schema.Object):
+			if _, ok := value.(map[string]any); ok {
+				return true
+			}
+		case string(schema.Array):
+			if _, ok := value.([]any); ok {
+				return true
+			}
+		case string(schema.String):
+			if _, ok := value.(string); ok {
+				return true
+			}
+		case string(schema.Number):
+			if _, ok := value.(float64); ok {
+				return true
+			}
+		case string(schema.Integer):
+			if f, ok := value.(float64); ok && f == math.Trunc(f) {
+				return true
+			}
+		case string(schema.Boolean):
+			if _, ok := value.(bool); ok {
+				return true
+			}
+		case string(schema.Null):
+			if value == nil {
+				return true
+			}
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// jsonTypeOf 返回值对应的 JSON 类型名称。
+func jsonTypeOf(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return string(schema.Null)
+	case map[string]any:
+		return string(schema.Object)
+	case []any:
+		return string(schema.Array)
+	case string:
+		return string(schema.String)
+	case bool:
+		return string(schema.Boolean)
+	case float64:
+		if v == math.Trunc(v) {
+			return string(schema.Integer)
+		}
+		return string(schema.Number)
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// inEnum 判断值是否在枚举列表中，数值统一按 float64 比较。
+func inEnum(value any, enum []any) bool {
+	for _, e := range enum {
+		if reflect.DeepEqual(normalizeJSONNumber(e), value) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeJSONNumber 将各类数值统一转换为 float64，与 sonic 解析出的数值类型一致。
+func normalizeJSONNumber(v any) any {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	default:
+		return v
+	}
+}
+
+// repairToolArguments 修复 LLM 生成参数中的常见 JSON 缺陷，修复后仍非法时返回 false。
+//
+// 修复内容：
+//   - 空参数视为空对象
+//   - 去除 markdown 代码块包裹
+//   - 单引号字符串转为双引号字符串
+//   - 删除对象和数组结尾的多余逗号
+func repairToolArguments(arguments string) (string, bool) {
+	s := strings.TrimSpace(arguments)
+	if s == "" {
+		return "{}", true
+	}
+
+	if strings.HasPrefix(s, "```") {
+		s = strings.TrimPrefix(s, "```")
+		if idx := strings.IndexByte(s, '\n'); idx >= 0 {
+			s = s[idx+1:]
+		} else {
+			s = ""
+		}
+		s = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), "```"))
+	}
+
+	var (
+		sb      strings.Builder
+		quote   rune
+		escaped bool
+	)
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		if quote != 0 {
+			switch {
+			case escaped:
+				escaped = false
+				if quote == '\'' && c == '\'' {
+					// \' 在双引号字符串中无需转义
+					str := sb.String()
+					sb.Reset()
+					sb.WriteString(str[:len(str)-1])
+				}
+				sb.WriteRune(c)
+			case c == '\\':
+				escaped = true
+				sb.WriteRune(c)
+			case c == quote:
+				quote = 0
+				sb.WriteRune('"')
+			case c == '"' && quote == '\'':
+				sb.WriteString(`\"`)
+			default:
+				sb.WriteRune(c)
+			}
+			continue
+		}
+
+		switch c {
+		case '"', '\'':
+			quote = c
+			sb.WriteRune('"')
+		case ',':
+			j := i + 1
+			for j < len(runes) && strings.ContainsRune(" \t\r\n", runes[j]) {
+				j++
+			}
+			if j < len(runes) && (runes[j] == '}' || runes[j] == ']') {
+				continue
+			}
+			sb.WriteRune(c)
+		default:
+			sb.WriteRune(c)
+		}
+	}
+
+	repaired := sb.String()
+	var value any
+	if err := sonic.UnmarshalString(repaired, &value); err != nil {
+		return arguments, false
+	}
+	return repaired, true
+}
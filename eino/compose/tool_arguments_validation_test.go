@@ -0,0 +1,143 @@
+package compose
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bytedance/sonic"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/favbox/eino/components/tool"
+	"github.com/favbox/eino/schema"
+)
+
+type weatherTool struct {
+	calls []string
+}
+
+func (w *weatherTool) Info(_ context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "get_weather",
+		Desc: "查询天气",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"city": {Type: schema.String, Required: true},
+			"unit": {Type: schema.String, Enum: []string{"celsius", "fahrenheit"}},
+			"days": {Type: schema.Integer},
+			"tags": {Type: schema.Array, ElemInfo: &schema.ParameterInfo{Type: schema.String}},
+		}),
+	}, nil
+}
+
+func (w *weatherTool) InvokableRun(_ context.Context, argumentsInJSON string, _ ...tool.Option) (string, error) {
+	w.calls = append(w.calls, argumentsInJSON)
+	return "晴", nil
+}
+
+// 验证参数校验失败时返回结构化工具消息而不执行工具
+func TestToolsNodeArgumentsValidation(t *testing.T) {
+	ctx := context.Background()
+
+	newInput := func(args ...string) *schema.Message {
+		var tcs []schema.ToolCall
+		for i, arg := range args {
+			tcs = append(tcs, schema.ToolCall{
+				ID:       "call_" + string(rune('a'+i)),
+				Function: schema.FunctionCall{Name: "get_weather", Arguments: arg},
+			})
+		}
+		return schema.AssistantMessage("", tcs)
+	}
+
+	t.Run("合法参数正常执行", func(t *testing.T) {
+		wt := &weatherTool{}
+		tn, err := NewToolNode(ctx, &ToolsNodeConfig{
+			Tools:               []tool.BaseTool{wt},
+			ArgumentsValidation: &ToolArgumentsValidationConfig{},
+		})
+		assert.NoError(t, err)
+
+		out, err := tn.Invoke(ctx, newInput(`{"city":"北京","unit":"celsius","days":3,"tags":["a"]}`))
+		assert.NoError(t, err)
+		assert.Equal(t, "晴", out[0].Content)
+		assert.Len(t, wt.calls, 1)
+	})
+
+	t.Run("非法参数返回校验失败消息", func(t *testing.T) {
+		wt := &weatherTool{}
+		tn, err := NewToolNode(ctx, &ToolsNodeConfig{
+			Tools:               []tool.BaseTool{wt},
+			ArgumentsValidation: &ToolArgumentsValidationConfig{},
+		})
+		assert.NoError(t, err)
+
+		out, err := tn.Invoke(ctx, newInput(
+			`{"unit":"kelvin","days":1.5,"tags":[1]}`,
+			`{"city":"上海"}`,
+		))
+		assert.NoError(t, err)
+		assert.Len(t, out, 2)
+		assert.Equal(t, "call_a", out[0].ToolCallID)
+		assert.Equal(t, "get_weather", out[0].ToolName)
+
+		var failure struct {
+			Error      string   `json:"error"`
+			Tool       string   `json:"tool"`
+			Violations []string `json:"violations"`
+		}
+		assert.NoError(t, sonic.UnmarshalString(out[0].Content, &failure))
+		assert.Equal(t, "invalid_arguments", failure.Error)
+		assert.Equal(t, "get_weather", failure.Tool)
+		assert.ElementsMatch(t, []string{
+			`$: missing required field "city"`,
+			`$.unit: value kelvin is not one of [celsius fahrenheit]`,
+			`$.days: expected integer, got number`,
+			`$.tags[0]: expected string, got integer`,
+		}, failure.Violations)
+
+		assert.Equal(t, "晴", out[1].Content)
+		assert.Equal(t, []string{`{"city":"上海"}`}, wt.calls)
+	})
+
+	t.Run("修复常见 JSON 缺陷", func(t *testing.T) {
+		wt := &weatherTool{}
+		tn, err := NewToolNode(ctx, &ToolsNodeConfig{
+			Tools:               []tool.BaseTool{wt},
+			ArgumentsValidation: &ToolArgumentsValidationConfig{RepairJSON: true},
+		})
+		assert.NoError(t, err)
+
+		sr, err := tn.Stream(ctx, newInput("```json\n{'city': 'it\\'s \"x\"', 'tags': ['a',],}\n```"))
+		assert.NoError(t, err)
+		msgs, err := concatStreamReader(sr)
+		assert.NoError(t, err)
+		assert.Equal(t, "晴", msgs[0].Content)
+		assert.Len(t, wt.calls, 1)
+		assert.JSONEq(t, `{"city":"it's \"x\"","tags":["a"]}`, wt.calls[0])
+	})
+
+	t.Run("自定义失败处理", func(t *testing.T) {
+		wt := &weatherTool{}
+		handlerErr := errors.New("abort")
+		tn, err := NewToolNode(ctx, &ToolsNodeConfig{
+			Tools: []tool.BaseTool{wt},
+			ArgumentsValidation: &ToolArgumentsValidationConfig{
+				InvalidArgumentsHandler: func(ctx context.Context, err *ToolArgumentsValidationError) (string, error) {
+					if err.Arguments == "not json" {
+						return "", handlerErr
+					}
+					return "bad arguments: " + err.Error(), nil
+				},
+			},
+		})
+		assert.NoError(t, err)
+
+		out, err := tn.Invoke(ctx, newInput(`{}`))
+		assert.NoError(t, err)
+		assert.Equal(t, `bad arguments: invalid arguments for tool[get_weather]: $: missing required field "city"`, out[0].Content)
+
+		_, err = tn.Invoke(ctx, newInput("not json"))
+		assert.ErrorIs(t, err, handlerErr)
+		assert.Empty(t, wt.calls)
+	})
+}
@@ -6,7 +6,7 @@ package compose
  * 核心组件：
  *   - ToolsNode: 工具执行节点，支持并行/顺序执行、流式/非流式调用
  *   - ToolMiddleware: 工具调用中间件，用于拦截和增强工具执行
- *   - ToolsNodeConfig: 工具节点配置，支持未知工具处理、参数预处理
+ *   - ToolsNodeConfig: 工具节点配置，支持未知工具处理、参数预处理、参数校验
  *
  * 设计特点：
  *   - 自动适配 InvokableTool 和 StreamableTool 两种工具类型
@@ -75,6 +75,7 @@ type ToolsNode struct {
 	unknownToolHandler        func(ctx context.Context, name, input string) (string, error)
 	executeSequentially       bool
 	toolArgumentsHandler      func(ctx context.Context, name, input string) (string, error)
+	argumentsValidation       *ToolArgumentsValidationConfig
 	toolCallMiddlewares       []InvokableToolMiddleware
 	streamToolCallMiddlewares []StreamableToolMiddleware
 }
@@ -146,6 +147,12 @@ type ToolsNodeConfig struct {
 	// 返回用于工具执行的处理后参数字符串和预处理过程中的错误
 	ToolArgumentsHandler func(ctx context.Context, name, arguments string) (string, error)
 
+	// ArgumentsValidation 配置执行前的工具参数校验，在 ToolArgumentsHandler 之后进行。
+	// 此字段可选，未设置时不校验参数。
+	// 设置后，参数不满足工具声明的 Schema（类型、必填字段、枚举）时不会执行工具，
+	// 而是将结构化的失败说明作为该工具调用的 ToolMessage 返回，使模型可以修正参数后重试
+	ArgumentsValidation *ToolArgumentsValidationConfig
+
 	// ToolCallMiddlewares 配置工具调用的中间件。
 	// 每个元素可包含 Invokable 和/或 Streamable 中间件。
 	// Invokable 中间件仅适用于实现 InvokableTool 接口的工具，
@@ -183,6 +190,7 @@ func NewToolNode(ctx context.Context, conf *ToolsNodeConfig) (*ToolsNode, error)
 		unknownToolHandler:        conf.UnknownToolsHandler,
 		executeSequentially:       conf.ExecuteSequentially,
 		toolArgumentsHandler:      conf.ToolArgumentsHandler,
+		argumentsValidation:       conf.ArgumentsValidation,
 		toolCallMiddlewares:       middlewares,
 		streamToolCallMiddlewares: streamMiddlewares,
 	}, nil
@@ -204,6 +212,7 @@ func init() {
 // toolsTuple 工具元组，包含工具的索引、元数据、执行端点
 type toolsTuple struct {
 	indexes         map[string]int           // 工具名称到索引的映射
+	infos           []*schema.ToolInfo       // 工具信息列表
 	meta            []*executorMeta          // 执行器元数据列表
 	endpoints       []InvokableToolEndpoint  // 可调用工具端点列表
 	streamEndpoints []StreamableToolEndpoint // 可流式工具端点列表
@@ -213,6 +222,7 @@ type toolsTuple struct {
 func convTools(ctx context.Context, tools []tool.BaseTool, ms []InvokableToolMiddleware, sms []StreamableToolMiddleware) (*toolsTuple, error) {
 	ret := &toolsTuple{
 		indexes:         make(map[string]int),
+		infos:           make([]*schema.ToolInfo, len(tools)),
 		meta:            make([]*executorMeta, len(tools)),
 		endpoints:       make([]InvokableToolEndpoint, len(tools)),
 		streamEndpoints: make([]StreamableToolEndpoint, len(tools)),
@@ -258,6 +268,7 @@ func convTools(ctx context.Context, tools []tool.BaseTool, ms []InvokableToolMid
 		}
 
 		ret.indexes[toolName] = idx
+		ret.infos[idx] = tl
 		ret.meta[idx] = meta
 		ret.endpoints[idx] = invokable
 		ret.streamEndpoints[idx] = streamable
@@ -414,20 +425,28 @@ func (tn *ToolsNode) genToolCallTasks(ctx context.Context, tuple *toolsTuple,
 			}
 			toolCallTasks[i] = newUnknownToolTask(toolCall.Function.Name, toolCall.Function.Arguments, toolCall.ID, tn.unknownToolHandler)
 		} else {
-			toolCallTasks[i].endpoint = tuple.endpoints[index]
-			toolCallTasks[i].streamEndpoint = tuple.streamEndpoints[index]
-			toolCallTasks[i].meta = tuple.meta[index]
-			toolCallTasks[i].name = toolCall.Function.Name
-			toolCallTasks[i].callID = toolCall.ID
+			arg := toolCall.Function.Arguments
 			if tn.toolArgumentsHandler != nil {
-				arg, err := tn.toolArgumentsHandler(ctx, toolCall.Function.Name, toolCall.Function.Arguments)
+				var err error
+				arg, err = tn.toolArgumentsHandler(ctx, toolCall.Function.Name, toolCall.Function.Arguments)
 				if err != nil {
 					return nil, fmt.Errorf("failed to executed tool[name:%s arguments:%s] arguments handler: %w", toolCall.Function.Name, toolCall.Function.Arguments, err)
 				}
-				toolCallTasks[i].arg = arg
-			} else {
-				toolCallTasks[i].arg = toolCall.Function.Arguments
 			}
+			if tn.argumentsValidation != nil {
+				var verr *ToolArgumentsValidationError
+				arg, verr = validateToolArguments(tn.argumentsValidation, tuple.infos[index], arg)
+				if verr != nil {
+					toolCallTasks[i] = newInvalidArgumentsTask(toolCall.ID, verr, tn.argumentsValidation.InvalidArgumentsHandler)
+					continue
+				}
+			}
+			toolCallTasks[i].endpoint = tuple.endpoints[index]
+			toolCallTasks[i].streamEndpoint = tuple.streamEndpoints[index]
+			toolCallTasks[i].meta = tuple.meta[index]
+			toolCallTasks[i].name = toolCall.Function.Name
+			toolCallTasks[i].callID = toolCall.ID
+			toolCallTasks[i].arg = arg
 		}
 	}
 
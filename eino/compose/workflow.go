@@ -34,6 +34,7 @@ import (
 	"github.com/favbox/eino/components/indexer"
 	"github.com/favbox/eino/components/model"
 	"github.com/favbox/eino/components/prompt"
+	"github.com/favbox/eino/components/reranker"
 	"github.com/favbox/eino/components/retriever"
 	"github.com/favbox/eino/schema"
 )
@@ -128,6 +129,12 @@ func (wf *Workflow[I, O]) AddRetrieverNode(key string, retriever retriever.Retri
 	return wf.initNode(key)
 }
 
+// AddRerankerNode 添加重排序器节点，节点输入为 *reranker.Request
+func (wf *Workflow[I, O]) AddRerankerNode(key string, reranker reranker.Reranker, opts ...GraphAddNodeOpt) *WorkflowNode {
+	_ = wf.g.AddRerankerNode(key, reranker, opts...)
+	return wf.initNode(key)
+}
+
 // AddEmbeddingNode 添加嵌入模型节点
 func (wf *Workflow[I, O]) AddEmbeddingNode(key string, embedding embedding.Embedder, opts ...GraphAddNodeOpt) *WorkflowNode {
 	_ = wf.g.AddEmbeddingNode(key, embedding, opts...)
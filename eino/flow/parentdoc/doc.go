@@ -0,0 +1,25 @@
+// Package parentdoc 提供父文档索引器与检索器。
+//
+// 为兼顾向量检索的精度与提供给模型的上下文完整性，索引时使用 document.Transformer 将父文档切分为较小的子文档，
+// 子文档写入向量索引并在元数据中记录父文档 ID，父文档则原样保存到可插拔的 DocStore 中；
+// 检索时先召回子文档，再按排名顺序映射回去重后的父文档。
+//
+// 示例：
+//
+//	store := parentdoc.NewMemoryDocStore()
+//	idx, _ := parentdoc.NewIndexer(ctx, &parentdoc.IndexerConfig{
+//		Indexer:     vectorStore,
+//		Transformer: splitter,
+//		DocStore:    store,
+//	})
+//	_, _ = idx.Store(ctx, docs)
+//
+//	r, _ := parentdoc.NewRetriever(ctx, &parentdoc.RetrieverConfig{
+//		Retriever: vectorStore,
+//		DocStore:  store,
+//	})
+//	parents, _ := r.Retrieve(ctx, "如何退货")
+package parentdoc
+
+// DefaultParentIDKey 子文档元数据中父文档 ID 的默认键。
+const DefaultParentIDKey = "parent_id"
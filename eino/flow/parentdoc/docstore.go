@@ -0,0 +1,81 @@
+package parentdoc
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/favbox/eino/schema"
+)
+
+// DocStore 父文档存储，按文档 ID 读写。
+type DocStore interface {
+	// MSet 保存文档，ID 已存在时覆盖。
+	MSet(ctx context.Context, docs []*schema.Document) error
+	// MGet 按 ID 读取文档，结果与 ids 一一对应，不存在的文档为 nil。
+	MGet(ctx context.Context, ids []string) ([]*schema.Document, error)
+}
+
+// MemoryDocStore 基于内存的 DocStore 实现，可并发使用。
+type MemoryDocStore struct {
+	mu   sync.RWMutex
+	docs map[string]*schema.Document
+}
+
+// NewMemoryDocStore 创建内存父文档存储。
+func NewMemoryDocStore() *MemoryDocStore {
+	return &MemoryDocStore{docs: make(map[string]*schema.Document)}
+}
+
+func (s *MemoryDocStore) MSet(_ context.Context, docs []*schema.Document) error {
+	for _, doc := range docs {
+		if doc == nil || doc.ID == "" {
+			return errors.New("document id is required")
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, doc := range docs {
+		s.docs[doc.ID] = copyDoc(doc)
+	}
+
+	return nil
+}
+
+func (s *MemoryDocStore) MGet(_ context.Context, ids []string) ([]*schema.Document, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	docs := make([]*schema.Document, len(ids))
+	for i, id := range ids {
+		if doc, ok := s.docs[id]; ok {
+			docs[i] = copyDoc(doc)
+		}
+	}
+
+	return docs, nil
+}
+
+// MDelete 删除文档，不存在的 ID 会被忽略。
+func (s *MemoryDocStore) MDelete(_ context.Context, ids []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ids {
+		delete(s.docs, id)
+	}
+
+	return nil
+}
+
+// copyDoc 复制文档及其顶层元数据，避免调用方修改影响存储内容。
+func copyDoc(doc *schema.Document) *schema.Document {
+	c := &schema.Document{ID: doc.ID, Content: doc.Content}
+	if doc.MetaData != nil {
+		c.MetaData = make(map[string]any, len(doc.MetaData))
+		for k, v := range doc.MetaData {
+			c.MetaData[k] = v
+		}
+	}
+	return c
+}
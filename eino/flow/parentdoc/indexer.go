@@ -0,0 +1,197 @@
+package parentdoc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/favbox/eino/callbacks"
+	"github.com/favbox/eino/components"
+	"github.com/favbox/eino/components/document"
+	"github.com/favbox/eino/components/indexer"
+	"github.com/favbox/eino/internal/delegate"
+	"github.com/favbox/eino/schema"
+)
+
+// IndexerConfig 父文档索引器配置。
+type IndexerConfig struct {
+	// Indexer 存储子文档的索引器，必填。
+	Indexer indexer.Indexer
+	// Transformer 将单个父文档切分为子文档的转换器，必填。
+	Transformer document.Transformer
+	// DocStore 保存父文档的存储，必填。
+	DocStore DocStore
+	// ParentIDKey 子文档元数据中父文档 ID 的键，默认 DefaultParentIDKey。
+	ParentIDKey string
+	// SubIDGenerator 生成子文档 ID，需返回 num 个 ID，默认为 "{父文档ID}_{序号}"。
+	SubIDGenerator func(ctx context.Context, parentID string, num int) ([]string, error)
+}
+
+// NewIndexer 创建父文档索引器。
+// 未设置 ID 的父文档会分配 UUID，Store 返回父文档 ID，与输入文档一一对应。
+// 父文档通过 Document.WithSubIndexes 指定的子索引会传递给其未指定子索引的子文档。
+func NewIndexer(_ context.Context, config *IndexerConfig) (indexer.Indexer, error) {
+	if config == nil {
+		return nil, errors.New("parent indexer config is required")
+	}
+	if config.Indexer == nil {
+		return nil, errors.New("parent indexer 'Indexer' is required")
+	}
+	if config.Transformer == nil {
+		return nil, errors.New("parent indexer 'Transformer' is required")
+	}
+	if config.DocStore == nil {
+		return nil, errors.New("parent indexer 'DocStore' is required")
+	}
+
+	p := &parentIndexer{
+		indexer:        config.Indexer,
+		transformer:    config.Transformer,
+		docStore:       config.DocStore,
+		parentIDKey:    config.ParentIDKey,
+		subIDGenerator: config.SubIDGenerator,
+	}
+	if p.parentIDKey == "" {
+		p.parentIDKey = DefaultParentIDKey
+	}
+	if p.subIDGenerator == nil {
+		p.subIDGenerator = defaultSubIDGenerator
+	}
+
+	return p, nil
+}
+
+type parentIndexer struct {
+	indexer        indexer.Indexer
+	transformer    document.Transformer
+	docStore       DocStore
+	parentIDKey    string
+	subIDGenerator func(ctx context.Context, parentID string, num int) ([]string, error)
+}
+
+func (p *parentIndexer) Store(ctx context.Context, docs []*schema.Document, opts ...indexer.Option) (ids []string, err error) {
+	ctx = callbacks.EnsureRunInfo(ctx, p.GetType(), components.ComponentOfIndexer)
+	ctx = callbacks.OnStart(ctx, &indexer.CallbackInput{Docs: docs})
+	defer func() {
+		if err != nil {
+			_ = callbacks.OnError(ctx, err)
+		}
+	}()
+
+	parents := make([]*schema.Document, 0, len(docs))
+	var children []*schema.Document
+	for i, doc := range docs {
+		if doc == nil {
+			return nil, fmt.Errorf("document[%d] is nil", i)
+		}
+		parent := copyDoc(doc)
+		if parent.ID == "" {
+			parent.ID = uuid.NewString()
+		}
+		parents = append(parents, parent)
+		ids = append(ids, parent.ID)
+
+		subDocs, err := p.split(ctx, parent)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, subDocs...)
+	}
+
+	// 先保存父文档，确保子文档可被检索时父文档已存在
+	if err = p.docStore.MSet(ctx, parents); err != nil {
+		return nil, fmt.Errorf("save parent documents failed: %w", err)
+	}
+	if len(children) > 0 {
+		if _, err = p.storeChildren(ctx, children, opts); err != nil {
+			return nil, fmt.Errorf("store sub documents failed: %w", err)
+		}
+	}
+
+	_ = callbacks.OnEnd(ctx, &indexer.CallbackOutput{IDs: ids})
+
+	return ids, nil
+}
+
+// split 切分单个父文档，为子文档分配 ID 并记录父文档 ID。
+func (p *parentIndexer) split(ctx context.Context, parent *schema.Document) ([]*schema.Document, error) {
+	subDocs, err := p.transform(ctx, parent)
+	if err != nil {
+		return nil, fmt.Errorf("split document[%s] failed: %w", parent.ID, err)
+	}
+	if len(subDocs) == 0 {
+		return nil, nil
+	}
+
+	subIDs, err := p.subIDGenerator(ctx, parent.ID, len(subDocs))
+	if err != nil {
+		return nil, fmt.Errorf("generate sub document ids of [%s] failed: %w", parent.ID, err)
+	}
+	if len(subIDs) != len(subDocs) {
+		return nil, fmt.Errorf("sub id generator returned %d ids for %d sub documents of [%s]", len(subIDs), len(subDocs), parent.ID)
+	}
+
+	children := make([]*schema.Document, 0, len(subDocs))
+	for i, sub := range subDocs {
+		if sub == nil {
+			continue
+		}
+		child := copyDoc(sub)
+		child.ID = subIDs[i]
+		if child.MetaData == nil {
+			child.MetaData = make(map[string]any)
+		}
+		child.MetaData[p.parentIDKey] = parent.ID
+		if len(child.SubIndexes()) == 0 && len(parent.SubIndexes()) > 0 {
+			child.WithSubIndexes(parent.SubIndexes())
+		}
+		children = append(children, child)
+	}
+
+	return children, nil
+}
+
+// transform 调用转换器切分单个父文档。
+func (p *parentIndexer) transform(ctx context.Context, parent *schema.Document) ([]*schema.Document, error) {
+	input := []*schema.Document{parent}
+	return delegate.Invoke(ctx, p.transformer, &callbacks.RunInfo{Component: components.ComponentOfTransformer},
+		&document.TransformerCallbackInput{Input: input},
+		func(ctx context.Context) ([]*schema.Document, error) {
+			return p.transformer.Transform(ctx, input)
+		},
+		func(docs []*schema.Document) callbacks.CallbackOutput {
+			return &document.TransformerCallbackOutput{Output: docs}
+		})
+}
+
+// storeChildren 调用子索引器存储子文档。
+func (p *parentIndexer) storeChildren(ctx context.Context, children []*schema.Document, opts []indexer.Option) ([]string, error) {
+	return delegate.Invoke(ctx, p.indexer, &callbacks.RunInfo{Component: components.ComponentOfIndexer},
+		&indexer.CallbackInput{Docs: children},
+		func(ctx context.Context) ([]string, error) {
+			return p.indexer.Store(ctx, children, opts...)
+		},
+		func(ids []string) callbacks.CallbackOutput {
+			return &indexer.CallbackOutput{IDs: ids}
+		})
+}
+
+// GetType 返回组件类型名称，用于回调的 RunInfo。
+func (p *parentIndexer) GetType() string {
+	return "ParentDocument"
+}
+
+// IsCallbacksEnabled 父文档索引器自行触发回调，编排框架无需再包装。
+func (p *parentIndexer) IsCallbacksEnabled() bool {
+	return true
+}
+
+func defaultSubIDGenerator(_ context.Context, parentID string, num int) ([]string, error) {
+	ids := make([]string, num)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("%s_%d", parentID, i)
+	}
+	return ids, nil
+}
@@ -0,0 +1,186 @@
+package parentdoc
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/favbox/eino/components/document"
+	"github.com/favbox/eino/components/embedding"
+	"github.com/favbox/eino/components/retriever"
+	"github.com/favbox/eino/components/vectorstore/memory"
+	"github.com/favbox/eino/schema"
+)
+
+// lineSplitter 按行切分文档。
+type lineSplitter struct{}
+
+func (lineSplitter) Transform(_ context.Context, docs []*schema.Document, _ ...document.TransformerOption) ([]*schema.Document, error) {
+	var out []*schema.Document
+	for _, doc := range docs {
+		for _, line := range strings.Split(doc.Content, "\n") {
+			out = append(out, &schema.Document{Content: line, MetaData: map[string]any{"source": doc.MetaData["source"]}})
+		}
+	}
+	return out, nil
+}
+
+// keywordEmbedder 以关键词出现与否作为向量维度。
+type keywordEmbedder []string
+
+func (k keywordEmbedder) EmbedStrings(_ context.Context, texts []string, _ ...embedding.Option) ([][]float64, error) {
+	vectors := make([][]float64, len(texts))
+	for i, text := range texts {
+		vectors[i] = make([]float64, len(k)+1)
+		vectors[i][len(k)] = 0.01
+		for j, word := range k {
+			if strings.Contains(text, word) {
+				vectors[i][j] = 1
+			}
+		}
+	}
+	return vectors, nil
+}
+
+func TestParentDocument(t *testing.T) {
+	ctx := context.Background()
+
+	newPipeline := func(t *testing.T) (*memory.Store, *MemoryDocStore) {
+		vs, err := memory.New(ctx, &memory.Config{Embedding: keywordEmbedder{"退货", "发票", "物流"}, TopK: 10})
+		assert.NoError(t, err)
+		return vs, NewMemoryDocStore()
+	}
+
+	t.Run("索引与检索", func(t *testing.T) {
+		vs, store := newPipeline(t)
+		idx, err := NewIndexer(ctx, &IndexerConfig{Indexer: vs, Transformer: lineSplitter{}, DocStore: store})
+		assert.NoError(t, err)
+
+		ids, err := idx.Store(ctx, []*schema.Document{
+			{ID: "after_sale", Content: "七天无理由退货\n退货需保持包装完好", MetaData: map[string]any{"source": "faq"}},
+			{ID: "invoice", Content: "发票随货寄出\n电子发票可在订单页下载"},
+			{Content: "物流一般三天送达"},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, ids, 3)
+		assert.Equal(t, []string{"after_sale", "invoice"}, ids[:2])
+		assert.NotEmpty(t, ids[2])
+		assert.Equal(t, 5, vs.Len())
+
+		child, ok := vs.Get("after_sale_1")
+		assert.True(t, ok)
+		assert.Equal(t, "after_sale", child.MetaData[DefaultParentIDKey])
+		assert.Equal(t, "faq", child.MetaData["source"])
+
+		r, err := NewRetriever(ctx, &RetrieverConfig{Retriever: vs, DocStore: store})
+		assert.NoError(t, err)
+
+		docs, err := r.Retrieve(ctx, "退货")
+		assert.NoError(t, err)
+		assert.Equal(t, "after_sale", docs[0].ID)
+		assert.Equal(t, "七天无理由退货\n退货需保持包装完好", docs[0].Content)
+		assert.ElementsMatch(t, []string{"after_sale_0", "after_sale_1"}, docs[0].MetaData[MetaKeyChildIDs])
+		assert.Greater(t, docs[0].Score(), 0.9)
+		assert.Len(t, docs, 3, "每个父文档只返回一次")
+
+		docs, err = r.Retrieve(ctx, "发票", retriever.WithTopK(1))
+		assert.NoError(t, err)
+		assert.Len(t, docs, 1)
+		assert.Equal(t, "invoice", docs[0].ID)
+	})
+
+	t.Run("子索引传递与返回数量", func(t *testing.T) {
+		vs, store := newPipeline(t)
+		idx, err := NewIndexer(ctx, &IndexerConfig{
+			Indexer:     vs,
+			Transformer: lineSplitter{},
+			DocStore:    store,
+			ParentIDKey: "pid",
+			SubIDGenerator: func(_ context.Context, parentID string, num int) ([]string, error) {
+				ids := make([]string, num)
+				for i := range ids {
+					ids[i] = parentID + "#" + string(rune('a'+i))
+				}
+				return ids, nil
+			},
+		})
+		assert.NoError(t, err)
+
+		_, err = idx.Store(ctx, []*schema.Document{
+			(&schema.Document{ID: "p1", Content: "退货\n发票"}).WithSubIndexes([]string{"cn"}),
+			{ID: "p2", Content: "退货"},
+		})
+		assert.NoError(t, err)
+		child, ok := vs.Get("p1#b")
+		assert.True(t, ok)
+		assert.Equal(t, "p1", child.MetaData["pid"])
+
+		r, err := NewRetriever(ctx, &RetrieverConfig{Retriever: vs, DocStore: store, ParentIDKey: "pid", MaxParents: 1})
+		assert.NoError(t, err)
+		docs, err := r.Retrieve(ctx, "退货", retriever.WithSubIndex("cn"))
+		assert.NoError(t, err)
+		assert.Len(t, docs, 1)
+		assert.Equal(t, "p1", docs[0].ID)
+	})
+
+	t.Run("跳过缺失的父文档", func(t *testing.T) {
+		vs, store := newPipeline(t)
+		_, err := vs.Store(ctx, []*schema.Document{
+			{ID: "orphan", Content: "退货", MetaData: map[string]any{DefaultParentIDKey: "missing"}},
+			{ID: "plain", Content: "退货"},
+		})
+		assert.NoError(t, err)
+
+		r, err := NewRetriever(ctx, &RetrieverConfig{Retriever: vs, DocStore: store})
+		assert.NoError(t, err)
+		docs, err := r.Retrieve(ctx, "退货")
+		assert.NoError(t, err)
+		assert.Empty(t, docs)
+	})
+
+	t.Run("子文档 ID 数量不匹配", func(t *testing.T) {
+		vs, store := newPipeline(t)
+		idx, err := NewIndexer(ctx, &IndexerConfig{
+			Indexer:     vs,
+			Transformer: lineSplitter{},
+			DocStore:    store,
+			SubIDGenerator: func(context.Context, string, int) ([]string, error) {
+				return []string{"only_one"}, nil
+			},
+		})
+		assert.NoError(t, err)
+
+		_, err = idx.Store(ctx, []*schema.Document{{ID: "p", Content: "a\nb"}})
+		assert.ErrorContains(t, err, "returned 1 ids for 2 sub documents")
+	})
+
+	t.Run("配置校验", func(t *testing.T) {
+		_, err := NewIndexer(ctx, &IndexerConfig{Transformer: lineSplitter{}, DocStore: NewMemoryDocStore()})
+		assert.ErrorContains(t, err, "Indexer")
+		_, err = NewRetriever(ctx, &RetrieverConfig{DocStore: NewMemoryDocStore()})
+		assert.ErrorContains(t, err, "Retriever")
+	})
+}
+
+func TestMemoryDocStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryDocStore()
+
+	assert.Error(t, store.MSet(ctx, []*schema.Document{{Content: "no id"}}))
+
+	doc := &schema.Document{ID: "a", Content: "A", MetaData: map[string]any{"k": "v"}}
+	assert.NoError(t, store.MSet(ctx, []*schema.Document{doc}))
+	doc.MetaData["k"] = "changed"
+
+	docs, err := store.MGet(ctx, []string{"a", "b"})
+	assert.NoError(t, err)
+	assert.Equal(t, "v", docs[0].MetaData["k"])
+	assert.Nil(t, docs[1])
+
+	assert.NoError(t, store.MDelete(ctx, []string{"a"}))
+	docs, err = store.MGet(ctx, []string{"a"})
+	assert.NoError(t, err)
+	assert.Nil(t, docs[0])
+}
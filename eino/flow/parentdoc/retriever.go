@@ -0,0 +1,158 @@
+package parentdoc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/favbox/eino/callbacks"
+	"github.com/favbox/eino/components"
+	"github.com/favbox/eino/components/retriever"
+	"github.com/favbox/eino/internal/delegate"
+	"github.com/favbox/eino/schema"
+)
+
+// MetaKeyChildIDs 返回的父文档元数据中命中的子文档 ID 的键，值为 []string，按子文档排名排列。
+const MetaKeyChildIDs = "_parent_child_ids"
+
+// RetrieverConfig 父文档检索器配置。
+type RetrieverConfig struct {
+	// Retriever 检索子文档的检索器，必填。检索选项会原样传给它。
+	Retriever retriever.Retriever
+	// DocStore 保存父文档的存储，必填。
+	DocStore DocStore
+	// ParentIDKey 子文档元数据中父文档 ID 的键，默认 DefaultParentIDKey。
+	ParentIDKey string
+	// MaxParents 最多返回的父文档数量，默认返回全部。
+	MaxParents int
+}
+
+// NewRetriever 创建父文档检索器。
+// 按子文档的排名顺序返回去重后的父文档副本，分数取其排名最靠前的子文档的分数；
+// 缺少父文档 ID 或父文档已不存在的子文档会被跳过。
+func NewRetriever(_ context.Context, config *RetrieverConfig) (retriever.Retriever, error) {
+	if config == nil {
+		return nil, errors.New("parent retriever config is required")
+	}
+	if config.Retriever == nil {
+		return nil, errors.New("parent retriever 'Retriever' is required")
+	}
+	if config.DocStore == nil {
+		return nil, errors.New("parent retriever 'DocStore' is required")
+	}
+
+	p := &parentRetriever{
+		retriever:   config.Retriever,
+		docStore:    config.DocStore,
+		parentIDKey: config.ParentIDKey,
+		maxParents:  config.MaxParents,
+	}
+	if p.parentIDKey == "" {
+		p.parentIDKey = DefaultParentIDKey
+	}
+
+	return p, nil
+}
+
+type parentRetriever struct {
+	retriever   retriever.Retriever
+	docStore    DocStore
+	parentIDKey string
+	maxParents  int
+}
+
+func (p *parentRetriever) Retrieve(ctx context.Context, query string, opts ...retriever.Option) (docs []*schema.Document, err error) {
+	options := retriever.GetCommonOptions(&retriever.Options{}, opts...)
+	input := &retriever.CallbackInput{Query: query, ScoreThreshold: options.ScoreThreshold}
+	if options.TopK != nil {
+		input.TopK = *options.TopK
+	}
+
+	ctx = callbacks.EnsureRunInfo(ctx, p.GetType(), components.ComponentOfRetriever)
+	ctx = callbacks.OnStart(ctx, input)
+	defer func() {
+		if err != nil {
+			_ = callbacks.OnError(ctx, err)
+		}
+	}()
+
+	children, err := p.retrieveChildren(ctx, query, opts)
+	if err != nil {
+		return nil, fmt.Errorf("retrieve sub documents failed: %w", err)
+	}
+
+	var (
+		parentIDs []string
+		childIDs  = make(map[string][]string)
+		scores    = make(map[string]float64)
+	)
+	for _, child := range children {
+		if child == nil {
+			continue
+		}
+		parentID, ok := child.MetaData[p.parentIDKey].(string)
+		if !ok || parentID == "" {
+			continue
+		}
+		if _, seen := childIDs[parentID]; !seen {
+			parentIDs = append(parentIDs, parentID)
+			scores[parentID] = child.Score()
+		}
+		childIDs[parentID] = append(childIDs[parentID], child.ID)
+	}
+
+	parents, err := p.docStore.MGet(ctx, parentIDs)
+	if err != nil {
+		return nil, fmt.Errorf("get parent documents failed: %w", err)
+	}
+	if len(parents) != len(parentIDs) {
+		return nil, fmt.Errorf("doc store returned %d documents for %d ids", len(parents), len(parentIDs))
+	}
+
+	docs = make([]*schema.Document, 0, len(parents))
+	for i, parent := range parents {
+		if parent == nil {
+			continue
+		}
+		if p.maxParents > 0 && len(docs) >= p.maxParents {
+			break
+		}
+		doc := copyDoc(parent)
+		if doc.MetaData == nil {
+			doc.MetaData = make(map[string]any)
+		}
+		doc.MetaData[MetaKeyChildIDs] = childIDs[parentIDs[i]]
+		docs = append(docs, doc.WithScore(scores[parentIDs[i]]))
+	}
+
+	_ = callbacks.OnEnd(ctx, &retriever.CallbackOutput{Docs: docs})
+
+	return docs, nil
+}
+
+// retrieveChildren 调用子检索器检索子文档。
+func (p *parentRetriever) retrieveChildren(ctx context.Context, query string, opts []retriever.Option) ([]*schema.Document, error) {
+	options := retriever.GetCommonOptions(&retriever.Options{}, opts...)
+	input := &retriever.CallbackInput{Query: query, ScoreThreshold: options.ScoreThreshold}
+	if options.TopK != nil {
+		input.TopK = *options.TopK
+	}
+
+	return delegate.Invoke(ctx, p.retriever, &callbacks.RunInfo{Component: components.ComponentOfRetriever}, input,
+		func(ctx context.Context) ([]*schema.Document, error) {
+			return p.retriever.Retrieve(ctx, query, opts...)
+		},
+		func(docs []*schema.Document) callbacks.CallbackOutput {
+			return &retriever.CallbackOutput{Docs: docs}
+		})
+}
+
+// GetType 返回组件类型名称，用于回调的 RunInfo。
+func (p *parentRetriever) GetType() string {
+	return "ParentDocument"
+}
+
+// IsCallbacksEnabled 父文档检索器自行触发回调，编排框架无需再包装。
+func (p *parentRetriever) IsCallbacksEnabled() bool {
+	return true
+}
@@ -0,0 +1,431 @@
+// Package fusion 提供融合多个检索器结果的混合检索器。
+//
+// 检索时并行调用所有子检索器，按文档 ID 去重后使用倒数排名融合（RRF）或加权归一化分数融合排序，
+// 各子检索器的原始分数与排名保存在文档元数据中（见 SourceScores、SourceRanks）。
+// 每个子检索器以自己的 RunInfo 触发 retriever 回调，便于区分结果来源。
+//
+// 示例：
+//
+//	r, _ := fusion.NewRetriever(ctx, &fusion.Config{
+//		Sources: []*fusion.Source{
+//			{Name: "keyword", Retriever: keywordRetriever},
+//			{Name: "dense", Retriever: denseRetriever, Weight: 2},
+//		},
+//		FailurePolicy: fusion.FailurePolicyDegrade,
+//		TopK:          5,
+//	})
+//	docs, _ := r.Retrieve(ctx, "如何退货")
+package fusion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/favbox/eino/callbacks"
+	"github.com/favbox/eino/components"
+	"github.com/favbox/eino/components/retriever"
+	"github.com/favbox/eino/internal/delegate"
+	"github.com/favbox/eino/internal/safe"
+	"github.com/favbox/eino/schema"
+)
+
+const (
+	// MetaKeySourceScores 文档元数据中各来源原始分数的键，值为 map[string]float64。
+	MetaKeySourceScores = "_fusion_source_scores"
+	// MetaKeySourceRanks 文档元数据中各来源排名（从 1 开始）的键，值为 map[string]int。
+	MetaKeySourceRanks = "_fusion_source_ranks"
+
+	// ExtraKeySourceErrors 回调输出 Extra 中降级跳过的来源错误的键，值为 map[string]string。
+	ExtraKeySourceErrors = "fusion_source_errors"
+
+	defaultRRFK = 60
+)
+
+// Mode 融合方式。
+type Mode string
+
+const (
+	// ModeRRF 倒数排名融合，分数为 Σ weight/(RRFK+rank)，不依赖各来源分数的量纲。
+	ModeRRF Mode = "rrf"
+	// ModeWeightedScore 将各来源分数按最小-最大值归一化到 [0, 1] 后加权求和。
+	ModeWeightedScore Mode = "weighted_score"
+)
+
+// FailurePolicy 子检索器失败时的处理策略。
+type FailurePolicy string
+
+const (
+	// FailurePolicyFailFast 任一子检索器失败即取消其他子检索器并返回错误。
+	FailurePolicyFailFast FailurePolicy = "fail_fast"
+	// FailurePolicyDegrade 跳过失败的子检索器，仅在全部失败时返回错误。
+	FailurePolicyDegrade FailurePolicy = "degrade"
+)
+
+// Source 参与融合的子检索器。
+type Source struct {
+	// Name 来源名称，必填且唯一，用作回调 RunInfo.Name 与元数据中的键。
+	Name string
+	// Retriever 子检索器，必填。
+	Retriever retriever.Retriever
+	// Weight 融合权重，默认 1。
+	Weight float64
+}
+
+// Config 融合检索器配置。
+type Config struct {
+	// Sources 子检索器列表，必填。
+	Sources []*Source
+	// Mode 融合方式，默认 ModeRRF。
+	Mode Mode
+	// RRFK RRF 的平滑常数，默认 60。
+	RRFK float64
+	// TopK 融合后返回的文档数量，可被 retriever.WithTopK 覆盖，默认返回全部。
+	// 检索选项会原样传给所有子检索器。
+	TopK int
+	// FailurePolicy 子检索器失败时的处理策略，默认 FailurePolicyFailFast。
+	FailurePolicy FailurePolicy
+}
+
+// NewRetriever 创建融合检索器。
+func NewRetriever(_ context.Context, config *Config) (retriever.Retriever, error) {
+	if config == nil {
+		return nil, errors.New("fusion config is required")
+	}
+	if len(config.Sources) == 0 {
+		return nil, errors.New("fusion 'Sources' is required")
+	}
+
+	r := &fusionRetriever{
+		mode:          config.Mode,
+		rrfK:          config.RRFK,
+		topK:          config.TopK,
+		failurePolicy: config.FailurePolicy,
+	}
+	names := make(map[string]bool, len(config.Sources))
+	for i, s := range config.Sources {
+		if s == nil || s.Retriever == nil {
+			return nil, fmt.Errorf("fusion source[%d] retriever is required", i)
+		}
+		if s.Name == "" {
+			return nil, fmt.Errorf("fusion source[%d] name is required", i)
+		}
+		if names[s.Name] {
+			return nil, fmt.Errorf("duplicate fusion source name: %s", s.Name)
+		}
+		names[s.Name] = true
+
+		weight := s.Weight
+		if weight == 0 {
+			weight = 1
+		}
+		if weight < 0 {
+			return nil, fmt.Errorf("fusion source[%s] weight must be positive, got %v", s.Name, weight)
+		}
+		r.sources = append(r.sources, &Source{Name: s.Name, Retriever: s.Retriever, Weight: weight})
+	}
+
+	switch r.mode {
+	case "":
+		r.mode = ModeRRF
+	case ModeRRF, ModeWeightedScore:
+	default:
+		return nil, fmt.Errorf("unknown fusion mode: %q", r.mode)
+	}
+	switch r.failurePolicy {
+	case "":
+		r.failurePolicy = FailurePolicyFailFast
+	case FailurePolicyFailFast, FailurePolicyDegrade:
+	default:
+		return nil, fmt.Errorf("unknown fusion failure policy: %q", r.failurePolicy)
+	}
+	if r.rrfK <= 0 {
+		r.rrfK = defaultRRFK
+	}
+
+	return r, nil
+}
+
+// SourceScores 返回融合结果中文档在各来源的原始分数。
+func SourceScores(doc *schema.Document) map[string]float64 {
+	if doc == nil || doc.MetaData == nil {
+		return nil
+	}
+	scores, _ := doc.MetaData[MetaKeySourceScores].(map[string]float64)
+	return scores
+}
+
+// SourceRanks 返回融合结果中文档在各来源的排名，从 1 开始。
+func SourceRanks(doc *schema.Document) map[string]int {
+	if doc == nil || doc.MetaData == nil {
+		return nil
+	}
+	ranks, _ := doc.MetaData[MetaKeySourceRanks].(map[string]int)
+	return ranks
+}
+
+type fusionRetriever struct {
+	sources       []*Source
+	mode          Mode
+	rrfK          float64
+	topK          int
+	failurePolicy FailurePolicy
+}
+
+// sourceResult 单个子检索器的结果。
+type sourceResult struct {
+	docs []*schema.Document
+	err  error
+}
+
+func (f *fusionRetriever) Retrieve(ctx context.Context, query string, opts ...retriever.Option) (docs []*schema.Document, err error) {
+	options := retriever.GetCommonOptions(&retriever.Options{}, opts...)
+	topK := f.topK
+	if options.TopK != nil {
+		topK = *options.TopK
+	}
+
+	ctx = callbacks.EnsureRunInfo(ctx, f.GetType(), components.ComponentOfRetriever)
+	ctx = callbacks.OnStart(ctx, &retriever.CallbackInput{
+		Query:          query,
+		TopK:           topK,
+		ScoreThreshold: options.ScoreThreshold,
+	})
+	defer func() {
+		if err != nil {
+			_ = callbacks.OnError(ctx, err)
+		}
+	}()
+
+	results, firstFailed := f.retrieveAll(ctx, query, opts)
+
+	sourceErrors := make(map[string]string)
+	var errs []error
+	for i, res := range results {
+		if res.err != nil {
+			sourceErrors[f.sources[i].Name] = res.err.Error()
+			errs = append(errs, fmt.Errorf("[%s] %w", f.sources[i].Name, res.err))
+		}
+	}
+	switch {
+	case firstFailed >= 0 && f.failurePolicy == FailurePolicyFailFast:
+		// 其余来源可能因取消而失败，只返回最先失败的来源的错误
+		return nil, fmt.Errorf("fusion source[%s] failed: %w", f.sources[firstFailed].Name, results[firstFailed].err)
+	case len(errs) == len(results):
+		return nil, fmt.Errorf("all fusion sources failed: %w", errors.Join(errs...))
+	}
+
+	docs = f.fuse(results)
+	if topK > 0 && len(docs) > topK {
+		docs = docs[:topK]
+	}
+
+	output := &retriever.CallbackOutput{Docs: docs}
+	if len(sourceErrors) > 0 {
+		output.Extra = map[string]any{ExtraKeySourceErrors: sourceErrors}
+	}
+	_ = callbacks.OnEnd(ctx, output)
+
+	return docs, nil
+}
+
+// retrieveAll 并行调用所有子检索器，快速失败策略下任一失败会取消其余调用。
+// 返回各来源的结果与最先失败的来源下标，没有失败时为 -1。
+func (f *fusionRetriever) retrieveAll(ctx context.Context, query string, opts []retriever.Option) ([]*sourceResult, int) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]*sourceResult, len(f.sources))
+	firstFailed := -1
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	for i, source := range f.sources {
+		wg.Add(1)
+		go func(i int, source *Source) {
+			defer wg.Done()
+			res := &sourceResult{}
+			defer func() {
+				if panicErr := recover(); panicErr != nil {
+					res.err = safe.NewPanicErr(panicErr, debug.Stack())
+				}
+				if res.err != nil {
+					mu.Lock()
+					if firstFailed < 0 {
+						firstFailed = i
+					}
+					mu.Unlock()
+					if f.failurePolicy == FailurePolicyFailFast {
+						cancel()
+					}
+				}
+				results[i] = res
+			}()
+
+			res.docs, res.err = retrieveSource(ctx, source, query, opts)
+		}(i, source)
+	}
+	wg.Wait()
+
+	return results, firstFailed
+}
+
+// retrieveSource 以来源的名称调用子检索器。
+func retrieveSource(ctx context.Context, source *Source, query string, opts []retriever.Option) ([]*schema.Document, error) {
+	options := retriever.GetCommonOptions(&retriever.Options{}, opts...)
+	input := &retriever.CallbackInput{Query: query, ScoreThreshold: options.ScoreThreshold}
+	if options.TopK != nil {
+		input.TopK = *options.TopK
+	}
+
+	return delegate.Invoke(ctx, source.Retriever,
+		&callbacks.RunInfo{Name: source.Name, Component: components.ComponentOfRetriever}, input,
+		func(ctx context.Context) ([]*schema.Document, error) {
+			return source.Retriever.Retrieve(ctx, query, opts...)
+		},
+		func(docs []*schema.Document) callbacks.CallbackOutput {
+			return &retriever.CallbackOutput{Docs: docs}
+		})
+}
+
+// ranked 参与融合的一组有序结果。
+type ranked struct {
+	name   string
+	weight float64
+	docs   []*schema.Document
+}
+
+// fused 融合过程中的文档。
+type fused struct {
+	doc    *schema.Document
+	score  float64
+	scores map[string]float64
+	ranks  map[string]int
+}
+
+// fuse 去重并融合各来源的结果，跳过失败的来源。
+func (f *fusionRetriever) fuse(results []*sourceResult) []*schema.Document {
+	lists := make([]*ranked, 0, len(results))
+	for i, res := range results {
+		if res.err != nil {
+			continue
+		}
+		lists = append(lists, &ranked{name: f.sources[i].Name, weight: f.sources[i].Weight, docs: res.docs})
+	}
+
+	return fuse(lists, f.mode, f.rrfK, true)
+}
+
+// RRF 以倒数排名融合合并多组有序结果，分数为 Σ 1/(k+rank)，k 不大于 0 时默认为 60。
+// 返回按融合分数降序排列的文档副本，去重规则与融合检索器相同，元数据中不记录各组的分数与排名。
+func RRF(lists [][]*schema.Document, k float64) []*schema.Document {
+	if k <= 0 {
+		k = defaultRRFK
+	}
+	rankedLists := make([]*ranked, 0, len(lists))
+	for i, docs := range lists {
+		rankedLists = append(rankedLists, &ranked{name: strconv.Itoa(i), weight: 1, docs: docs})
+	}
+
+	return fuse(rankedLists, ModeRRF, k, false)
+}
+
+// fuse 去重并融合多组有序结果，返回按融合分数降序排列的文档副本，
+// withSources 为 true 时在元数据中记录各来源的原始分数与排名。
+// 文档按 ID 去重，ID 为空时按内容去重；同一文档以最先出现时的内容为准。
+func fuse(lists []*ranked, mode Mode, rrfK float64, withSources bool) []*schema.Document {
+	var (
+		merged = make(map[string]*fused)
+		items  []*fused
+	)
+	for _, list := range lists {
+		normalized := normalizeScores(list.docs)
+		for rank, doc := range list.docs {
+			if doc == nil {
+				continue
+			}
+			key := doc.ID
+			if key == "" {
+				key = "content:" + doc.Content
+			}
+			item, ok := merged[key]
+			if !ok {
+				item = &fused{
+					doc:    doc,
+					scores: make(map[string]float64),
+					ranks:  make(map[string]int),
+				}
+				merged[key] = item
+				items = append(items, item)
+			}
+			if _, seen := item.ranks[list.name]; seen {
+				continue // 同一来源内重复的文档只计最靠前的一次
+			}
+
+			item.scores[list.name] = doc.Score()
+			item.ranks[list.name] = rank + 1
+			switch mode {
+			case ModeWeightedScore:
+				item.score += list.weight * normalized[rank]
+			default:
+				item.score += list.weight / (rrfK + float64(rank+1))
+			}
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].score > items[j].score })
+
+	docs := make([]*schema.Document, 0, len(items))
+	for _, item := range items {
+		doc := &schema.Document{ID: item.doc.ID, Content: item.doc.Content, MetaData: make(map[string]any, len(item.doc.MetaData)+3)}
+		for k, v := range item.doc.MetaData {
+			doc.MetaData[k] = v
+		}
+		if withSources {
+			doc.MetaData[MetaKeySourceScores] = item.scores
+			doc.MetaData[MetaKeySourceRanks] = item.ranks
+		}
+		docs = append(docs, doc.WithScore(item.score))
+	}
+
+	return docs
+}
+
+// normalizeScores 将文档分数按最小-最大值归一化到 [0, 1]，分数全部相同时均为 1。
+func normalizeScores(docs []*schema.Document) []float64 {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, doc := range docs {
+		if doc == nil {
+			continue
+		}
+		lo, hi = math.Min(lo, doc.Score()), math.Max(hi, doc.Score())
+	}
+
+	normalized := make([]float64, len(docs))
+	for i, doc := range docs {
+		switch {
+		case doc == nil:
+		case hi == lo:
+			normalized[i] = 1
+		default:
+			normalized[i] = (doc.Score() - lo) / (hi - lo)
+		}
+	}
+
+	return normalized
+}
+
+// GetType 返回组件类型名称，用于回调的 RunInfo。
+func (f *fusionRetriever) GetType() string {
+	return "Fusion"
+}
+
+// IsCallbacksEnabled 融合检索器自行触发回调，编排框架无需再包装。
+func (f *fusionRetriever) IsCallbacksEnabled() bool {
+	return true
+}
@@ -0,0 +1,183 @@
+package fusion
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/favbox/eino/callbacks"
+	"github.com/favbox/eino/components/retriever"
+	"github.com/favbox/eino/schema"
+)
+
+// fakeRetriever 返回固定文档，未实现回调。
+type fakeRetriever struct {
+	docs []*schema.Document
+	err  error
+	wait bool
+}
+
+func (f *fakeRetriever) Retrieve(ctx context.Context, _ string, _ ...retriever.Option) ([]*schema.Document, error) {
+	if f.wait {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return f.docs, f.err
+}
+
+func doc(id string, score float64) *schema.Document {
+	return (&schema.Document{ID: id, Content: id}).WithScore(score)
+}
+
+func ids(docs []*schema.Document) []string {
+	out := make([]string, 0, len(docs))
+	for _, d := range docs {
+		out = append(out, d.ID)
+	}
+	return out
+}
+
+func TestFusionRetriever(t *testing.T) {
+	ctx := context.Background()
+	keyword := &fakeRetriever{docs: []*schema.Document{doc("a", 12), doc("b", 8), doc("c", 2)}}
+	dense := &fakeRetriever{docs: []*schema.Document{doc("c", 0.9), doc("a", 0.8), doc("d", 0.1)}}
+
+	t.Run("倒数排名融合", func(t *testing.T) {
+		r, err := NewRetriever(ctx, &Config{Sources: []*Source{
+			{Name: "keyword", Retriever: keyword},
+			{Name: "dense", Retriever: dense},
+		}})
+		assert.NoError(t, err)
+
+		docs, err := r.Retrieve(ctx, "q")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a", "c", "b", "d"}, ids(docs))
+		assert.InDelta(t, 1.0/61+1.0/62, docs[0].Score(), 1e-12)
+		assert.Equal(t, map[string]float64{"keyword": 12, "dense": 0.8}, SourceScores(docs[0]))
+		assert.Equal(t, map[string]int{"keyword": 1, "dense": 2}, SourceRanks(docs[0]))
+
+		// 原始文档不被修改
+		assert.Equal(t, 12.0, keyword.docs[0].Score())
+	})
+
+	t.Run("加权归一化分数融合", func(t *testing.T) {
+		r, err := NewRetriever(ctx, &Config{
+			Mode: ModeWeightedScore,
+			TopK: 2,
+			Sources: []*Source{
+				{Name: "keyword", Retriever: keyword},
+				{Name: "dense", Retriever: dense, Weight: 3},
+			},
+		})
+		assert.NoError(t, err)
+
+		docs, err := r.Retrieve(ctx, "q")
+		assert.NoError(t, err)
+		// c: 0 + 3*1, a: 1 + 3*0.875
+		assert.Equal(t, []string{"a", "c"}, ids(docs))
+		assert.InDelta(t, 3.625, docs[0].Score(), 1e-9)
+
+		docs, err = r.Retrieve(ctx, "q", retriever.WithTopK(3))
+		assert.NoError(t, err)
+		assert.Len(t, docs, 3)
+	})
+
+	t.Run("失败策略", func(t *testing.T) {
+		failing := &fakeRetriever{err: errors.New("timeout")}
+		slow := &fakeRetriever{wait: true}
+
+		r, err := NewRetriever(ctx, &Config{Sources: []*Source{
+			{Name: "keyword", Retriever: keyword},
+			{Name: "slow", Retriever: slow},
+			{Name: "broken", Retriever: failing},
+		}})
+		assert.NoError(t, err)
+		// 快速失败会取消仍在执行的子检索器
+		_, err = r.Retrieve(ctx, "q")
+		assert.ErrorContains(t, err, "timeout")
+
+		// 其余来源均因取消而失败时，仍只返回最先失败的来源的错误
+		r, err = NewRetriever(ctx, &Config{Sources: []*Source{
+			{Name: "slow", Retriever: slow},
+			{Name: "broken", Retriever: failing},
+		}})
+		assert.NoError(t, err)
+		_, err = r.Retrieve(ctx, "q")
+		assert.EqualError(t, err, "fusion source[broken] failed: timeout")
+
+		r, err = NewRetriever(ctx, &Config{
+			FailurePolicy: FailurePolicyDegrade,
+			Sources: []*Source{
+				{Name: "keyword", Retriever: keyword},
+				{Name: "broken", Retriever: failing},
+			},
+		})
+		assert.NoError(t, err)
+
+		var extra map[string]any
+		handler := callbacks.NewHandlerBuilder().
+			OnEndFn(func(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+				if info.Type == "Fusion" {
+					extra = retriever.ConvCallbackOutput(output).Extra
+				}
+				return ctx
+			}).Build()
+		docs, err := r.Retrieve(callbacks.InitCallbacks(ctx, nil, handler), "q")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a", "b", "c"}, ids(docs))
+		assert.Equal(t, map[string]any{ExtraKeySourceErrors: map[string]string{"broken": "timeout"}}, extra)
+
+		r, err = NewRetriever(ctx, &Config{
+			FailurePolicy: FailurePolicyDegrade,
+			Sources:       []*Source{{Name: "broken", Retriever: failing}},
+		})
+		assert.NoError(t, err)
+		_, err = r.Retrieve(ctx, "q")
+		assert.ErrorContains(t, err, "all fusion sources failed")
+	})
+
+	t.Run("子检索器回调", func(t *testing.T) {
+		r, err := NewRetriever(ctx, &Config{Sources: []*Source{
+			{Name: "keyword", Retriever: keyword},
+			{Name: "dense", Retriever: dense},
+		}})
+		assert.NoError(t, err)
+
+		var (
+			mu      sync.Mutex
+			started = make(map[string]string)
+			ended   = make(map[string]int)
+		)
+		handler := callbacks.NewHandlerBuilder().
+			OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+				mu.Lock()
+				defer mu.Unlock()
+				started[info.Name+"/"+info.Type] = retriever.ConvCallbackInput(input).Query
+				return ctx
+			}).
+			OnEndFn(func(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+				mu.Lock()
+				defer mu.Unlock()
+				ended[info.Name+"/"+info.Type] = len(retriever.ConvCallbackOutput(output).Docs)
+				return ctx
+			}).Build()
+
+		_, err = r.Retrieve(callbacks.InitCallbacks(ctx, &callbacks.RunInfo{Name: "hybrid"}, handler), "退货",
+			retriever.WithTopK(3))
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"hybrid/": "退货", "keyword/": "退货", "dense/": "退货"}, started)
+		assert.Equal(t, map[string]int{"hybrid/": 3, "keyword/": 3, "dense/": 3}, ended)
+	})
+
+	t.Run("配置校验", func(t *testing.T) {
+		_, err := NewRetriever(ctx, &Config{})
+		assert.ErrorContains(t, err, "'Sources' is required")
+		_, err = NewRetriever(ctx, &Config{Sources: []*Source{{Name: "a", Retriever: keyword}, {Name: "a", Retriever: dense}}})
+		assert.ErrorContains(t, err, "duplicate fusion source name")
+		_, err = NewRetriever(ctx, &Config{Mode: "max", Sources: []*Source{{Name: "a", Retriever: keyword}}})
+		assert.ErrorContains(t, err, "unknown fusion mode")
+	})
+}
@@ -0,0 +1,347 @@
+// Package multiquery 提供多查询改写检索器。
+//
+// 检索时先由聊天模型将原始查询改写为多个不同表述，再用每个查询并发调用被包装的检索器，
+// 最后去重并融合各查询的结果（默认使用倒数排名融合，见 RRFFusion）。
+// 整个流程基于 compose 编排，返回的检索器可直接用于 Chain.AppendRetriever 等场景。
+//
+// 示例：
+//
+//	r, _ := multiquery.NewRetriever(ctx, &multiquery.Config{
+//		OrigRetriever: vectorRetriever,
+//		RewriteLLM:    chatModel,
+//		MaxQueriesNum: 3,
+//	})
+//	docs, _ := r.Retrieve(ctx, "退货流程是什么")
+package multiquery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"runtime/debug"
+	"strings"
+	"sync"
+
+	"github.com/favbox/eino/callbacks"
+	"github.com/favbox/eino/components"
+	"github.com/favbox/eino/components/model"
+	"github.com/favbox/eino/components/prompt"
+	"github.com/favbox/eino/components/retriever"
+	"github.com/favbox/eino/compose"
+	"github.com/favbox/eino/flow/retriever/fusion"
+	"github.com/favbox/eino/internal/delegate"
+	"github.com/favbox/eino/internal/safe"
+	"github.com/favbox/eino/schema"
+)
+
+const (
+	// GraphName 内部编排图的名称。
+	GraphName = "MultiQueryRetriever"
+
+	// MaxQueriesVar 改写模板中最大改写数量的变量名。
+	MaxQueriesVar = "max_queries"
+
+	defaultQueryVar      = "query"
+	defaultMaxQueriesNum = 5
+
+	defaultRewritePrompt = `你是一个检索查询改写助手。请针对用户的问题生成 {max_queries} 个不同表述的搜索查询，
+以便从向量数据库中检索到更多相关文档。每行输出一个查询，不要编号，不要输出其他内容。
+
+用户问题：{query}`
+)
+
+// 内部编排图中的节点键。
+const (
+	nodeKeyOriginal  = "original"
+	nodeKeyRewritten = "rewritten"
+	nodeKeyRetrieve  = "retrieve"
+	nodeKeyFusion    = "fusion"
+)
+
+// FusionFunc 融合各查询的检索结果，docs[i] 为 queries[i] 的检索结果，queries[0] 为原始查询（未排除时）。
+type FusionFunc func(ctx context.Context, queries []string, docs [][]*schema.Document) ([]*schema.Document, error)
+
+// Config 多查询检索器配置。
+type Config struct {
+	// OrigRetriever 被包装的检索器，必填。
+	OrigRetriever retriever.Retriever
+
+	// RewriteLLM 生成改写查询的聊天模型，未设置 RewriteHandler 时必填。
+	RewriteLLM model.BaseChatModel
+	// RewriteTemplate 改写提示词模板，可用变量为 QueryVar 与 MaxQueriesVar，默认使用内置的 FString 模板。
+	RewriteTemplate prompt.ChatTemplate
+	// QueryVar 改写模板中原始查询的变量名，默认 "query"。
+	QueryVar string
+	// LLMOutputParser 将模型输出解析为查询列表，默认按行切分并去除行首编号。
+	LLMOutputParser func(ctx context.Context, output *schema.Message) ([]string, error)
+
+	// RewriteHandler 自定义改写逻辑，设置后忽略 RewriteLLM、RewriteTemplate 与 LLMOutputParser。
+	RewriteHandler func(ctx context.Context, query string) ([]string, error)
+
+	// MaxQueriesNum 最多使用的改写查询数量，不含原始查询，默认 5。
+	MaxQueriesNum int
+	// ExcludeOriginalQuery 为 true 时不使用原始查询检索，仅使用改写后的查询。
+	ExcludeOriginalQuery bool
+
+	// FusionFunc 融合各查询的检索结果，默认 RRFFusion。
+	// 检索选项会原样传给 OrigRetriever，其中的 TopK 同时用于截断融合结果。
+	FusionFunc FusionFunc
+}
+
+// NewRetriever 创建多查询检索器。
+func NewRetriever(ctx context.Context, config *Config) (retriever.Retriever, error) {
+	if config == nil {
+		return nil, errors.New("multi query config is required")
+	}
+	if config.OrigRetriever == nil {
+		return nil, errors.New("multi query 'OrigRetriever' is required")
+	}
+	if config.RewriteHandler == nil && config.RewriteLLM == nil {
+		return nil, errors.New("multi query 'RewriteLLM' or 'RewriteHandler' is required")
+	}
+	if config.MaxQueriesNum < 0 {
+		return nil, fmt.Errorf("multi query 'MaxQueriesNum' must not be negative, got %d", config.MaxQueriesNum)
+	}
+
+	m := &multiQueryRetriever{
+		origRetriever:        config.OrigRetriever,
+		maxQueriesNum:        config.MaxQueriesNum,
+		excludeOriginalQuery: config.ExcludeOriginalQuery,
+		fusionFunc:           config.FusionFunc,
+	}
+	if m.maxQueriesNum == 0 {
+		m.maxQueriesNum = defaultMaxQueriesNum
+	}
+	if m.fusionFunc == nil {
+		m.fusionFunc = RRFFusion
+	}
+
+	rewriter, err := m.buildRewriter(config)
+	if err != nil {
+		return nil, err
+	}
+
+	chain := compose.NewChain[string, []*schema.Document]()
+	chain.
+		AppendParallel(compose.NewParallel().
+			AddPassthrough(nodeKeyOriginal).
+			AddGraph(nodeKeyRewritten, rewriter, compose.WithNodeName("QueryRewriter"))).
+		AppendLambda(compose.InvokableLambdaWithOption(m.retrieve), compose.WithNodeKey(nodeKeyRetrieve), compose.WithNodeName("Retrieve")).
+		AppendLambda(compose.InvokableLambda(m.fuse), compose.WithNodeKey(nodeKeyFusion), compose.WithNodeName("Fusion"))
+
+	m.runnable, err = chain.Compile(ctx, compose.WithGraphName(GraphName))
+	if err != nil {
+		return nil, fmt.Errorf("compile multi query retriever failed: %w", err)
+	}
+
+	return m, nil
+}
+
+// buildRewriter 构建查询改写子链，输入原始查询，输出改写后的查询列表。
+func (m *multiQueryRetriever) buildRewriter(config *Config) (*compose.Chain[string, []string], error) {
+	rewriter := compose.NewChain[string, []string]()
+	if config.RewriteHandler != nil {
+		rewriter.AppendLambda(compose.InvokableLambda(config.RewriteHandler), compose.WithNodeName("CustomQueryRewriter"))
+		return rewriter, nil
+	}
+
+	queryVar := config.QueryVar
+	if queryVar == "" {
+		queryVar = defaultQueryVar
+	}
+	if queryVar == MaxQueriesVar {
+		return nil, fmt.Errorf("multi query 'QueryVar' must not be %q", MaxQueriesVar)
+	}
+	tpl := config.RewriteTemplate
+	if tpl == nil {
+		tpl = prompt.FromMessages(schema.FString, schema.UserMessage(strings.ReplaceAll(defaultRewritePrompt, "{query}", "{"+queryVar+"}")))
+	}
+	parser := config.LLMOutputParser
+	if parser == nil {
+		parser = defaultOutputParser
+	}
+
+	rewriter.
+		AppendLambda(compose.InvokableLambda(func(_ context.Context, query string) (map[string]any, error) {
+			return map[string]any{queryVar: query, MaxQueriesVar: m.maxQueriesNum}, nil
+		}), compose.WithNodeName("Converter")).
+		AppendChatTemplate(tpl).
+		AppendChatModel(config.RewriteLLM).
+		AppendLambda(compose.InvokableLambda(parser), compose.WithNodeName("OutputParser"))
+
+	return rewriter, nil
+}
+
+type multiQueryRetriever struct {
+	runnable             compose.Runnable[string, []*schema.Document]
+	origRetriever        retriever.Retriever
+	maxQueriesNum        int
+	excludeOriginalQuery bool
+	fusionFunc           FusionFunc
+}
+
+// retrieveResult 检索节点的输出。
+type retrieveResult struct {
+	queries []string
+	docs    [][]*schema.Document
+	topK    int
+}
+
+func (m *multiQueryRetriever) Retrieve(ctx context.Context, query string, opts ...retriever.Option) ([]*schema.Document, error) {
+	var callOpts []compose.Option
+	if len(opts) > 0 {
+		lambdaOpts := make([]any, 0, len(opts))
+		for _, opt := range opts {
+			lambdaOpts = append(lambdaOpts, opt)
+		}
+		callOpts = append(callOpts, compose.WithLambdaOption(lambdaOpts...).DesignateNode(nodeKeyRetrieve))
+	}
+
+	return m.runnable.Invoke(ctx, query, callOpts...)
+}
+
+// retrieve 整理查询列表并用每个查询并发调用被包装的检索器，任一查询失败即取消其余调用。
+func (m *multiQueryRetriever) retrieve(ctx context.Context, input map[string]any, opts ...retriever.Option) (*retrieveResult, error) {
+	original, _ := input[nodeKeyOriginal].(string)
+	rewritten, _ := input[nodeKeyRewritten].([]string)
+
+	queries := m.collectQueries(original, rewritten)
+	if len(queries) == 0 {
+		return nil, errors.New("no query to retrieve")
+	}
+
+	res := &retrieveResult{queries: queries, docs: make([][]*schema.Document, len(queries))}
+	if topK := retriever.GetCommonOptions(&retriever.Options{}, opts...).TopK; topK != nil {
+		res.topK = *topK
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	for i, q := range queries {
+		wg.Add(1)
+		go func(i int, q string) {
+			defer wg.Done()
+			var err error
+			defer func() {
+				if panicErr := recover(); panicErr != nil {
+					err = safe.NewPanicErr(panicErr, debug.Stack())
+				}
+				if err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = fmt.Errorf("retrieve query[%s] failed: %w", q, err)
+					}
+					mu.Unlock()
+					cancel()
+				}
+			}()
+
+			res.docs[i], err = m.retrieveQuery(ctx, q, opts)
+		}(i, q)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	return res, nil
+}
+
+// collectQueries 合并原始查询与改写查询，去除空白与重复项，改写查询最多保留 maxQueriesNum 个。
+func (m *multiQueryRetriever) collectQueries(original string, rewritten []string) []string {
+	seen := make(map[string]bool, len(rewritten)+1)
+	queries := make([]string, 0, len(rewritten)+1)
+	if original = strings.TrimSpace(original); !m.excludeOriginalQuery && original != "" {
+		queries = append(queries, original)
+	}
+	seen[original] = true
+
+	count := 0
+	for _, q := range rewritten {
+		if count >= m.maxQueriesNum {
+			break
+		}
+		q = strings.TrimSpace(q)
+		if q == "" || seen[q] {
+			continue
+		}
+		seen[q] = true
+		queries = append(queries, q)
+		count++
+	}
+
+	return queries
+}
+
+// retrieveQuery 调用被包装的检索器。
+func (m *multiQueryRetriever) retrieveQuery(ctx context.Context, query string, opts []retriever.Option) ([]*schema.Document, error) {
+	options := retriever.GetCommonOptions(&retriever.Options{}, opts...)
+	input := &retriever.CallbackInput{Query: query, ScoreThreshold: options.ScoreThreshold}
+	if options.TopK != nil {
+		input.TopK = *options.TopK
+	}
+
+	return delegate.Invoke(ctx, m.origRetriever, &callbacks.RunInfo{Component: components.ComponentOfRetriever}, input,
+		func(ctx context.Context) ([]*schema.Document, error) {
+			return m.origRetriever.Retrieve(ctx, query, opts...)
+		},
+		func(docs []*schema.Document) callbacks.CallbackOutput {
+			return &retriever.CallbackOutput{Docs: docs}
+		})
+}
+
+func (m *multiQueryRetriever) fuse(ctx context.Context, res *retrieveResult) ([]*schema.Document, error) {
+	docs, err := m.fusionFunc(ctx, res.queries, res.docs)
+	if err != nil {
+		return nil, err
+	}
+	if res.topK > 0 && len(docs) > res.topK {
+		docs = docs[:res.topK]
+	}
+
+	return docs, nil
+}
+
+// GetType 返回组件类型名称，用于回调的 RunInfo。
+func (m *multiQueryRetriever) GetType() string {
+	return "MultiQuery"
+}
+
+// IsCallbacksEnabled 多查询检索器通过内部编排图自行触发回调，编排框架无需再包装。
+func (m *multiQueryRetriever) IsCallbacksEnabled() bool {
+	return true
+}
+
+// RRFFusion 以倒数排名融合（RRF）合并各查询的结果，返回按融合分数降序排列的文档副本。
+// 文档按 ID 去重，ID 为空时按内容去重；同一文档以最先出现时的内容为准，分数为 Σ 1/(60+rank)。
+func RRFFusion(_ context.Context, _ []string, docs [][]*schema.Document) ([]*schema.Document, error) {
+	return fusion.RRF(docs, 0), nil
+}
+
+// listPrefix 匹配行首的编号或列表符号，如 "1."、"2)"、"-"、"*"。
+var listPrefix = regexp.MustCompile(`^\s*(?:\d+[.)、]|[-*•])\s*`)
+
+// defaultOutputParser 按行切分模型输出，去除行首编号与空行。
+func defaultOutputParser(_ context.Context, output *schema.Message) ([]string, error) {
+	if output == nil {
+		return nil, errors.New("rewrite model output is nil")
+	}
+
+	var queries []string
+	for _, line := range strings.Split(output.Content, "\n") {
+		line = strings.TrimSpace(listPrefix.ReplaceAllString(line, ""))
+		if line != "" {
+			queries = append(queries, line)
+		}
+	}
+
+	return queries, nil
+}
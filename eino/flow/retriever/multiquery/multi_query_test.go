@@ -0,0 +1,187 @@
+package multiquery
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/favbox/eino/callbacks"
+	"github.com/favbox/eino/components"
+	"github.com/favbox/eino/components/model"
+	"github.com/favbox/eino/components/prompt"
+	"github.com/favbox/eino/components/retriever"
+	"github.com/favbox/eino/compose"
+	mockModel "github.com/favbox/eino/internal/mock/components/model"
+	"github.com/favbox/eino/schema"
+)
+
+// mockRetriever 按查询返回预设文档，并记录收到的查询。
+type mockRetriever struct {
+	mu      sync.Mutex
+	docs    map[string][]*schema.Document
+	errs    map[string]error
+	queries []string
+	topK    []int
+}
+
+func (m *mockRetriever) Retrieve(_ context.Context, query string, opts ...retriever.Option) ([]*schema.Document, error) {
+	options := retriever.GetCommonOptions(&retriever.Options{}, opts...)
+	m.mu.Lock()
+	m.queries = append(m.queries, query)
+	if options.TopK != nil {
+		m.topK = append(m.topK, *options.TopK)
+	}
+	m.mu.Unlock()
+	if err := m.errs[query]; err != nil {
+		return nil, err
+	}
+	return m.docs[query], nil
+}
+
+func TestMultiQueryRetriever(t *testing.T) {
+	ctx := context.Background()
+	newRetriever := func() *mockRetriever {
+		return &mockRetriever{docs: map[string][]*schema.Document{
+			"退货":   {{ID: "a"}, {ID: "b"}},
+			"如何退货": {{ID: "b"}, {ID: "c"}},
+			"退款流程": {{ID: "b"}, {ID: "d"}},
+		}}
+	}
+
+	t.Run("模型改写并融合结果", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		cm := mockModel.NewMockBaseChatModel(ctrl)
+		cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, input []*schema.Message, _ ...model.Option) (*schema.Message, error) {
+				assert.Len(t, input, 1)
+				assert.Contains(t, input[0].Content, "生成 2 个")
+				assert.Contains(t, input[0].Content, "用户问题：退货")
+				return schema.AssistantMessage("1. 如何退货\n\n2) 退款流程\n- 退货\n- 多余的查询", nil), nil
+			}).Times(1)
+
+		orig := newRetriever()
+		r, err := NewRetriever(ctx, &Config{OrigRetriever: orig, RewriteLLM: cm, MaxQueriesNum: 2})
+		assert.NoError(t, err)
+
+		docs, err := r.Retrieve(ctx, "退货", retriever.WithTopK(3))
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"退货", "如何退货", "退款流程"}, orig.queries)
+		assert.Equal(t, []int{3, 3, 3}, orig.topK)
+		assert.Len(t, docs, 3)
+		assert.Equal(t, "b", docs[0].ID)
+		assert.InDelta(t, 2/61.0+1/62.0, docs[0].Score(), 1e-9)
+	})
+
+	t.Run("自定义改写与融合", func(t *testing.T) {
+		orig := newRetriever()
+		var fusedQueries []string
+		r, err := NewRetriever(ctx, &Config{
+			OrigRetriever: orig,
+			RewriteHandler: func(_ context.Context, query string) ([]string, error) {
+				return []string{"如何" + query}, nil
+			},
+			ExcludeOriginalQuery: true,
+			FusionFunc: func(_ context.Context, queries []string, docs [][]*schema.Document) ([]*schema.Document, error) {
+				fusedQueries = queries
+				return docs[0], nil
+			},
+		})
+		assert.NoError(t, err)
+
+		docs, err := r.Retrieve(ctx, "退货")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"如何退货"}, fusedQueries)
+		assert.Equal(t, []string{"如何退货"}, orig.queries)
+		assert.Len(t, docs, 2)
+	})
+
+	t.Run("自定义模板与解析", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		cm := mockModel.NewMockBaseChatModel(ctrl)
+		cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, input []*schema.Message, _ ...model.Option) (*schema.Message, error) {
+				assert.Equal(t, "question: 退货", input[0].Content)
+				return schema.AssistantMessage("如何退货|退款流程", nil), nil
+			}).Times(1)
+
+		orig := newRetriever()
+		r, err := NewRetriever(ctx, &Config{
+			OrigRetriever:   orig,
+			RewriteLLM:      cm,
+			RewriteTemplate: prompt.FromMessages(schema.FString, schema.UserMessage("question: {question}")),
+			QueryVar:        "question",
+			LLMOutputParser: func(_ context.Context, output *schema.Message) ([]string, error) {
+				return strings.Split(output.Content, "|"), nil
+			},
+		})
+		assert.NoError(t, err)
+
+		docs, err := r.Retrieve(ctx, "退货")
+		assert.NoError(t, err)
+		assert.Len(t, orig.queries, 3)
+		assert.Len(t, docs, 4)
+	})
+
+	t.Run("检索失败", func(t *testing.T) {
+		orig := newRetriever()
+		orig.errs = map[string]error{"如何退货": errors.New("boom")}
+		r, err := NewRetriever(ctx, &Config{
+			OrigRetriever: orig,
+			RewriteHandler: func(_ context.Context, query string) ([]string, error) {
+				return []string{"如何" + query}, nil
+			},
+		})
+		assert.NoError(t, err)
+
+		_, err = r.Retrieve(ctx, "退货")
+		assert.ErrorContains(t, err, "retrieve query[如何退货] failed: boom")
+	})
+
+	t.Run("配置校验", func(t *testing.T) {
+		_, err := NewRetriever(ctx, nil)
+		assert.Error(t, err)
+		_, err = NewRetriever(ctx, &Config{RewriteHandler: func(context.Context, string) ([]string, error) { return nil, nil }})
+		assert.ErrorContains(t, err, "OrigRetriever")
+		_, err = NewRetriever(ctx, &Config{OrigRetriever: newRetriever()})
+		assert.ErrorContains(t, err, "RewriteLLM")
+	})
+
+	t.Run("作为链中的检索器节点", func(t *testing.T) {
+		orig := newRetriever()
+		r, err := NewRetriever(ctx, &Config{
+			OrigRetriever: orig,
+			RewriteHandler: func(_ context.Context, query string) ([]string, error) {
+				return []string{"如何" + query}, nil
+			},
+		})
+		assert.NoError(t, err)
+
+		var (
+			mu              sync.Mutex
+			retrieverStarts []string
+		)
+		handler := callbacks.NewHandlerBuilder().OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+			if info.Component == components.ComponentOfRetriever && info.Type == "" {
+				mu.Lock()
+				retrieverStarts = append(retrieverStarts, retriever.ConvCallbackInput(input).Query)
+				mu.Unlock()
+			}
+			return ctx
+		}).Build()
+
+		chain := compose.NewChain[string, []*schema.Document]()
+		chain.AppendRetriever(r)
+		run, err := chain.Compile(ctx)
+		assert.NoError(t, err)
+
+		docs, err := run.Invoke(ctx, "退货", compose.WithCallbacks(handler))
+		assert.NoError(t, err)
+		assert.Len(t, docs, 3)
+		assert.ElementsMatch(t, []string{"退货", "如何退货"}, retrieverStarts)
+	})
+}
@@ -0,0 +1,64 @@
+// Package delegate 供包装其他组件的实现（如融合检索、缓存、重试）调用被包装的组件。
+//
+// 调用时以被包装组件自身的类型作为运行信息；被包装组件未实现 components.Checker 或未开启回调时，
+// 由此以给定的输入输出补齐 OnStart、OnEnd 与 OnError 回调，使链路追踪中能看到每一次内部调用。
+package delegate
+
+import (
+	"context"
+
+	"github.com/favbox/eino/callbacks"
+	"github.com/favbox/eino/components"
+	"github.com/favbox/eino/schema"
+)
+
+// runContext 以 info 中的名称、组件分类与 component 自身的类型作为运行信息。
+func runContext(ctx context.Context, component any, info *callbacks.RunInfo) context.Context {
+	typ, _ := components.GetType(component)
+	return callbacks.ReuseHandlers(ctx, &callbacks.RunInfo{Name: info.Name, Type: typ, Component: info.Component})
+}
+
+// Invoke 调用被包装的组件 component，run 执行实际调用，output 将结果转换为回调输出。
+func Invoke[T any](ctx context.Context, component any, info *callbacks.RunInfo, input callbacks.CallbackInput,
+	run func(ctx context.Context) (T, error), output func(T) callbacks.CallbackOutput) (T, error) {
+
+	ctx = runContext(ctx, component, info)
+	if components.IsCallbacksEnabled(component) {
+		return run(ctx)
+	}
+
+	ctx = callbacks.OnStart(ctx, input)
+	out, err := run(ctx)
+	if err != nil {
+		_ = callbacks.OnError(ctx, err)
+		var zero T
+		return zero, err
+	}
+	_ = callbacks.OnEnd(ctx, output(out))
+
+	return out, nil
+}
+
+// Stream 以流式方式调用被包装的组件 component，output 将每个数据块转换为回调输出。
+func Stream[T any](ctx context.Context, component any, info *callbacks.RunInfo, input callbacks.CallbackInput,
+	run func(ctx context.Context) (*schema.StreamReader[T], error), output func(T) callbacks.CallbackOutput) (*schema.StreamReader[T], error) {
+
+	ctx = runContext(ctx, component, info)
+	if components.IsCallbacksEnabled(component) {
+		return run(ctx)
+	}
+
+	ctx = callbacks.OnStart(ctx, input)
+	sr, err := run(ctx)
+	if err != nil {
+		_ = callbacks.OnError(ctx, err)
+		return nil, err
+	}
+
+	srs := sr.Copy(2)
+	_, _ = callbacks.OnEndWithStreamOutput(ctx, schema.StreamReaderWithConvert(srs[0], func(chunk T) (callbacks.CallbackOutput, error) {
+		return output(chunk), nil
+	}))
+
+	return srs[1], nil
+}
@@ -0,0 +1,99 @@
+package delegate
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/favbox/eino/callbacks"
+	"github.com/favbox/eino/components"
+	"github.com/favbox/eino/schema"
+)
+
+type plain struct{}
+
+func (plain) GetType() string { return "Plain" }
+
+type enabled struct{}
+
+func (enabled) GetType() string          { return "Enabled" }
+func (enabled) IsCallbacksEnabled() bool { return true }
+
+func TestInvoke(t *testing.T) {
+	var events []string
+	handler := callbacks.NewHandlerBuilder().
+		OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+			events = append(events, "start:"+info.Name+":"+info.Type+":"+input.(string))
+			return ctx
+		}).
+		OnEndFn(func(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+			events = append(events, "end:"+output.(string))
+			return ctx
+		}).
+		OnErrorFn(func(ctx context.Context, info *callbacks.RunInfo, err error) context.Context {
+			events = append(events, "error:"+err.Error())
+			return ctx
+		}).
+		OnEndWithStreamOutputFn(func(ctx context.Context, info *callbacks.RunInfo, output *schema.StreamReader[callbacks.CallbackOutput]) context.Context {
+			defer output.Close()
+			for {
+				chunk, err := output.Recv()
+				if err == io.EOF {
+					return ctx
+				}
+				events = append(events, "chunk:"+chunk.(string))
+			}
+		}).Build()
+	ctx := callbacks.InitCallbacks(context.Background(), &callbacks.RunInfo{Type: "Outer"}, handler)
+	info := &callbacks.RunInfo{Name: "inner", Component: components.ComponentOfRetriever}
+	output := func(s string) callbacks.CallbackOutput { return "out-" + s }
+
+	t.Run("补齐回调", func(t *testing.T) {
+		events = nil
+		out, err := Invoke(ctx, plain{}, info, "in", func(ctx context.Context) (string, error) {
+			return "ok", nil
+		}, output)
+		assert.NoError(t, err)
+		assert.Equal(t, "ok", out)
+		assert.Equal(t, []string{"start:inner:Plain:in", "end:out-ok"}, events)
+
+		events = nil
+		_, err = Invoke(ctx, plain{}, info, "in", func(ctx context.Context) (string, error) {
+			return "", errors.New("boom")
+		}, output)
+		assert.EqualError(t, err, "boom")
+		assert.Equal(t, []string{"start:inner:Plain:in", "error:boom"}, events)
+	})
+
+	t.Run("组件自行触发回调", func(t *testing.T) {
+		events = nil
+		out, err := Invoke(ctx, enabled{}, info, "in", func(ctx context.Context) (string, error) {
+			return "ok", nil
+		}, output)
+		assert.NoError(t, err)
+		assert.Equal(t, "ok", out)
+		assert.Empty(t, events)
+	})
+
+	t.Run("流式", func(t *testing.T) {
+		events = nil
+		sr, err := Stream(ctx, plain{}, info, "in", func(ctx context.Context) (*schema.StreamReader[string], error) {
+			return schema.StreamReaderFromArray([]string{"a", "b"}), nil
+		}, output)
+		assert.NoError(t, err)
+		defer sr.Close()
+		var chunks []string
+		for {
+			chunk, err := sr.Recv()
+			if err == io.EOF {
+				break
+			}
+			chunks = append(chunks, chunk)
+		}
+		assert.Equal(t, []string{"a", "b"}, chunks)
+		assert.Equal(t, []string{"start:inner:Plain:in", "chunk:out-a", "chunk:out-b"}, events)
+	})
+}
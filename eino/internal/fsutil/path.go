@@ -0,0 +1,15 @@
+package fsutil
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// ShardedPath 返回 key 对应的缓存文件路径 dir/<key 前两个字符>/<key><ext>，避免单个目录下文件过多。
+// key 过短或包含路径分隔符时返回错误，防止写到 dir 之外。
+func ShardedPath(dir, key, ext string) (string, error) {
+	if len(key) < 3 || filepath.Base(key) != key {
+		return "", fmt.Errorf("invalid cache key: %q", key)
+	}
+	return filepath.Join(dir, key[:2], key+ext), nil
+}
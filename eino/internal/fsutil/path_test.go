@@ -0,0 +1,19 @@
+package fsutil
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShardedPath(t *testing.T) {
+	path, err := ShardedPath("cache", "abcdef", ".json")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join("cache", "ab", "abcdef.json"), path)
+
+	for _, key := range []string{"", "ab", "../etc", "a/bcd"} {
+		_, err = ShardedPath("cache", key, "")
+		assert.ErrorContains(t, err, "invalid cache key")
+	}
+}
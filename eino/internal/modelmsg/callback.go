@@ -0,0 +1,29 @@
+package modelmsg
+
+import (
+	"github.com/favbox/eino/components/model"
+	"github.com/favbox/eino/schema"
+)
+
+// CallbackInput 以请求消息与调用选项构造 model.CallbackInput，供模型包装器为被包装模型补齐回调。
+func CallbackInput(input []*schema.Message, options *model.Options) *model.CallbackInput {
+	conf := &model.Config{Stop: options.Stop}
+	if options.Model != nil {
+		conf.Model = *options.Model
+	}
+	if options.MaxTokens != nil {
+		conf.MaxTokens = *options.MaxTokens
+	}
+	if options.Temperature != nil {
+		conf.Temperature = *options.Temperature
+	}
+	if options.TopP != nil {
+		conf.TopP = *options.TopP
+	}
+	return &model.CallbackInput{
+		Messages:   input,
+		Tools:      options.Tools,
+		ToolChoice: options.ToolChoice,
+		Config:     conf,
+	}
+}
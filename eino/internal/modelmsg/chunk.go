@@ -0,0 +1,81 @@
+package modelmsg
+
+import (
+	"github.com/favbox/eino/schema"
+)
+
+// Chunk 将完整消息拆分为可由 schema.ConcatMessages 合并还原的流式数据块，规则见 modeltest.ChunkMessage。
+func Chunk(msg *schema.Message, chunkSize int) []*schema.Message {
+	if msg == nil {
+		return nil
+	}
+	if chunkSize <= 0 {
+		cp := *msg
+		return []*schema.Message{&cp}
+	}
+
+	newChunk := func() *schema.Message {
+		return &schema.Message{
+			Role:       msg.Role,
+			Name:       msg.Name,
+			ToolCallID: msg.ToolCallID,
+			ToolName:   msg.ToolName,
+		}
+	}
+
+	var chunks []*schema.Message
+	for _, part := range splitRunes(msg.ReasoningContent, chunkSize) {
+		c := newChunk()
+		c.ReasoningContent = part
+		chunks = append(chunks, c)
+	}
+	for _, part := range splitRunes(msg.Content, chunkSize) {
+		c := newChunk()
+		c.Content = part
+		chunks = append(chunks, c)
+	}
+	for i, tc := range msg.ToolCalls {
+		index := i
+		if tc.Index != nil {
+			index = *tc.Index
+		}
+		parts := splitRunes(tc.Function.Arguments, chunkSize)
+		if len(parts) == 0 {
+			parts = []string{""}
+		}
+		for j, part := range parts {
+			call := schema.ToolCall{Index: &index, Function: schema.FunctionCall{Arguments: part}}
+			if j == 0 {
+				call.ID, call.Type, call.Function.Name, call.Extra = tc.ID, tc.Type, tc.Function.Name, tc.Extra
+			}
+			c := newChunk()
+			c.ToolCalls = []schema.ToolCall{call}
+			chunks = append(chunks, c)
+		}
+	}
+
+	last := newChunk()
+	if len(chunks) > 0 && len(msg.AssistantGenMultiContent) == 0 {
+		last = chunks[len(chunks)-1]
+	} else {
+		chunks = append(chunks, last)
+	}
+	last.AssistantGenMultiContent = msg.AssistantGenMultiContent
+	last.ResponseMeta = msg.ResponseMeta
+	last.Extra = msg.Extra
+
+	return chunks
+}
+
+// splitRunes 按字符数切分字符串，空字符串返回 nil。
+func splitRunes(s string, size int) []string {
+	if s == "" {
+		return nil
+	}
+	runes := []rune(s)
+	parts := make([]string, 0, (len(runes)+size-1)/size)
+	for start := 0; start < len(runes); start += size {
+		parts = append(parts, string(runes[start:min(start+size, len(runes))]))
+	}
+	return parts
+}
@@ -0,0 +1,164 @@
+// Package modelmsg 提供聊天模型请求的规范化、哈希、响应拆分与回调输入构造，供模型包装器计算缓存或录制的键并补齐回调。
+package modelmsg
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/favbox/eino/schema"
+)
+
+// Message 参与匹配的消息字段。
+//
+// 工具调用 ID、Index、ResponseMeta、推理内容与 Extra 在不同运行间可能变化，不参与匹配；
+// 正文去除首尾空白，工具调用参数若为合法 JSON 则去除多余空白。
+type Message struct {
+	Role      schema.RoleType `json:"role"`
+	Name      string          `json:"name,omitempty"`
+	Content   string          `json:"content,omitempty"`
+	Parts     []Part          `json:"parts,omitempty"`
+	ToolCalls []ToolCall      `json:"tool_calls,omitempty"`
+	ToolName  string          `json:"tool_name,omitempty"`
+}
+
+// Part 参与匹配的多模态输入片段。
+type Part struct {
+	Type schema.ChatMessagePartType `json:"type"`
+	Text string                     `json:"text,omitempty"`
+	URL  string                     `json:"url,omitempty"`
+}
+
+// ToolCall 参与匹配的工具调用。
+type ToolCall struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// Tool 参与匹配的工具定义，Params 为参数的 JSON Schema。
+type Tool struct {
+	Name   string          `json:"name"`
+	Desc   string          `json:"desc,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// NormalizeMessages 规范化输入消息，忽略 nil 消息。
+func NormalizeMessages(input []*schema.Message) []Message {
+	msgs := make([]Message, 0, len(input))
+	for _, msg := range input {
+		if msg == nil {
+			continue
+		}
+		nm := Message{
+			Role:     msg.Role,
+			Name:     msg.Name,
+			Content:  strings.TrimSpace(msg.Content),
+			ToolName: msg.ToolName,
+		}
+		for _, part := range msg.UserInputMultiContent {
+			np := Part{Type: part.Type, Text: strings.TrimSpace(part.Text)}
+			if part.Image != nil && part.Image.URL != nil {
+				np.URL = *part.Image.URL
+			}
+			nm.Parts = append(nm.Parts, np)
+		}
+		for _, tc := range msg.ToolCalls {
+			nm.ToolCalls = append(nm.ToolCalls, ToolCall{Name: tc.Function.Name, Arguments: compactJSON(tc.Function.Arguments)})
+		}
+		msgs = append(msgs, nm)
+	}
+	return msgs
+}
+
+// NormalizeTools 规范化工具定义，忽略 nil 工具。
+func NormalizeTools(tools []*schema.ToolInfo) ([]Tool, error) {
+	var nts []Tool
+	for _, tool := range tools {
+		if tool == nil {
+			continue
+		}
+		nt := Tool{Name: tool.Name, Desc: strings.TrimSpace(tool.Desc)}
+		if tool.ParamsOneOf != nil {
+			sc, err := tool.ToJSONSchema()
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert params of tool[%s] to json schema: %w", tool.Name, err)
+			}
+			if nt.Params, err = canonicalJSON(sc); err != nil {
+				return nil, fmt.Errorf("failed to marshal json schema of tool[%s]: %w", tool.Name, err)
+			}
+		}
+		nts = append(nts, nt)
+	}
+	return nts, nil
+}
+
+// Hash 返回 v 的 JSON 序列化结果的十六进制 SHA-256。
+func Hash(v any) (string, error) {
+	data, err := sonic.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("marshal normalized request failed: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// canonicalJSON 将 v 序列化为确定的 JSON：对象的键按字典序排列，required 列表排序。
+// 由 schema.NewParamsOneOfByParams 生成的 JSON Schema 中属性与 required 的顺序取决于 map 遍历顺序，
+// 不规范化会使同一工具每次得到不同的键。
+func canonicalJSON(v any) (json.RawMessage, error) {
+	data, err := sonic.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var generic any
+	if err = dec.Decode(&generic); err != nil {
+		return nil, err
+	}
+	sortRequired(generic)
+
+	return json.Marshal(generic) // encoding/json 按字典序输出 map 的键
+}
+
+// sortRequired 递归排序 JSON 对象中 required 字段的字符串列表。
+func sortRequired(v any) {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, child := range t {
+			if names, ok := child.([]any); ok && k == "required" {
+				sortStrings(names)
+				continue
+			}
+			sortRequired(child)
+		}
+	case []any:
+		for _, child := range t {
+			sortRequired(child)
+		}
+	}
+}
+
+// sortStrings 在元素全部为字符串时原地排序。
+func sortStrings(items []any) {
+	for _, item := range items {
+		if _, ok := item.(string); !ok {
+			return
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].(string) < items[j].(string) })
+}
+
+func compactJSON(s string) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, []byte(s)); err != nil {
+		return strings.TrimSpace(s)
+	}
+	return buf.String()
+}
@@ -0,0 +1,41 @@
+package modelmsg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/favbox/eino/schema"
+)
+
+func TestNormalizeToolsDeterministic(t *testing.T) {
+	tool := &schema.ToolInfo{
+		Name: "search_order",
+		Desc: "查询订单",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"order_id": {Type: schema.String, Required: true},
+			"user_id":  {Type: schema.String, Required: true},
+			"status":   {Type: schema.String, Enum: []string{"paid", "shipped"}},
+			"page":     {Type: schema.Integer, Required: true},
+			"filter": {Type: schema.Object, SubParams: map[string]*schema.ParameterInfo{
+				"from": {Type: schema.String, Required: true},
+				"to":   {Type: schema.String, Required: true},
+				"tags": {Type: schema.Array, ElemInfo: &schema.ParameterInfo{Type: schema.String}},
+			}},
+		}),
+	}
+
+	keys := make(map[string]bool)
+	for i := 0; i < 50; i++ {
+		nts, err := NormalizeTools([]*schema.ToolInfo{tool})
+		assert.NoError(t, err)
+		key, err := Hash(nts)
+		assert.NoError(t, err)
+		keys[key] = true
+	}
+	assert.Len(t, keys, 1)
+
+	nts, err := NormalizeTools([]*schema.ToolInfo{tool})
+	assert.NoError(t, err)
+	assert.Contains(t, string(nts[0].Params), `"required":["order_id","page","user_id"]`)
+}
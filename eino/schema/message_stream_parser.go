@@ -0,0 +1,446 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime/debug"
+	"strings"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/favbox/eino/internal/safe"
+)
+
+// MessageStreamParser - 流式消息解析器接口，将消息块流解析为逐步完整的对象快照流。
+type MessageStreamParser[T any] interface {
+	ParseStream(ctx context.Context, sr *StreamReader[*Message]) *StreamReader[T]
+}
+
+// NewMessageJSONStreamParser 创建一个新的 MessageJSONStreamParser，配置与 NewMessageJSONParser 相同。
+func NewMessageJSONStreamParser[T any](config *MessageJSONParseConfig) MessageStreamParser[T] {
+	if config == nil {
+		config = &MessageJSONParseConfig{}
+	}
+
+	if config.ParseFrom == "" {
+		config.ParseFrom = MessageParseFromContent
+	}
+
+	return &MessageJSONStreamParser[T]{
+		ParseFrom:    config.ParseFrom,
+		ParseKeyPath: config.ParseKeyPath,
+	}
+}
+
+// MessageJSONStreamParser - 增量 JSON 消息解析器。
+// 每收到一个消息块，就把已累积的不完整 JSON 补全后解析为对象快照，快照变化时才向下游输出；
+// 流结束时对完整数据做一次严格解析，失败则以错误结束输出流。
+//
+// 从工具调用解析时，只累积第一个工具调用（按 Index 归并）的参数，与 MessageJSONParser 保持一致。
+type MessageJSONStreamParser[T any] struct {
+	ParseFrom    MessageParseFrom // 解析数据来源
+	ParseKeyPath string           // JSON字段路径
+}
+
+// ParseStream - 消费消息块流并返回对象快照流，调用方负责关闭返回的流，输入流会在解析结束后被关闭。
+func (p *MessageJSONStreamParser[T]) ParseStream(ctx context.Context, sr *StreamReader[*Message]) *StreamReader[T] {
+	out, sw := Pipe[T](1)
+
+	go func() {
+		defer func() {
+			if panicErr := recover(); panicErr != nil {
+				var zero T
+				_ = sw.Send(zero, safe.NewPanicErr(panicErr, debug.Stack()))
+			}
+
+			sw.Close()
+			sr.Close()
+		}()
+
+		p.run(ctx, sr, sw)
+	}()
+
+	return out
+}
+
+// run - 逐块累积数据并输出快照，直到输入流结束、出错或下游关闭。
+func (p *MessageJSONStreamParser[T]) run(ctx context.Context, sr *StreamReader[*Message], sw *StreamWriter[T]) {
+	var zero T
+	if p.ParseFrom != MessageParseFromContent && p.ParseFrom != MessageParseFromToolCall {
+		_ = sw.Send(zero, fmt.Errorf("无效的解析来源类型: %s", p.ParseFrom))
+		return
+	}
+
+	parser := &MessageJSONParser[T]{ParseFrom: p.ParseFrom, ParseKeyPath: p.ParseKeyPath}
+	acc := &streamDataAccumulator{parseFrom: p.ParseFrom, toolIndex: -1}
+
+	var last string
+	for {
+		chunk, err := sr.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			_ = sw.Send(zero, err)
+			return
+		}
+
+		if !acc.append(chunk) {
+			continue
+		}
+
+		completed, ok := completePartialJSON(acc.data.String())
+		if !ok {
+			continue
+		}
+		extracted, err := parser.extractData(completed)
+		if err != nil || extracted == last {
+			// 目标路径尚未出现或快照未变化
+			continue
+		}
+		var parsed T
+		if err := sonic.UnmarshalString(extracted, &parsed); err != nil {
+			// 补全后的片段暂不满足目标类型，等待更多数据
+			continue
+		}
+
+		last = extracted
+		if closed := sw.Send(parsed, nil); closed {
+			return
+		}
+	}
+
+	if p.ParseFrom == MessageParseFromToolCall && acc.toolIndex < 0 {
+		_ = sw.Send(zero, fmt.Errorf("消息中未找到工具调用信息"))
+		return
+	}
+
+	// 流结束后严格解析完整数据，确保最终快照与 MessageJSONParser 的结果一致
+	data := acc.data.String()
+	extracted, err := parser.extractData(data)
+	if err != nil {
+		_ = sw.Send(zero, err)
+		return
+	}
+	if extracted == last {
+		return
+	}
+	var parsed T
+	if err := sonic.UnmarshalString(extracted, &parsed); err != nil {
+		_ = sw.Send(zero, fmt.Errorf("JSON反序列化失败: %w", err))
+		return
+	}
+	_ = sw.Send(parsed, nil)
+}
+
+// streamDataAccumulator - 按解析来源累积消息块中的 JSON 文本。
+type streamDataAccumulator struct {
+	parseFrom MessageParseFrom
+	toolIndex int // 目标工具调用的索引，-1 表示尚未出现
+	data      strings.Builder
+}
+
+// append - 累积消息块中的数据，返回是否有新增内容。
+func (a *streamDataAccumulator) append(m *Message) bool {
+	if m == nil {
+		return false
+	}
+
+	if a.parseFrom == MessageParseFromContent {
+		a.data.WriteString(m.Content)
+		return len(m.Content) > 0
+	}
+
+	appended := false
+	for i, tc := range m.ToolCalls {
+		idx := i
+		if tc.Index != nil {
+			idx = *tc.Index
+		}
+		if a.toolIndex < 0 {
+			a.toolIndex = idx
+		}
+		if idx != a.toolIndex {
+			continue
+		}
+
+		a.data.WriteString(tc.Function.Arguments)
+		appended = appended || len(tc.Function.Arguments) > 0
+	}
+
+	return appended
+}
+
+// completePartialJSON 将不完整的 JSON 文本补全为合法 JSON：
+// 闭合未结束的字符串、数组和对象，丢弃尚无值的键、残缺的字面量与数字尾部。
+// 文本为空、尚无可用值或存在语法错误时返回 false。
+func completePartialJSON(s string) (string, bool) {
+	c := &jsonCompleter{s: s}
+	c.skipSpace()
+
+	var sb strings.Builder
+	st := c.value(&sb)
+	if st == jsonInvalid || st == jsonEmpty {
+		return "", false
+	}
+	if st == jsonComplete {
+		// 完整值之后只允许空白
+		c.skipSpace()
+		if c.pos < len(c.s) {
+			return "", false
+		}
+	}
+
+	return sb.String(), true
+}
+
+// jsonState - 补全过程中单个 JSON 值的解析状态。
+type jsonState int
+
+const (
+	jsonComplete jsonState = iota // 值已完整
+	jsonPartial                   // 值在文本末尾被截断，已补全
+	jsonEmpty                     // 文本已结束，尚无可用值
+	jsonInvalid                   // 语法错误
+)
+
+// jsonCompleter - 面向截断文本的递归下降 JSON 扫描器。
+type jsonCompleter struct {
+	s   string
+	pos int
+}
+
+func (c *jsonCompleter) skipSpace() {
+	for c.pos < len(c.s) {
+		switch c.s[c.pos] {
+		case ' ', '\t', '\n', '\r':
+			c.pos++
+		default:
+			return
+		}
+	}
+}
+
+// value - 扫描一个 JSON 值并把补全后的文本写入 sb。
+func (c *jsonCompleter) value(sb *strings.Builder) jsonState {
+	if c.pos >= len(c.s) {
+		return jsonEmpty
+	}
+
+	switch ch := c.s[c.pos]; {
+	case ch == '{':
+		return c.object(sb)
+	case ch == '[':
+		return c.array(sb)
+	case ch == '"':
+		return c.str(sb)
+	case ch == '-' || (ch >= '0' && ch <= '9'):
+		return c.number(sb)
+	case ch == 't' || ch == 'f' || ch == 'n':
+		return c.literal(sb)
+	default:
+		return jsonInvalid
+	}
+}
+
+func (c *jsonCompleter) object(sb *strings.Builder) jsonState {
+	c.pos++ // '{'
+	sb.WriteByte('{')
+
+	first := true
+	for {
+		c.skipSpace()
+		if c.pos >= len(c.s) {
+			sb.WriteByte('}')
+			return jsonPartial
+		}
+		if c.s[c.pos] == '}' && first {
+			c.pos++
+			sb.WriteByte('}')
+			return jsonComplete
+		}
+		if c.s[c.pos] != '"' {
+			return jsonInvalid
+		}
+
+		// 键或值不完整时整个成员都不输出
+		var member strings.Builder
+		if !first {
+			member.WriteByte(',')
+		}
+		if st := c.str(&member); st != jsonComplete {
+			sb.WriteByte('}')
+			return jsonPartial
+		}
+
+		c.skipSpace()
+		if c.pos >= len(c.s) {
+			sb.WriteByte('}')
+			return jsonPartial
+		}
+		if c.s[c.pos] != ':' {
+			return jsonInvalid
+		}
+		c.pos++
+		member.WriteByte(':')
+
+		c.skipSpace()
+		st := c.value(&member)
+		switch st {
+		case jsonInvalid:
+			return jsonInvalid
+		case jsonEmpty:
+			sb.WriteByte('}')
+			return jsonPartial
+		}
+		sb.WriteString(member.String())
+		first = false
+		if st == jsonPartial {
+			sb.WriteByte('}')
+			return jsonPartial
+		}
+
+		c.skipSpace()
+		if c.pos >= len(c.s) {
+			sb.WriteByte('}')
+			return jsonPartial
+		}
+		switch c.s[c.pos] {
+		case ',':
+			c.pos++
+		case '}':
+			c.pos++
+			sb.WriteByte('}')
+			return jsonComplete
+		default:
+			return jsonInvalid
+		}
+	}
+}
+
+func (c *jsonCompleter) array(sb *strings.Builder) jsonState {
+	c.pos++ // '['
+	sb.WriteByte('[')
+
+	first := true
+	for {
+		c.skipSpace()
+		if c.pos >= len(c.s) {
+			sb.WriteByte(']')
+			return jsonPartial
+		}
+		if c.s[c.pos] == ']' && first {
+			c.pos++
+			sb.WriteByte(']')
+			return jsonComplete
+		}
+
+		var elem strings.Builder
+		if !first {
+			elem.WriteByte(',')
+		}
+		st := c.value(&elem)
+		switch st {
+		case jsonInvalid:
+			return jsonInvalid
+		case jsonEmpty:
+			sb.WriteByte(']')
+			return jsonPartial
+		}
+		sb.WriteString(elem.String())
+		first = false
+		if st == jsonPartial {
+			sb.WriteByte(']')
+			return jsonPartial
+		}
+
+		c.skipSpace()
+		if c.pos >= len(c.s) {
+			sb.WriteByte(']')
+			return jsonPartial
+		}
+		switch c.s[c.pos] {
+		case ',':
+			c.pos++
+		case ']':
+			c.pos++
+			sb.WriteByte(']')
+			return jsonComplete
+		default:
+			return jsonInvalid
+		}
+	}
+}
+
+// str - 扫描字符串，截断时丢弃残缺的转义序列并补上结束引号。
+func (c *jsonCompleter) str(sb *strings.Builder) jsonState {
+	start := c.pos
+	c.pos++ // '"'
+	for c.pos < len(c.s) {
+		switch c.s[c.pos] {
+		case '"':
+			c.pos++
+			sb.WriteString(c.s[start:c.pos])
+			return jsonComplete
+		case '\\':
+			n := 2
+			if c.pos+1 < len(c.s) && c.s[c.pos+1] == 'u' {
+				n = 6
+			}
+			if c.pos+n > len(c.s) {
+				sb.WriteString(c.s[start:c.pos])
+				sb.WriteByte('"')
+				c.pos = len(c.s)
+				return jsonPartial
+			}
+			c.pos += n
+		default:
+			c.pos++
+		}
+	}
+
+	sb.WriteString(c.s[start:])
+	sb.WriteByte('"')
+	return jsonPartial
+}
+
+// number - 扫描数字，截断时去掉末尾的符号、小数点与指数标记。
+func (c *jsonCompleter) number(sb *strings.Builder) jsonState {
+	start := c.pos
+	for c.pos < len(c.s) && strings.IndexByte("+-0123456789.eE", c.s[c.pos]) >= 0 {
+		c.pos++
+	}
+	if c.pos < len(c.s) {
+		sb.WriteString(c.s[start:c.pos])
+		return jsonComplete
+	}
+
+	num := strings.TrimRight(c.s[start:], "+-.eE")
+	if num == "" {
+		return jsonEmpty
+	}
+	sb.WriteString(num)
+	return jsonPartial
+}
+
+// literal - 扫描 true/false/null，截断的字面量视为尚无值。
+func (c *jsonCompleter) literal(sb *strings.Builder) jsonState {
+	for _, lit := range []string{"true", "false", "null"} {
+		if lit[0] != c.s[c.pos] {
+			continue
+		}
+		rest := c.s[c.pos:]
+		if strings.HasPrefix(rest, lit) {
+			c.pos += len(lit)
+			sb.WriteString(lit)
+			return jsonComplete
+		}
+		if strings.HasPrefix(lit, rest) {
+			c.pos = len(c.s)
+			return jsonEmpty
+		}
+	}
+
+	return jsonInvalid
+}
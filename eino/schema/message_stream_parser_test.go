@@ -0,0 +1,149 @@
+package schema
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type streamPlan struct {
+	Title string   `json:"title"`
+	Steps []string `json:"steps"`
+	Done  bool     `json:"done"`
+}
+
+func collectSnapshots[T any](t *testing.T, sr *StreamReader[T]) ([]T, error) {
+	t.Helper()
+	defer sr.Close()
+
+	var out []T
+	for {
+		v, err := sr.Recv()
+		if err == io.EOF {
+			return out, nil
+		}
+		if err != nil {
+			return out, err
+		}
+		out = append(out, v)
+	}
+}
+
+func TestCompletePartialJSON(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{in: "", ok: false},
+		{in: "  ", ok: false},
+		{in: "{", want: "{}", ok: true},
+		{in: `{"ti`, want: "{}", ok: true},
+		{in: `{"title"`, want: "{}", ok: true},
+		{in: `{"title":`, want: "{}", ok: true},
+		{in: `{"title":"计`, want: `{"title":"计"}`, ok: true},
+		{in: `{"title":"a\`, want: `{"title":"a"}`, ok: true},
+		{in: `{"title":"a\u00`, want: `{"title":"a"}`, ok: true},
+		{in: `{"title":"a","steps":["x",`, want: `{"title":"a","steps":["x"]}`, ok: true},
+		{in: `{"done":tr`, want: "{}", ok: true},
+		{in: `{"done":true,"n":-`, want: `{"done":true}`, ok: true},
+		{in: `{"n":1.`, want: `{"n":1}`, ok: true},
+		{in: `{"n":12e`, want: `{"n":12}`, ok: true},
+		{in: `[1, [2, {"a": nu`, want: `[1,[2,{}]]`, ok: true},
+		{in: `{"a":1} `, want: `{"a":1}`, ok: true},
+		{in: `{"a":1}}`, ok: false},
+		{in: `{a:1}`, ok: false},
+		{in: `"部分`, want: `"部分"`, ok: true},
+	}
+
+	for _, c := range cases {
+		got, ok := completePartialJSON(c.in)
+		assert.Equal(t, c.ok, ok, c.in)
+		assert.Equal(t, c.want, got, c.in)
+	}
+}
+
+func TestMessageJSONStreamParser(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("从内容增量解析", func(t *testing.T) {
+		chunks := []*Message{
+			AssistantMessage(`{"title":"出`, nil),
+			AssistantMessage(`行计划","st`, nil),
+			AssistantMessage(`eps":["订票",`, nil),
+			AssistantMessage(``, nil),
+			AssistantMessage(`"出发"],"done":tr`, nil),
+			AssistantMessage(`ue}`, nil),
+		}
+
+		parser := NewMessageJSONStreamParser[streamPlan](nil)
+		snapshots, err := collectSnapshots(t, parser.ParseStream(ctx, StreamReaderFromArray(chunks)))
+		assert.NoError(t, err)
+		assert.Equal(t, []streamPlan{
+			{Title: "出"},
+			{Title: "出行计划"},
+			{Title: "出行计划", Steps: []string{"订票"}},
+			{Title: "出行计划", Steps: []string{"订票", "出发"}},
+			{Title: "出行计划", Steps: []string{"订票", "出发"}, Done: true},
+		}, snapshots)
+	})
+
+	t.Run("从工具调用参数增量解析", func(t *testing.T) {
+		idx0, idx1 := 0, 1
+		chunks := []*Message{
+			AssistantMessage("", []ToolCall{{Index: &idx0, ID: "call_1", Function: FunctionCall{Name: "plan"}}}),
+			AssistantMessage("", []ToolCall{{Index: &idx0, Function: FunctionCall{Arguments: `{"plan":{"title":"A"`}}}),
+			AssistantMessage("", []ToolCall{{Index: &idx1, Function: FunctionCall{Arguments: `{"other":1}`}}}),
+			AssistantMessage("", []ToolCall{{Index: &idx0, Function: FunctionCall{Arguments: `,"steps":["s1"]}}`}}}),
+		}
+
+		parser := NewMessageJSONStreamParser[streamPlan](&MessageJSONParseConfig{
+			ParseFrom:    MessageParseFromToolCall,
+			ParseKeyPath: "plan",
+		})
+		snapshots, err := collectSnapshots(t, parser.ParseStream(ctx, StreamReaderFromArray(chunks)))
+		assert.NoError(t, err)
+		assert.Equal(t, []streamPlan{
+			{Title: "A"},
+			{Title: "A", Steps: []string{"s1"}},
+		}, snapshots)
+	})
+
+	t.Run("最终数据不完整时返回错误", func(t *testing.T) {
+		parser := NewMessageJSONStreamParser[streamPlan](nil)
+		snapshots, err := collectSnapshots(t, parser.ParseStream(ctx, StreamReaderFromArray([]*Message{
+			AssistantMessage(`{"title":"x"`, nil),
+		})))
+		assert.Error(t, err)
+		assert.Equal(t, []streamPlan{{Title: "x"}}, snapshots)
+	})
+
+	t.Run("没有工具调用时返回错误", func(t *testing.T) {
+		parser := NewMessageJSONStreamParser[streamPlan](&MessageJSONParseConfig{ParseFrom: MessageParseFromToolCall})
+		_, err := collectSnapshots(t, parser.ParseStream(ctx, StreamReaderFromArray([]*Message{
+			AssistantMessage("hi", nil),
+		})))
+		assert.Error(t, err)
+	})
+
+	t.Run("透传上游错误", func(t *testing.T) {
+		sr, sw := Pipe[*Message](2)
+		sw.Send(AssistantMessage(`{"title":"x"}`, nil), nil)
+		sw.Send(nil, errors.New("upstream"))
+		sw.Close()
+
+		parser := NewMessageJSONStreamParser[streamPlan](nil)
+		snapshots, err := collectSnapshots(t, parser.ParseStream(ctx, sr))
+		assert.EqualError(t, err, "upstream")
+		assert.Equal(t, []streamPlan{{Title: "x"}}, snapshots)
+	})
+
+	t.Run("无效的解析来源", func(t *testing.T) {
+		parser := &MessageJSONStreamParser[streamPlan]{ParseFrom: "invalid"}
+		_, err := collectSnapshots(t, parser.ParseStream(ctx, StreamReaderFromArray([]*Message{})))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,143 @@
+package schema
+
+import (
+	"context"
+)
+
+// TrimConfig - 消息裁剪配置，MaxTurns 与 MaxTokens 可以组合使用，均为 0 时不裁剪。
+//
+// 裁剪以“轮次”为单位：每条用户消息开启新的一轮，直到下一条用户消息为止。
+// 开头连续的系统消息始终保留；带工具调用的助手消息与其后的工具结果消息视为不可拆分的整体。
+type TrimConfig struct {
+	// MaxTurns - 保留最近的轮次数，0 表示不限制。
+	MaxTurns int
+
+	// MaxTokens - token 预算，超出时从最早的轮次开始丢弃，0 表示不限制。
+	// 仅剩一轮仍超出预算时，会继续丢弃该轮中较早的消息，但至少保留最后一组消息。
+	MaxTokens int
+
+	// Tokenizer - 用于统计 token 的分词器，默认使用 EstimateTokenizer。
+	Tokenizer Tokenizer
+}
+
+// TrimMessages 按配置裁剪消息列表，返回新的切片，不修改输入。
+// 开头孤立的工具结果消息（找不到对应的工具调用）会被一并丢弃。
+// 裁剪是尽力而为的：系统消息与最后一组消息本身超出预算时，结果仍可能超出 MaxTokens。
+func TrimMessages(ctx context.Context, msgs []*Message, config *TrimConfig) ([]*Message, error) {
+	if config == nil {
+		config = &TrimConfig{}
+	}
+	tokenizer := config.Tokenizer
+	if tokenizer == nil {
+		tokenizer = NewEstimateTokenizer(nil)
+	}
+
+	sysEnd := 0
+	for sysEnd < len(msgs) && msgs[sysEnd] != nil && msgs[sysEnd].Role == System {
+		sysEnd++
+	}
+	system := msgs[:sysEnd]
+	turns := splitTurns(msgs[sysEnd:])
+
+	if config.MaxTurns > 0 && len(turns) > config.MaxTurns {
+		turns = turns[len(turns)-config.MaxTurns:]
+	}
+
+	if config.MaxTokens > 0 {
+		total, err := CountMessagesTokens(ctx, tokenizer, system)
+		if err != nil {
+			return nil, err
+		}
+
+		turnTokens := make([][]int, len(turns))
+		for i, turn := range turns {
+			turnTokens[i] = make([]int, len(turn))
+			for j, group := range turn {
+				n, err := CountMessagesTokens(ctx, tokenizer, group)
+				if err != nil {
+					return nil, err
+				}
+				turnTokens[i][j] = n
+				total += n
+			}
+		}
+
+		// 先整轮丢弃最早的轮次
+		for len(turns) > 1 && total > config.MaxTokens {
+			for _, n := range turnTokens[0] {
+				total -= n
+			}
+			turns, turnTokens = turns[1:], turnTokens[1:]
+		}
+
+		// 仅剩一轮时按消息组丢弃
+		if len(turns) == 1 {
+			turn, tokens := turns[0], turnTokens[0]
+			for len(turn) > 1 && total > config.MaxTokens {
+				total -= tokens[0]
+				turn, tokens = turn[1:], tokens[1:]
+			}
+			turns[0] = turn
+		}
+	}
+
+	result := make([]*Message, 0, len(msgs))
+	result = append(result, system...)
+	leading := true
+	for _, turn := range turns {
+		for _, group := range turn {
+			if leading && group[0].Role == Tool {
+				continue
+			}
+			leading = false
+			result = append(result, group...)
+		}
+	}
+
+	return result, nil
+}
+
+// NewMessageTrimmer 创建消息裁剪函数，可直接用于 adk.WithHistoryModifier 与 react.MessageModifier。
+// 分词器返回错误时原样返回输入消息。
+func NewMessageTrimmer(config *TrimConfig) func(ctx context.Context, msgs []*Message) []*Message {
+	return func(ctx context.Context, msgs []*Message) []*Message {
+		trimmed, err := TrimMessages(ctx, msgs, config)
+		if err != nil {
+			return msgs
+		}
+		return trimmed
+	}
+}
+
+// splitTurns 将消息划分为轮次，每个轮次由若干不可拆分的消息组构成。
+// 带工具调用的助手消息会与其后连续的工具结果消息归为一组，
+// 孤立的工具结果消息并入前一组；nil 消息会被忽略。
+func splitTurns(msgs []*Message) [][][]*Message {
+	var (
+		turns [][][]*Message
+		turn  [][]*Message
+	)
+
+	for _, m := range msgs {
+		if m == nil {
+			continue
+		}
+
+		if m.Role == Tool && len(turn) > 0 {
+			last := len(turn) - 1
+			turn[last] = append(turn[last], m)
+			continue
+		}
+
+		if m.Role == User && len(turn) > 0 {
+			turns = append(turns, turn)
+			turn = nil
+		}
+		turn = append(turn, []*Message{m})
+	}
+	if len(turn) > 0 {
+		turns = append(turns, turn)
+	}
+
+	return turns
+}
@@ -0,0 +1,116 @@
+package schema
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fixedTokenizer 每条消息固定计为 1 个 token
+type fixedTokenizer struct {
+	err error
+}
+
+func (f *fixedTokenizer) CountTokens(_ context.Context, _ *Message) (int, error) {
+	return 1, f.err
+}
+
+func TestEstimateTokenizer(t *testing.T) {
+	ctx := context.Background()
+	tk := NewEstimateTokenizer(nil)
+
+	n, err := tk.CountTokens(ctx, UserMessage("abcdefgh"))
+	assert.NoError(t, err)
+	assert.Equal(t, 2+4, n)
+
+	n, err = tk.CountTokens(ctx, UserMessage("你好世界"))
+	assert.NoError(t, err)
+	assert.Equal(t, 4+4, n)
+
+	n, err = tk.CountTokens(ctx, AssistantMessage("", []ToolCall{{Function: FunctionCall{Name: "ab", Arguments: "cd"}}}))
+	assert.NoError(t, err)
+	assert.Equal(t, 1+4, n)
+
+	url := "https://example.com/a.png"
+	n, err = tk.CountTokens(ctx, &Message{Role: User, UserInputMultiContent: []MessageInputPart{
+		{Type: ChatMessagePartTypeText, Text: "abcd"},
+		{Type: ChatMessagePartTypeImageURL, Image: &MessageInputImage{MessagePartCommon: MessagePartCommon{URL: &url}}},
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, 1+256+4, n)
+
+	tk = NewEstimateTokenizer(&EstimateTokenizerConfig{ASCIICharsPerToken: 2, MessageOverhead: 1})
+	n, err = tk.CountTokens(ctx, UserMessage("abcdefgh"))
+	assert.NoError(t, err)
+	assert.Equal(t, 4+1, n)
+
+	total, err := CountMessagesTokens(ctx, &fixedTokenizer{}, []*Message{UserMessage("a"), UserMessage("b")})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, total)
+}
+
+func TestTrimMessages(t *testing.T) {
+	ctx := context.Background()
+
+	sys := SystemMessage("sys")
+	u1 := UserMessage("u1")
+	call := AssistantMessage("", []ToolCall{{ID: "c1", Function: FunctionCall{Name: "f"}}, {ID: "c2", Function: FunctionCall{Name: "g"}}})
+	r1 := ToolMessage("r1", "c1")
+	r2 := ToolMessage("r2", "c2")
+	a1 := AssistantMessage("a1", nil)
+	u2 := UserMessage("u2")
+	a2 := AssistantMessage("a2", nil)
+	u3 := UserMessage("u3")
+
+	msgs := []*Message{sys, u1, call, r1, r2, a1, u2, a2, u3}
+
+	t.Run("不配置时不裁剪", func(t *testing.T) {
+		out, err := TrimMessages(ctx, msgs, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, msgs, out)
+	})
+
+	t.Run("保留系统消息与最近 N 轮", func(t *testing.T) {
+		out, err := TrimMessages(ctx, msgs, &TrimConfig{MaxTurns: 2})
+		assert.NoError(t, err)
+		assert.Equal(t, []*Message{sys, u2, a2, u3}, out)
+	})
+
+	t.Run("按预算丢弃最早的轮次", func(t *testing.T) {
+		out, err := TrimMessages(ctx, msgs, &TrimConfig{MaxTokens: 4, Tokenizer: &fixedTokenizer{}})
+		assert.NoError(t, err)
+		assert.Equal(t, []*Message{sys, u2, a2, u3}, out)
+	})
+
+	t.Run("单轮超出预算时不拆分工具调用与结果", func(t *testing.T) {
+		single := []*Message{sys, u1, call, r1, r2, a1}
+		out, err := TrimMessages(ctx, single, &TrimConfig{MaxTokens: 4, Tokenizer: &fixedTokenizer{}})
+		assert.NoError(t, err)
+		assert.Equal(t, []*Message{sys, a1}, out)
+
+		out, err = TrimMessages(ctx, single, &TrimConfig{MaxTokens: 5, Tokenizer: &fixedTokenizer{}})
+		assert.NoError(t, err)
+		assert.Equal(t, []*Message{sys, call, r1, r2, a1}, out)
+	})
+
+	t.Run("丢弃开头孤立的工具结果", func(t *testing.T) {
+		out, err := TrimMessages(ctx, []*Message{r1, a1, u2}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []*Message{a1, u2}, out)
+	})
+
+	t.Run("分词器出错", func(t *testing.T) {
+		_, err := TrimMessages(ctx, msgs, &TrimConfig{MaxTokens: 1, Tokenizer: &fixedTokenizer{err: errors.New("boom")}})
+		assert.Error(t, err)
+
+		trimmer := NewMessageTrimmer(&TrimConfig{MaxTokens: 1, Tokenizer: &fixedTokenizer{err: errors.New("boom")}})
+		assert.Equal(t, msgs, trimmer(ctx, msgs))
+	})
+
+	t.Run("裁剪函数", func(t *testing.T) {
+		trimmer := NewMessageTrimmer(&TrimConfig{MaxTurns: 1})
+		assert.Equal(t, []*Message{sys, u3}, trimmer(ctx, msgs))
+	})
+}
@@ -0,0 +1,355 @@
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/nikolalohinski/gonja/tokens"
+)
+
+// TemplateVariable 模板中引用的变量。
+type TemplateVariable struct {
+	// Name 变量名，嵌套访问（如 user.name、items[0]）只取最外层的键。
+	Name string
+	// IsMessages 变量来自 MessagesPlaceholder，取值必须是 []*Message。
+	IsMessages bool
+	// Optional 变量缺失时模板仍可格式化，目前只有可选的 MessagesPlaceholder。
+	Optional bool
+}
+
+// TemplateVariablesProvider 可选接口，自定义 MessagesTemplate 实现它以支持变量提取。
+type TemplateVariablesProvider interface {
+	TemplateVariables(formatType FormatType) ([]TemplateVariable, error)
+}
+
+// ExtractTemplateVariables 静态提取消息模板引用的变量，结果按变量名排序。
+// 支持 *Message（Content 与 UserInputMultiContent 中参与格式化的文本）、MessagesPlaceholder，
+// 以及实现了 TemplateVariablesProvider 的自定义模板。
+//
+// 注意：Jinja2 对缺失变量渲染为空字符串而不报错，这里仍将其视为必需变量；
+// Jinja2 的变量提取基于词法分析，for/set/macro 等语句绑定的局部变量会在整个模板范围内被排除。
+func ExtractTemplateVariables(tpl MessagesTemplate, formatType FormatType) ([]TemplateVariable, error) {
+	switch t := tpl.(type) {
+	case *messagesPlaceholder:
+		return []TemplateVariable{{Name: t.key, IsMessages: true, Optional: t.optional}}, nil
+	case *Message:
+		return extractMessageVariables(t, formatType)
+	case TemplateVariablesProvider:
+		return t.TemplateVariables(formatType)
+	default:
+		return nil, fmt.Errorf("template variables of %T cannot be extracted", tpl)
+	}
+}
+
+// MergeTemplateVariables 合并多组变量：同名变量只要有一处必需即为必需，有一处为消息占位符即为消息类型。
+func MergeTemplateVariables(groups ...[]TemplateVariable) []TemplateVariable {
+	merged := make(map[string]*TemplateVariable)
+	for _, group := range groups {
+		for _, v := range group {
+			if m, ok := merged[v.Name]; ok {
+				m.IsMessages = m.IsMessages || v.IsMessages
+				m.Optional = m.Optional && v.Optional
+				continue
+			}
+			v := v
+			merged[v.Name] = &v
+		}
+	}
+
+	result := make([]TemplateVariable, 0, len(merged))
+	for _, v := range merged {
+		result = append(result, *v)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+
+	return result
+}
+
+// extractMessageVariables 提取消息中所有参与格式化的文本所引用的变量。
+func extractMessageVariables(m *Message, formatType FormatType) ([]TemplateVariable, error) {
+	contents := []string{m.Content}
+	for _, part := range m.UserInputMultiContent {
+		switch part.Type {
+		case ChatMessagePartTypeText:
+			contents = append(contents, part.Text)
+		case ChatMessagePartTypeImageURL:
+			if part.Image != nil {
+				contents = append(contents, partCommonContents(part.Image.MessagePartCommon)...)
+			}
+		case ChatMessagePartTypeAudioURL:
+			if part.Audio != nil {
+				contents = append(contents, partCommonContents(part.Audio.MessagePartCommon)...)
+			}
+		case ChatMessagePartTypeVideoURL:
+			if part.Video != nil {
+				contents = append(contents, partCommonContents(part.Video.MessagePartCommon)...)
+			}
+		case ChatMessagePartTypeFileURL:
+			if part.File != nil {
+				contents = append(contents, partCommonContents(part.File.MessagePartCommon)...)
+			}
+		}
+	}
+
+	groups := make([][]TemplateVariable, 0, len(contents))
+	for _, content := range contents {
+		if content == "" {
+			continue
+		}
+		names, err := extractContentVariables(content, formatType)
+		if err != nil {
+			return nil, err
+		}
+		vars := make([]TemplateVariable, 0, len(names))
+		for _, name := range names {
+			vars = append(vars, TemplateVariable{Name: name})
+		}
+		groups = append(groups, vars)
+	}
+
+	return MergeTemplateVariables(groups...), nil
+}
+
+func partCommonContents(common MessagePartCommon) []string {
+	var contents []string
+	if common.URL != nil {
+		contents = append(contents, *common.URL)
+	}
+	if common.Base64Data != nil {
+		contents = append(contents, *common.Base64Data)
+	}
+	return contents
+}
+
+// extractContentVariables 按格式类型提取单段文本引用的变量名。
+func extractContentVariables(content string, formatType FormatType) ([]string, error) {
+	switch formatType {
+	case FString:
+		return extractFStringVariables(content)
+	case GoTemplate:
+		return extractGoTemplateVariables(content)
+	case Jinja2:
+		return extractJinja2Variables(content)
+	default:
+		return nil, fmt.Errorf("unknown format type: %v", formatType)
+	}
+}
+
+// extractFStringVariables 提取 {name}、{name:fmt}、{name.attr} 形式的变量，{{ 与 }} 视为转义。
+func extractFStringVariables(content string) ([]string, error) {
+	var names []string
+	for i := 0; i < len(content); i++ {
+		switch content[i] {
+		case '{':
+			if i+1 < len(content) && content[i+1] == '{' {
+				i++
+				continue
+			}
+			end := strings.IndexByte(content[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed '{' at position %d", i)
+			}
+			field := content[i+1 : i+end]
+			if cut := strings.IndexAny(field, ".[:!"); cut >= 0 {
+				field = field[:cut]
+			}
+			if field = strings.TrimSpace(field); field != "" {
+				names = append(names, field)
+			}
+			i += end
+		case '}':
+			if i+1 < len(content) && content[i+1] == '}' {
+				i++
+			}
+		}
+	}
+
+	return names, nil
+}
+
+// extractGoTemplateVariables 遍历 text/template 语法树，提取以根数据为起点的字段访问。
+// range/with 代码块内的 . 已被重新绑定，不计入变量；$.name 始终指向根数据。
+func extractGoTemplateVariables(content string) ([]string, error) {
+	tpl, err := template.New("template").Parse(content)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	var walk func(node parse.Node, rootDot bool)
+	walk = func(node parse.Node, rootDot bool) {
+		switch n := node.(type) {
+		case nil:
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, c := range n.Nodes {
+				walk(c, rootDot)
+			}
+		case *parse.ActionNode:
+			walk(n.Pipe, rootDot)
+		case *parse.PipeNode:
+			if n == nil {
+				return
+			}
+			for _, cmd := range n.Cmds {
+				for _, arg := range cmd.Args {
+					walk(arg, rootDot)
+				}
+			}
+		case *parse.FieldNode:
+			if rootDot && len(n.Ident) > 0 {
+				names = append(names, n.Ident[0])
+			}
+		case *parse.ChainNode:
+			walk(n.Node, rootDot)
+		case *parse.VariableNode:
+			if len(n.Ident) > 1 && n.Ident[0] == "$" {
+				names = append(names, n.Ident[1])
+			}
+		case *parse.IfNode:
+			walk(n.Pipe, rootDot)
+			walk(n.List, rootDot)
+			walk(n.ElseList, rootDot)
+		case *parse.RangeNode:
+			walk(n.Pipe, rootDot)
+			walk(n.List, false)
+			walk(n.ElseList, rootDot)
+		case *parse.WithNode:
+			walk(n.Pipe, rootDot)
+			walk(n.List, false)
+			walk(n.ElseList, rootDot)
+		case *parse.TemplateNode:
+			walk(n.Pipe, rootDot)
+		}
+	}
+	if tpl.Tree != nil {
+		walk(tpl.Tree.Root, true)
+	}
+
+	return names, nil
+}
+
+// jinja2Reserved Jinja2 关键字、常量与内置全局函数，不视为变量。
+var jinja2Reserved = map[string]bool{
+	"for": true, "endfor": true, "in": true, "if": true, "elif": true, "else": true, "endif": true,
+	"set": true, "endset": true, "macro": true, "endmacro": true, "call": true, "endcall": true,
+	"filter": true, "endfilter": true, "with": true, "endwith": true, "block": true, "endblock": true,
+	"autoescape": true, "endautoescape": true, "raw": true, "endraw": true, "import": true, "from": true,
+	"as": true, "include": true, "extends": true, "ignore": true, "missing": true, "recursive": true,
+	"context": true, "without": true, "not": true, "and": true, "or": true, "is": true,
+	"true": true, "false": true, "none": true, "True": true, "False": true, "None": true,
+	"loop": true, "super": true, "self": true, "caller": true, "varargs": true, "kwargs": true,
+	"range": true, "dict": true, "lipsum": true, "cycler": true, "joiner": true, "namespace": true,
+}
+
+// extractJinja2Variables 基于 gonja 词法分析提取 Jinja2 模板引用的变量。
+func extractJinja2Variables(content string) ([]string, error) {
+	lexer := tokens.NewLexer(content)
+	go lexer.Run()
+
+	// 收集表达式中的词法单元，忽略空白
+	var toks []*tokens.Token
+	for tok := range lexer.Tokens {
+		if tok.Type == tokens.Error {
+			// 排空通道以结束词法分析协程
+			for range lexer.Tokens {
+			}
+			return nil, fmt.Errorf("invalid jinja2 template: %s", tok.Val)
+		}
+		if tok.Type != tokens.Whitespace {
+			toks = append(toks, tok)
+		}
+	}
+
+	var (
+		candidates []string
+		bound      = make(map[string]bool)
+	)
+	inBlock := false
+	stmt := ""    // 当前语句块的关键字
+	binding := "" // 正在绑定局部变量的语句
+	parenDepth := 0
+	for i, tok := range toks {
+		switch tok.Type {
+		case tokens.BlockBegin:
+			inBlock, stmt, binding, parenDepth = true, "", "", 0
+			continue
+		case tokens.BlockEnd, tokens.VariableEnd:
+			inBlock, stmt, binding, parenDepth = false, "", "", 0
+			continue
+		case tokens.Lparen:
+			parenDepth++
+			continue
+		case tokens.Rparen:
+			parenDepth--
+			if stmt == "macro" && parenDepth == 0 {
+				binding = ""
+			}
+			continue
+		case tokens.In:
+			if binding == "for" {
+				binding = ""
+			}
+			continue
+		case tokens.Assign:
+			if binding == "set" || binding == "with" {
+				binding = ""
+			}
+			continue
+		case tokens.Comma:
+			if stmt == "with" && parenDepth == 0 {
+				binding = "with"
+			}
+			continue
+		case tokens.Name:
+		default:
+			continue
+		}
+
+		if inBlock && stmt == "" {
+			stmt = tok.Val
+			switch stmt {
+			case "for", "set", "with", "macro":
+				binding = stmt
+			}
+			continue
+		}
+		if binding != "" {
+			bound[tok.Val] = true
+			continue
+		}
+
+		var prev, next *tokens.Token
+		if i > 0 {
+			prev = toks[i-1]
+		}
+		if i+1 < len(toks) {
+			next = toks[i+1]
+		}
+		if prev != nil && (prev.Type == tokens.Dot || prev.Type == tokens.Pipe || prev.Type == tokens.Is) {
+			continue // 属性、过滤器与测试名
+		}
+		if next != nil && next.Type == tokens.Assign && parenDepth > 0 {
+			continue // 关键字参数名
+		}
+		if jinja2Reserved[tok.Val] {
+			continue
+		}
+
+		candidates = append(candidates, tok.Val)
+	}
+
+	names := make([]string, 0, len(candidates))
+	for _, name := range candidates {
+		if !bound[name] {
+			names = append(names, name)
+		}
+	}
+
+	return names, nil
+}
@@ -0,0 +1,85 @@
+package schema
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func names(vars []TemplateVariable) []string {
+	out := make([]string, 0, len(vars))
+	for _, v := range vars {
+		out = append(out, v.Name)
+	}
+	return out
+}
+
+func TestExtractTemplateVariables(t *testing.T) {
+	t.Run("FString", func(t *testing.T) {
+		vars, err := ExtractTemplateVariables(UserMessage("你好 {name}，{{转义}} {user.age:>3} {items[0]}"), FString)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"items", "name", "user"}, names(vars))
+
+		_, err = ExtractTemplateVariables(UserMessage("{name"), FString)
+		assert.Error(t, err)
+	})
+
+	t.Run("GoTemplate", func(t *testing.T) {
+		vars, err := ExtractTemplateVariables(UserMessage(
+			`{{.name}} {{if .vip}}{{.level}}{{end}} {{range .items}}{{.title}}{{$.suffix}}{{end}} {{with .user}}{{.age}}{{end}} {{printf "%s" .greeting | len}}`,
+		), GoTemplate)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"greeting", "items", "level", "name", "suffix", "user", "vip"}, names(vars))
+
+		_, err = ExtractTemplateVariables(UserMessage("{{.name"), GoTemplate)
+		assert.Error(t, err)
+	})
+
+	t.Run("Jinja2", func(t *testing.T) {
+		vars, err := ExtractTemplateVariables(UserMessage(
+			`{{ name | default(fallback) }} {% for item in items %}{{ item.title }}{{ loop.index }}{% endfor %}`+
+				`{% set total = count + 1 %}{{ total }}{% if user is defined and not hidden %}{{ user.name }}{% endif %}`+
+				`{{ format(value, width=size) }}`,
+		), Jinja2)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"count", "fallback", "format", "hidden", "items", "name", "size", "user", "value"}, names(vars))
+	})
+
+	t.Run("多模态输入与消息占位符", func(t *testing.T) {
+		url := "https://example.com/{image}.png"
+		vars, err := ExtractTemplateVariables(&Message{
+			Role:    User,
+			Content: "{query}",
+			UserInputMultiContent: []MessageInputPart{
+				{Type: ChatMessagePartTypeText, Text: "描述 {subject}"},
+				{Type: ChatMessagePartTypeImageURL, Image: &MessageInputImage{MessagePartCommon: MessagePartCommon{URL: &url}}},
+			},
+		}, FString)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"image", "query", "subject"}, names(vars))
+
+		vars, err = ExtractTemplateVariables(MessagesPlaceholder("history", true), FString)
+		assert.NoError(t, err)
+		assert.Equal(t, []TemplateVariable{{Name: "history", IsMessages: true, Optional: true}}, vars)
+	})
+
+	t.Run("自定义模板", func(t *testing.T) {
+		_, err := ExtractTemplateVariables(customTemplate{}, FString)
+		assert.Error(t, err)
+	})
+}
+
+func TestMergeTemplateVariables(t *testing.T) {
+	merged := MergeTemplateVariables(
+		[]TemplateVariable{{Name: "b", Optional: true}, {Name: "a"}},
+		[]TemplateVariable{{Name: "b", IsMessages: true}},
+	)
+	assert.Equal(t, []TemplateVariable{{Name: "a"}, {Name: "b", IsMessages: true}}, merged)
+}
+
+type customTemplate struct{}
+
+func (customTemplate) Format(_ context.Context, _ map[string]any, _ FormatType) ([]*Message, error) {
+	return nil, nil
+}
@@ -0,0 +1,127 @@
+package schema
+
+import (
+	"context"
+	"math"
+	"unicode/utf8"
+)
+
+// Tokenizer - 分词器接口，用于估算消息占用的 token 数量，以便控制上下文窗口预算。
+// 可接入模型厂商提供的精确分词实现，默认使用 EstimateTokenizer 离线估算。
+type Tokenizer interface {
+	CountTokens(ctx context.Context, m *Message) (int, error)
+}
+
+// CountMessagesTokens 统计消息列表占用的 token 总数，tokenizer 为 nil 时使用默认估算器。
+func CountMessagesTokens(ctx context.Context, tokenizer Tokenizer, msgs []*Message) (int, error) {
+	if tokenizer == nil {
+		tokenizer = NewEstimateTokenizer(nil)
+	}
+
+	total := 0
+	for _, m := range msgs {
+		n, err := tokenizer.CountTokens(ctx, m)
+		if err != nil {
+			return 0, err
+		}
+		total += n
+	}
+
+	return total, nil
+}
+
+// EstimateTokenizerConfig - 估算分词器配置，零值字段使用默认值。
+type EstimateTokenizerConfig struct {
+	// ASCIICharsPerToken - 每个 token 对应的 ASCII 字符数，默认 4。
+	ASCIICharsPerToken float64
+	// NonASCIIRunesPerToken - 每个 token 对应的非 ASCII 字符（如中文）数，默认 1。
+	NonASCIIRunesPerToken float64
+	// MessageOverhead - 每条消息固定附加的 token 数（角色、分隔符等），默认 4。
+	MessageOverhead int
+	// MediaPartTokens - 每个图片、音频、视频、文件等多模态部分计入的 token 数，默认 256。
+	MediaPartTokens int
+}
+
+// NewEstimateTokenizer 创建基于字符数的估算分词器，无需网络或词表即可使用。
+func NewEstimateTokenizer(config *EstimateTokenizerConfig) *EstimateTokenizer {
+	t := &EstimateTokenizer{
+		asciiCharsPerToken:    4,
+		nonASCIIRunesPerToken: 1,
+		messageOverhead:       4,
+		mediaPartTokens:       256,
+	}
+	if config == nil {
+		return t
+	}
+
+	if config.ASCIICharsPerToken > 0 {
+		t.asciiCharsPerToken = config.ASCIICharsPerToken
+	}
+	if config.NonASCIIRunesPerToken > 0 {
+		t.nonASCIIRunesPerToken = config.NonASCIIRunesPerToken
+	}
+	if config.MessageOverhead > 0 {
+		t.messageOverhead = config.MessageOverhead
+	}
+	if config.MediaPartTokens > 0 {
+		t.mediaPartTokens = config.MediaPartTokens
+	}
+
+	return t
+}
+
+// EstimateTokenizer - 估算分词器，按 ASCII 与非 ASCII 字符分别折算 token 数。
+// 结果只是近似值，适合作为预算控制的保守估计。
+type EstimateTokenizer struct {
+	asciiCharsPerToken    float64
+	nonASCIIRunesPerToken float64
+	messageOverhead       int
+	mediaPartTokens       int
+}
+
+// CountTokens 估算单条消息的 token 数，包括文本、推理内容、工具调用与多模态部分。
+func (t *EstimateTokenizer) CountTokens(_ context.Context, m *Message) (int, error) {
+	if m == nil {
+		return 0, nil
+	}
+
+	var ascii, nonASCII int
+	count := func(s string) {
+		for _, r := range s {
+			if r < utf8.RuneSelf {
+				ascii++
+			} else {
+				nonASCII++
+			}
+		}
+	}
+
+	count(m.Content)
+	count(m.ReasoningContent)
+	count(m.Name)
+	count(m.ToolName)
+	for _, tc := range m.ToolCalls {
+		count(tc.Function.Name)
+		count(tc.Function.Arguments)
+	}
+
+	media := 0
+	for _, part := range m.UserInputMultiContent {
+		if part.Type == ChatMessagePartTypeText {
+			count(part.Text)
+		} else {
+			media++
+		}
+	}
+	for _, part := range m.AssistantGenMultiContent {
+		if part.Type == ChatMessagePartTypeText {
+			count(part.Text)
+		} else {
+			media++
+		}
+	}
+
+	tokens := int(math.Ceil(float64(ascii)/t.asciiCharsPerToken + float64(nonASCII)/t.nonASCIIRunesPerToken))
+
+	return tokens + media*t.mediaPartTokens + t.messageOverhead, nil
+}
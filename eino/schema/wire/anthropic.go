@@ -0,0 +1,781 @@
+package wire
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/favbox/eino/schema"
+)
+
+// AnthropicRequest Anthropic Messages 请求体中与消息和工具相关的部分。
+// 其余采样参数由调用方按需补充。
+type AnthropicRequest struct {
+	Model      string               `json:"model,omitempty"`
+	MaxTokens  int                  `json:"max_tokens,omitempty"`
+	System     AnthropicContent     `json:"system,omitempty"`
+	Messages   []AnthropicMessage   `json:"messages"`
+	Tools      []AnthropicTool      `json:"tools,omitempty"`
+	ToolChoice *AnthropicToolChoice `json:"tool_choice,omitempty"`
+	Stream     bool                 `json:"stream,omitempty"`
+}
+
+// AnthropicMessage Anthropic 消息，角色只有 user 与 assistant。
+type AnthropicMessage struct {
+	Role    string           `json:"role"`
+	Content AnthropicContent `json:"content"`
+}
+
+// AnthropicContent Anthropic 内容块列表。
+// 反序列化时兼容字符串形式，字符串会被视为单个文本块。
+type AnthropicContent []AnthropicContentBlock
+
+// UnmarshalJSON 实现 json.Unmarshaler。
+func (c *AnthropicContent) UnmarshalJSON(data []byte) error {
+	s := strings.TrimSpace(string(data))
+	switch {
+	case s == "" || s == "null":
+		*c = nil
+		return nil
+	case strings.HasPrefix(s, "["):
+		var blocks []AnthropicContentBlock
+		if err := sonic.Unmarshal(data, &blocks); err != nil {
+			return err
+		}
+		*c = blocks
+		return nil
+	default:
+		var text string
+		if err := sonic.Unmarshal(data, &text); err != nil {
+			return err
+		}
+		*c = AnthropicContent{{Type: anthropicBlockText, Text: text}}
+		return nil
+	}
+}
+
+// AnthropicContentBlock Anthropic 内容块，字段按 Type 取用：
+//   - text: Text
+//   - image / document: Source
+//   - tool_use: ID、Name、Input
+//   - tool_result: ToolUseID、Content、IsError
+//   - thinking: Thinking、Signature
+type AnthropicContentBlock struct {
+	Type      string           `json:"type"`
+	Text      string           `json:"text,omitempty"`
+	Source    *AnthropicSource `json:"source,omitempty"`
+	ID        string           `json:"id,omitempty"`
+	Name      string           `json:"name,omitempty"`
+	Input     json.RawMessage  `json:"input,omitempty"`
+	ToolUseID string           `json:"tool_use_id,omitempty"`
+	Content   AnthropicContent `json:"content,omitempty"`
+	IsError   bool             `json:"is_error,omitempty"`
+	Thinking  string           `json:"thinking,omitempty"`
+	Signature string           `json:"signature,omitempty"`
+}
+
+// AnthropicSource 图片或文档的数据来源，Type 为 base64 或 url。
+type AnthropicSource struct {
+	Type      string `json:"type"`
+	MediaType string `json:"media_type,omitempty"`
+	Data      string `json:"data,omitempty"`
+	URL       string `json:"url,omitempty"`
+}
+
+// AnthropicTool Anthropic 工具定义。
+type AnthropicTool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	InputSchema json.RawMessage `json:"input_schema"`
+}
+
+// AnthropicToolChoice Anthropic 工具调用策略，Type 为 auto、any、tool 或 none。
+type AnthropicToolChoice struct {
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
+}
+
+// AnthropicResponse Anthropic Messages 非流式响应，也是流式 message_start 事件中的消息。
+type AnthropicResponse struct {
+	ID           string           `json:"id,omitempty"`
+	Type         string           `json:"type,omitempty"`
+	Role         string           `json:"role,omitempty"`
+	Model        string           `json:"model,omitempty"`
+	Content      AnthropicContent `json:"content"`
+	StopReason   string           `json:"stop_reason,omitempty"`
+	StopSequence string           `json:"stop_sequence,omitempty"`
+	Usage        *AnthropicUsage  `json:"usage,omitempty"`
+}
+
+// AnthropicUsage token 使用量。
+type AnthropicUsage struct {
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens,omitempty"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens,omitempty"`
+}
+
+// AnthropicStreamEvent Anthropic 流式事件，字段按 Type 取用：
+//   - message_start: Message
+//   - content_block_start: Index、ContentBlock
+//   - content_block_delta: Index、Delta
+//   - message_delta: Delta（StopReason）、Usage
+//   - error: Error
+type AnthropicStreamEvent struct {
+	Type         string                 `json:"type"`
+	Message      *AnthropicResponse     `json:"message,omitempty"`
+	Index        int                    `json:"index"`
+	ContentBlock *AnthropicContentBlock `json:"content_block,omitempty"`
+	Delta        *AnthropicStreamDelta  `json:"delta,omitempty"`
+	Usage        *AnthropicUsage        `json:"usage,omitempty"`
+	Error        *AnthropicError        `json:"error,omitempty"`
+}
+
+// AnthropicStreamDelta 流式增量内容。
+type AnthropicStreamDelta struct {
+	Type         string `json:"type,omitempty"`
+	Text         string `json:"text,omitempty"`
+	PartialJSON  string `json:"partial_json,omitempty"`
+	Thinking     string `json:"thinking,omitempty"`
+	Signature    string `json:"signature,omitempty"`
+	StopReason   string `json:"stop_reason,omitempty"`
+	StopSequence string `json:"stop_sequence,omitempty"`
+}
+
+// AnthropicError 流式 error 事件中的错误信息。
+type AnthropicError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+const (
+	anthropicRoleUser      = "user"
+	anthropicRoleAssistant = "assistant"
+
+	anthropicBlockText       = "text"
+	anthropicBlockImage      = "image"
+	anthropicBlockDocument   = "document"
+	anthropicBlockToolUse    = "tool_use"
+	anthropicBlockToolResult = "tool_result"
+	anthropicBlockThinking   = "thinking"
+
+	anthropicSourceBase64 = "base64"
+	anthropicSourceURL    = "url"
+
+	// ExtraKeyAnthropicThinkingSignature 存放 Anthropic 思考块签名的 Message.Extra 键，
+	// 多轮对话中回传带思考内容的助手消息时需要。
+	ExtraKeyAnthropicThinkingSignature = "anthropic_thinking_signature"
+)
+
+// ToAnthropicRequest 将消息与工具转换为 Anthropic 请求。
+//
+// 转换规则：
+//   - 系统消息提取为顶层 System 文本块
+//   - 工具消息转换为 user 角色的 tool_result 块
+//   - 相邻的同角色消息合并为一条消息，以满足 user/assistant 交替的要求
+func ToAnthropicRequest(msgs []*schema.Message, tools []*schema.ToolInfo) (*AnthropicRequest, error) {
+	req := &AnthropicRequest{Messages: make([]AnthropicMessage, 0, len(msgs))}
+	for i, m := range msgs {
+		if m.Role == schema.System {
+			req.System = append(req.System, AnthropicContentBlock{Type: anthropicBlockText, Text: m.Content})
+			continue
+		}
+
+		am, err := toAnthropicMessage(m)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert message[%d] to anthropic format: %w", i, err)
+		}
+
+		if n := len(req.Messages); n > 0 && req.Messages[n-1].Role == am.Role {
+			req.Messages[n-1].Content = append(req.Messages[n-1].Content, am.Content...)
+			continue
+		}
+		req.Messages = append(req.Messages, am)
+	}
+
+	aTools, err := ToAnthropicTools(tools)
+	if err != nil {
+		return nil, err
+	}
+	req.Tools = aTools
+
+	return req, nil
+}
+
+// FromAnthropicRequest 将 Anthropic 请求转换为消息与工具。
+//
+// 每个 System 文本块转换为一条系统消息；user 消息中的每个 tool_result 块转换为一条工具消息，
+// 工具消息的 ToolName 由之前助手消息中同 ID 的工具调用推断。
+func FromAnthropicRequest(req *AnthropicRequest) ([]*schema.Message, []*schema.ToolInfo, error) {
+	msgs := make([]*schema.Message, 0, len(req.System)+len(req.Messages))
+	for _, b := range req.System {
+		msgs = append(msgs, schema.SystemMessage(b.Text))
+	}
+
+	toolNames := make(map[string]string)
+	for i := range req.Messages {
+		am := &req.Messages[i]
+		switch am.Role {
+		case anthropicRoleAssistant:
+			m, err := fromAnthropicAssistantContent(am.Content)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to convert anthropic message[%d]: %w", i, err)
+			}
+			for _, tc := range m.ToolCalls {
+				toolNames[tc.ID] = tc.Function.Name
+			}
+			msgs = append(msgs, m)
+		case anthropicRoleUser:
+			ms, err := fromAnthropicUserContent(am.Content, toolNames)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to convert anthropic message[%d]: %w", i, err)
+			}
+			msgs = append(msgs, ms...)
+		default:
+			return nil, nil, fmt.Errorf("unsupported anthropic message role: %s", am.Role)
+		}
+	}
+
+	tools, err := FromAnthropicTools(req.Tools)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return msgs, tools, nil
+}
+
+// ToAnthropicToolChoice 将工具调用策略转换为 Anthropic 的 tool_choice。
+func ToAnthropicToolChoice(tc schema.ToolChoice) *AnthropicToolChoice {
+	switch tc {
+	case schema.ToolChoiceForbidden:
+		return &AnthropicToolChoice{Type: "none"}
+	case schema.ToolChoiceForced:
+		return &AnthropicToolChoice{Type: "any"}
+	default:
+		return &AnthropicToolChoice{Type: "auto"}
+	}
+}
+
+// FromAnthropicToolChoice 将 Anthropic 的 tool_choice 转换为工具调用策略。
+// 指定具体工具（tool）视为强制调用。
+func FromAnthropicToolChoice(tc *AnthropicToolChoice) schema.ToolChoice {
+	if tc == nil {
+		return schema.ToolChoiceAllowed
+	}
+	switch tc.Type {
+	case "none":
+		return schema.ToolChoiceForbidden
+	case "any", "tool":
+		return schema.ToolChoiceForced
+	default:
+		return schema.ToolChoiceAllowed
+	}
+}
+
+// ToAnthropicTools 将工具信息转换为 Anthropic 工具定义。
+func ToAnthropicTools(tools []*schema.ToolInfo) ([]AnthropicTool, error) {
+	if len(tools) == 0 {
+		return nil, nil
+	}
+
+	ret := make([]AnthropicTool, 0, len(tools))
+	for _, t := range tools {
+		params, err := toolParamsToRaw(t)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, AnthropicTool{
+			Name:        t.Name,
+			Description: t.Desc,
+			InputSchema: params,
+		})
+	}
+
+	return ret, nil
+}
+
+// FromAnthropicTools 将 Anthropic 工具定义转换为工具信息。
+func FromAnthropicTools(tools []AnthropicTool) ([]*schema.ToolInfo, error) {
+	if len(tools) == 0 {
+		return nil, nil
+	}
+
+	ret := make([]*schema.ToolInfo, 0, len(tools))
+	for _, t := range tools {
+		params, err := rawToToolParams(t.Name, t.InputSchema)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, &schema.ToolInfo{
+			Name:        t.Name,
+			Desc:        t.Description,
+			ParamsOneOf: params,
+		})
+	}
+
+	return ret, nil
+}
+
+// FromAnthropicResponse 将 Anthropic 非流式响应转换为消息。
+func FromAnthropicResponse(resp *AnthropicResponse) (*schema.Message, error) {
+	m, err := fromAnthropicAssistantContent(resp.Content)
+	if err != nil {
+		return nil, err
+	}
+	m.ResponseMeta = &schema.ResponseMeta{
+		FinishReason: fromAnthropicStopReason(resp.StopReason),
+		Usage:        fromAnthropicUsage(resp.Usage),
+	}
+
+	return m, nil
+}
+
+// ToAnthropicResponse 将助手消息转换为 Anthropic 非流式响应，适用于实现兼容 Anthropic 的服务端或测试桩。
+func ToAnthropicResponse(m *schema.Message) (*AnthropicResponse, error) {
+	am, err := toAnthropicMessage(m)
+	if err != nil {
+		return nil, err
+	}
+	if am.Role != anthropicRoleAssistant {
+		return nil, fmt.Errorf("anthropic response requires assistant message, got %s", m.Role)
+	}
+
+	resp := &AnthropicResponse{
+		Type:    "message",
+		Role:    anthropicRoleAssistant,
+		Content: am.Content,
+	}
+	if m.ResponseMeta != nil {
+		resp.StopReason = toAnthropicStopReason(m.ResponseMeta.FinishReason)
+		resp.Usage = toAnthropicUsage(m.ResponseMeta.Usage)
+	}
+
+	return resp, nil
+}
+
+// AnthropicStreamDecoder 将 Anthropic 流式事件逐个转换为消息块。
+//
+// 解码器记录内容块下标与工具调用序号的对应关系，输出的工具调用块带有连续的 Index，
+// 同一次响应的所有消息块可通过 schema.ConcatMessages 合并为完整消息。
+// 解码器有状态，每个响应流使用一个新的解码器，且不可并发使用。
+type AnthropicStreamDecoder struct {
+	toolIndexes  map[int]int
+	promptTokens int
+	cachedTokens int
+}
+
+// NewAnthropicStreamDecoder 创建 Anthropic 流式事件解码器。
+func NewAnthropicStreamDecoder() *AnthropicStreamDecoder {
+	return &AnthropicStreamDecoder{toolIndexes: make(map[int]int)}
+}
+
+// Decode 将一个流式事件转换为消息块。
+// 不携带内容的事件（如 ping、content_block_stop、message_stop）返回 nil 消息；error 事件返回错误。
+func (d *AnthropicStreamDecoder) Decode(ev *AnthropicStreamEvent) (*schema.Message, error) {
+	switch ev.Type {
+	case "message_start":
+		m := &schema.Message{Role: schema.Assistant}
+		if ev.Message != nil && ev.Message.Usage != nil {
+			u := fromAnthropicUsage(ev.Message.Usage)
+			d.promptTokens = u.PromptTokens
+			d.cachedTokens = u.PromptTokenDetails.CachedTokens
+			m.ResponseMeta = &schema.ResponseMeta{Usage: u}
+		}
+		return m, nil
+
+	case "content_block_start":
+		if ev.ContentBlock == nil {
+			return nil, nil
+		}
+		b := ev.ContentBlock
+		switch b.Type {
+		case anthropicBlockText:
+			if b.Text == "" {
+				return nil, nil
+			}
+			return &schema.Message{Role: schema.Assistant, Content: b.Text}, nil
+		case anthropicBlockThinking:
+			if b.Thinking == "" {
+				return nil, nil
+			}
+			return &schema.Message{Role: schema.Assistant, ReasoningContent: b.Thinking}, nil
+		case anthropicBlockToolUse:
+			idx := len(d.toolIndexes)
+			d.toolIndexes[ev.Index] = idx
+			return &schema.Message{
+				Role: schema.Assistant,
+				ToolCalls: []schema.ToolCall{{
+					Index:    &idx,
+					ID:       b.ID,
+					Type:     "function",
+					Function: schema.FunctionCall{Name: b.Name},
+				}},
+			}, nil
+		default:
+			return nil, nil
+		}
+
+	case "content_block_delta":
+		if ev.Delta == nil {
+			return nil, nil
+		}
+		switch ev.Delta.Type {
+		case "text_delta":
+			return &schema.Message{Role: schema.Assistant, Content: ev.Delta.Text}, nil
+		case "thinking_delta":
+			return &schema.Message{Role: schema.Assistant, ReasoningContent: ev.Delta.Thinking}, nil
+		case "signature_delta":
+			return &schema.Message{
+				Role:  schema.Assistant,
+				Extra: map[string]any{ExtraKeyAnthropicThinkingSignature: ev.Delta.Signature},
+			}, nil
+		case "input_json_delta":
+			idx, ok := d.toolIndexes[ev.Index]
+			if !ok {
+				return nil, fmt.Errorf("received input_json_delta for unknown content block: %d", ev.Index)
+			}
+			return &schema.Message{
+				Role: schema.Assistant,
+				ToolCalls: []schema.ToolCall{{
+					Index:    &idx,
+					Function: schema.FunctionCall{Arguments: ev.Delta.PartialJSON},
+				}},
+			}, nil
+		default:
+			return nil, nil
+		}
+
+	case "message_delta":
+		meta := &schema.ResponseMeta{}
+		if ev.Delta != nil {
+			meta.FinishReason = fromAnthropicStopReason(ev.Delta.StopReason)
+		}
+		if ev.Usage != nil {
+			u := fromAnthropicUsage(ev.Usage)
+			if u.PromptTokens < d.promptTokens {
+				u.PromptTokens = d.promptTokens
+			}
+			if u.PromptTokenDetails.CachedTokens < d.cachedTokens {
+				u.PromptTokenDetails.CachedTokens = d.cachedTokens
+			}
+			u.TotalTokens = u.PromptTokens + u.CompletionTokens
+			meta.Usage = u
+		}
+		return &schema.Message{Role: schema.Assistant, ResponseMeta: meta}, nil
+
+	case "error":
+		if ev.Error != nil {
+			return nil, fmt.Errorf("anthropic stream error: type=%s, message=%s", ev.Error.Type, ev.Error.Message)
+		}
+		return nil, fmt.Errorf("anthropic stream error")
+
+	default:
+		return nil, nil
+	}
+}
+
+// FromAnthropicEventStream 将 Anthropic 流式事件流转换为消息块流，不携带内容的事件会被跳过。
+func FromAnthropicEventStream(sr *schema.StreamReader[*AnthropicStreamEvent]) *schema.StreamReader[*schema.Message] {
+	d := NewAnthropicStreamDecoder()
+	return schema.StreamReaderWithConvert(sr, func(ev *AnthropicStreamEvent) (*schema.Message, error) {
+		m, err := d.Decode(ev)
+		if err != nil {
+			return nil, err
+		}
+		if m == nil {
+			return nil, schema.ErrNoValue
+		}
+		return m, nil
+	})
+}
+
+// toAnthropicMessage 转换单条非系统消息。
+func toAnthropicMessage(m *schema.Message) (AnthropicMessage, error) {
+	switch m.Role {
+	case schema.User:
+		blocks, err := toAnthropicUserBlocks(m)
+		if err != nil {
+			return AnthropicMessage{}, err
+		}
+		return AnthropicMessage{Role: anthropicRoleUser, Content: blocks}, nil
+
+	case schema.Assistant:
+		blocks, err := toAnthropicAssistantBlocks(m)
+		if err != nil {
+			return AnthropicMessage{}, err
+		}
+		return AnthropicMessage{Role: anthropicRoleAssistant, Content: blocks}, nil
+
+	case schema.Tool:
+		return AnthropicMessage{
+			Role: anthropicRoleUser,
+			Content: AnthropicContent{{
+				Type:      anthropicBlockToolResult,
+				ToolUseID: m.ToolCallID,
+				Content:   AnthropicContent{{Type: anthropicBlockText, Text: m.Content}},
+			}},
+		}, nil
+
+	default:
+		return AnthropicMessage{}, fmt.Errorf("unsupported message role for anthropic: %s", m.Role)
+	}
+}
+
+// toAnthropicUserBlocks 转换用户消息内容，Content 非空时作为首个文本块。
+func toAnthropicUserBlocks(m *schema.Message) (AnthropicContent, error) {
+	blocks := make(AnthropicContent, 0, len(m.UserInputMultiContent)+1)
+	if m.Content != "" || len(m.UserInputMultiContent) == 0 {
+		blocks = append(blocks, AnthropicContentBlock{Type: anthropicBlockText, Text: m.Content})
+	}
+
+	for _, part := range m.UserInputMultiContent {
+		switch part.Type {
+		case schema.ChatMessagePartTypeText:
+			blocks = append(blocks, AnthropicContentBlock{Type: anthropicBlockText, Text: part.Text})
+		case schema.ChatMessagePartTypeImageURL:
+			if part.Image == nil {
+				return nil, fmt.Errorf("image part has no image")
+			}
+			src, err := toAnthropicSource(part.Image.MessagePartCommon)
+			if err != nil {
+				return nil, fmt.Errorf("invalid image part: %w", err)
+			}
+			blocks = append(blocks, AnthropicContentBlock{Type: anthropicBlockImage, Source: src})
+		case schema.ChatMessagePartTypeFileURL:
+			if part.File == nil {
+				return nil, fmt.Errorf("file part has no file")
+			}
+			src, err := toAnthropicSource(part.File.MessagePartCommon)
+			if err != nil {
+				return nil, fmt.Errorf("invalid file part: %w", err)
+			}
+			blocks = append(blocks, AnthropicContentBlock{Type: anthropicBlockDocument, Source: src})
+		default:
+			return nil, fmt.Errorf("unsupported user input part type for anthropic: %s", part.Type)
+		}
+	}
+
+	return blocks, nil
+}
+
+// toAnthropicAssistantBlocks 转换助手消息内容，依次为思考块、文本块与 tool_use 块。
+func toAnthropicAssistantBlocks(m *schema.Message) (AnthropicContent, error) {
+	var blocks AnthropicContent
+	if m.ReasoningContent != "" {
+		signature, _ := m.Extra[ExtraKeyAnthropicThinkingSignature].(string)
+		blocks = append(blocks, AnthropicContentBlock{
+			Type:      anthropicBlockThinking,
+			Thinking:  m.ReasoningContent,
+			Signature: signature,
+		})
+	}
+
+	text := m.Content
+	for _, part := range m.AssistantGenMultiContent {
+		if part.Type != schema.ChatMessagePartTypeText {
+			return nil, fmt.Errorf("unsupported assistant output part type for anthropic: %s", part.Type)
+		}
+		text += part.Text
+	}
+	if text != "" {
+		blocks = append(blocks, AnthropicContentBlock{Type: anthropicBlockText, Text: text})
+	}
+
+	for _, tc := range m.ToolCalls {
+		args := strings.TrimSpace(tc.Function.Arguments)
+		if args == "" {
+			args = "{}"
+		}
+		if !json.Valid([]byte(args)) {
+			return nil, fmt.Errorf("arguments of tool call[%s] is not valid json", tc.ID)
+		}
+		blocks = append(blocks, AnthropicContentBlock{
+			Type:  anthropicBlockToolUse,
+			ID:    tc.ID,
+			Name:  tc.Function.Name,
+			Input: json.RawMessage(args),
+		})
+	}
+
+	return blocks, nil
+}
+
+// toAnthropicSource 将多模态部分转换为 Anthropic 数据来源。
+func toAnthropicSource(common schema.MessagePartCommon) (*AnthropicSource, error) {
+	if mimeType, data, ok := partBase64(common); ok {
+		if mimeType == "" {
+			return nil, fmt.Errorf("mime type is required for base64 data")
+		}
+		return &AnthropicSource{Type: anthropicSourceBase64, MediaType: mimeType, Data: data}, nil
+	}
+	if common.URL != nil {
+		return &AnthropicSource{Type: anthropicSourceURL, URL: *common.URL}, nil
+	}
+
+	return nil, fmt.Errorf("neither url nor base64 data is set")
+}
+
+// fromAnthropicSource 将 Anthropic 数据来源转换为多模态部分。
+func fromAnthropicSource(src *AnthropicSource) (schema.MessagePartCommon, error) {
+	if src == nil {
+		return schema.MessagePartCommon{}, fmt.Errorf("source is missing")
+	}
+	switch src.Type {
+	case anthropicSourceBase64:
+		return base64PartCommon(src.MediaType, src.Data), nil
+	case anthropicSourceURL:
+		return urlPartCommon(src.URL), nil
+	default:
+		return schema.MessagePartCommon{}, fmt.Errorf("unsupported anthropic source type: %s", src.Type)
+	}
+}
+
+// fromAnthropicAssistantContent 将助手内容块转换为消息。
+func fromAnthropicAssistantContent(blocks AnthropicContent) (*schema.Message, error) {
+	m := &schema.Message{Role: schema.Assistant}
+	var text strings.Builder
+	for _, b := range blocks {
+		switch b.Type {
+		case anthropicBlockText:
+			text.WriteString(b.Text)
+		case anthropicBlockThinking:
+			m.ReasoningContent += b.Thinking
+			if b.Signature != "" {
+				if m.Extra == nil {
+					m.Extra = map[string]any{}
+				}
+				m.Extra[ExtraKeyAnthropicThinkingSignature] = b.Signature
+			}
+		case anthropicBlockToolUse:
+			args := string(b.Input)
+			if args == "" {
+				args = "{}"
+			}
+			m.ToolCalls = append(m.ToolCalls, schema.ToolCall{
+				ID:       b.ID,
+				Type:     "function",
+				Function: schema.FunctionCall{Name: b.Name, Arguments: args},
+			})
+		default:
+			return nil, fmt.Errorf("unsupported anthropic assistant block type: %s", b.Type)
+		}
+	}
+	m.Content = text.String()
+
+	return m, nil
+}
+
+// fromAnthropicUserContent 将用户内容块转换为消息：每个 tool_result 块对应一条工具消息，
+// 相邻的其他块合并为一条用户消息。
+func fromAnthropicUserContent(blocks AnthropicContent, toolNames map[string]string) ([]*schema.Message, error) {
+	var (
+		ret   []*schema.Message
+		parts []schema.MessageInputPart
+	)
+	flush := func() {
+		if len(parts) == 0 {
+			return
+		}
+		if len(parts) == 1 && parts[0].Type == schema.ChatMessagePartTypeText {
+			ret = append(ret, schema.UserMessage(parts[0].Text))
+		} else {
+			ret = append(ret, &schema.Message{Role: schema.User, UserInputMultiContent: parts})
+		}
+		parts = nil
+	}
+
+	for _, b := range blocks {
+		switch b.Type {
+		case anthropicBlockToolResult:
+			flush()
+			var text strings.Builder
+			for _, c := range b.Content {
+				text.WriteString(c.Text)
+			}
+			ret = append(ret, schema.ToolMessage(text.String(), b.ToolUseID, schema.WithToolName(toolNames[b.ToolUseID])))
+		case anthropicBlockText:
+			parts = append(parts, schema.MessageInputPart{Type: schema.ChatMessagePartTypeText, Text: b.Text})
+		case anthropicBlockImage:
+			common, err := fromAnthropicSource(b.Source)
+			if err != nil {
+				return nil, fmt.Errorf("invalid image block: %w", err)
+			}
+			parts = append(parts, schema.MessageInputPart{
+				Type:  schema.ChatMessagePartTypeImageURL,
+				Image: &schema.MessageInputImage{MessagePartCommon: common},
+			})
+		case anthropicBlockDocument:
+			common, err := fromAnthropicSource(b.Source)
+			if err != nil {
+				return nil, fmt.Errorf("invalid document block: %w", err)
+			}
+			parts = append(parts, schema.MessageInputPart{
+				Type: schema.ChatMessagePartTypeFileURL,
+				File: &schema.MessageInputFile{MessagePartCommon: common},
+			})
+		default:
+			return nil, fmt.Errorf("unsupported anthropic user block type: %s", b.Type)
+		}
+	}
+	flush()
+
+	return ret, nil
+}
+
+// fromAnthropicStopReason 将 Anthropic 的 stop_reason 转换为通用的结束原因。
+func fromAnthropicStopReason(reason string) string {
+	switch reason {
+	case "end_turn", "stop_sequence":
+		return "stop"
+	case "max_tokens":
+		return "length"
+	case "tool_use":
+		return "tool_calls"
+	default:
+		return reason
+	}
+}
+
+// toAnthropicStopReason 将通用的结束原因转换为 Anthropic 的 stop_reason。
+func toAnthropicStopReason(reason string) string {
+	switch reason {
+	case "stop":
+		return "end_turn"
+	case "length":
+		return "max_tokens"
+	case "tool_calls":
+		return "tool_use"
+	default:
+		return reason
+	}
+}
+
+// fromAnthropicUsage 转换 token 使用量，缓存读取的 token 计入提示词 token。
+func fromAnthropicUsage(u *AnthropicUsage) *schema.TokenUsage {
+	if u == nil {
+		return nil
+	}
+
+	prompt := u.InputTokens + u.CacheReadInputTokens + u.CacheCreationInputTokens
+	return &schema.TokenUsage{
+		PromptTokens:       prompt,
+		PromptTokenDetails: schema.PromptTokenDetails{CachedTokens: u.CacheReadInputTokens},
+		CompletionTokens:   u.OutputTokens,
+		TotalTokens:        prompt + u.OutputTokens,
+	}
+}
+
+// toAnthropicUsage 转换 token 使用量。
+func toAnthropicUsage(u *schema.TokenUsage) *AnthropicUsage {
+	if u == nil {
+		return nil
+	}
+
+	return &AnthropicUsage{
+		InputTokens:          u.PromptTokens - u.PromptTokenDetails.CachedTokens,
+		OutputTokens:         u.CompletionTokens,
+		CacheReadInputTokens: u.PromptTokenDetails.CachedTokens,
+	}
+}
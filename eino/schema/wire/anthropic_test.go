@@ -0,0 +1,181 @@
+package wire
+
+import (
+	"testing"
+
+	"github.com/bytedance/sonic"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/favbox/eino/schema"
+)
+
+// 验证消息转换为 Anthropic 请求：系统消息提取、工具结果合并与思考块
+func TestToAnthropicRequest(t *testing.T) {
+	msgs := []*schema.Message{
+		schema.SystemMessage("你是助手"),
+		{
+			Role: schema.User,
+			UserInputMultiContent: []schema.MessageInputPart{
+				{Type: schema.ChatMessagePartTypeText, Text: "看图"},
+				{Type: schema.ChatMessagePartTypeImageURL, Image: &schema.MessageInputImage{
+					MessagePartCommon: schema.MessagePartCommon{URL: strPtr("data:image/png;base64,iVBO")},
+				}},
+			},
+		},
+		{
+			Role:             schema.Assistant,
+			ReasoningContent: "思考",
+			Extra:            map[string]any{ExtraKeyAnthropicThinkingSignature: "sig"},
+			ToolCalls: []schema.ToolCall{
+				{ID: "tu_1", Function: schema.FunctionCall{Name: "get_weather", Arguments: `{"city":"北京"}`}},
+				{ID: "tu_2", Function: schema.FunctionCall{Name: "now"}},
+			},
+		},
+		schema.ToolMessage("晴", "tu_1"),
+		schema.ToolMessage("12:00", "tu_2"),
+		schema.UserMessage("谢谢"),
+	}
+
+	req, err := ToAnthropicRequest(msgs, testTools())
+	assert.NoError(t, err)
+	data, err := sonic.Marshal(req)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"system": [{"type": "text", "text": "你是助手"}],
+		"messages": [
+			{"role": "user", "content": [
+				{"type": "text", "text": "看图"},
+				{"type": "image", "source": {"type": "base64", "media_type": "image/png", "data": "iVBO"}}
+			]},
+			{"role": "assistant", "content": [
+				{"type": "thinking", "thinking": "思考", "signature": "sig"},
+				{"type": "tool_use", "id": "tu_1", "name": "get_weather", "input": {"city": "北京"}},
+				{"type": "tool_use", "id": "tu_2", "name": "now", "input": {}}
+			]},
+			{"role": "user", "content": [
+				{"type": "tool_result", "tool_use_id": "tu_1", "content": [{"type": "text", "text": "晴"}]},
+				{"type": "tool_result", "tool_use_id": "tu_2", "content": [{"type": "text", "text": "12:00"}]},
+				{"type": "text", "text": "谢谢"}
+			]}
+		],
+		"tools": [
+			{"name": "get_weather", "description": "查询天气", "input_schema": {
+				"type": "object",
+				"properties": {"city": {"type": "string", "description": "城市"}},
+				"required": ["city"]
+			}},
+			{"name": "now", "description": "当前时间", "input_schema": {"type": "object", "properties": {}}}
+		]
+	}`, string(data))
+
+	// 反向转换，工具结果拆分为独立的工具消息
+	var decoded AnthropicRequest
+	assert.NoError(t, sonic.Unmarshal(data, &decoded))
+	back, tools, err := FromAnthropicRequest(&decoded)
+	assert.NoError(t, err)
+	assert.Len(t, tools, 2)
+	assert.Len(t, back, 6)
+	assert.Equal(t, schema.System, back[0].Role)
+	assert.Equal(t, "image/png", back[1].UserInputMultiContent[1].Image.MIMEType)
+	assert.Equal(t, "思考", back[2].ReasoningContent)
+	assert.Equal(t, "sig", back[2].Extra[ExtraKeyAnthropicThinkingSignature])
+	assert.Equal(t, `{"city":"北京"}`, back[2].ToolCalls[0].Function.Arguments)
+	assert.Equal(t, "{}", back[2].ToolCalls[1].Function.Arguments)
+	assert.Equal(t, schema.ToolMessage("晴", "tu_1", schema.WithToolName("get_weather")), back[3])
+	assert.Equal(t, schema.ToolMessage("12:00", "tu_2", schema.WithToolName("now")), back[4])
+	assert.Equal(t, schema.UserMessage("谢谢"), back[5])
+
+	// 字符串形式的 content 与 system
+	assert.NoError(t, sonic.UnmarshalString(`{"system":"sys","messages":[{"role":"user","content":"hi"}]}`, &decoded))
+	back, _, err = FromAnthropicRequest(&decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, []*schema.Message{schema.SystemMessage("sys"), schema.UserMessage("hi")}, back)
+
+	_, err = ToAnthropicRequest([]*schema.Message{schema.AssistantMessage("", []schema.ToolCall{
+		{ID: "x", Function: schema.FunctionCall{Arguments: "{bad"}},
+	})}, nil)
+	assert.Error(t, err)
+}
+
+// 验证 Anthropic 非流式响应转换
+func TestFromAnthropicResponse(t *testing.T) {
+	body := `{
+		"id": "msg_1", "type": "message", "role": "assistant",
+		"content": [
+			{"type": "text", "text": "我来查一下"},
+			{"type": "tool_use", "id": "tu_1", "name": "get_weather", "input": {"city": "北京"}}
+		],
+		"stop_reason": "tool_use",
+		"usage": {"input_tokens": 8, "output_tokens": 4, "cache_read_input_tokens": 2}
+	}`
+
+	var resp AnthropicResponse
+	assert.NoError(t, sonic.UnmarshalString(body, &resp))
+	m, err := FromAnthropicResponse(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "我来查一下", m.Content)
+	assert.Equal(t, "tool_calls", m.ResponseMeta.FinishReason)
+	assert.Equal(t, &schema.TokenUsage{
+		PromptTokens:       10,
+		PromptTokenDetails: schema.PromptTokenDetails{CachedTokens: 2},
+		CompletionTokens:   4,
+		TotalTokens:        14,
+	}, m.ResponseMeta.Usage)
+
+	out, err := ToAnthropicResponse(m)
+	assert.NoError(t, err)
+	assert.Equal(t, "tool_use", out.StopReason)
+	assert.Equal(t, resp.Usage, out.Usage)
+	assert.Len(t, out.Content, 2)
+
+	_, err = ToAnthropicResponse(schema.UserMessage("hi"))
+	assert.Error(t, err)
+}
+
+// 验证 Anthropic 流式事件转换后可通过 ConcatMessages 合并
+func TestFromAnthropicEventStream(t *testing.T) {
+	bodies := []string{
+		`{"type":"message_start","message":{"id":"msg_1","role":"assistant","content":[],"usage":{"input_tokens":10,"output_tokens":1}}}`,
+		`{"type":"content_block_start","index":0,"content_block":{"type":"thinking","thinking":""}}`,
+		`{"type":"content_block_delta","index":0,"delta":{"type":"thinking_delta","thinking":"想一想"}}`,
+		`{"type":"content_block_delta","index":0,"delta":{"type":"signature_delta","signature":"sig"}}`,
+		`{"type":"content_block_stop","index":0}`,
+		`{"type":"content_block_start","index":1,"content_block":{"type":"text","text":""}}`,
+		`{"type":"content_block_delta","index":1,"delta":{"type":"text_delta","text":"查询"}}`,
+		`{"type":"content_block_delta","index":1,"delta":{"type":"text_delta","text":"中"}}`,
+		`{"type":"ping"}`,
+		`{"type":"content_block_start","index":2,"content_block":{"type":"tool_use","id":"tu_1","name":"get_weather","input":{}}}`,
+		`{"type":"content_block_delta","index":2,"delta":{"type":"input_json_delta","partial_json":"{\"city\":"}}`,
+		`{"type":"content_block_delta","index":2,"delta":{"type":"input_json_delta","partial_json":"\"北京\"}"}}`,
+		`{"type":"content_block_stop","index":2}`,
+		`{"type":"message_delta","delta":{"stop_reason":"tool_use"},"usage":{"output_tokens":20}}`,
+		`{"type":"message_stop"}`,
+	}
+
+	events := make([]*AnthropicStreamEvent, 0, len(bodies))
+	for _, b := range bodies {
+		ev := &AnthropicStreamEvent{}
+		assert.NoError(t, sonic.UnmarshalString(b, ev))
+		events = append(events, ev)
+	}
+
+	m, err := schema.ConcatMessageStream(FromAnthropicEventStream(schema.StreamReaderFromArray(events)))
+	assert.NoError(t, err)
+	assert.Equal(t, schema.Assistant, m.Role)
+	assert.Equal(t, "想一想", m.ReasoningContent)
+	assert.Equal(t, "sig", m.Extra[ExtraKeyAnthropicThinkingSignature])
+	assert.Equal(t, "查询中", m.Content)
+	assert.Len(t, m.ToolCalls, 1)
+	assert.Equal(t, 0, *m.ToolCalls[0].Index)
+	assert.Equal(t, "tu_1", m.ToolCalls[0].ID)
+	assert.Equal(t, `{"city":"北京"}`, m.ToolCalls[0].Function.Arguments)
+	assert.Equal(t, "tool_calls", m.ResponseMeta.FinishReason)
+	assert.Equal(t, 10, m.ResponseMeta.Usage.PromptTokens)
+	assert.Equal(t, 20, m.ResponseMeta.Usage.CompletionTokens)
+	assert.Equal(t, 30, m.ResponseMeta.Usage.TotalTokens)
+
+	// error 事件
+	d := NewAnthropicStreamDecoder()
+	_, err = d.Decode(&AnthropicStreamEvent{Type: "error", Error: &AnthropicError{Type: "overloaded_error", Message: "busy"}})
+	assert.ErrorContains(t, err, "overloaded_error")
+}
@@ -0,0 +1,120 @@
+package wire
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/bytedance/sonic"
+	"github.com/eino-contrib/jsonschema"
+
+	"github.com/favbox/eino/schema"
+)
+
+// emptyObjectSchema 无参数工具使用的参数 Schema。
+var emptyObjectSchema = json.RawMessage(`{"type":"object","properties":{}}`)
+
+// toolParamsToRaw 将工具参数描述转换为 JSON Schema 原始文本。
+func toolParamsToRaw(info *schema.ToolInfo) (json.RawMessage, error) {
+	if info.ParamsOneOf == nil {
+		return emptyObjectSchema, nil
+	}
+
+	sc, err := info.ToJSONSchema()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert params of tool[%s] to json schema: %w", info.Name, err)
+	}
+	if sc == nil {
+		return emptyObjectSchema, nil
+	}
+
+	raw, err := sonic.Marshal(sc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal json schema of tool[%s]: %w", info.Name, err)
+	}
+
+	return raw, nil
+}
+
+// rawToToolParams 将 JSON Schema 原始文本转换为工具参数描述，为空时返回 nil。
+func rawToToolParams(name string, raw json.RawMessage) (*schema.ParamsOneOf, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+
+	sc := &jsonschema.Schema{}
+	if err := sonic.Unmarshal(raw, sc); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal json schema of tool[%s]: %w", name, err)
+	}
+
+	return schema.NewParamsOneOfByJSONSchema(sc), nil
+}
+
+// partURL 返回多模态部分的 URL，只有 Base64Data 时拼装为 RFC-2397 data URI。
+func partURL(common schema.MessagePartCommon) (string, error) {
+	if common.URL != nil {
+		return *common.URL, nil
+	}
+	if common.Base64Data != nil {
+		if common.MIMEType == "" {
+			return "", fmt.Errorf("mime type is required for base64 data")
+		}
+		return "data:" + common.MIMEType + ";base64," + *common.Base64Data, nil
+	}
+
+	return "", fmt.Errorf("neither url nor base64 data is set")
+}
+
+// partBase64 返回多模态部分的 MIME 类型与 Base64 数据，URL 为 data URI 时会解析出数据。
+// ok 为 false 表示该部分只有普通 URL。
+func partBase64(common schema.MessagePartCommon) (mimeType, data string, ok bool) {
+	if common.Base64Data != nil {
+		return common.MIMEType, *common.Base64Data, true
+	}
+	if common.URL != nil {
+		return parseDataURI(*common.URL)
+	}
+
+	return "", "", false
+}
+
+// parseDataURI 解析 base64 编码的 RFC-2397 data URI。
+func parseDataURI(uri string) (mimeType, data string, ok bool) {
+	rest, found := strings.CutPrefix(uri, "data:")
+	if !found {
+		return "", "", false
+	}
+	meta, data, found := strings.Cut(rest, ",")
+	if !found {
+		return "", "", false
+	}
+	mimeType, found = strings.CutSuffix(meta, ";base64")
+	if !found {
+		return "", "", false
+	}
+
+	return mimeType, data, true
+}
+
+// urlPartCommon 由 URL 构建多模态部分，data URI 会拆分为 MIMEType 与 Base64Data。
+func urlPartCommon(url string) schema.MessagePartCommon {
+	if mimeType, data, ok := parseDataURI(url); ok {
+		return schema.MessagePartCommon{MIMEType: mimeType, Base64Data: &data}
+	}
+
+	return schema.MessagePartCommon{URL: &url}
+}
+
+// base64PartCommon 由 MIME 类型与 Base64 数据构建多模态部分。
+func base64PartCommon(mimeType, data string) schema.MessagePartCommon {
+	return schema.MessagePartCommon{MIMEType: mimeType, Base64Data: &data}
+}
+
+// toolCallIndex 返回工具调用的索引，未设置时使用其在消息中的位置。
+func toolCallIndex(tc schema.ToolCall, pos int) int {
+	if tc.Index != nil {
+		return *tc.Index
+	}
+
+	return pos
+}
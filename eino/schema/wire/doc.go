@@ -0,0 +1,19 @@
+// Package wire 提供 schema.Message 与主流对话 API 线路格式（JSON 请求/响应）之间的双向转换。
+//
+// 支持的格式：
+//   - OpenAI Chat Completions：请求消息、工具定义、响应及流式增量块
+//   - Anthropic Messages：请求消息（含 system）、工具定义、响应及流式事件
+//
+// 转换覆盖 ToolCalls、UserInputMultiContent、AssistantGenMultiContent、ReasoningContent 与 ResponseMeta。
+// 流式增量块转换得到的消息块保留工具调用的 Index，可直接交给 schema.ConcatMessages 合并。
+//
+// 示例：
+//
+//	req, err := wire.ToOpenAIRequest(msgs, tools)
+//	req.Model = "gpt-4o"
+//	body, err := sonic.Marshal(req)
+//
+//	var resp wire.OpenAIChatResponse
+//	err = sonic.Unmarshal(respBody, &resp)
+//	msg, err := wire.FromOpenAIResponse(&resp)
+package wire
@@ -0,0 +1,733 @@
+package wire
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/favbox/eino/schema"
+)
+
+// OpenAIChatRequest OpenAI Chat Completions 请求体中与消息和工具相关的部分。
+// 其余采样参数由调用方按需补充。
+type OpenAIChatRequest struct {
+	Model      string          `json:"model,omitempty"`
+	Messages   []OpenAIMessage `json:"messages"`
+	Tools      []OpenAITool    `json:"tools,omitempty"`
+	ToolChoice any             `json:"tool_choice,omitempty"`
+	Stream     bool            `json:"stream,omitempty"`
+}
+
+// OpenAIMessage OpenAI 消息，同时用于请求消息、响应消息与流式增量（delta）。
+type OpenAIMessage struct {
+	Role             string           `json:"role,omitempty"`
+	Content          OpenAIContent    `json:"content"`
+	Name             string           `json:"name,omitempty"`
+	ToolCalls        []OpenAIToolCall `json:"tool_calls,omitempty"`
+	ToolCallID       string           `json:"tool_call_id,omitempty"`
+	ReasoningContent string           `json:"reasoning_content,omitempty"`
+	Audio            *OpenAIAudio     `json:"audio,omitempty"`
+}
+
+// OpenAIContent OpenAI 消息内容，可以是字符串或内容部分数组。
+// Parts 非空时序列化为数组，否则序列化为字符串（空字符串序列化为 null）。
+type OpenAIContent struct {
+	Text  string
+	Parts []OpenAIContentPart
+}
+
+// MarshalJSON 实现 json.Marshaler。
+func (c OpenAIContent) MarshalJSON() ([]byte, error) {
+	if len(c.Parts) > 0 {
+		return sonic.Marshal(c.Parts)
+	}
+	if c.Text == "" {
+		return []byte("null"), nil
+	}
+	return sonic.Marshal(c.Text)
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler。
+func (c *OpenAIContent) UnmarshalJSON(data []byte) error {
+	*c = OpenAIContent{}
+	s := strings.TrimSpace(string(data))
+	switch {
+	case s == "" || s == "null":
+		return nil
+	case strings.HasPrefix(s, "["):
+		return sonic.Unmarshal(data, &c.Parts)
+	default:
+		return sonic.Unmarshal(data, &c.Text)
+	}
+}
+
+// OpenAIContentPart OpenAI 多模态内容部分。
+type OpenAIContentPart struct {
+	Type       string            `json:"type"`
+	Text       string            `json:"text,omitempty"`
+	ImageURL   *OpenAIImageURL   `json:"image_url,omitempty"`
+	InputAudio *OpenAIInputAudio `json:"input_audio,omitempty"`
+	File       *OpenAIFile       `json:"file,omitempty"`
+}
+
+// OpenAIImageURL 图片内容。
+type OpenAIImageURL struct {
+	URL    string `json:"url"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// OpenAIInputAudio 音频输入内容。
+type OpenAIInputAudio struct {
+	Data   string `json:"data"`
+	Format string `json:"format"`
+}
+
+// OpenAIFile 文件输入内容。
+type OpenAIFile struct {
+	FileData string `json:"file_data,omitempty"`
+	FileID   string `json:"file_id,omitempty"`
+	Filename string `json:"filename,omitempty"`
+}
+
+// OpenAIAudio 模型生成的音频输出。
+type OpenAIAudio struct {
+	ID         string `json:"id,omitempty"`
+	Data       string `json:"data,omitempty"`
+	Transcript string `json:"transcript,omitempty"`
+	ExpiresAt  int64  `json:"expires_at,omitempty"`
+}
+
+// OpenAIToolCall OpenAI 工具调用，流式增量中 Index 用于标识同一工具调用的分块。
+type OpenAIToolCall struct {
+	Index    *int               `json:"index,omitempty"`
+	ID       string             `json:"id,omitempty"`
+	Type     string             `json:"type,omitempty"`
+	Function OpenAIFunctionCall `json:"function"`
+}
+
+// OpenAIFunctionCall 函数调用。
+type OpenAIFunctionCall struct {
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments"`
+}
+
+// OpenAITool OpenAI 工具定义。
+type OpenAITool struct {
+	Type     string            `json:"type"`
+	Function OpenAIFunctionDef `json:"function"`
+}
+
+// OpenAIFunctionDef 函数定义，Parameters 为 JSON Schema。
+type OpenAIFunctionDef struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"`
+}
+
+// OpenAIChatResponse OpenAI Chat Completions 非流式响应。
+type OpenAIChatResponse struct {
+	ID      string         `json:"id,omitempty"`
+	Object  string         `json:"object,omitempty"`
+	Created int64          `json:"created,omitempty"`
+	Model   string         `json:"model,omitempty"`
+	Choices []OpenAIChoice `json:"choices"`
+	Usage   *OpenAIUsage   `json:"usage,omitempty"`
+}
+
+// OpenAIChoice 非流式响应的候选结果。
+type OpenAIChoice struct {
+	Index        int              `json:"index"`
+	Message      OpenAIMessage    `json:"message"`
+	FinishReason string           `json:"finish_reason,omitempty"`
+	LogProbs     *schema.LogProbs `json:"logprobs,omitempty"`
+}
+
+// OpenAIChatChunk OpenAI Chat Completions 流式增量块。
+type OpenAIChatChunk struct {
+	ID      string              `json:"id,omitempty"`
+	Object  string              `json:"object,omitempty"`
+	Created int64               `json:"created,omitempty"`
+	Model   string              `json:"model,omitempty"`
+	Choices []OpenAIChunkChoice `json:"choices"`
+	Usage   *OpenAIUsage        `json:"usage,omitempty"`
+}
+
+// OpenAIChunkChoice 流式增量块的候选结果。
+type OpenAIChunkChoice struct {
+	Index        int              `json:"index"`
+	Delta        OpenAIMessage    `json:"delta"`
+	FinishReason string           `json:"finish_reason,omitempty"`
+	LogProbs     *schema.LogProbs `json:"logprobs,omitempty"`
+}
+
+// OpenAIUsage token 使用量。
+type OpenAIUsage struct {
+	PromptTokens        int                        `json:"prompt_tokens"`
+	CompletionTokens    int                        `json:"completion_tokens"`
+	TotalTokens         int                        `json:"total_tokens"`
+	PromptTokensDetails *OpenAIPromptTokensDetails `json:"prompt_tokens_details,omitempty"`
+}
+
+// OpenAIPromptTokensDetails 提示词 token 明细。
+type OpenAIPromptTokensDetails struct {
+	CachedTokens int `json:"cached_tokens"`
+}
+
+const (
+	openAIPartText       = "text"
+	openAIPartImageURL   = "image_url"
+	openAIPartInputAudio = "input_audio"
+	openAIPartFile       = "file"
+
+	// ExtraKeyOpenAIAudioID 存放模型生成音频 ID 的 MessagePartCommon.Extra 键，
+	// 多轮对话中回传助手音频时使用。
+	ExtraKeyOpenAIAudioID = "openai_audio_id"
+	// ExtraKeyOpenAIAudioTranscript 存放模型生成音频文字稿的 MessagePartCommon.Extra 键。
+	ExtraKeyOpenAIAudioTranscript = "openai_audio_transcript"
+	// ExtraKeyFilename 存放文件名的 MessagePartCommon.Extra 键。
+	ExtraKeyFilename = "filename"
+)
+
+// ToOpenAIRequest 将消息与工具转换为 OpenAI 请求。
+func ToOpenAIRequest(msgs []*schema.Message, tools []*schema.ToolInfo) (*OpenAIChatRequest, error) {
+	oMsgs, err := ToOpenAIMessages(msgs)
+	if err != nil {
+		return nil, err
+	}
+	oTools, err := ToOpenAITools(tools)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OpenAIChatRequest{Messages: oMsgs, Tools: oTools}, nil
+}
+
+// FromOpenAIRequest 将 OpenAI 请求转换为消息与工具。
+func FromOpenAIRequest(req *OpenAIChatRequest) ([]*schema.Message, []*schema.ToolInfo, error) {
+	msgs, err := FromOpenAIMessages(req.Messages)
+	if err != nil {
+		return nil, nil, err
+	}
+	tools, err := FromOpenAITools(req.Tools)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return msgs, tools, nil
+}
+
+// ToOpenAIToolChoice 将工具调用策略转换为 OpenAI 的 tool_choice 取值。
+func ToOpenAIToolChoice(tc schema.ToolChoice) string {
+	switch tc {
+	case schema.ToolChoiceForbidden:
+		return "none"
+	case schema.ToolChoiceForced:
+		return "required"
+	default:
+		return "auto"
+	}
+}
+
+// FromOpenAIToolChoice 将 OpenAI 的 tool_choice 取值转换为工具调用策略。
+// 指定具体函数的对象形式视为强制调用。
+func FromOpenAIToolChoice(tc any) schema.ToolChoice {
+	switch v := tc.(type) {
+	case string:
+		switch v {
+		case "none":
+			return schema.ToolChoiceForbidden
+		case "required":
+			return schema.ToolChoiceForced
+		default:
+			return schema.ToolChoiceAllowed
+		}
+	case nil:
+		return schema.ToolChoiceAllowed
+	default:
+		return schema.ToolChoiceForced
+	}
+}
+
+// ToOpenAITools 将工具信息转换为 OpenAI 工具定义。
+func ToOpenAITools(tools []*schema.ToolInfo) ([]OpenAITool, error) {
+	if len(tools) == 0 {
+		return nil, nil
+	}
+
+	ret := make([]OpenAITool, 0, len(tools))
+	for _, t := range tools {
+		params, err := toolParamsToRaw(t)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, OpenAITool{
+			Type: "function",
+			Function: OpenAIFunctionDef{
+				Name:        t.Name,
+				Description: t.Desc,
+				Parameters:  params,
+			},
+		})
+	}
+
+	return ret, nil
+}
+
+// FromOpenAITools 将 OpenAI 工具定义转换为工具信息。
+func FromOpenAITools(tools []OpenAITool) ([]*schema.ToolInfo, error) {
+	if len(tools) == 0 {
+		return nil, nil
+	}
+
+	ret := make([]*schema.ToolInfo, 0, len(tools))
+	for _, t := range tools {
+		params, err := rawToToolParams(t.Function.Name, t.Function.Parameters)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, &schema.ToolInfo{
+			Name:        t.Function.Name,
+			Desc:        t.Function.Description,
+			ParamsOneOf: params,
+		})
+	}
+
+	return ret, nil
+}
+
+// ToOpenAIMessages 将消息列表转换为 OpenAI 请求消息。
+func ToOpenAIMessages(msgs []*schema.Message) ([]OpenAIMessage, error) {
+	ret := make([]OpenAIMessage, 0, len(msgs))
+	for i, m := range msgs {
+		om, err := toOpenAIMessage(m)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert message[%d] to openai format: %w", i, err)
+		}
+		ret = append(ret, om)
+	}
+
+	return ret, nil
+}
+
+// FromOpenAIMessages 将 OpenAI 请求消息转换为消息列表。
+// 工具消息的 ToolName 由之前助手消息中同 ID 的工具调用推断。
+func FromOpenAIMessages(oMsgs []OpenAIMessage) ([]*schema.Message, error) {
+	toolNames := make(map[string]string)
+	ret := make([]*schema.Message, 0, len(oMsgs))
+	for i := range oMsgs {
+		m, err := fromOpenAIMessage(&oMsgs[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert openai message[%d]: %w", i, err)
+		}
+		for _, tc := range m.ToolCalls {
+			toolNames[tc.ID] = tc.Function.Name
+		}
+		if m.Role == schema.Tool && m.ToolName == "" {
+			m.ToolName = toolNames[m.ToolCallID]
+		}
+		ret = append(ret, m)
+	}
+
+	return ret, nil
+}
+
+// FromOpenAIResponse 将 OpenAI 非流式响应的第一个候选结果转换为消息。
+func FromOpenAIResponse(resp *OpenAIChatResponse) (*schema.Message, error) {
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("openai response has no choice")
+	}
+
+	choice := &resp.Choices[0]
+	m, err := fromOpenAIMessage(&choice.Message)
+	if err != nil {
+		return nil, err
+	}
+	if m.Role == "" {
+		m.Role = schema.Assistant
+	}
+	m.ResponseMeta = &schema.ResponseMeta{
+		FinishReason: choice.FinishReason,
+		Usage:        fromOpenAIUsage(resp.Usage),
+		LogProbs:     choice.LogProbs,
+	}
+
+	return m, nil
+}
+
+// ToOpenAIResponse 将消息转换为 OpenAI 非流式响应，适用于实现兼容 OpenAI 的服务端或测试桩。
+func ToOpenAIResponse(m *schema.Message) (*OpenAIChatResponse, error) {
+	om, err := toOpenAIMessage(m)
+	if err != nil {
+		return nil, err
+	}
+
+	choice := OpenAIChoice{Message: om}
+	resp := &OpenAIChatResponse{Object: "chat.completion"}
+	if m.ResponseMeta != nil {
+		choice.FinishReason = m.ResponseMeta.FinishReason
+		choice.LogProbs = m.ResponseMeta.LogProbs
+		resp.Usage = toOpenAIUsage(m.ResponseMeta.Usage)
+	}
+	resp.Choices = []OpenAIChoice{choice}
+
+	return resp, nil
+}
+
+// FromOpenAIChunk 将 OpenAI 流式增量块的第一个候选结果转换为消息块。
+//
+// 工具调用保留 Index，多个消息块可通过 schema.ConcatMessages 合并为完整消息。
+// 仅包含 usage 的结尾块转换为只有 ResponseMeta 的消息块。
+func FromOpenAIChunk(chunk *OpenAIChatChunk) (*schema.Message, error) {
+	m := &schema.Message{}
+	if len(chunk.Choices) > 0 {
+		choice := &chunk.Choices[0]
+		var err error
+		m, err = fromOpenAIMessage(&choice.Delta)
+		if err != nil {
+			return nil, err
+		}
+		if choice.FinishReason != "" || choice.LogProbs != nil {
+			m.ResponseMeta = &schema.ResponseMeta{
+				FinishReason: choice.FinishReason,
+				LogProbs:     choice.LogProbs,
+			}
+		}
+	}
+
+	if chunk.Usage != nil {
+		if m.ResponseMeta == nil {
+			m.ResponseMeta = &schema.ResponseMeta{}
+		}
+		m.ResponseMeta.Usage = fromOpenAIUsage(chunk.Usage)
+	}
+
+	return m, nil
+}
+
+// ToOpenAIChunk 将消息块转换为 OpenAI 流式增量块。
+// 未设置 Index 的工具调用以其在消息中的位置作为索引。
+func ToOpenAIChunk(m *schema.Message) (*OpenAIChatChunk, error) {
+	delta, err := toOpenAIMessage(m)
+	if err != nil {
+		return nil, err
+	}
+	for i := range delta.ToolCalls {
+		idx := toolCallIndex(m.ToolCalls[i], i)
+		delta.ToolCalls[i].Index = &idx
+	}
+
+	choice := OpenAIChunkChoice{Delta: delta}
+	chunk := &OpenAIChatChunk{Object: "chat.completion.chunk"}
+	if m.ResponseMeta != nil {
+		choice.FinishReason = m.ResponseMeta.FinishReason
+		choice.LogProbs = m.ResponseMeta.LogProbs
+		chunk.Usage = toOpenAIUsage(m.ResponseMeta.Usage)
+	}
+	chunk.Choices = []OpenAIChunkChoice{choice}
+
+	return chunk, nil
+}
+
+// FromOpenAIChunkStream 将 OpenAI 流式增量块流转换为消息块流。
+func FromOpenAIChunkStream(sr *schema.StreamReader[*OpenAIChatChunk]) *schema.StreamReader[*schema.Message] {
+	return schema.StreamReaderWithConvert(sr, FromOpenAIChunk)
+}
+
+// toOpenAIMessage 转换单条消息。
+func toOpenAIMessage(m *schema.Message) (OpenAIMessage, error) {
+	om := OpenAIMessage{
+		Role:             string(m.Role),
+		Content:          OpenAIContent{Text: m.Content},
+		Name:             m.Name,
+		ToolCallID:       m.ToolCallID,
+		ReasoningContent: m.ReasoningContent,
+	}
+
+	if len(m.UserInputMultiContent) > 0 {
+		parts, err := toOpenAIContentParts(m.Content, m.UserInputMultiContent)
+		if err != nil {
+			return om, err
+		}
+		om.Content = OpenAIContent{Parts: parts}
+	}
+
+	for _, part := range m.AssistantGenMultiContent {
+		switch part.Type {
+		case schema.ChatMessagePartTypeText:
+			om.Content.Text += part.Text
+		case schema.ChatMessagePartTypeAudioURL:
+			if part.Audio == nil {
+				return om, fmt.Errorf("audio part has no audio")
+			}
+			audio := &OpenAIAudio{}
+			audio.ID, _ = part.Audio.Extra[ExtraKeyOpenAIAudioID].(string)
+			audio.Transcript, _ = part.Audio.Extra[ExtraKeyOpenAIAudioTranscript].(string)
+			if part.Audio.Base64Data != nil {
+				audio.Data = *part.Audio.Base64Data
+			}
+			om.Audio = audio
+		default:
+			return om, fmt.Errorf("unsupported assistant output part type for openai: %s", part.Type)
+		}
+	}
+
+	for _, tc := range m.ToolCalls {
+		typ := tc.Type
+		if typ == "" {
+			typ = "function"
+		}
+		om.ToolCalls = append(om.ToolCalls, OpenAIToolCall{
+			ID:   tc.ID,
+			Type: typ,
+			Function: OpenAIFunctionCall{
+				Name:      tc.Function.Name,
+				Arguments: tc.Function.Arguments,
+			},
+		})
+	}
+
+	return om, nil
+}
+
+// toOpenAIContentParts 转换用户多模态输入，Content 非空时作为首个文本部分。
+func toOpenAIContentParts(content string, parts []schema.MessageInputPart) ([]OpenAIContentPart, error) {
+	ret := make([]OpenAIContentPart, 0, len(parts)+1)
+	if content != "" {
+		ret = append(ret, OpenAIContentPart{Type: openAIPartText, Text: content})
+	}
+
+	for _, part := range parts {
+		switch part.Type {
+		case schema.ChatMessagePartTypeText:
+			ret = append(ret, OpenAIContentPart{Type: openAIPartText, Text: part.Text})
+		case schema.ChatMessagePartTypeImageURL:
+			if part.Image == nil {
+				return nil, fmt.Errorf("image part has no image")
+			}
+			url, err := partURL(part.Image.MessagePartCommon)
+			if err != nil {
+				return nil, fmt.Errorf("invalid image part: %w", err)
+			}
+			ret = append(ret, OpenAIContentPart{
+				Type:     openAIPartImageURL,
+				ImageURL: &OpenAIImageURL{URL: url, Detail: string(part.Image.Detail)},
+			})
+		case schema.ChatMessagePartTypeAudioURL:
+			if part.Audio == nil {
+				return nil, fmt.Errorf("audio part has no audio")
+			}
+			mimeType, data, ok := partBase64(part.Audio.MessagePartCommon)
+			if !ok {
+				return nil, fmt.Errorf("openai audio input requires base64 data")
+			}
+			ret = append(ret, OpenAIContentPart{
+				Type:       openAIPartInputAudio,
+				InputAudio: &OpenAIInputAudio{Data: data, Format: audioMIMEToFormat(mimeType)},
+			})
+		case schema.ChatMessagePartTypeFileURL:
+			if part.File == nil {
+				return nil, fmt.Errorf("file part has no file")
+			}
+			url, err := partURL(part.File.MessagePartCommon)
+			if err != nil {
+				return nil, fmt.Errorf("invalid file part: %w", err)
+			}
+			if _, _, ok := parseDataURI(url); !ok {
+				return nil, fmt.Errorf("openai file input requires base64 data")
+			}
+			filename, _ := part.File.Extra[ExtraKeyFilename].(string)
+			ret = append(ret, OpenAIContentPart{
+				Type: openAIPartFile,
+				File: &OpenAIFile{FileData: url, Filename: filename},
+			})
+		default:
+			return nil, fmt.Errorf("unsupported user input part type for openai: %s", part.Type)
+		}
+	}
+
+	return ret, nil
+}
+
+// fromOpenAIMessage 转换单条 OpenAI 消息。
+func fromOpenAIMessage(om *OpenAIMessage) (*schema.Message, error) {
+	m := &schema.Message{
+		Role:             schema.RoleType(om.Role),
+		Content:          om.Content.Text,
+		Name:             om.Name,
+		ToolCallID:       om.ToolCallID,
+		ReasoningContent: om.ReasoningContent,
+	}
+
+	if len(om.Content.Parts) > 0 {
+		parts, err := fromOpenAIContentParts(om.Content.Parts)
+		if err != nil {
+			return nil, err
+		}
+		if m.Role == schema.Assistant {
+			for _, p := range parts {
+				m.Content += p.Text
+			}
+		} else {
+			m.UserInputMultiContent = parts
+		}
+	}
+
+	if om.Audio != nil {
+		extra := map[string]any{}
+		if om.Audio.ID != "" {
+			extra[ExtraKeyOpenAIAudioID] = om.Audio.ID
+		}
+		if om.Audio.Transcript != "" {
+			extra[ExtraKeyOpenAIAudioTranscript] = om.Audio.Transcript
+		}
+		audio := &schema.MessageOutputAudio{MessagePartCommon: schema.MessagePartCommon{Extra: extra}}
+		if om.Audio.Data != "" {
+			data := om.Audio.Data
+			audio.Base64Data = &data
+		}
+		m.AssistantGenMultiContent = append(m.AssistantGenMultiContent, schema.MessageOutputPart{
+			Type:  schema.ChatMessagePartTypeAudioURL,
+			Audio: audio,
+		})
+	}
+
+	for i := range om.ToolCalls {
+		otc := &om.ToolCalls[i]
+		tc := schema.ToolCall{
+			ID:   otc.ID,
+			Type: otc.Type,
+			Function: schema.FunctionCall{
+				Name:      otc.Function.Name,
+				Arguments: otc.Function.Arguments,
+			},
+		}
+		if otc.Index != nil {
+			idx := *otc.Index
+			tc.Index = &idx
+		}
+		m.ToolCalls = append(m.ToolCalls, tc)
+	}
+
+	return m, nil
+}
+
+// fromOpenAIContentParts 转换 OpenAI 内容部分。
+func fromOpenAIContentParts(parts []OpenAIContentPart) ([]schema.MessageInputPart, error) {
+	ret := make([]schema.MessageInputPart, 0, len(parts))
+	for _, p := range parts {
+		switch p.Type {
+		case openAIPartText:
+			ret = append(ret, schema.MessageInputPart{Type: schema.ChatMessagePartTypeText, Text: p.Text})
+		case openAIPartImageURL:
+			if p.ImageURL == nil {
+				return nil, fmt.Errorf("image_url part has no image_url")
+			}
+			ret = append(ret, schema.MessageInputPart{
+				Type: schema.ChatMessagePartTypeImageURL,
+				Image: &schema.MessageInputImage{
+					MessagePartCommon: urlPartCommon(p.ImageURL.URL),
+					Detail:            schema.ImageURLDetail(p.ImageURL.Detail),
+				},
+			})
+		case openAIPartInputAudio:
+			if p.InputAudio == nil {
+				return nil, fmt.Errorf("input_audio part has no input_audio")
+			}
+			ret = append(ret, schema.MessageInputPart{
+				Type: schema.ChatMessagePartTypeAudioURL,
+				Audio: &schema.MessageInputAudio{
+					MessagePartCommon: base64PartCommon(audioFormatToMIME(p.InputAudio.Format), p.InputAudio.Data),
+				},
+			})
+		case openAIPartFile:
+			if p.File == nil {
+				return nil, fmt.Errorf("file part has no file")
+			}
+			common := urlPartCommon(p.File.FileData)
+			if p.File.FileData == "" {
+				common = schema.MessagePartCommon{}
+			}
+			if p.File.Filename != "" || p.File.FileID != "" {
+				common.Extra = map[string]any{}
+				if p.File.Filename != "" {
+					common.Extra[ExtraKeyFilename] = p.File.Filename
+				}
+				if p.File.FileID != "" {
+					common.Extra["file_id"] = p.File.FileID
+				}
+			}
+			ret = append(ret, schema.MessageInputPart{
+				Type: schema.ChatMessagePartTypeFileURL,
+				File: &schema.MessageInputFile{MessagePartCommon: common},
+			})
+		default:
+			return nil, fmt.Errorf("unsupported openai content part type: %s", p.Type)
+		}
+	}
+
+	return ret, nil
+}
+
+// fromOpenAIUsage 转换 token 使用量。
+func fromOpenAIUsage(u *OpenAIUsage) *schema.TokenUsage {
+	if u == nil {
+		return nil
+	}
+
+	usage := &schema.TokenUsage{
+		PromptTokens:     u.PromptTokens,
+		CompletionTokens: u.CompletionTokens,
+		TotalTokens:      u.TotalTokens,
+	}
+	if u.PromptTokensDetails != nil {
+		usage.PromptTokenDetails.CachedTokens = u.PromptTokensDetails.CachedTokens
+	}
+
+	return usage
+}
+
+// toOpenAIUsage 转换 token 使用量。
+func toOpenAIUsage(u *schema.TokenUsage) *OpenAIUsage {
+	if u == nil {
+		return nil
+	}
+
+	usage := &OpenAIUsage{
+		PromptTokens:     u.PromptTokens,
+		CompletionTokens: u.CompletionTokens,
+		TotalTokens:      u.TotalTokens,
+	}
+	if u.PromptTokenDetails.CachedTokens > 0 {
+		usage.PromptTokensDetails = &OpenAIPromptTokensDetails{CachedTokens: u.PromptTokenDetails.CachedTokens}
+	}
+
+	return usage
+}
+
+// audioMIMEToFormat 将音频 MIME 类型转换为 OpenAI 的音频格式名。
+func audioMIMEToFormat(mimeType string) string {
+	switch mimeType {
+	case "audio/mpeg", "audio/mp3":
+		return "mp3"
+	case "":
+		return "wav"
+	default:
+		format, _ := strings.CutPrefix(mimeType, "audio/")
+		format, _ = strings.CutPrefix(format, "x-")
+		return format
+	}
+}
+
+// audioFormatToMIME 将 OpenAI 的音频格式名转换为 MIME 类型。
+func audioFormatToMIME(format string) string {
+	switch format {
+	case "mp3":
+		return "audio/mpeg"
+	case "":
+		return ""
+	default:
+		return "audio/" + format
+	}
+}
@@ -0,0 +1,193 @@
+package wire
+
+import (
+	"testing"
+
+	"github.com/bytedance/sonic"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/favbox/eino/schema"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func testTools() []*schema.ToolInfo {
+	return []*schema.ToolInfo{
+		{
+			Name: "get_weather",
+			Desc: "查询天气",
+			ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+				"city": {Type: schema.String, Desc: "城市", Required: true},
+			}),
+		},
+		{Name: "now", Desc: "当前时间"},
+	}
+}
+
+// 验证消息与工具转换为 OpenAI 请求的 JSON 结构
+func TestToOpenAIRequest(t *testing.T) {
+	msgs := []*schema.Message{
+		schema.SystemMessage("你是助手"),
+		{
+			Role:    schema.User,
+			Content: "描述这些内容",
+			UserInputMultiContent: []schema.MessageInputPart{
+				{Type: schema.ChatMessagePartTypeImageURL, Image: &schema.MessageInputImage{
+					MessagePartCommon: schema.MessagePartCommon{URL: strPtr("https://example.com/a.png")},
+					Detail:            schema.ImageURLDetailHigh,
+				}},
+				{Type: schema.ChatMessagePartTypeAudioURL, Audio: &schema.MessageInputAudio{
+					MessagePartCommon: schema.MessagePartCommon{Base64Data: strPtr("UklG"), MIMEType: "audio/wav"},
+				}},
+				{Type: schema.ChatMessagePartTypeFileURL, File: &schema.MessageInputFile{
+					MessagePartCommon: schema.MessagePartCommon{
+						Base64Data: strPtr("JVBE"),
+						MIMEType:   "application/pdf",
+						Extra:      map[string]any{ExtraKeyFilename: "a.pdf"},
+					},
+				}},
+			},
+		},
+		{
+			Role:             schema.Assistant,
+			ReasoningContent: "需要查天气",
+			ToolCalls: []schema.ToolCall{
+				{ID: "call_1", Function: schema.FunctionCall{Name: "get_weather", Arguments: `{"city":"北京"}`}},
+			},
+		},
+		schema.ToolMessage("晴", "call_1", schema.WithToolName("get_weather")),
+	}
+
+	req, err := ToOpenAIRequest(msgs, testTools())
+	assert.NoError(t, err)
+	req.Model = "gpt-4o"
+
+	data, err := sonic.Marshal(req)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"model": "gpt-4o",
+		"messages": [
+			{"role": "system", "content": "你是助手"},
+			{"role": "user", "content": [
+				{"type": "text", "text": "描述这些内容"},
+				{"type": "image_url", "image_url": {"url": "https://example.com/a.png", "detail": "high"}},
+				{"type": "input_audio", "input_audio": {"data": "UklG", "format": "wav"}},
+				{"type": "file", "file": {"file_data": "data:application/pdf;base64,JVBE", "filename": "a.pdf"}}
+			]},
+			{"role": "assistant", "content": null, "reasoning_content": "需要查天气", "tool_calls": [
+				{"id": "call_1", "type": "function", "function": {"name": "get_weather", "arguments": "{\"city\":\"北京\"}"}}
+			]},
+			{"role": "tool", "content": "晴", "tool_call_id": "call_1"}
+		],
+		"tools": [
+			{"type": "function", "function": {"name": "get_weather", "description": "查询天气", "parameters": {
+				"type": "object",
+				"properties": {"city": {"type": "string", "description": "城市"}},
+				"required": ["city"]
+			}}},
+			{"type": "function", "function": {"name": "now", "description": "当前时间", "parameters": {"type": "object", "properties": {}}}}
+		]
+	}`, string(data))
+
+	// 反向转换
+	var decoded OpenAIChatRequest
+	assert.NoError(t, sonic.Unmarshal(data, &decoded))
+	back, tools, err := FromOpenAIRequest(&decoded)
+	assert.NoError(t, err)
+	assert.Len(t, back, 4)
+	assert.Equal(t, "你是助手", back[0].Content)
+	assert.Len(t, back[1].UserInputMultiContent, 4)
+	assert.Equal(t, "描述这些内容", back[1].UserInputMultiContent[0].Text)
+	assert.Equal(t, "https://example.com/a.png", *back[1].UserInputMultiContent[1].Image.URL)
+	assert.Equal(t, "audio/wav", back[1].UserInputMultiContent[2].Audio.MIMEType)
+	assert.Equal(t, "JVBE", *back[1].UserInputMultiContent[3].File.Base64Data)
+	assert.Equal(t, "application/pdf", back[1].UserInputMultiContent[3].File.MIMEType)
+	assert.Equal(t, msgs[2].ToolCalls[0].Function, back[2].ToolCalls[0].Function)
+	assert.Equal(t, "需要查天气", back[2].ReasoningContent)
+	assert.Equal(t, "get_weather", back[3].ToolName)
+	assert.Equal(t, "call_1", back[3].ToolCallID)
+
+	assert.Len(t, tools, 2)
+	js, err := tools[0].ToJSONSchema()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"city"}, js.Required)
+}
+
+// 验证 OpenAI 非流式响应转换
+func TestFromOpenAIResponse(t *testing.T) {
+	body := `{
+		"id": "chatcmpl-1",
+		"choices": [{
+			"index": 0,
+			"message": {"role": "assistant", "content": null, "tool_calls": [
+				{"id": "call_1", "type": "function", "function": {"name": "get_weather", "arguments": "{}"}}
+			]},
+			"finish_reason": "tool_calls"
+		}],
+		"usage": {"prompt_tokens": 10, "completion_tokens": 5, "total_tokens": 15, "prompt_tokens_details": {"cached_tokens": 2}}
+	}`
+
+	var resp OpenAIChatResponse
+	assert.NoError(t, sonic.UnmarshalString(body, &resp))
+	m, err := FromOpenAIResponse(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, schema.Assistant, m.Role)
+	assert.Equal(t, "get_weather", m.ToolCalls[0].Function.Name)
+	assert.Equal(t, "tool_calls", m.ResponseMeta.FinishReason)
+	assert.Equal(t, &schema.TokenUsage{
+		PromptTokens:       10,
+		PromptTokenDetails: schema.PromptTokenDetails{CachedTokens: 2},
+		CompletionTokens:   5,
+		TotalTokens:        15,
+	}, m.ResponseMeta.Usage)
+
+	out, err := ToOpenAIResponse(m)
+	assert.NoError(t, err)
+	assert.Equal(t, "tool_calls", out.Choices[0].FinishReason)
+	assert.Equal(t, resp.Usage, out.Usage)
+	assert.Equal(t, resp.Choices[0].Message.ToolCalls, out.Choices[0].Message.ToolCalls)
+
+	_, err = FromOpenAIResponse(&OpenAIChatResponse{})
+	assert.Error(t, err)
+}
+
+// 验证 OpenAI 流式增量块转换后可通过 ConcatMessages 合并
+func TestFromOpenAIChunkStream(t *testing.T) {
+	bodies := []string{
+		`{"choices":[{"index":0,"delta":{"role":"assistant","content":""}}]}`,
+		`{"choices":[{"index":0,"delta":{"content":"好的，"}}]}`,
+		`{"choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"get_weather","arguments":""}}]}}]}`,
+		`{"choices":[{"index":0,"delta":{"tool_calls":[{"index":1,"id":"call_2","type":"function","function":{"name":"now","arguments":""}}]}}]}`,
+		`{"choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{\"city\":"}}]}}]}`,
+		`{"choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"北京\"}"}}]}}]}`,
+		`{"choices":[{"index":0,"delta":{"tool_calls":[{"index":1,"function":{"arguments":"{}"}}]}}]}`,
+		`{"choices":[{"index":0,"delta":{},"finish_reason":"tool_calls"}]}`,
+		`{"choices":[],"usage":{"prompt_tokens":10,"completion_tokens":5,"total_tokens":15}}`,
+	}
+
+	chunks := make([]*OpenAIChatChunk, 0, len(bodies))
+	for _, b := range bodies {
+		c := &OpenAIChatChunk{}
+		assert.NoError(t, sonic.UnmarshalString(b, c))
+		chunks = append(chunks, c)
+	}
+
+	m, err := schema.ConcatMessageStream(FromOpenAIChunkStream(schema.StreamReaderFromArray(chunks)))
+	assert.NoError(t, err)
+	assert.Equal(t, schema.Assistant, m.Role)
+	assert.Equal(t, "好的，", m.Content)
+	assert.Len(t, m.ToolCalls, 2)
+	assert.Equal(t, "call_1", m.ToolCalls[0].ID)
+	assert.Equal(t, `{"city":"北京"}`, m.ToolCalls[0].Function.Arguments)
+	assert.Equal(t, "now", m.ToolCalls[1].Function.Name)
+	assert.Equal(t, "{}", m.ToolCalls[1].Function.Arguments)
+	assert.Equal(t, "tool_calls", m.ResponseMeta.FinishReason)
+	assert.Equal(t, 15, m.ResponseMeta.Usage.TotalTokens)
+
+	// 消息块重新编码为增量块时补全工具调用索引
+	chunk, err := ToOpenAIChunk(schema.AssistantMessage("", []schema.ToolCall{{ID: "a"}, {ID: "b"}}))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, *chunk.Choices[0].Delta.ToolCalls[1].Index)
+}
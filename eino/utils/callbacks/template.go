@@ -10,6 +10,7 @@ import (
 	"github.com/favbox/eino/components/indexer"
 	"github.com/favbox/eino/components/model"
 	"github.com/favbox/eino/components/prompt"
+	"github.com/favbox/eino/components/reranker"
 	"github.com/favbox/eino/components/retriever"
 	"github.com/favbox/eino/components/tool"
 	"github.com/favbox/eino/compose"
@@ -39,6 +40,7 @@ type HandlerHelper struct {
 	embeddingHandler   *EmbeddingCallbackHandler
 	indexerHandler     *IndexerCallbackHandler
 	retrieverHandler   *RetrieverCallbackHandler
+	rerankerHandler    *RerankerCallbackHandler
 	loaderHandler      *LoaderCallbackHandler
 	transformerHandler *TransformerCallbackHandler
 	toolHandler        *ToolCallbackHandler
@@ -81,6 +83,12 @@ func (c *HandlerHelper) Retriever(handler *RetrieverCallbackHandler) *HandlerHel
 	return c
 }
 
+// Reranker 设置重排序器组件的回调处理器。
+func (c *HandlerHelper) Reranker(handler *RerankerCallbackHandler) *HandlerHelper {
+	c.rerankerHandler = handler
+	return c
+}
+
 // Loader 设置加载器组件的回调处理器。
 func (c *HandlerHelper) Loader(handler *LoaderCallbackHandler) *HandlerHelper {
 	c.loaderHandler = handler
@@ -142,6 +150,8 @@ func (c *handlerTemplate) OnStart(ctx context.Context, info *callbacks.RunInfo,
 		return c.indexerHandler.OnStart(ctx, info, indexer.ConvCallbackInput(input))
 	case components.ComponentOfRetriever:
 		return c.retrieverHandler.OnStart(ctx, info, retriever.ConvCallbackInput(input))
+	case components.ComponentOfReranker:
+		return c.rerankerHandler.OnStart(ctx, info, reranker.ConvCallbackInput(input))
 	case components.ComponentOfLoader:
 		return c.loaderHandler.OnStart(ctx, info, document.ConvLoaderCallbackInput(input))
 	case components.ComponentOfTransformer:
@@ -172,6 +182,8 @@ func (c *handlerTemplate) OnEnd(ctx context.Context, info *callbacks.RunInfo, ou
 		return c.indexerHandler.OnEnd(ctx, info, indexer.ConvCallbackOutput(output))
 	case components.ComponentOfRetriever:
 		return c.retrieverHandler.OnEnd(ctx, info, retriever.ConvCallbackOutput(output))
+	case components.ComponentOfReranker:
+		return c.rerankerHandler.OnEnd(ctx, info, reranker.ConvCallbackOutput(output))
 	case components.ComponentOfLoader:
 		return c.loaderHandler.OnEnd(ctx, info, document.ConvLoaderCallbackOutput(output))
 	case components.ComponentOfTransformer:
@@ -202,6 +214,8 @@ func (c *handlerTemplate) OnError(ctx context.Context, info *callbacks.RunInfo,
 		return c.indexerHandler.OnError(ctx, info, err)
 	case components.ComponentOfRetriever:
 		return c.retrieverHandler.OnError(ctx, info, err)
+	case components.ComponentOfReranker:
+		return c.rerankerHandler.OnError(ctx, info, err)
 	case components.ComponentOfLoader:
 		return c.loaderHandler.OnError(ctx, info, err)
 	case components.ComponentOfTransformer:
@@ -290,6 +304,10 @@ func (c *handlerTemplate) Needed(ctx context.Context, info *callbacks.RunInfo, t
 		if c.retrieverHandler != nil && c.retrieverHandler.Needed(ctx, info, timing) {
 			return true
 		}
+	case components.ComponentOfReranker:
+		if c.rerankerHandler != nil && c.rerankerHandler.Needed(ctx, info, timing) {
+			return true
+		}
 	case components.ComponentOfTool:
 		if c.toolHandler != nil && c.toolHandler.Needed(ctx, info, timing) {
 			return true
@@ -469,6 +487,27 @@ func (ch *RetrieverCallbackHandler) Needed(ctx context.Context, runInfo *callbac
 	}
 }
 
+// RerankerCallbackHandler 重排序器组件的回调处理器。
+type RerankerCallbackHandler struct {
+	OnStart func(ctx context.Context, runInfo *callbacks.RunInfo, input *reranker.CallbackInput) context.Context
+	OnEnd   func(ctx context.Context, runInfo *callbacks.RunInfo, output *reranker.CallbackOutput) context.Context
+	OnError func(ctx context.Context, runInfo *callbacks.RunInfo, err error) context.Context
+}
+
+// Needed 检查指定时机是否需要执行回调。
+func (ch *RerankerCallbackHandler) Needed(ctx context.Context, runInfo *callbacks.RunInfo, timing callbacks.CallbackTiming) bool {
+	switch timing {
+	case callbacks.TimingOnStart:
+		return ch.OnStart != nil
+	case callbacks.TimingOnEnd:
+		return ch.OnEnd != nil
+	case callbacks.TimingOnError:
+		return ch.OnError != nil
+	default:
+		return false
+	}
+}
+
 // ToolCallbackHandler 工具组件的回调处理器。
 type ToolCallbackHandler struct {
 	OnStart               func(ctx context.Context, info *callbacks.RunInfo, input *tool.CallbackInput) context.Context
@@ -14,6 +14,7 @@ import (
 	"github.com/favbox/eino/components/indexer"
 	"github.com/favbox/eino/components/model"
 	"github.com/favbox/eino/components/prompt"
+	"github.com/favbox/eino/components/reranker"
 	"github.com/favbox/eino/components/retriever"
 	"github.com/favbox/eino/components/tool"
 	"github.com/favbox/eino/compose"
@@ -272,3 +273,45 @@ func TestNewHandlerHelper(t *testing.T) {
 		assert.Equal(t, 30, cnt)
 	})
 }
+
+func TestRerankerHandler(t *testing.T) {
+	ctx := context.Background()
+
+	var (
+		input  *reranker.CallbackInput
+		output *reranker.CallbackOutput
+	)
+	handler := NewHandlerHelper().Reranker(&RerankerCallbackHandler{
+		OnStart: func(ctx context.Context, runInfo *callbacks.RunInfo, in *reranker.CallbackInput) context.Context {
+			assert.Equal(t, components.ComponentOfReranker, runInfo.Component)
+			input = in
+			return ctx
+		},
+		OnEnd: func(ctx context.Context, runInfo *callbacks.RunInfo, out *reranker.CallbackOutput) context.Context {
+			output = out
+			return ctx
+		},
+	}).Handler()
+
+	docs := []*schema.Document{{ID: "1"}, {ID: "2"}}
+	chain := compose.NewChain[*reranker.Request, []*schema.Document]()
+	chain.AppendReranker(reverseReranker{})
+	r, err := chain.Compile(ctx)
+	assert.NoError(t, err)
+
+	out, err := r.Invoke(ctx, &reranker.Request{Query: "q", Docs: docs}, compose.WithCallbacks(handler))
+	assert.NoError(t, err)
+	assert.Equal(t, &reranker.CallbackInput{Query: "q", Docs: docs}, input)
+	assert.Equal(t, out, output.Docs)
+}
+
+// reverseReranker 将文档倒序，不自行触发回调。
+type reverseReranker struct{}
+
+func (reverseReranker) Rerank(_ context.Context, _ string, docs []*schema.Document, _ ...reranker.Option) ([]*schema.Document, error) {
+	out := make([]*schema.Document, 0, len(docs))
+	for i := len(docs) - 1; i >= 0; i-- {
+		out = append(out, docs[i])
+	}
+	return out, nil
+}